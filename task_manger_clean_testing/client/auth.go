@@ -0,0 +1,128 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"time"
+)
+
+// Session is the answer to a login: the signed in user with their tokens in
+// Token and RefreshToken, or a Challenge when the account has two-factor
+// authentication.
+type Session struct {
+	domain.User
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	Challenge         string `json:"challenge"`
+}
+
+func (s *Session) tokens() Tokens {
+	var tokens Tokens
+	if s.Token != nil {
+		tokens.Token = *s.Token
+	}
+	if s.RefreshToken != nil {
+		tokens.RefreshToken = *s.RefreshToken
+	}
+	return tokens
+}
+
+// refreshMargin is how long before it expires a token is refreshed, so it
+// does not expire on the way to the server.
+const refreshMargin = time.Minute
+
+// Login signs in with a password. Unless the account needs a second factor,
+// for which LoginTwoFactor redeems the challenge, the session continues with
+// the new tokens.
+func (c *Client) Login(ctx context.Context, email string, password string) (*Session, error) {
+	return c.startSession(ctx, "/login", map[string]string{"email": email, "password": password})
+}
+
+// LoginTwoFactor finishes a login with a one-time or recovery code.
+func (c *Client) LoginTwoFactor(ctx context.Context, challenge string, code string) (*Session, error) {
+	return c.startSession(ctx, "/login/2fa", map[string]string{"challenge": challenge, "code": code})
+}
+
+func (c *Client) startSession(ctx context.Context, path string, body map[string]string) (*Session, error) {
+	var session Session
+	if err := c.doPublic(ctx, http.MethodPost, path, body, &session); err != nil {
+		return nil, err
+	}
+	if session.Token != nil {
+		c.SetTokens(session.tokens())
+	}
+	return &session, nil
+}
+
+// Refresh renews the tokens of the session now. Requests refresh them by
+// themselves when they are about to expire.
+func (c *Client) Refresh(ctx context.Context) error {
+	return c.refresh(ctx, c.Tokens().Token)
+}
+
+// refreshIfExpiring refreshes the tokens when the token expires within
+// refreshMargin.
+func (c *Client) refreshIfExpiring(ctx context.Context) error {
+	tokens := c.Tokens()
+	if tokens.RefreshToken == "" || !expiresWithin(tokens.Token, refreshMargin) {
+		return nil
+	}
+	return c.refresh(ctx, tokens.Token)
+}
+
+// refresh trades the refresh token for new tokens, unless a concurrent
+// request has already replaced stale, the token that needed it.
+func (c *Client) refresh(ctx context.Context, stale string) error {
+	c.refreshing.Lock()
+	defer c.refreshing.Unlock()
+	tokens := c.Tokens()
+	if tokens.Token != stale {
+		return nil
+	}
+	if tokens.RefreshToken == "" {
+		return fmt.Errorf("the session cannot be refreshed without a refresh token")
+	}
+
+	var session Session
+	if err := c.doPublic(ctx, http.MethodPost, "/login/refresh", map[string]string{"refreshtoken": tokens.RefreshToken}, &session); err != nil {
+		return fmt.Errorf("refreshing the session: %w", err)
+	}
+	return c.replaceTokens(session.tokens())
+}
+
+// replaceTokens continues the session with the tokens the API issued in place
+// of the current ones, and hands them to the refresh handler.
+func (c *Client) replaceTokens(tokens Tokens) error {
+	c.SetTokens(tokens)
+	if c.onRefresh != nil {
+		if err := c.onRefresh(tokens); err != nil {
+			return fmt.Errorf("saving the new tokens: %w", err)
+		}
+	}
+	return nil
+}
+
+// expiresWithin reports whether the JWT token expires within d. Only the
+// server can check the signature, so only the expiry is read. Personal
+// access tokens are not JWTs and never count as expiring.
+func expiresWithin(token string, d time.Duration) bool {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+	var claims struct {
+		ExpiresAt int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.ExpiresAt == 0 {
+		return false
+	}
+	return time.Until(time.Unix(claims.ExpiresAt, 0)) < d
+}
@@ -0,0 +1,271 @@
+// Package client is a typed Go client of the task manager HTTP API. Its
+// services have the methods of domain.TaskUsecase and domain.UserUseCase, so
+// other Go services and taskctl call the API like they would the use cases,
+// instead of each writing the requests by hand.
+//
+// A Client keeps the session of one user: it refreshes the token before it
+// expires or when the API rejects it, and retries the requests the API could
+// not serve with backoff.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tokens are the tokens of a session. RefreshToken is empty for personal
+// access tokens, which cannot be refreshed.
+type Tokens struct {
+	Token        string
+	RefreshToken string
+}
+
+// Client calls the API at a base URL on behalf of one signed in user. It is
+// safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	onRefresh  func(Tokens) error
+	maxRetries int
+	retryDelay time.Duration
+
+	mu     sync.Mutex
+	tokens Tokens
+	// refreshing is held while the tokens are refreshed, so concurrent
+	// requests refresh them once.
+	refreshing sync.Mutex
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sends the requests with httpClient instead of
+// http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithToken authorizes the requests with token, such as a personal access
+// token, without refreshing it.
+func WithToken(token string) Option {
+	return WithTokens(Tokens{Token: token})
+}
+
+// WithTokens resumes a session, such as one saved after an earlier login.
+func WithTokens(tokens Tokens) Option {
+	return func(c *Client) {
+		c.tokens = tokens
+	}
+}
+
+// WithRefreshHandler calls onRefresh with the new tokens whenever the client
+// replaces them by itself, to save them: when it refreshes them, or when a
+// change of email reissues them. A refresh token works once, so a session
+// whose new tokens are lost must log in again. The request that replaced
+// them fails with the error of onRefresh.
+func WithRefreshHandler(onRefresh func(Tokens) error) Option {
+	return func(c *Client) {
+		c.onRefresh = onRefresh
+	}
+}
+
+// WithRetries retries a request the API answered with 429, or with a 5xx
+// when retrying cannot apply it twice, up to maxRetries times. The delays
+// start at delay and double, unless the API asks for a longer one. A request
+// the API asks to retry only after maxRetryDelay fails at once with an *Error
+// telling how long to wait.
+func WithRetries(maxRetries int, delay time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryDelay = delay
+	}
+}
+
+// maxRetryDelay caps the backoff. A longer Retry-After is left to the caller.
+const maxRetryDelay = 10 * time.Second
+
+// New returns a client of the API served at baseURL, such as
+// http://localhost:8080. It retries 3 times from 200ms by default.
+func New(baseURL string, options ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		maxRetries: 3,
+		retryDelay: 200 * time.Millisecond,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// Tokens returns the tokens of the session.
+func (c *Client) Tokens() Tokens {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.tokens
+}
+
+// SetTokens authorizes the following requests with tokens.
+func (c *Client) SetTokens(tokens Tokens) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tokens = tokens
+}
+
+// do sends body as JSON to path with the session's token and decodes the
+// answer into out, unless out is nil. Answers of 400 and above are returned as
+// an *Error.
+func (c *Client) do(ctx context.Context, method string, path string, body any, out any) error {
+	return c.call(ctx, method, path, body, out, true)
+}
+
+// doPublic is do for the routes that take no token, such as the logins.
+func (c *Client) doPublic(ctx context.Context, method string, path string, body any, out any) error {
+	return c.call(ctx, method, path, body, out, false)
+}
+
+func (c *Client) call(ctx context.Context, method string, path string, body any, out any, authenticated bool) error {
+	var encoded []byte
+	if body != nil {
+		var err error
+		if encoded, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("encoding the request: %w", err)
+		}
+	}
+
+	if authenticated {
+		if err := c.refreshIfExpiring(ctx); err != nil {
+			return err
+		}
+	}
+	refreshed := false
+	for retries := 0; ; {
+		token := ""
+		if authenticated {
+			token = c.Tokens().Token
+		}
+		resp, err := c.send(ctx, method, path, encoded, token)
+		if err != nil {
+			return err
+		}
+
+		// A token can be rejected before it expires, when the server's
+		// clock is ahead. Refreshing is worth one more try.
+		if authenticated && resp.StatusCode == http.StatusUnauthorized && !refreshed && c.Tokens().RefreshToken != "" {
+			discard(resp)
+			refreshed = true
+			if err := c.refresh(ctx, token); err != nil {
+				return err
+			}
+			continue
+		}
+		if retries < c.maxRetries && retryable(method, resp.StatusCode) {
+			delay, ok := c.backoff(retries, resp)
+			if !ok {
+				return decode(resp, method, path, out)
+			}
+			discard(resp)
+			retries++
+			if err := sleep(ctx, delay); err != nil {
+				return err
+			}
+			continue
+		}
+		return decode(resp, method, path, out)
+	}
+}
+
+func (c *Client) send(ctx context.Context, method string, path string, body []byte, token string) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return c.httpClient.Do(req)
+}
+
+func decode(resp *http.Response, method string, path string, out any) error {
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newError(resp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding the answer to %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// discard drains resp so its connection can be reused.
+func discard(resp *http.Response) {
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+// retryable reports whether a request answered with status is worth
+// sending again. A 429 was refused before it ran. A 5xx may have applied the
+// request, so only idempotent methods are retried.
+func retryable(method string, status int) bool {
+	if status == http.StatusTooManyRequests {
+		return true
+	}
+	if status < http.StatusInternalServerError || status == http.StatusNotImplemented {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// backoff returns how long to wait before the retry after retries others:
+// the API's Retry-After, or an exponential delay with jitter. It returns
+// false when the API asks to wait longer than maxRetryDelay.
+func (c *Client) backoff(retries int, resp *http.Response) (time.Duration, bool) {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		delay := time.Duration(seconds) * time.Second
+		return delay, delay <= maxRetryDelay
+	}
+	delay := c.retryDelay << retries
+	if delay > maxRetryDelay || delay <= 0 {
+		delay = maxRetryDelay
+	}
+	// Waiting between half and all of the delay spreads out clients that
+	// failed together
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1)), true
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
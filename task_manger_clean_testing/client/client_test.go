@@ -0,0 +1,263 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"task_manger_clean_architecture/domain"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type ClientTestSuite struct {
+	suite.Suite
+	mux    *http.ServeMux
+	server *httptest.Server
+}
+
+func (suite *ClientTestSuite) SetupTest() {
+	suite.mux = http.NewServeMux()
+	suite.server = httptest.NewServer(suite.mux)
+}
+
+func (suite *ClientTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+// newClient returns a client of the test server that retries without waiting.
+func (suite *ClientTestSuite) newClient(options ...Option) *Client {
+	return New(suite.server.URL+"/", append([]Option{WithRetries(3, time.Millisecond)}, options...)...)
+}
+
+func reply(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func signed(expiresIn time.Duration) string {
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{ExpiresAt: time.Now().Add(expiresIn).Unix()}).SignedString([]byte("secret"))
+	return token
+}
+
+func (suite *ClientTestSuite) TestRetriesIdempotentRequests() {
+	var calls atomic.Int32
+	suite.mux.HandleFunc("GET /task", func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			reply(w, http.StatusServiceUnavailable, map[string]string{"error": "unavailable"})
+			return
+		}
+		reply(w, http.StatusOK, []domain.Task{{ID: "task1"}})
+	})
+
+	tasks, err := suite.newClient().Tasks().GetTasks(context.Background())
+	suite.Require().NoError(err)
+	suite.Equal("task1", tasks[0].ID)
+	suite.Equal(int32(3), calls.Load())
+}
+
+func (suite *ClientTestSuite) TestGivesUpAfterMaxRetries() {
+	var calls atomic.Int32
+	suite.mux.HandleFunc("GET /task", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		reply(w, http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve tasks", "message": "boom"})
+	})
+
+	_, err := suite.newClient().Tasks().GetTasks(context.Background())
+	suite.True(errors.Is(err, ErrServer))
+	suite.Equal(int32(4), calls.Load())
+}
+
+func (suite *ClientTestSuite) TestDoesNotRetryUnsafeRequestsOnServerErrors() {
+	var calls atomic.Int32
+	suite.mux.HandleFunc("POST /task", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		reply(w, http.StatusInternalServerError, map[string]string{"error": "Failed to add task to the database"})
+	})
+
+	err := suite.newClient().Tasks().AddTask(context.Background(), domain.Task{ID: "task1"})
+	suite.True(errors.Is(err, ErrServer))
+	suite.Equal(int32(1), calls.Load())
+}
+
+func (suite *ClientTestSuite) TestRetriesRateLimitedRequestsAfterRetryAfter() {
+	var calls atomic.Int32
+	suite.mux.HandleFunc("POST /task", func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			reply(w, http.StatusTooManyRequests, map[string]string{"error": "too many requests"})
+			return
+		}
+		reply(w, http.StatusOK, map[string]string{"message": "Task added successfully"})
+	})
+
+	// The hour of backoff is never waited, the API asks to retry at once
+	err := suite.newClient(WithRetries(1, time.Hour)).Tasks().AddTask(context.Background(), domain.Task{ID: "task1"})
+	suite.NoError(err)
+	suite.Equal(int32(2), calls.Load())
+}
+
+func (suite *ClientTestSuite) TestReturnsLongRetryAfterInsteadOfWaiting() {
+	var calls atomic.Int32
+	suite.mux.HandleFunc("POST /task", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		reply(w, http.StatusTooManyRequests, map[string]string{"error": "too many requests"})
+	})
+
+	start := time.Now()
+	err := suite.newClient(WithRetries(3, time.Millisecond)).Tasks().AddTask(context.Background(), domain.Task{ID: "task1"})
+	var apiErr *Error
+	suite.Require().ErrorAs(err, &apiErr)
+	suite.Equal(http.StatusTooManyRequests, apiErr.StatusCode)
+	suite.Equal(time.Hour, apiErr.RetryAfter)
+	suite.Equal(int32(1), calls.Load())
+	suite.Less(time.Since(start), time.Second)
+}
+
+func (suite *ClientTestSuite) TestCancelledWhileWaitingToRetry() {
+	suite.mux.HandleFunc("GET /task", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, http.StatusServiceUnavailable, map[string]string{"error": "unavailable"})
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := suite.newClient(WithRetries(3, time.Hour)).Tasks().GetTasks(ctx)
+	suite.ErrorIs(err, context.DeadlineExceeded)
+	suite.Less(time.Since(start), time.Second)
+}
+
+func (suite *ClientTestSuite) TestRefreshesExpiringTokenOnce() {
+	fresh := signed(time.Hour)
+	var refreshes atomic.Int32
+	suite.mux.HandleFunc("POST /login/refresh", func(w http.ResponseWriter, r *http.Request) {
+		refreshes.Add(1)
+		var body map[string]string
+		suite.Require().NoError(json.NewDecoder(r.Body).Decode(&body))
+		suite.Equal("refresh1", body["refreshtoken"])
+		suite.Empty(r.Header.Get("Authorization"))
+		reply(w, http.StatusOK, map[string]string{"token": fresh, "refreshtoken": "refresh2"})
+	})
+	suite.mux.HandleFunc("GET /task", func(w http.ResponseWriter, r *http.Request) {
+		suite.Equal("Bearer "+fresh, r.Header.Get("Authorization"))
+		reply(w, http.StatusOK, []domain.Task{})
+	})
+	var saved []Tokens
+	var mu sync.Mutex
+	c := suite.newClient(
+		WithTokens(Tokens{Token: signed(10 * time.Second), RefreshToken: "refresh1"}),
+		WithRefreshHandler(func(tokens Tokens) error {
+			mu.Lock()
+			defer mu.Unlock()
+			saved = append(saved, tokens)
+			return nil
+		}),
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.Tasks().GetTasks(context.Background())
+			suite.NoError(err)
+		}()
+	}
+	wg.Wait()
+	suite.Equal(int32(1), refreshes.Load())
+	suite.Equal([]Tokens{{Token: fresh, RefreshToken: "refresh2"}}, saved)
+	suite.Equal(Tokens{Token: fresh, RefreshToken: "refresh2"}, c.Tokens())
+}
+
+func (suite *ClientTestSuite) TestRefreshesRejectedToken() {
+	stale, fresh := signed(time.Hour), signed(2*time.Hour)
+	suite.mux.HandleFunc("POST /login/refresh", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, http.StatusOK, map[string]string{"token": fresh, "refreshtoken": "refresh2"})
+	})
+	suite.mux.HandleFunc("GET /task", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer "+stale {
+			reply(w, http.StatusUnauthorized, map[string]string{"error": "token is expired"})
+			return
+		}
+		reply(w, http.StatusOK, []domain.Task{})
+	})
+
+	c := suite.newClient(WithTokens(Tokens{Token: stale, RefreshToken: "refresh1"}))
+	_, err := c.Tasks().GetTasks(context.Background())
+	suite.NoError(err)
+	suite.Equal(fresh, c.Tokens().Token)
+}
+
+func (suite *ClientTestSuite) TestFailedRefreshIsUnauthorized() {
+	suite.mux.HandleFunc("POST /login/refresh", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, http.StatusUnauthorized, map[string]string{"error": "refresh token is invalid or has expired, please log in again"})
+	})
+
+	c := suite.newClient(WithTokens(Tokens{Token: signed(time.Second), RefreshToken: "refresh1"}))
+	_, err := c.Tasks().GetTasks(context.Background())
+	suite.True(errors.Is(err, ErrUnauthorized))
+	suite.ErrorContains(err, "refreshing the session")
+}
+
+func (suite *ClientTestSuite) TestAccessTokensAreNotRefreshed() {
+	suite.mux.HandleFunc("GET /task", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, http.StatusUnauthorized, map[string]string{"error": domain.ErrInvalidAccessToken.Error()})
+	})
+
+	_, err := suite.newClient(WithToken(domain.AccessTokenPrefix + "abc")).Tasks().GetTasks(context.Background())
+	suite.True(errors.Is(err, ErrUnauthorized))
+	suite.True(errors.Is(err, domain.ErrInvalidAccessToken))
+}
+
+func (suite *ClientTestSuite) TestErrorsCarryTheAnswer() {
+	suite.mux.HandleFunc("GET /task/{id}", func(w http.ResponseWriter, r *http.Request) {
+		suite.Equal("a/b", r.PathValue("id"))
+		w.Header().Set(domain.RequestIDHeader, "req1")
+		reply(w, http.StatusBadRequest, map[string]string{"error": "Failed to retrieve task, the ID doesn't exist", "message": mongo.ErrNoDocuments.Error()})
+	})
+	suite.mux.HandleFunc("PATCH /users/me", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, http.StatusConflict, map[string]string{"error": domain.ErrEmailTaken.Error(), "request_id": "req2"})
+	})
+
+	_, err := suite.newClient().Tasks().GetTasksById(context.Background(), "a/b")
+	var apiErr *Error
+	suite.Require().True(errors.As(err, &apiErr))
+	suite.Equal(&Error{StatusCode: http.StatusBadRequest, Message: "Failed to retrieve task, the ID doesn't exist", Detail: mongo.ErrNoDocuments.Error(), RequestID: "req1"}, apiErr)
+	suite.True(errors.Is(err, mongo.ErrNoDocuments))
+	suite.False(errors.Is(err, ErrNotFound))
+	suite.Equal("400 Failed to retrieve task, the ID doesn't exist: mongo: no documents in result (request req1)", err.Error())
+
+	_, err = suite.newClient().Users().UpdateProfile(context.Background(), "user1", "USER", domain.ProfileUpdate{})
+	suite.True(errors.Is(err, domain.ErrEmailTaken))
+	suite.True(errors.Is(err, ErrConflict))
+	suite.False(errors.Is(err, domain.ErrProfileFieldNotEditable))
+	suite.Require().True(errors.As(err, &apiErr))
+	suite.Equal("req2", apiErr.RequestID)
+}
+
+func (suite *ClientTestSuite) TestGetUsersNeedsWholePages() {
+	_, err := suite.newClient().Users().GetUsers(context.Background(), 5, 10)
+	suite.ErrorContains(err, "not the start of a page")
+}
+
+func TestExpiresWithin(t *testing.T) {
+	assert.True(t, expiresWithin(signed(time.Second), time.Minute))
+	assert.True(t, expiresWithin(signed(-time.Hour), time.Minute))
+	assert.False(t, expiresWithin(signed(time.Hour), time.Minute))
+	assert.False(t, expiresWithin(domain.AccessTokenPrefix+"abc", time.Minute))
+	assert.False(t, expiresWithin("a.b.c", time.Minute))
+}
+
+func TestClientTestSuite(t *testing.T) {
+	suite.Run(t, new(ClientTestSuite))
+}
@@ -0,0 +1,231 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"task_manger_clean_architecture/delivery/controllers"
+	"task_manger_clean_architecture/delivery/middleware"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"task_manger_clean_architecture/infrastructure"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ContractTestSuite runs the client against the API's controllers and
+// authentication behind a real gin router, so a change to either side that
+// breaks the other fails here. Only the use cases are mocked.
+type ContractTestSuite struct {
+	suite.Suite
+	server   *httptest.Server
+	tasks    *mocks.TaskUsecase
+	users    *mocks.UserUseCase
+	admin    domain.User
+	client   *Client
+	password string
+}
+
+func (suite *ContractTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+	suite.tasks = new(mocks.TaskUsecase)
+	suite.users = new(mocks.UserUseCase)
+	throttle := new(mocks.LoginThrottleUsecase)
+	throttle.On("Reserve", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	throttle.On("Success", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	hasher := new(mocks.PasswordHasher)
+	hasher.On("Verify", "secret", "hash").Return(true, false, nil)
+	audit := new(mocks.AuditUsecase)
+	audit.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	jwt := infrastructure.NewJWT("contract")
+	metrics := infrastructure.NewMetrics(prometheus.NewRegistry())
+	tc := &controllers.TaskController{TaskUseCase: suite.tasks}
+	uc := &controllers.UserController{
+		UserUseCase:    suite.users,
+		AuditUseCase:   audit,
+		LoginThrottle:  throttle,
+		PasswordHasher: hasher,
+		JWT:            jwt,
+		Logger:         slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Metrics:        metrics,
+	}
+
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.POST("/login", uc.Login())
+	router.POST("/login/refresh", uc.Refresh())
+	signedIn := router.Group("", middleware.Authenticate(jwt, metrics))
+	signedIn.POST("/task", tc.AddTask())
+	signedIn.GET("/task", tc.GetTasks())
+	signedIn.GET("/task/search", tc.SearchTasks())
+	signedIn.POST("/task/search/reindex", tc.ReindexTasks())
+	signedIn.GET("/task/:task_id", tc.GetTasksById())
+	signedIn.DELETE("/task/:task_id", tc.DeleteById())
+	signedIn.PUT("/task/:task_id", tc.UpdateTask())
+	signedIn.GET("/users", uc.GetUsers())
+	signedIn.GET("/users/:user_id", uc.GetUser())
+	signedIn.PATCH("/users/me", uc.UpdateProfile())
+	signedIn.POST("/promote/:user_id", uc.Promote())
+	suite.server = httptest.NewServer(router)
+
+	email, first, last, role, hash := "admin@example.com", "Ada", "Admin", "ADMIN", "hash"
+	suite.admin = domain.User{
+		UserId:     "admin1",
+		Email:      &email,
+		FirstName:  &first,
+		LastName:   &last,
+		UserType:   &role,
+		Password:   &hash,
+		Workspaces: []domain.WorkspaceMembership{{WorkspaceID: domain.DefaultWorkspaceID, Role: "ADMIN"}},
+	}
+	// The stored tokens follow UpdateAllTokens, like in the database
+	suite.users.On("Login", mock.Anything, email).Return(func(context.Context, string) *domain.User {
+		admin := suite.admin
+		return &admin
+	}, nil)
+	suite.users.On("GetUser", mock.Anything, "admin1").Return(func(context.Context, string) domain.User {
+		return suite.admin
+	}, nil)
+	suite.users.On("UpdateAllTokens", mock.Anything, mock.Anything, mock.Anything, "admin1").Run(func(args mock.Arguments) {
+		token, refreshToken := args.String(1), args.String(2)
+		suite.admin.Token, suite.admin.RefreshToken = &token, &refreshToken
+	}).Return(nil)
+
+	suite.client = New(suite.server.URL, WithRetries(0, 0))
+	_, err := suite.client.Login(context.Background(), email, "secret")
+	suite.Require().NoError(err)
+}
+
+func (suite *ContractTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *ContractTestSuite) TestLoginIssuesTokens() {
+	tokens := suite.client.Tokens()
+	suite.NotEmpty(tokens.Token)
+	suite.NotEmpty(tokens.RefreshToken)
+	suite.Equal(*suite.admin.RefreshToken, tokens.RefreshToken)
+}
+
+func (suite *ContractTestSuite) TestTasks() {
+	due := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	task := domain.Task{ID: "task1", Title: "Write docs", Status: "Pending", DueDate: due}
+	stored := task
+	stored.CreatedBy, stored.WorkspaceID = "admin1", domain.DefaultWorkspaceID
+	suite.tasks.On("AddTask", mock.Anything, stored).Return(nil)
+	suite.tasks.On("GetTasks", mock.Anything).Return([]*domain.Task{&stored}, nil)
+	suite.tasks.On("GetTasksById", mock.Anything, "task1").Return(&stored, nil)
+	suite.tasks.On("GetTasksById", mock.Anything, "missing").Return(nil, mongo.ErrNoDocuments)
+	suite.tasks.On("UpdateTask", mock.Anything, "task1", task).Return(nil)
+	suite.tasks.On("DeleteById", mock.Anything, "task1").Return(int64(1), nil)
+	suite.tasks.On("DeleteById", mock.Anything, "missing").Return(int64(0), nil)
+	suite.tasks.On("SearchTasks", mock.Anything, "docs", 5).Return([]*domain.TaskSearchResult{{Task: &stored, Score: 1}}, nil)
+	suite.tasks.On("ReindexTasks", mock.Anything).Return(7, nil)
+	tasks := suite.client.Tasks()
+	ctx := context.Background()
+
+	suite.Require().NoError(tasks.AddTask(ctx, task))
+	all, err := tasks.GetTasks(ctx)
+	suite.Require().NoError(err)
+	suite.Equal([]*domain.Task{&stored}, all)
+	got, err := tasks.GetTasksById(ctx, "task1")
+	suite.Require().NoError(err)
+	suite.Equal(&stored, got)
+	_, err = tasks.GetTasksById(ctx, "missing")
+	suite.ErrorIs(err, mongo.ErrNoDocuments)
+	suite.NoError(tasks.UpdateTask(ctx, "task1", task))
+
+	deleted, err := tasks.DeleteById(ctx, "task1")
+	suite.NoError(err)
+	suite.Equal(int64(1), deleted)
+	deleted, err = tasks.DeleteById(ctx, "missing")
+	suite.NoError(err)
+	suite.Equal(int64(0), deleted)
+
+	results, err := tasks.SearchTasks(ctx, "docs", 5)
+	suite.Require().NoError(err)
+	suite.Equal("task1", results[0].Task.ID)
+	indexed, err := tasks.ReindexTasks(ctx)
+	suite.NoError(err)
+	suite.Equal(7, indexed)
+}
+
+func (suite *ContractTestSuite) TestUsers() {
+	email, first, last, role := "user@example.com", "Uma", "User", "USER"
+	member := domain.User{UserId: "user1", Email: &email, FirstName: &first, LastName: &last, UserType: &role,
+		Workspaces: []domain.WorkspaceMembership{{WorkspaceID: domain.DefaultWorkspaceID, Role: "USER"}}}
+	suite.users.On("GetUsers", mock.Anything, int64(10), int64(5)).Return([]*domain.User{&member}, nil)
+	suite.users.On("GetMember", mock.Anything, "user1").Return(member, nil)
+	suite.users.On("Promote", mock.Anything, "user1", "ADMIN").Return(nil, int64(1), int64(1))
+	suite.users.On("Promote", mock.Anything, "missing", "ADMIN").Return(nil, int64(0), int64(0))
+	users := suite.client.Users()
+	ctx := context.Background()
+
+	page, err := users.GetUsers(ctx, 10, 5)
+	suite.Require().NoError(err)
+	suite.Require().Len(page, 1)
+	suite.Equal("user@example.com", *page[0].Email)
+	suite.Equal("USER", *page[0].UserType)
+
+	user, err := users.GetUser(ctx, "user1")
+	suite.Require().NoError(err)
+	suite.Equal("user1", user.UserId)
+	suite.Equal("Uma", *user.FirstName)
+	suite.Nil(user.Password)
+
+	err, matched, modified := users.Promote(ctx, "user1", "ADMIN")
+	suite.NoError(err)
+	suite.Equal([]int64{1, 1}, []int64{matched, modified})
+	err, matched, modified = users.Promote(ctx, "missing", "ADMIN")
+	suite.NoError(err)
+	suite.Equal([]int64{0, 0}, []int64{matched, modified})
+}
+
+func (suite *ContractTestSuite) TestUpdateProfileErrors() {
+	phone := "0911"
+	suite.users.On("UpdateProfile", mock.Anything, "admin1", "ADMIN", domain.ProfileUpdate{Phone: &phone}).Return(domain.User{}, domain.ErrEmailTaken)
+
+	_, err := suite.client.Users().UpdateProfile(context.Background(), "admin1", "ADMIN", domain.ProfileUpdate{Phone: &phone})
+	suite.True(errors.Is(err, domain.ErrEmailTaken))
+	suite.True(errors.Is(err, ErrConflict))
+	var apiErr *Error
+	suite.Require().True(errors.As(err, &apiErr))
+	suite.NotEmpty(apiErr.RequestID)
+}
+
+func (suite *ContractTestSuite) TestRefreshRotatesTokens() {
+	suite.tasks.On("GetTasks", mock.Anything).Return([]*domain.Task{}, nil)
+	before := suite.client.Tokens()
+
+	suite.Require().NoError(suite.client.Refresh(context.Background()))
+	after := suite.client.Tokens()
+	suite.NotEqual(before, after)
+	_, err := suite.client.Tasks().GetTasks(context.Background())
+	suite.NoError(err)
+
+	// The old refresh token was used up
+	stale := New(suite.server.URL, WithTokens(before))
+	suite.True(errors.Is(stale.Refresh(context.Background()), ErrUnauthorized))
+}
+
+func (suite *ContractTestSuite) TestRequestsNeedAToken() {
+	_, err := New(suite.server.URL).Tasks().GetTasks(context.Background())
+	suite.True(errors.Is(err, ErrUnauthorized))
+
+	// A refresh token cannot stand in for the token
+	_, err = New(suite.server.URL, WithToken(suite.client.Tokens().RefreshToken)).Tasks().GetTasks(context.Background())
+	suite.True(errors.Is(err, ErrUnauthorized))
+}
+
+func TestContractTestSuite(t *testing.T) {
+	suite.Run(t, new(ContractTestSuite))
+}
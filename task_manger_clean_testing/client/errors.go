@@ -0,0 +1,141 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// The errors an *Error matches by status code.
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServer       = errors.New("server error")
+)
+
+// serverErrors are the errors the API answers with their own text, which an
+// *Error matches like the use cases' errors, so errors.Is works the same
+// over HTTP.
+var serverErrors = []error{
+	mongo.ErrNoDocuments,
+	domain.ErrInvalidAccessToken,
+	domain.ErrAccessTokenNotFound,
+	domain.ErrUnknownScope,
+	domain.ErrScopeNotAllowed,
+	domain.ErrExpiryInPast,
+	domain.ErrInvalidUserToken,
+	domain.ErrInvalidInvite,
+	domain.ErrInviteNotFound,
+	domain.ErrAlreadyMember,
+	domain.ErrInviteNeedsAccount,
+	domain.ErrInviteUnverifiedAccount,
+	domain.ErrSignupClosed,
+	domain.ErrPasswordTooShort,
+	domain.ErrPasswordBreached,
+	domain.ErrProfileFieldNotEditable,
+	domain.ErrEmailTaken,
+	domain.ErrTwoFactorNotEnabled,
+	domain.ErrTwoFactorAlreadyEnabled,
+	domain.ErrTwoFactorNotEnrolled,
+	domain.ErrInvalidTwoFactorCode,
+	domain.ErrTwoFactorRequired,
+	domain.ErrNoWorkspace,
+	domain.ErrNotWorkspaceMember,
+	domain.ErrWorkspaceNotFound,
+}
+
+// Error is an error answered by the API: {"error": ..., "message": ...}.
+type Error struct {
+	StatusCode int
+	// Message is the error of the body, or the status text without one.
+	Message string
+	// Detail is the message the body adds to the error, often the error of
+	// the use case.
+	Detail string
+	// RequestID finds the request in the server's logs.
+	RequestID string
+	// RetryAfter is how long a rate limited client should wait.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	message := fmt.Sprintf("%d %s", e.StatusCode, e.Message)
+	if e.Detail != "" {
+		message += ": " + e.Detail
+	}
+	if e.RequestID != "" {
+		message += " (request " + e.RequestID + ")"
+	}
+	return message
+}
+
+// Is matches the Err errors of this package by status code, and the errors of
+// the use cases the API answers with by their text.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= http.StatusInternalServerError
+	}
+	for _, known := range serverErrors {
+		if target == known {
+			return says(e.Message, known) || says(e.Detail, known)
+		}
+	}
+	return false
+}
+
+// says reports whether message is err, or err wrapped with more details.
+func says(message string, err error) bool {
+	return message == err.Error() || strings.HasPrefix(message, err.Error()+": ")
+}
+
+// newError reads the error of an answer. Most handlers answer
+// {"error": ...}, some {"message": ...}, and the task handlers both.
+func newError(resp *http.Response) *Error {
+	apiErr := &Error{
+		StatusCode: resp.StatusCode,
+		Message:    http.StatusText(resp.StatusCode),
+		RequestID:  resp.Header.Get(domain.RequestIDHeader),
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	var body struct {
+		Error     string `json:"error"`
+		Message   string `json:"message"`
+		RequestID string `json:"request_id"`
+	}
+	if json.NewDecoder(resp.Body).Decode(&body) != nil {
+		return apiErr
+	}
+	switch {
+	case body.Error != "":
+		apiErr.Message, apiErr.Detail = body.Error, body.Message
+	case body.Message != "":
+		apiErr.Message = body.Message
+	}
+	if body.RequestID != "" {
+		apiErr.RequestID = body.RequestID
+	}
+	return apiErr
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"task_manger_clean_architecture/domain"
+)
+
+// TaskService calls the task routes. It is a domain.TaskUsecase, acting in
+// the workspace of the client's token.
+type TaskService struct {
+	client *Client
+}
+
+var _ domain.TaskUsecase = (*TaskService)(nil)
+
+func (c *Client) Tasks() *TaskService {
+	return &TaskService{client: c}
+}
+
+// GetTasks implements domain.TaskUsecase.
+func (s *TaskService) GetTasks(c context.Context) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	if err := s.client.do(c, http.MethodGet, "/task", nil, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// GetTasksById implements domain.TaskUsecase. A missing task is
+// mongo.ErrNoDocuments, as from the use case.
+func (s *TaskService) GetTasksById(c context.Context, id string) (*domain.Task, error) {
+	var task domain.Task
+	if err := s.client.do(c, http.MethodGet, "/task/"+url.PathEscape(id), nil, &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// DeleteById implements domain.TaskUsecase. Like the use case it deletes
+// nothing, without an error, when the task does not exist.
+func (s *TaskService) DeleteById(c context.Context, id string) (int64, error) {
+	err := s.client.do(c, http.MethodDelete, "/task/"+url.PathEscape(id), nil, nil)
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound && apiErr.Message == "Task not found" {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+// UpdateTask implements domain.TaskUsecase. It replaces the whole task.
+func (s *TaskService) UpdateTask(c context.Context, id string, updatedTask domain.Task) error {
+	return s.client.do(c, http.MethodPut, "/task/"+url.PathEscape(id), updatedTask, nil)
+}
+
+// AddTask implements domain.TaskUsecase. The caller chooses the ID of the
+// task.
+func (s *TaskService) AddTask(c context.Context, newTask domain.Task) error {
+	return s.client.do(c, http.MethodPost, "/task", newTask, nil)
+}
+
+// SearchTasks implements domain.TaskUsecase. The API answers 100 results at
+// most.
+func (s *TaskService) SearchTasks(c context.Context, query string, limit int) ([]*domain.TaskSearchResult, error) {
+	values := url.Values{}
+	values.Set("q", query)
+	values.Set("limit", strconv.Itoa(limit))
+	var results []*domain.TaskSearchResult
+	if err := s.client.do(c, http.MethodGet, "/task/search?"+values.Encode(), nil, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ReindexTasks implements domain.TaskUsecase.
+func (s *TaskService) ReindexTasks(c context.Context) (int, error) {
+	var answer struct {
+		Indexed int `json:"indexed"`
+	}
+	if err := s.client.do(c, http.MethodPost, "/task/search/reindex", nil, &answer); err != nil {
+		return 0, err
+	}
+	return answer.Indexed, nil
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"task_manger_clean_architecture/domain"
+)
+
+// UserService calls the user routes with the methods of domain.UserUseCase
+// that the API offers. The rest, such as Login by email alone or
+// UpdateAllTokens, are the server's business: Client.Login signs in.
+type UserService struct {
+	client *Client
+}
+
+func (c *Client) Users() *UserService {
+	return &UserService{client: c}
+}
+
+// Signup creates an account and returns its ID, like
+// domain.UserUseCase.Signup. The user then signs in with Client.Login.
+func (s *UserService) Signup(c context.Context, user domain.User) (interface{}, error) {
+	var answer struct {
+		InsertionNumber interface{} `json:"insertionnumber"`
+	}
+	if err := s.client.doPublic(c, http.MethodPost, "/signup", user, &answer); err != nil {
+		return nil, err
+	}
+	return answer.InsertionNumber, nil
+}
+
+// GetUsers returns the members of the workspace from startIndex on, like
+// domain.UserUseCase.GetUsers. The API serves pages of recordsPerPage, so
+// startIndex must be a multiple of it.
+func (s *UserService) GetUsers(c context.Context, startIndex int64, recordsPerPage int64) ([]*domain.User, error) {
+	if recordsPerPage < 1 || startIndex%recordsPerPage != 0 {
+		return nil, fmt.Errorf("startIndex %d is not the start of a page of %d", startIndex, recordsPerPage)
+	}
+	query := url.Values{}
+	query.Set("page", strconv.FormatInt(startIndex/recordsPerPage+1, 10))
+	query.Set("recordPerPage", strconv.FormatInt(recordsPerPage, 10))
+	var users []*domain.User
+	if err := s.client.do(c, http.MethodGet, "/users?"+query.Encode(), nil, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// GetUser returns a member of the workspace, like
+// domain.UserUseCase.GetUser. Other members than administrators and the user
+// themselves only see their name.
+func (s *UserService) GetUser(c context.Context, user_id string) (domain.User, error) {
+	var user domain.User
+	if err := s.client.do(c, http.MethodGet, "/users/"+url.PathEscape(user_id), nil, &user); err != nil {
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+// GetMember is GetUser: the API only shows members of the workspace.
+func (s *UserService) GetMember(c context.Context, user_id string) (domain.User, error) {
+	return s.GetUser(c, user_id)
+}
+
+// Promote makes the user an administrator of the workspace and returns the
+// matched and modified counts, like domain.UserUseCase.Promote. The API only
+// promotes to ADMIN.
+func (s *UserService) Promote(c context.Context, user_id string, userType string) (error, int64, int64) {
+	if userType != "ADMIN" {
+		return fmt.Errorf("the API cannot promote to %q, only to ADMIN", userType), 0, 0
+	}
+	err := s.client.do(c, http.MethodPost, "/promote/"+url.PathEscape(user_id), nil, nil)
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+		switch apiErr.Message {
+		case "user not found":
+			return nil, 0, 0
+		case "user already found":
+			return nil, 1, 0
+		}
+	}
+	if err != nil {
+		return err, 0, 0
+	}
+	return nil, 1, 1
+}
+
+// UpdateProfile changes the signed in user's profile, like
+// domain.UserUseCase.UpdateProfile; the API takes the user and role from the
+// token, so user_id and role are not sent. Changing the email revokes the
+// old tokens, and the session continues with the new ones.
+func (s *UserService) UpdateProfile(c context.Context, user_id string, role string, update domain.ProfileUpdate) (domain.User, error) {
+	var session Session
+	if err := s.client.do(c, http.MethodPatch, "/users/me", update, &session); err != nil {
+		return domain.User{}, err
+	}
+	if session.Token != nil {
+		if err := s.client.replaceTokens(session.tokens()); err != nil {
+			return session.User, err
+		}
+	}
+	return session.User, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"task_manger_clean_architecture/client"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+func newLoginCommand(opts *options) *cobra.Command {
+	var email string
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Sign in and keep the tokens for the other commands",
+		Long: `Sign in with an email and password, and a two-factor code when the account
+has one. The password is read without echo from a terminal, or as the first
+line of piped input.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadConfig(opts.configPath)
+			if err != nil {
+				return err
+			}
+			config.Server = opts.serverURL(config)
+
+			in := bufio.NewReader(cmd.InOrStdin())
+			if email == "" {
+				if email, err = prompt(cmd, in, "Email: "); err != nil {
+					return err
+				}
+			}
+			password, err := promptSecret(cmd, in, "Password: ")
+			if err != nil {
+				return err
+			}
+
+			c := client.New(config.Server)
+			session, err := c.Login(cmd.Context(), email, password)
+			if err != nil {
+				return err
+			}
+			if session.TwoFactorRequired {
+				code, err := prompt(cmd, in, "Two-factor code: ")
+				if err != nil {
+					return err
+				}
+				if session, err = c.LoginTwoFactor(cmd.Context(), session.Challenge, code); err != nil {
+					return err
+				}
+			}
+			if session.Token == nil || session.RefreshToken == nil {
+				return fmt.Errorf("the server answered the login without tokens")
+			}
+
+			config.Token, config.RefreshToken = *session.Token, *session.RefreshToken
+			if err := config.save(opts.configPath); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Logged in to %s as %s\n", config.Server, email)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&email, "email", "", "email to sign in with, prompted for without it")
+	return cmd
+}
+
+func prompt(cmd *cobra.Command, in *bufio.Reader, label string) (string, error) {
+	fmt.Fprint(cmd.ErrOrStderr(), label)
+	line, err := in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("reading %s%w", strings.ToLower(label), err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// promptSecret reads without echo when the input is the terminal.
+func promptSecret(cmd *cobra.Command, in *bufio.Reader, label string) (string, error) {
+	if cmd.InOrStdin() != os.Stdin || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return prompt(cmd, in, label)
+	}
+	fmt.Fprint(cmd.ErrOrStderr(), label)
+	secret, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(cmd.ErrOrStderr())
+	if err != nil {
+		return "", err
+	}
+	return string(secret), nil
+}
@@ -0,0 +1,18 @@
+// Command taskctl calls the task manager API from the command line. It keeps
+// the tokens of the last login in a config file and refreshes them before
+// they expire, so commands need no pasted bearer tokens.
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := newRootCommand().ExecuteContext(ctx); err != nil {
+		os.Exit(1)
+	}
+}
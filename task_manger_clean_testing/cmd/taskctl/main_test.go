@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/suite"
+)
+
+type TaskctlTestSuite struct {
+	suite.Suite
+	mux        *http.ServeMux
+	server     *httptest.Server
+	configPath string
+}
+
+func (suite *TaskctlTestSuite) SetupTest() {
+	suite.mux = http.NewServeMux()
+	suite.server = httptest.NewServer(suite.mux)
+	suite.configPath = filepath.Join(suite.T().TempDir(), "taskctl", "config.yaml")
+}
+
+func (suite *TaskctlTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+// run runs taskctl with args and input, and returns what it printed.
+func (suite *TaskctlTestSuite) run(input string, args ...string) (string, error) {
+	cmd := newRootCommand()
+	var out bytes.Buffer
+	cmd.SetIn(strings.NewReader(input))
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(append([]string{"--config", suite.configPath}, args...))
+	err := cmd.ExecuteContext(context.Background())
+	return out.String(), err
+}
+
+func (suite *TaskctlTestSuite) login(token string, refreshToken string) {
+	suite.Require().NoError((&Config{Server: suite.server.URL, Token: token, RefreshToken: refreshToken}).save(suite.configPath))
+}
+
+func signed(expiresIn time.Duration) string {
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.StandardClaims{ExpiresAt: time.Now().Add(expiresIn).Unix()}).SignedString([]byte("secret"))
+	return token
+}
+
+func reply(w http.ResponseWriter, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
+
+func (suite *TaskctlTestSuite) TestLoginWithSecondFactorSavesTokens() {
+	suite.mux.HandleFunc("POST /login", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, map[string]any{"two_factor_required": true, "challenge": "challenge1"})
+	})
+	suite.mux.HandleFunc("POST /login/2fa", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		suite.Require().NoError(json.NewDecoder(r.Body).Decode(&body))
+		suite.Equal(map[string]string{"challenge": "challenge1", "code": "123456"}, body)
+		reply(w, map[string]string{"token": "access", "refreshtoken": "refresh"})
+	})
+
+	out, err := suite.run("secret\n123456\n", "--server", suite.server.URL, "login", "--email", "bisrat@example.com")
+	suite.Require().NoError(err)
+	suite.Contains(out, "Logged in to "+suite.server.URL+" as bisrat@example.com")
+
+	config, err := loadConfig(suite.configPath)
+	suite.Require().NoError(err)
+	suite.Equal(&Config{Server: suite.server.URL, Token: "access", RefreshToken: "refresh"}, config)
+}
+
+func (suite *TaskctlTestSuite) TestCommandsNeedALogin() {
+	_, err := suite.run("", "task", "list")
+	suite.ErrorContains(err, "not logged in")
+}
+
+func (suite *TaskctlTestSuite) TestTokensOnlyGoToTheirServer() {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.Fail("the tokens were sent to another server", r.Header.Get("Authorization"))
+	}))
+	defer other.Close()
+	suite.login(signed(time.Hour), "refresh1")
+
+	_, err := suite.run("", "--server", other.URL, "task", "list")
+	suite.ErrorContains(err, "not logged in to "+other.URL)
+}
+
+func (suite *TaskctlTestSuite) TestSaveMakesAnExistingConfigPrivate() {
+	suite.Require().NoError(os.MkdirAll(filepath.Dir(suite.configPath), 0o700))
+	suite.Require().NoError(os.WriteFile(suite.configPath, []byte("server: http://old\n"), 0o644))
+
+	suite.login("access", "refresh")
+	info, err := os.Stat(suite.configPath)
+	suite.Require().NoError(err)
+	suite.Equal(os.FileMode(0o600), info.Mode().Perm())
+	config, err := loadConfig(suite.configPath)
+	suite.Require().NoError(err)
+	suite.Equal("access", config.Token)
+	entries, err := os.ReadDir(filepath.Dir(suite.configPath))
+	suite.Require().NoError(err)
+	suite.Len(entries, 1)
+}
+
+func (suite *TaskctlTestSuite) TestExpiringTokenIsRefreshed() {
+	fresh := signed(time.Hour)
+	suite.login(signed(10*time.Second), "refresh1")
+	suite.mux.HandleFunc("POST /login/refresh", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		suite.Require().NoError(json.NewDecoder(r.Body).Decode(&body))
+		suite.Equal("refresh1", body["refreshtoken"])
+		reply(w, map[string]string{"token": fresh, "refreshtoken": "refresh2"})
+	})
+	suite.mux.HandleFunc("GET /task", func(w http.ResponseWriter, r *http.Request) {
+		suite.Equal("Bearer "+fresh, r.Header.Get("Authorization"))
+		reply(w, []domain.Task{})
+	})
+
+	_, err := suite.run("", "task", "list")
+	suite.Require().NoError(err)
+	config, err := loadConfig(suite.configPath)
+	suite.Require().NoError(err)
+	suite.Equal(fresh, config.Token)
+	suite.Equal("refresh2", config.RefreshToken)
+
+	// The new token is good for an hour, so the next command does not refresh
+	suite.mux.HandleFunc("GET /task/{id}", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, domain.Task{ID: r.PathValue("id")})
+	})
+	_, err = suite.run("", "task", "get", "task1")
+	suite.NoError(err)
+}
+
+func (suite *TaskctlTestSuite) TestTaskListFormats() {
+	suite.login(signed(time.Hour), "refresh")
+	due := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	suite.mux.HandleFunc("GET /task", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, []domain.Task{{ID: "task1", Title: "Write docs", Status: "Pending", DueDate: due}})
+	})
+
+	out, err := suite.run("", "task", "list")
+	suite.Require().NoError(err)
+	suite.Equal("ID     TITLE       STATUS   DUE\ntask1  Write docs  Pending  2024-08-01\n", out)
+
+	out, err = suite.run("", "task", "list", "-o", "json")
+	suite.Require().NoError(err)
+	var tasks []domain.Task
+	suite.Require().NoError(json.Unmarshal([]byte(out), &tasks))
+	suite.Equal("Write docs", tasks[0].Title)
+
+	out, err = suite.run("", "task", "list", "-o", "yaml")
+	suite.Require().NoError(err)
+	suite.Contains(out, "- id: task1\n")
+	suite.Contains(out, "due_date: \"2024-08-01T00:00:00Z\"\n")
+
+	_, err = suite.run("", "task", "list", "-o", "xml")
+	suite.ErrorContains(err, "unknown output format")
+}
+
+func (suite *TaskctlTestSuite) TestTaskUpdateKeepsOtherFields() {
+	suite.login(signed(time.Hour), "refresh")
+	suite.mux.HandleFunc("GET /task/task1", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, domain.Task{ID: "task1", Title: "Write docs", Description: "for the CLI", Status: "Pending"})
+	})
+	var updated domain.Task
+	suite.mux.HandleFunc("PUT /task/task1", func(w http.ResponseWriter, r *http.Request) {
+		suite.Require().NoError(json.NewDecoder(r.Body).Decode(&updated))
+		reply(w, map[string]string{"message": "Task updated successfully"})
+	})
+
+	_, err := suite.run("", "task", "update", "task1", "--status", "Completed", "--due", "2024-08-01")
+	suite.Require().NoError(err)
+	suite.Equal("Write docs", updated.Title)
+	suite.Equal("for the CLI", updated.Description)
+	suite.Equal("Completed", updated.Status)
+	suite.Equal(time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC), updated.DueDate)
+}
+
+func (suite *TaskctlTestSuite) TestTaskAddChoosesAnID() {
+	suite.login(signed(time.Hour), "refresh")
+	var added domain.Task
+	suite.mux.HandleFunc("POST /task", func(w http.ResponseWriter, r *http.Request) {
+		suite.Require().NoError(json.NewDecoder(r.Body).Decode(&added))
+		reply(w, map[string]any{"message": "Task added successfully", "task": added})
+	})
+	suite.mux.HandleFunc("GET /task/{id}", func(w http.ResponseWriter, r *http.Request) {
+		suite.Equal(added.ID, r.PathValue("id"))
+		reply(w, added)
+	})
+
+	out, err := suite.run("", "task", "add", "--title", "Write docs")
+	suite.Require().NoError(err)
+	suite.Len(added.ID, 24)
+	suite.Contains(out, added.ID+"  Write docs")
+
+	_, err = suite.run("", "task", "add", "--title", "Write more docs", "--id", "docs2")
+	suite.Require().NoError(err)
+	suite.Equal("docs2", added.ID)
+}
+
+func (suite *TaskctlTestSuite) TestUserPromoteReportsErrors() {
+	suite.login(signed(time.Hour), "refresh")
+	suite.mux.HandleFunc("POST /promote/user2", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		reply(w, map[string]string{"error": "You are not authorized to perform this action"})
+	})
+
+	_, err := suite.run("", "user", "promote", "user2")
+	suite.EqualError(err, "403 You are not authorized to perform this action")
+}
+
+func (suite *TaskctlTestSuite) TestCompletesTaskIDs() {
+	suite.login(signed(time.Hour), "refresh")
+	suite.mux.HandleFunc("GET /task", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, []domain.Task{{ID: "task1", Title: "Write docs"}})
+	})
+
+	out, err := suite.run("", "__complete", "task", "delete", "")
+	suite.Require().NoError(err)
+	suite.Contains(out, "task1\tWrite docs\n")
+}
+
+func TestTaskctlTestSuite(t *testing.T) {
+	suite.Run(t, new(TaskctlTestSuite))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+)
+
+var formats = []string{formatTable, formatJSON, formatYAML}
+
+// table is how a result is printed as a table.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// print writes v in the chosen format. JSON and YAML use the keys of the
+// API, table the columns of rows.
+func (o *options) print(w io.Writer, v any, rows func() table) error {
+	switch o.output {
+	case formatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case formatYAML:
+		// Going through JSON keeps the keys of the API
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		var document yaml.Node
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return err
+		}
+		blockStyle(&document)
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		defer encoder.Close()
+		return encoder.Encode(&document)
+	case formatTable:
+		t := rows()
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		writeRow(tw, t.header)
+		for _, row := range t.rows {
+			writeRow(tw, row)
+		}
+		return tw.Flush()
+	}
+	return fmt.Errorf("unknown output format %q, use table, json or yaml", o.output)
+}
+
+// blockStyle drops the JSON flow style and quotes the parsed nodes keep, so
+// the YAML reads like YAML.
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+func writeRow(w io.Writer, cells []string) {
+	for i, cell := range cells {
+		if i > 0 {
+			fmt.Fprint(w, "\t")
+		}
+		fmt.Fprint(w, cell)
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+const defaultServer = "http://localhost:8080"
+
+// options are the flags every command shares.
+type options struct {
+	configPath string
+	server     string
+	output     string
+}
+
+func newRootCommand() *cobra.Command {
+	opts := &options{}
+	root := &cobra.Command{
+		Use:          "taskctl",
+		Short:        "Manage the tasks and users of a task manager server",
+		SilenceUsage: true,
+	}
+	flags := root.PersistentFlags()
+	flags.StringVar(&opts.configPath, "config", defaultConfigPath(), "file keeping the server and tokens of the last login")
+	flags.StringVar(&opts.server, "server", "", "URL of the server instead of the one of the last login (default "+defaultServer+")")
+	flags.StringVarP(&opts.output, "output", "o", formatTable, "output format: table, json or yaml")
+	root.RegisterFlagCompletionFunc("output", cobra.FixedCompletions(formats, cobra.ShellCompDirectiveNoFileComp))
+
+	root.AddCommand(newLoginCommand(opts), newTaskCommand(opts), newUserCommand(opts))
+	return root
+}
+
+// serverURL returns the server the command talks to.
+func (o *options) serverURL(config *Config) string {
+	switch {
+	case o.server != "":
+		return o.server
+	case config.Server != "":
+		return config.Server
+	}
+	return defaultServer
+}
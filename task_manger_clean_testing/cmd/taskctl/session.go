@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"task_manger_clean_architecture/client"
+)
+
+// connect returns a client that continues the session of the last login. The
+// client refreshes the tokens when they are about to expire, and the new ones
+// are saved for the next command. The tokens only go to the server that
+// issued them, so another server needs a login of its own.
+func (o *options) connect(ctx context.Context) (*client.Client, error) {
+	config, err := loadConfig(o.configPath)
+	if err != nil {
+		return nil, err
+	}
+	if config.Token == "" {
+		return nil, errors.New("not logged in, run taskctl login first")
+	}
+	server := o.serverURL(config)
+	if server != config.Server {
+		return nil, fmt.Errorf("not logged in to %s, run taskctl login --server %s first", server, server)
+	}
+	return client.New(server,
+		client.WithTokens(client.Tokens{Token: config.Token, RefreshToken: config.RefreshToken}),
+		client.WithRefreshHandler(func(tokens client.Tokens) error {
+			config.Token, config.RefreshToken = tokens.Token, tokens.RefreshToken
+			return config.save(o.configPath)
+		}),
+	), nil
+}
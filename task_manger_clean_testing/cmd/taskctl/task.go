@@ -0,0 +1,242 @@
+package main
+
+import (
+	"fmt"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTaskCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "task",
+		Aliases: []string{"tasks"},
+		Short:   "Manage the tasks of your workspace",
+	}
+	cmd.AddCommand(
+		newTaskListCommand(opts),
+		newTaskGetCommand(opts),
+		newTaskAddCommand(opts),
+		newTaskUpdateCommand(opts),
+		newTaskDeleteCommand(opts),
+	)
+	return cmd
+}
+
+func taskTable(tasks ...*domain.Task) func() table {
+	return func() table {
+		t := table{header: []string{"ID", "TITLE", "STATUS", "DUE"}}
+		for _, task := range tasks {
+			t.rows = append(t.rows, []string{task.ID, task.Title, task.Status, formatDate(task.DueDate)})
+		}
+		return t
+	}
+}
+
+func formatDate(date time.Time) string {
+	if date.IsZero() {
+		return "-"
+	}
+	return date.Format(time.DateOnly)
+}
+
+func newTaskListCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the tasks",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			tasks, err := c.Tasks().GetTasks(cmd.Context())
+			if err != nil {
+				return err
+			}
+			return opts.print(cmd.OutOrStdout(), tasks, taskTable(tasks...))
+		},
+	}
+}
+
+func newTaskGetCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:               "get TASK_ID",
+		Short:             "Show a task",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: opts.completeTaskIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			task, err := c.Tasks().GetTasksById(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return opts.print(cmd.OutOrStdout(), task, taskTable(task))
+		},
+	}
+}
+
+// taskFlags are the fields of a task that add and update set.
+type taskFlags struct {
+	title       string
+	description string
+	due         string
+	status      string
+}
+
+func (f *taskFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.title, "title", "", "title of the task")
+	cmd.Flags().StringVar(&f.description, "description", "", "description of the task")
+	cmd.Flags().StringVar(&f.due, "due", "", "due date, as 2006-01-02 or an RFC 3339 time")
+	cmd.Flags().StringVar(&f.status, "status", "", "status of the task, such as Pending or Completed")
+}
+
+// apply sets the fields of task whose flags were given.
+func (f *taskFlags) apply(cmd *cobra.Command, task *domain.Task) error {
+	flags := cmd.Flags()
+	if flags.Changed("title") {
+		task.Title = f.title
+	}
+	if flags.Changed("description") {
+		task.Description = f.description
+	}
+	if flags.Changed("status") {
+		task.Status = f.status
+	}
+	if flags.Changed("due") {
+		due, err := parseDate(f.due)
+		if err != nil {
+			return err
+		}
+		task.DueDate = due
+	}
+	return nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--due %q is neither 2006-01-02 nor an RFC 3339 time", value)
+	}
+	return date, nil
+}
+
+func newTaskAddCommand(opts *options) *cobra.Command {
+	var fields taskFlags
+	var id string
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Create a task",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			task := domain.Task{ID: id}
+			if task.ID == "" {
+				task.ID = primitive.NewObjectID().Hex()
+			}
+			if err := fields.apply(cmd, &task); err != nil {
+				return err
+			}
+			c, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if err := c.Tasks().AddTask(cmd.Context(), task); err != nil {
+				return err
+			}
+			// Show the task as stored, with the fields the server sets
+			created, err := c.Tasks().GetTasksById(cmd.Context(), task.ID)
+			if err != nil {
+				return err
+			}
+			return opts.print(cmd.OutOrStdout(), created, taskTable(created))
+		},
+	}
+	fields.register(cmd)
+	cmd.Flags().StringVar(&id, "id", "", "ID of the task (default a new object ID)")
+	cmd.MarkFlagRequired("title")
+	return cmd
+}
+
+func newTaskUpdateCommand(opts *options) *cobra.Command {
+	var fields taskFlags
+	cmd := &cobra.Command{
+		Use:               "update TASK_ID",
+		Short:             "Change the given fields of a task",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: opts.completeTaskIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			// The API replaces the whole task, so start from the stored one
+			task, err := c.Tasks().GetTasksById(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			if err := fields.apply(cmd, task); err != nil {
+				return err
+			}
+			if err := c.Tasks().UpdateTask(cmd.Context(), args[0], *task); err != nil {
+				return err
+			}
+			return opts.print(cmd.OutOrStdout(), task, taskTable(task))
+		},
+	}
+	fields.register(cmd)
+	return cmd
+}
+
+func newTaskDeleteCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:               "delete TASK_ID",
+		Aliases:           []string{"rm"},
+		Short:             "Delete a task",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: opts.completeTaskIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			deleted, err := c.Tasks().DeleteById(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			if deleted == 0 {
+				return fmt.Errorf("task %s not found", args[0])
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted task %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+// completeTaskIDs completes the IDs of the tasks, described by their titles.
+func (o *options) completeTaskIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	c, err := o.connect(cmd.Context())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	tasks, err := c.Tasks().GetTasks(cmd.Context())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	var ids []string
+	for _, task := range tasks {
+		ids = append(ids, task.ID+"\t"+task.Title)
+	}
+	return ids, cobra.ShellCompDirectiveNoFileComp
+}
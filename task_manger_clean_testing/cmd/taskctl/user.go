@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"task_manger_clean_architecture/domain"
+
+	"github.com/spf13/cobra"
+)
+
+func newUserCommand(opts *options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "user",
+		Aliases: []string{"users"},
+		Short:   "Manage the members of your workspace",
+	}
+	cmd.AddCommand(
+		newUserListCommand(opts),
+		newUserGetCommand(opts),
+		newUserPromoteCommand(opts),
+	)
+	return cmd
+}
+
+func userTable(users ...*domain.User) func() table {
+	return func() table {
+		t := table{header: []string{"ID", "NAME", "EMAIL", "ROLE"}}
+		for _, user := range users {
+			name := strings.TrimSpace(stringValue(user.FirstName) + " " + stringValue(user.LastName))
+			t.rows = append(t.rows, []string{user.UserId, name, stringValue(user.Email), stringValue(user.UserType)})
+		}
+		return t
+	}
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func newUserListCommand(opts *options) *cobra.Command {
+	var page, perPage int
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the members, for administrators",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if page < 1 || perPage < 1 {
+				return fmt.Errorf("--page and --per-page start at 1")
+			}
+			c, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			users, err := c.Users().GetUsers(cmd.Context(), int64((page-1)*perPage), int64(perPage))
+			if err != nil {
+				return err
+			}
+			return opts.print(cmd.OutOrStdout(), users, userTable(users...))
+		},
+	}
+	cmd.Flags().IntVar(&page, "page", 1, "page to list, from 1")
+	cmd.Flags().IntVar(&perPage, "per-page", 10, "members per page")
+	return cmd
+}
+
+func newUserGetCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get USER_ID",
+		Short: "Show a member",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			user, err := c.Users().GetUser(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return opts.print(cmd.OutOrStdout(), user, userTable(&user))
+		},
+	}
+}
+
+func newUserPromoteCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "promote USER_ID",
+		Short: "Make a member an administrator of the workspace",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := opts.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			err, matched, modified := c.Users().Promote(cmd.Context(), args[0], "ADMIN")
+			switch {
+			case err != nil:
+				return err
+			case matched == 0:
+				return fmt.Errorf("user %s not found", args[0])
+			case modified == 0:
+				return fmt.Errorf("%s is already an administrator", args[0])
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Promoted %s to administrator\n", args[0])
+			return nil
+		},
+	}
+}
@@ -0,0 +1,381 @@
+// Package config loads the settings of the server. Every setting has a
+// default, which a YAML or TOML file, the environment and the command line
+// override in that order.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Each setting is named by its config tag, which is also its key in the
+// file: the section and the name joined by a dot. The flag replaces dots and
+// underscores with dashes. Settings tagged secret are redacted when printed.
+type Config struct {
+	Server  ServerConfig
+	Mongo   MongoConfig
+	Auth    AuthConfig
+	Mail    MailConfig
+	OIDC    OIDCConfig
+	Log     LogConfig
+	Tracing TracingConfig
+
+	// PrintConfig is set by --print-config: print the configuration and exit.
+	PrintConfig bool
+}
+
+type ServerConfig struct {
+	Port           int           `config:"server.port" env:"PORT" validate:"min=1,max=65535" usage:"port to listen on"`
+	ContextTimeout time.Duration `config:"server.context_timeout" env:"CONTEXT_TIMEOUT" validate:"gt=0" usage:"time a request may take, shared by its database calls"`
+	// ShutdownTimeout is how long in-flight requests may take to finish
+	// once the server is asked to stop.
+	ShutdownTimeout time.Duration `config:"server.shutdown_timeout" env:"SHUTDOWN_TIMEOUT" validate:"gt=0" usage:"time to drain in-flight requests on shutdown"`
+	// BaseURL is where users reach the server, for the links in mails.
+	BaseURL string `config:"server.base_url" env:"APP_BASE_URL" validate:"required,url" usage:"public URL of the server"`
+	// GRPCPort serves the gRPC API next to the REST API unless it is 0.
+	GRPCPort int `config:"server.grpc_port" env:"GRPC_PORT" validate:"min=0,max=65535" usage:"port to serve the gRPC API on, 0 to disable it"`
+	// TrustedProxies may set X-Forwarded-For. The client address of a
+	// request, which the rate limits and the login throttle are keyed on, is
+	// its peer's unless the peer is one of them.
+	TrustedProxies []string `config:"server.trusted_proxies" env:"TRUSTED_PROXIES" validate:"dive,ip|cidr" usage:"addresses or CIDR ranges of the proxies in front of the server, separated by spaces or commas"`
+}
+
+type MongoConfig struct {
+	URL      string `config:"mongo.url" env:"MONGODB_URL" secret:"true" validate:"required" usage:"MongoDB connection string"`
+	Database string `config:"mongo.database" env:"DATABASE_NAME" validate:"required" usage:"MongoDB database name"`
+}
+
+type AuthConfig struct {
+	SecretKey             string `config:"auth.secret_key" env:"SECRET_KEY" secret:"true" validate:"required" usage:"key signing the JWTs"`
+	MinPasswordLength     int    `config:"auth.min_password_length" env:"MIN_PASSWORD_LENGTH" validate:"min=6" usage:"shortest password accepted"`
+	BreachedPasswordsFile string `config:"auth.breached_passwords_file" env:"BREACHED_PASSWORDS_FILE" usage:"extra breached passwords to refuse, one per line"`
+	TOTPIssuer            string `config:"auth.totp_issuer" env:"TOTP_ISSUER" validate:"required" usage:"name authenticator apps show next to the codes"`
+	// InviteOnly closes signup, so accounts are only created by accepting
+	// an invite.
+	InviteOnly bool `config:"auth.invite_only" env:"INVITE_ONLY" usage:"only create accounts through invites"`
+	// Operators run the deployment. Only they may read the audit log, which
+	// spans every workspace. No role in a workspace grants it.
+	Operators []string `config:"auth.operators" env:"OPERATORS" usage:"user IDs that may read the audit log of every workspace, separated by spaces or commas"`
+}
+
+type MailConfig struct {
+	// File receives the mail instead of the log when it is set.
+	File string `config:"mail.file" env:"MAIL_FILE" usage:"file to append mail to"`
+}
+
+// OIDCConfig enables login through an OpenID Connect provider when Issuer is
+// set.
+type OIDCConfig struct {
+	Issuer       string `config:"oidc.issuer" env:"OIDC_ISSUER" validate:"omitempty,url" usage:"OpenID Connect issuer URL"`
+	ClientID     string `config:"oidc.client_id" env:"OIDC_CLIENT_ID" validate:"required_with=Issuer" usage:"OpenID Connect client ID"`
+	ClientSecret string `config:"oidc.client_secret" env:"OIDC_CLIENT_SECRET" secret:"true" usage:"OpenID Connect client secret"`
+	// RedirectURL defaults to the callback under the server's base URL.
+	RedirectURL string   `config:"oidc.redirect_url" env:"OIDC_REDIRECT_URL" validate:"omitempty,url" usage:"OpenID Connect redirect URL"`
+	Scopes      []string `config:"oidc.scopes" env:"OIDC_SCOPES" usage:"OpenID Connect scopes, separated by spaces or commas"`
+}
+
+type LogConfig struct {
+	Level string `config:"log.level" env:"LOG_LEVEL" validate:"oneof=debug info warn error" usage:"least severe level logged: debug, info, warn or error"`
+}
+
+// TracingConfig selects where the OpenTelemetry spans go: an OTLP collector
+// over HTTP, standard output, or nowhere.
+type TracingConfig struct {
+	Exporter string `config:"tracing.exporter" env:"TRACING_EXPORTER" validate:"oneof=none stdout otlp" usage:"where spans are exported: none, stdout or otlp"`
+	// Endpoint defaults to the collector on localhost when it is empty.
+	Endpoint    string `config:"tracing.endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT" validate:"omitempty,url" usage:"OTLP/HTTP collector URL"`
+	ServiceName string `config:"tracing.service_name" env:"OTEL_SERVICE_NAME" validate:"required" usage:"service name on the spans"`
+}
+
+// SlogLevel returns Level for log/slog. Validate has checked it.
+func (l LogConfig) SlogLevel() slog.Level {
+	var level slog.Level
+	level.UnmarshalText([]byte(l.Level))
+	return level
+}
+
+// Default returns the configuration before any file, environment or flag.
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:            8080,
+			ContextTimeout:  30 * time.Second,
+			ShutdownTimeout: 15 * time.Second,
+			BaseURL:         "http://localhost:8080",
+			GRPCPort:        9090,
+		},
+		Auth: AuthConfig{
+			MinPasswordLength: 8,
+			TOTPIssuer:        "Task Manager",
+		},
+		OIDC: OIDCConfig{
+			Scopes: []string{"openid", "email", "profile"},
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			ServiceName: "task-manager",
+		},
+	}
+}
+
+// OIDCRedirectURL returns the redirect URL registered with the provider.
+func (c *Config) OIDCRedirectURL() string {
+	if c.OIDC.RedirectURL != "" {
+		return c.OIDC.RedirectURL
+	}
+	return strings.TrimRight(c.Server.BaseURL, "/") + "/login/oidc/callback"
+}
+
+// setting is a field of the configuration with its tags.
+type setting struct {
+	key   string
+	env   string
+	usage string
+	value reflect.Value
+	// secret settings are redacted when printed.
+	secret bool
+}
+
+// settings lists the settings of c in the order they are declared.
+func (c *Config) settings() []setting {
+	var settings []setting
+	sections := reflect.ValueOf(c).Elem()
+	for i := 0; i < sections.NumField(); i++ {
+		section := sections.Field(i)
+		if section.Kind() != reflect.Struct {
+			continue
+		}
+		for j := 0; j < section.NumField(); j++ {
+			field := section.Type().Field(j)
+			settings = append(settings, setting{
+				key:    field.Tag.Get("config"),
+				env:    field.Tag.Get("env"),
+				usage:  field.Tag.Get("usage"),
+				value:  section.Field(j),
+				secret: field.Tag.Get("secret") == "true",
+			})
+		}
+	}
+	return settings
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func (s setting) set(text string) error {
+	text = strings.TrimSpace(text)
+	switch {
+	case s.value.Type() == durationType:
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return fmt.Errorf("%s: %q is not a duration such as 30s", s.key, text)
+		}
+		s.value.SetInt(int64(d))
+	case s.value.Kind() == reflect.Int:
+		n, err := strconv.Atoi(text)
+		if err != nil {
+			return fmt.Errorf("%s: %q is not a whole number", s.key, text)
+		}
+		s.value.SetInt(int64(n))
+	case s.value.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return fmt.Errorf("%s: %q is not true or false", s.key, text)
+		}
+		s.value.SetBool(b)
+	case s.value.Kind() == reflect.Slice:
+		// An empty list is stored as none, like the defaults
+		words := strings.Fields(strings.ReplaceAll(text, ",", " "))
+		if len(words) == 0 {
+			words = nil
+		}
+		s.value.Set(reflect.ValueOf(words))
+	default:
+		s.value.SetString(text)
+	}
+	return nil
+}
+
+// Load returns the defaults overridden by the config file, the environment
+// and then args, the command line without the program name. The file is
+// named by --config or CONFIG_FILE. lookupEnv is os.LookupEnv outside tests.
+// Load does not validate the result, so an invalid configuration can still
+// be printed.
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, error) {
+	c := Default()
+	settings := c.settings()
+
+	type flagValue struct {
+		setting setting
+		text    string
+	}
+	var flags []flagValue
+	var file string
+	fs := flag.NewFlagSet("task-manager", flag.ContinueOnError)
+	fs.StringVar(&file, "config", "", "YAML or TOML `file` to read the configuration from")
+	fs.BoolVar(&c.PrintConfig, "print-config", false, "print the configuration with secrets redacted and exit")
+	for _, s := range settings {
+		s := s
+		name := strings.NewReplacer(".", "-", "_", "-").Replace(s.key)
+		collect := func(text string) error {
+			flags = append(flags, flagValue{s, text})
+			return nil
+		}
+		if s.value.Kind() == reflect.Bool {
+			fs.BoolFunc(name, s.usage, collect)
+		} else {
+			fs.Func(name, s.usage, collect)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	if file == "" {
+		file, _ = lookupEnv("CONFIG_FILE")
+	}
+	if file != "" {
+		if err := c.loadFile(file, settings); err != nil {
+			return nil, err
+		}
+	}
+	// Empty variables count as unset, like the blanks in .env
+	for _, s := range settings {
+		if text, ok := lookupEnv(s.env); ok && strings.TrimSpace(text) != "" {
+			if err := s.set(text); err != nil {
+				return nil, fmt.Errorf("%s: %w", s.env, err)
+			}
+		}
+	}
+	for _, f := range flags {
+		if err := f.setting.set(f.text); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *Config) loadFile(path string, settings []setting) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+	sections := map[string]map[string]any{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &sections)
+	case ".toml":
+		err = toml.Unmarshal(data, &sections)
+	default:
+		return fmt.Errorf("config file %s: use a .yaml, .yml or .toml file", path)
+	}
+	if err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	byKey := map[string]setting{}
+	for _, s := range settings {
+		byKey[s.key] = s
+	}
+	for section, values := range sections {
+		for name, value := range values {
+			key := section + "." + name
+			s, ok := byKey[key]
+			if !ok {
+				return fmt.Errorf("config file %s: unknown setting %s", path, key)
+			}
+			if list, ok := value.([]any); ok {
+				words := make([]string, len(list))
+				for i, word := range list {
+					words[i] = fmt.Sprint(word)
+				}
+				value = strings.Join(words, " ")
+			}
+			if err := s.set(fmt.Sprint(value)); err != nil {
+				return fmt.Errorf("config file %s: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+var validate = validator.New()
+
+func init() {
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return field.Tag.Get("config")
+	})
+}
+
+// Validate returns an error naming every invalid setting.
+func (c *Config) Validate() error {
+	err := validate.Struct(c)
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		return err
+	}
+	problems := make([]error, len(invalid))
+	for i, fe := range invalid {
+		problems[i] = fmt.Errorf("%s %s", fe.Field(), problem(fe))
+	}
+	return fmt.Errorf("invalid configuration:\n%w", errors.Join(problems...))
+}
+
+func problem(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "required_with":
+		return "is required when oidc.issuer is set"
+	case "url":
+		return "must be an absolute URL"
+	case "gt":
+		return "must be positive"
+	case "min":
+		return "must be at least " + fe.Param()
+	case "max":
+		return "must be at most " + fe.Param()
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	}
+	return "is invalid"
+}
+
+// Print writes the configuration as YAML, which Load reads back, with the
+// secrets redacted.
+func (c *Config) Print(w io.Writer) error {
+	sections := map[string]map[string]any{}
+	for _, s := range c.settings() {
+		section, name, _ := strings.Cut(s.key, ".")
+		if sections[section] == nil {
+			sections[section] = map[string]any{}
+		}
+		var value any = s.value.Interface()
+		switch {
+		case s.secret && !s.value.IsZero():
+			value = "REDACTED"
+		case s.value.Type() == durationType:
+			value = s.value.Interface().(time.Duration).String()
+		}
+		sections[section][name] = value
+	}
+	return yaml.NewEncoder(w).Encode(sections)
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"task_manger_clean_architecture/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccessTokenController struct {
+	AccessTokenUseCase domain.AccessTokenUsecase
+}
+
+func (ac *AccessTokenController) CreateToken() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        var request domain.NewAccessToken
+        if err := c.ShouldBindJSON(&request); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        token, err := ac.AccessTokenUseCase.Create(ctx, c.GetString("uid"), c.GetString("usertype"), request)
+        if err != nil {
+            switch {
+            case errors.Is(err, domain.ErrUnknownScope), errors.Is(err, domain.ErrExpiryInPast):
+                c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            case errors.Is(err, domain.ErrScopeNotAllowed), errors.Is(err, domain.ErrNoWorkspace):
+                c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+            default:
+                c.JSON(http.StatusInternalServerError, gin.H{"error": "error occured while creating the access token"})
+            }
+            return
+        }
+
+        // The token is never shown again
+        c.Header("Cache-Control", "no-store")
+        c.JSON(http.StatusCreated, token)
+    }
+}
+
+func (ac *AccessTokenController) GetTokens() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        tokens, err := ac.AccessTokenUseCase.List(ctx, c.GetString("uid"))
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "error occured while listing access tokens"})
+            return
+        }
+
+        c.JSON(http.StatusOK, tokens)
+    }
+}
+
+func (ac *AccessTokenController) RevokeToken() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        err := ac.AccessTokenUseCase.Revoke(ctx, c.GetString("uid"), c.Param("token_id"))
+        if errors.Is(err, domain.ErrAccessTokenNotFound) {
+            c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+            return
+        }
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "error occured while revoking the access token"})
+            return
+        }
+
+        c.JSON(http.StatusOK, gin.H{"message": "access token revoked"})
+    }
+}
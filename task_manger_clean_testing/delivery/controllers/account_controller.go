@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"task_manger_clean_architecture/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccountController struct {
+	AccountUseCase domain.AccountUsecase
+	PasswordHasher domain.PasswordHasher
+	PasswordPolicy domain.PasswordPolicy
+}
+
+type emailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type resetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+func (ac *AccountController) VerifyEmail() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        token := c.Query("token")
+        if token == "" {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "token is required"})
+            return
+        }
+
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        if err := ac.AccountUseCase.VerifyEmail(ctx, token); err != nil {
+            if errors.Is(err, domain.ErrInvalidUserToken) {
+                c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+                return
+            }
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify email"})
+            return
+        }
+
+        // Tokens issued before verification do not carry the flag yet
+        c.JSON(http.StatusOK, gin.H{"message": "email verified, please log in again"})
+    }
+}
+
+func (ac *AccountController) ResendVerification() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        var request emailRequest
+        if err := c.ShouldBindJSON(&request); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        if err := ac.AccountUseCase.ResendVerification(ctx, request.Email); err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send verification mail"})
+            return
+        }
+
+        c.JSON(http.StatusAccepted, gin.H{"message": "if the account exists and is not verified, a new link has been sent"})
+    }
+}
+
+func (ac *AccountController) ForgotPassword() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        var request emailRequest
+        if err := c.ShouldBindJSON(&request); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        if err := ac.AccountUseCase.ForgotPassword(ctx, request.Email); err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to send password reset mail"})
+            return
+        }
+
+        c.JSON(http.StatusAccepted, gin.H{"message": "if the account exists, a password reset link has been sent"})
+    }
+}
+
+func (ac *AccountController) ResetPassword() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        var request resetPasswordRequest
+        if err := c.ShouldBindJSON(&request); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        if err := ac.PasswordPolicy.Validate(request.Password); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+
+        password, err := ac.PasswordHasher.Hash(request.Password)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to hash password"})
+            return
+        }
+        if err := ac.AccountUseCase.ResetPassword(ctx, request.Token, password); err != nil {
+            if errors.Is(err, domain.ErrInvalidUserToken) {
+                c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+                return
+            }
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reset password"})
+            return
+        }
+
+        c.JSON(http.StatusOK, gin.H{"message": "password updated, please log in"})
+    }
+}
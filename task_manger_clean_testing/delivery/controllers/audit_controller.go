@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditController struct {
+	AuditUseCase domain.AuditUsecase
+	// Operators are the user IDs of auth.operators.
+	Operators []string
+}
+
+// parseAuditTime reads an optional RFC 3339 timestamp query parameter.
+func parseAuditTime(c *gin.Context, name string) (time.Time, error) {
+    value := c.Query(name)
+    if value == "" {
+        return time.Time{}, nil
+    }
+    return time.Parse(time.RFC3339, value)
+}
+
+// requireOperator limits the audit log, which spans every workspace, to the
+// operators: the people who run the deployment. They are configured on the
+// server, as any role in a workspace can be had by joining it.
+func (ac *AuditController) requireOperator(c *gin.Context) error {
+    uid := c.GetString("uid")
+    for _, operator := range ac.Operators {
+        if uid != "" && uid == operator {
+            return nil
+        }
+    }
+    return errors.New("the audit log is only available to operators")
+}
+
+func (ac *AuditController) GetAuditLog() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if err := ac.requireOperator(c); err != nil {
+            c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+            return
+        }
+
+        recordsPerPage, err := strconv.Atoi(c.Query("recordPerPage"))
+        if err != nil || recordsPerPage < 1 {
+            recordsPerPage = 50
+        }
+        if recordsPerPage > 500 {
+            recordsPerPage = 500
+        }
+
+        page, err := strconv.Atoi(c.Query("page"))
+        if err != nil || page < 1 {
+            page = 1
+        }
+
+        from, err := parseAuditTime(c, "from")
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "from must be an RFC 3339 timestamp"})
+            return
+        }
+        to, err := parseAuditTime(c, "to")
+        if err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "to must be an RFC 3339 timestamp"})
+            return
+        }
+
+        filter := domain.AuditFilter{
+            ActorID: c.Query("actor"),
+            Action:  c.Query("action"),
+            Target:  c.Query("target"),
+            From:    from,
+            To:      to,
+            Page:    int64(page - 1),
+            PerPage: int64(recordsPerPage),
+        }
+
+        var ctx, cancel = requestContext(c)
+        defer cancel()
+
+        entries, total, err := ac.AuditUseCase.Query(ctx, filter)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "error occurred while listing audit entries"})
+            return
+        }
+
+        c.JSON(http.StatusOK, gin.H{
+            "total":         total,
+            "page":          page,
+            "recordPerPage": recordsPerPage,
+            "entries":       entries,
+        })
+    }
+}
+
+func (ac *AuditController) VerifyAuditLog() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if err := ac.requireOperator(c); err != nil {
+            c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+            return
+        }
+
+        var ctx, cancel = requestContext(c)
+        defer cancel()
+
+        result, err := ac.AuditUseCase.Verify(ctx)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "error occurred while verifying the audit log"})
+            return
+        }
+
+        c.JSON(http.StatusOK, result)
+    }
+}
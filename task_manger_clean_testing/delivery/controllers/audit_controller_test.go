@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditControllerTestSuite struct {
+	suite.Suite
+	router           *gin.Engine
+	mockAuditUseCase *mocks.AuditUsecase
+}
+
+func (suite *AuditControllerTestSuite) SetupTest() {
+	suite.router = gin.Default()
+	suite.mockAuditUseCase = new(mocks.AuditUsecase)
+	ac := &AuditController{AuditUseCase: suite.mockAuditUseCase, Operators: []string{"operator1"}}
+
+	suite.router.GET("/audit", authenticate(), ac.GetAuditLog())
+	suite.router.GET("/audit/verify", authenticate(), ac.VerifyAuditLog())
+}
+
+func (suite *AuditControllerTestSuite) get(path string, uid string, userType string) *httptest.ResponseRecorder {
+	claims := &SignedDetails{
+		Email:    "caller@example.com",
+		UserType: userType,
+		Uid:      uid,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(SECRET_KEY))
+	suite.Require().NoError(err)
+
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	suite.Require().NoError(err)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	recorder := httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func (suite *AuditControllerTestSuite) TestAdministratorsAreNotOperators() {
+	// Anyone can be an administrator of a workspace by creating one
+	suite.Equal(http.StatusForbidden, suite.get("/audit", "admin1", "ADMIN").Code)
+	suite.Equal(http.StatusForbidden, suite.get("/audit/verify", "admin1", "ADMIN").Code)
+	suite.mockAuditUseCase.AssertNotCalled(suite.T(), "Query", mock.Anything, mock.Anything)
+	suite.mockAuditUseCase.AssertNotCalled(suite.T(), "Verify", mock.Anything)
+}
+
+func (suite *AuditControllerTestSuite) TestOperatorsReadTheAuditLog() {
+	suite.mockAuditUseCase.On("Query", mock.Anything, mock.AnythingOfType("domain.AuditFilter")).Return([]*domain.AuditEntry{}, int64(0), nil)
+	suite.mockAuditUseCase.On("Verify", mock.Anything).Return(&domain.AuditVerification{}, nil)
+
+	suite.Equal(http.StatusOK, suite.get("/audit", "operator1", "USER").Code)
+	suite.Equal(http.StatusOK, suite.get("/audit/verify", "operator1", "USER").Code)
+}
+
+func TestAuditControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditControllerTestSuite))
+}
@@ -0,0 +1,23 @@
+package controllers
+
+import (
+	"context"
+	"task_manger_clean_architecture/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestContext returns the context a handler passes to the use cases. It
+// derives from the request's, so it carries the deadline of the route, the
+// request ID and the trace, and it is cancelled when the client goes away. It
+// adds the audit actor of the request so writes can be attributed, and the
+// workspace its token acts in, which scopes tenant data.
+func requestContext(c *gin.Context) (context.Context, context.CancelFunc) {
+	actor := domain.AuditActor{
+		UID:       c.GetString("uid"),
+		ClientIP:  c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	ctx := domain.WithWorkspace(domain.WithAuditActor(c.Request.Context(), actor), c.GetString("workspace"))
+	return context.WithCancel(ctx)
+}
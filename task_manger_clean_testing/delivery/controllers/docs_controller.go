@@ -0,0 +1,243 @@
+package controllers
+
+import (
+	"net/http"
+	"task_manger_clean_architecture/delivery/docs"
+	"task_manger_clean_architecture/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DocsController serves the OpenAPI description of the API and a page to
+// browse it.
+type DocsController struct {
+	// Spec is the OpenAPI document as JSON, built once by APISpec.
+	Spec []byte
+}
+
+func message(description string) *docs.Schema {
+	return docs.Object(map[string]*docs.Schema{"message": docs.String(description)})
+}
+
+var paging = []docs.Query{
+	{Name: "page", Description: "page to return, from 1", Type: "integer"},
+	{Name: "recordPerPage", Description: "entries per page", Type: "integer"},
+}
+
+// APISpec returns the OpenAPI 3.1 document of every route Setup registers.
+// A route added there must be added here too, or the routers test fails.
+func APISpec() *docs.Spec {
+	spec := docs.New(docs.Info{
+		Title:       "Task Manager API",
+		Version:     "1.0.0",
+		Description: "Tasks, users and workspaces. Errors are answered with an Error body and the request's X-Request-ID.",
+	}, "A JWT from /login, or a personal access token starting with "+domain.AccessTokenPrefix+" limited to its scopes.")
+
+	tokens := docs.Object(map[string]*docs.Schema{
+		"message":      docs.String(""),
+		"token":        docs.String("new access JWT"),
+		"refreshtoken": docs.String("new refresh JWT"),
+	})
+	authenticated := spec.SchemaOf(AuthenticatedUserResponse{})
+	newUser := spec.Without(domain.User{}, "token", "refreshtoken", "createdat", "updatedat", "userid", "email_verified", "usertype")
+
+	for _, route := range []docs.Route{
+		// Operations
+		{Method: "GET", Path: "/healthz", OperationID: "liveness", Summary: "Report that the process is up", Tag: "operations", Public: true,
+			Responses: map[int]any{http.StatusOK: docs.Object(map[string]*docs.Schema{"status": docs.String("ok")})}},
+		{Method: "GET", Path: "/readyz", OperationID: "readiness", Summary: "Report whether the dependencies are reachable", Tag: "operations", Public: true,
+			Responses: map[int]any{
+				http.StatusOK:                 docs.Object(map[string]*docs.Schema{"status": docs.String("ok"), "checks": {Type: "object", AdditionalProperties: docs.String("ok or the error")}}),
+				http.StatusServiceUnavailable: docs.Object(map[string]*docs.Schema{"status": docs.String("unavailable"), "checks": {Type: "object", AdditionalProperties: docs.String("ok or the error")}}),
+			}},
+		{Method: "GET", Path: "/metrics", OperationID: "metrics", Summary: "Prometheus metrics", Tag: "operations", Public: true,
+			Responses: map[int]any{http.StatusOK: docs.Text("text/plain")}},
+		{Method: "GET", Path: "/openapi.json", OperationID: "openAPI", Summary: "This document", Tag: "operations", Public: true,
+			Responses: map[int]any{http.StatusOK: &docs.Schema{Type: "object"}}},
+		{Method: "GET", Path: "/docs", OperationID: "docs", Summary: "Browse this document", Tag: "operations", Public: true,
+			Responses: map[int]any{http.StatusOK: docs.Text("text/html")}},
+
+		// Accounts
+		{Method: "POST", Path: "/signup", OperationID: "signup", Summary: "Create an account", Tag: "accounts", Public: true,
+			Description: "Not registered when signup is invite-only. A verification link is mailed to the address. The account is a USER in no workspace until it creates one or accepts an invite.",
+			Body:        newUser,
+			Responses:   map[int]any{http.StatusOK: docs.Object(map[string]*docs.Schema{"insertionnumber": docs.String("ID of the new document")})}},
+		{Method: "POST", Path: "/login", OperationID: "login", Summary: "Sign in with email and password", Tag: "accounts", Public: true,
+			Body: docs.Object(map[string]*docs.Schema{"email": docs.String(""), "password": docs.String("")}),
+			Responses: map[int]any{http.StatusOK: &docs.Schema{
+				Description: "The user with tokens, or a challenge for /login/2fa when the account has two-factor authentication",
+				Type:        "object",
+				Properties: map[string]*docs.Schema{
+					"two_factor_required": docs.Boolean(""),
+					"challenge":           docs.String("to redeem at /login/2fa"),
+				},
+			}}},
+		{Method: "POST", Path: "/login/2fa", OperationID: "loginTwoFactor", Summary: "Finish a sign in with a one-time or recovery code", Tag: "accounts", Public: true,
+			Body: loginTwoFactorRequest{}, Responses: map[int]any{http.StatusOK: authenticated}},
+		{Method: "POST", Path: "/login/refresh", OperationID: "refreshTokens", Summary: "Trade a refresh token for new tokens", Tag: "accounts", Public: true,
+			Description: "Each refresh token works once: the answer carries the next one.",
+			Body:        refreshRequest{}, Responses: map[int]any{http.StatusOK: authenticated}},
+		{Method: "GET", Path: "/login/oidc", OperationID: "loginOIDC", Summary: "Sign in through the identity provider", Tag: "accounts", Public: true,
+			Description: "Only registered when an OpenID Connect provider is configured.",
+			Responses:   map[int]any{http.StatusFound: nil}},
+		{Method: "GET", Path: "/login/oidc/callback", OperationID: "loginOIDCCallback", Summary: "Return from the identity provider", Tag: "accounts", Public: true,
+			Query:     []docs.Query{{Name: "state"}, {Name: "code"}, {Name: "error"}},
+			Responses: map[int]any{http.StatusOK: authenticated}},
+		{Method: "GET", Path: "/verify", OperationID: "verifyEmail", Summary: "Verify an email address", Tag: "accounts", Public: true,
+			Query:     []docs.Query{{Name: "token", Description: "from the verification mail", Required: true}},
+			Responses: map[int]any{http.StatusOK: message("")}},
+		{Method: "POST", Path: "/verify/resend", OperationID: "resendVerification", Summary: "Mail a new verification link", Tag: "accounts", Public: true,
+			Body: emailRequest{}, Responses: map[int]any{http.StatusAccepted: message("")}},
+		{Method: "POST", Path: "/password/forgot", OperationID: "forgotPassword", Summary: "Mail a password reset link", Tag: "accounts", Public: true,
+			Body: emailRequest{}, Responses: map[int]any{http.StatusAccepted: message("")}},
+		{Method: "POST", Path: "/password/reset", OperationID: "resetPassword", Summary: "Set a new password with a reset token", Tag: "accounts", Public: true,
+			Body: resetPasswordRequest{}, Responses: map[int]any{http.StatusOK: message("")}},
+		{Method: "POST", Path: "/users/me/2fa/enroll", OperationID: "enrollTwoFactor", Summary: "Start setting up two-factor authentication", Tag: "accounts",
+			Responses: map[int]any{http.StatusOK: domain.TwoFactorEnrollment{}}},
+		{Method: "POST", Path: "/users/me/2fa/confirm", OperationID: "confirmTwoFactor", Summary: "Turn on two-factor authentication", Tag: "accounts",
+			Body: twoFactorCodeRequest{}, Responses: map[int]any{http.StatusOK: message("")}},
+		{Method: "POST", Path: "/users/me/2fa/disable", OperationID: "disableTwoFactor", Summary: "Turn off two-factor authentication", Tag: "accounts",
+			Body: twoFactorCodeRequest{}, Responses: map[int]any{http.StatusOK: message("")}},
+		{Method: "PATCH", Path: "/users/me", OperationID: "updateProfile", Summary: "Change your profile", Tag: "accounts",
+			Description: "Changing the email revokes your tokens and answers with new ones.",
+			Body:        domain.ProfileUpdate{}, Responses: map[int]any{http.StatusOK: authenticated}},
+		{Method: "POST", Path: "/users/me/password", OperationID: "changePassword", Summary: "Change your password", Tag: "accounts",
+			Description: "A wrong current password counts as a failed login of the account, so it is throttled and locked out the same way.",
+			Body: changePasswordRequest{}, Responses: map[int]any{http.StatusOK: tokens}},
+		{Method: "POST", Path: "/users/me/tokens", OperationID: "createAccessToken", Summary: "Create a personal access token", Tag: "accounts",
+			Description: "The token acts in the workspace of your session, so you must be in one.",
+			Body: domain.NewAccessToken{}, Responses: map[int]any{http.StatusCreated: domain.CreatedAccessToken{}}},
+		{Method: "GET", Path: "/users/me/tokens", OperationID: "listAccessTokens", Summary: "List your personal access tokens", Tag: "accounts",
+			Responses: map[int]any{http.StatusOK: []domain.AccessToken{}}},
+		{Method: "DELETE", Path: "/users/me/tokens/:token_id", OperationID: "revokeAccessToken", Summary: "Revoke a personal access token", Tag: "accounts",
+			Responses: map[int]any{http.StatusOK: message("")}},
+
+		// Workspaces
+		{Method: "GET", Path: "/workspaces", OperationID: "listWorkspaces", Summary: "List your workspaces", Tag: "workspaces",
+			Responses: map[int]any{http.StatusOK: docs.Object(map[string]*docs.Schema{
+				"active":     docs.String("workspace the token acts in"),
+				"workspaces": docs.ArrayOf(spec.SchemaOf(domain.MemberWorkspace{})),
+			})}},
+		{Method: "POST", Path: "/workspaces", OperationID: "createWorkspace", Summary: "Create a workspace you administer", Tag: "workspaces",
+			Body: createWorkspaceRequest{}, Responses: map[int]any{http.StatusCreated: domain.Workspace{}}},
+		{Method: "POST", Path: "/workspaces/:workspace_id/switch", OperationID: "switchWorkspace", Summary: "Get tokens acting in another of your workspaces", Tag: "workspaces",
+			Responses: map[int]any{http.StatusOK: authenticated}},
+		{Method: "GET", Path: "/invites", OperationID: "listInvites", Summary: "List the pending invites of the workspace", Tag: "workspaces",
+			Responses: map[int]any{http.StatusOK: []domain.Invite{}}},
+		{Method: "POST", Path: "/invites", OperationID: "createInvite", Summary: "Invite someone to the workspace", Tag: "workspaces",
+			Body: domain.NewInvite{}, Responses: map[int]any{http.StatusCreated: domain.Invite{}}},
+		{Method: "DELETE", Path: "/invites/pending/:invite_id", OperationID: "revokeInvite", Summary: "Revoke a pending invite", Tag: "workspaces",
+			Responses: map[int]any{http.StatusNoContent: nil}},
+		{Method: "POST", Path: "/invites/pending/:invite_id/resend", OperationID: "resendInvite", Summary: "Mail a pending invite again with a new token", Tag: "workspaces",
+			Responses: map[int]any{http.StatusOK: domain.Invite{}}},
+		{Method: "POST", Path: "/invites/:token/accept", OperationID: "acceptInvite", Summary: "Join a workspace with an invite", Tag: "workspaces", Public: true,
+			Description:  "Without an account for the invited address, the body creates one like /signup. The email and role come from the invite.",
+			Body:         spec.Without(domain.User{}, "token", "refreshtoken", "createdat", "updatedat", "userid", "email_verified", "email", "usertype"),
+			BodyOptional: true,
+			Responses: map[int]any{http.StatusOK: docs.Object(map[string]*docs.Schema{
+				"workspace_id": docs.String(""),
+				"user":         spec.SchemaOf(SelfUserResponse{}),
+			})}},
+
+		// Users
+		{Method: "GET", Path: "/users", OperationID: "listUsers", Summary: "List the members of the workspace", Tag: "users",
+			Query: paging, Responses: map[int]any{http.StatusOK: []AdminUserResponse{}}},
+		{Method: "GET", Path: "/users/:user_id", OperationID: "getUser", Summary: "Get a member of the workspace", Tag: "users",
+			Description: "Administrators and the user themselves see the whole account, other members its PublicUserResponse fields.",
+			Responses:   map[int]any{http.StatusOK: AdminUserResponse{}}},
+		{Method: "POST", Path: "/promote/:user_id", OperationID: "promoteUser", Summary: "Make a member an administrator of the workspace", Tag: "users",
+			Responses: map[int]any{http.StatusOK: message("")}},
+		{Method: "POST", Path: "/unlock/:user_id", OperationID: "unlockUser", Summary: "Lift a login lockout", Tag: "users",
+			Responses: map[int]any{http.StatusOK: message("")}},
+
+		// Tasks
+		{Method: "GET", Path: "/task", OperationID: "listTasks", Summary: "List the tasks of the workspace", Tag: "tasks",
+			Responses: map[int]any{http.StatusOK: []domain.Task{}}},
+		{Method: "POST", Path: "/task", OperationID: "createTask", Summary: "Create a task", Tag: "tasks",
+			Body: domain.Task{},
+			Responses: map[int]any{http.StatusOK: docs.Object(map[string]*docs.Schema{
+				"message": docs.String(""),
+				"task":    spec.SchemaOf(domain.Task{}),
+			})}},
+		{Method: "GET", Path: "/task/search", OperationID: "searchTasks", Summary: "Search the tasks of the workspace", Tag: "tasks",
+			Query: []docs.Query{
+				{Name: "q", Description: `terms, "quoted phrases" and prefix* terms`, Required: true},
+				{Name: "limit", Description: "most results to return", Type: "integer"},
+			},
+			Responses: map[int]any{http.StatusOK: []domain.TaskSearchResult{}}},
+		{Method: "POST", Path: "/task/search/reindex", OperationID: "reindexTasks", Summary: "Rebuild the search index of the workspace", Tag: "tasks",
+			Responses: map[int]any{http.StatusOK: docs.Object(map[string]*docs.Schema{"message": docs.String(""), "indexed": docs.Integer("tasks indexed")})}},
+		{Method: "GET", Path: "/task/:task_id", OperationID: "getTask", Summary: "Get a task", Tag: "tasks",
+			Responses: map[int]any{http.StatusOK: domain.Task{}}},
+		{Method: "PUT", Path: "/task/:task_id", OperationID: "updateTask", Summary: "Replace a task", Tag: "tasks",
+			Body: domain.Task{}, Responses: map[int]any{http.StatusOK: message("")}},
+		{Method: "DELETE", Path: "/task/:task_id", OperationID: "deleteTask", Summary: "Delete a task", Tag: "tasks",
+			Responses: map[int]any{http.StatusOK: message("")}},
+		{Method: "GET", Path: "/reports/tasks", OperationID: "taskReport", Summary: "Report on the tasks of the workspace", Tag: "tasks",
+			Query: []docs.Query{{Name: "format", Description: "csv for a CSV file instead of JSON"}},
+			Responses: map[int]any{http.StatusOK: domain.TaskReport{}}},
+
+		// Audit
+		{Method: "GET", Path: "/audit", OperationID: "listAuditEntries", Summary: "Search the audit log", Tag: "audit",
+			Description: "Only for the operators listed in auth.operators.",
+			Query: append([]docs.Query{
+				{Name: "actor", Description: "uid of the actor"},
+				{Name: "action"},
+				{Name: "target"},
+				{Name: "from", Description: "RFC 3339 timestamp"},
+				{Name: "to", Description: "RFC 3339 timestamp"},
+			}, paging...),
+			Responses: map[int]any{http.StatusOK: docs.Object(map[string]*docs.Schema{
+				"total":         docs.Integer("matching entries"),
+				"page":          docs.Integer(""),
+				"recordPerPage": docs.Integer(""),
+				"entries":       docs.ArrayOf(spec.SchemaOf(domain.AuditEntry{})),
+			})}},
+		{Method: "GET", Path: "/audit/verify", OperationID: "verifyAuditLog", Summary: "Check the hash chain of the audit log", Tag: "audit",
+			Description: "Only for the operators listed in auth.operators.",
+			Responses: map[int]any{http.StatusOK: domain.AuditVerification{}}},
+
+		// GraphQL
+		{Method: "POST", Path: "/graphql", OperationID: "graphql", Summary: "Run a GraphQL query or mutation", Tag: "graphql",
+			Description: "The schema is in delivery/graph/schema.graphqls and can be introspected. Needs a password session: personal access tokens are refused.",
+			Body: &docs.Schema{Type: "object", Required: []string{"query"}, Properties: map[string]*docs.Schema{
+				"query":         docs.String("the GraphQL document"),
+				"operationName": docs.String("the operation to run when the document has several"),
+				"variables":     {Type: "object"},
+			}},
+			Responses: map[int]any{http.StatusOK: &docs.Schema{Type: "object", Properties: map[string]*docs.Schema{
+				"data":   {Type: "object", Description: "null when the request was rejected before it ran"},
+				"errors": docs.ArrayOf(docs.Object(map[string]*docs.Schema{"message": docs.String("")})),
+			}}}},
+	} {
+		spec.Add(route)
+	}
+	return spec
+}
+
+func (dc *DocsController) OpenAPI() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        c.Data(http.StatusOK, "application/json", dc.Spec)
+    }
+}
+
+// docsPage renders /openapi.json with Redoc, loaded from its CDN.
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Task Manager API</title>
+</head>
+<body>
+<redoc spec-url="/openapi.json"></redoc>
+<script src="https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js"></script>
+</body>
+</html>
+`
+
+func (dc *DocsController) Docs() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+    }
+}
@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"net/http"
+	"task_manger_clean_architecture/delivery/graph"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GraphQLController struct {
+	// Handler is the GraphQL API, from graph.NewHandler.
+	Handler http.Handler
+}
+
+// Query serves a GraphQL request as the signed in user, with their role in
+// the workspace of their token, so the resolvers apply the rules of the REST
+// routes.
+func (gc *GraphQLController) Query() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        ctx = graph.WithCaller(ctx, graph.Caller{
+            UID:       c.GetString("uid"),
+            Role:      c.GetString("usertype"),
+            Workspace: c.GetString("workspace"),
+        })
+        gc.Handler.ServeHTTP(c.Writer, c.Request.WithContext(ctx))
+    }
+}
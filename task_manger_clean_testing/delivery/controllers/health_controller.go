@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// healthCheckTimeout bounds each backend check, so a hung backend fails the
+// probe instead of outliving it.
+const healthCheckTimeout = 2 * time.Second
+
+type HealthController struct {
+	HealthCheckers []domain.HealthChecker
+}
+
+// Liveness answers as long as the process serves requests at all.
+func (hc *HealthController) Liveness() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        c.JSON(http.StatusOK, gin.H{"status": "ok"})
+    }
+}
+
+// Readiness checks every backend and answers 503 unless all of them are
+// reachable.
+func (hc *HealthController) Readiness() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        status := http.StatusOK
+        checks := gin.H{}
+        for _, checker := range hc.HealthCheckers {
+            ctx, cancel := context.WithTimeout(c.Request.Context(), healthCheckTimeout)
+            err := checker.Check(ctx)
+            cancel()
+            if err != nil {
+                status = http.StatusServiceUnavailable
+                checks[checker.Name()] = err.Error()
+                continue
+            }
+            checks[checker.Name()] = "ok"
+        }
+
+        if status != http.StatusOK {
+            c.JSON(status, gin.H{"status": "unavailable", "checks": checks})
+            return
+        }
+        c.JSON(status, gin.H{"status": "ok", "checks": checks})
+    }
+}
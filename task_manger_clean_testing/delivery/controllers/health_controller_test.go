@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type HealthControllerTestSuite struct {
+	suite.Suite
+	router     *gin.Engine
+	mockMongo  *mocks.HealthChecker
+	mockSearch *mocks.HealthChecker
+}
+
+func (suite *HealthControllerTestSuite) SetupTest() {
+	suite.router = gin.Default()
+	suite.mockMongo = new(mocks.HealthChecker)
+	suite.mockMongo.On("Name").Return("mongo")
+	suite.mockSearch = new(mocks.HealthChecker)
+	suite.mockSearch.On("Name").Return("search")
+	hc := &HealthController{HealthCheckers: []domain.HealthChecker{suite.mockMongo, suite.mockSearch}}
+	suite.router.GET("/healthz", hc.Liveness())
+	suite.router.GET("/readyz", hc.Readiness())
+}
+
+func (suite *HealthControllerTestSuite) get(path string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	resp := httptest.NewRecorder()
+	suite.router.ServeHTTP(resp, req)
+	var body map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(resp.Body.Bytes(), &body))
+	return resp, body
+}
+
+func (suite *HealthControllerTestSuite) TestLivenessChecksNoBackend() {
+	resp, body := suite.get("/healthz")
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal("ok", body["status"])
+	suite.mockMongo.AssertNotCalled(suite.T(), "Check", mock.Anything)
+}
+
+func (suite *HealthControllerTestSuite) TestReadiness() {
+	suite.mockMongo.On("Check", mock.Anything).Return(nil)
+	suite.mockSearch.On("Check", mock.Anything).Return(nil)
+
+	resp, body := suite.get("/readyz")
+	suite.Equal(http.StatusOK, resp.Code)
+	suite.Equal("ok", body["status"])
+	suite.Equal(map[string]interface{}{"mongo": "ok", "search": "ok"}, body["checks"])
+}
+
+func (suite *HealthControllerTestSuite) TestReadinessFailsWithAnyBackend() {
+	suite.mockMongo.On("Check", mock.Anything).Return(errors.New("server selection timeout"))
+	suite.mockSearch.On("Check", mock.Anything).Return(nil)
+
+	resp, body := suite.get("/readyz")
+	suite.Equal(http.StatusServiceUnavailable, resp.Code)
+	suite.Equal("unavailable", body["status"])
+	suite.Equal(map[string]interface{}{"mongo": "server selection timeout", "search": "ok"}, body["checks"])
+}
+
+func TestHealthControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthControllerTestSuite))
+}
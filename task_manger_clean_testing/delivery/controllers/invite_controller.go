@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/infrastructure"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InviteController struct {
+	InviteUseCase  domain.InviteUsecase
+	PasswordHasher domain.PasswordHasher
+	PasswordPolicy domain.PasswordPolicy
+}
+
+// inviteError answers with the status of the invite errors and reports
+// whether err was one of them.
+func inviteError(c *gin.Context, err error) bool {
+    switch {
+    case errors.Is(err, domain.ErrInvalidInvite), errors.Is(err, domain.ErrInviteNotFound):
+        c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+    case errors.Is(err, domain.ErrInviteNeedsAccount):
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+    case errors.Is(err, domain.ErrAlreadyMember), errors.Is(err, domain.ErrInviteUnverifiedAccount):
+        c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+    default:
+        return false
+    }
+    return true
+}
+
+func (ic *InviteController) CreateInvite() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if err := infrastructure.CheckUserType(c, "ADMIN"); err != nil {
+            c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+            return
+        }
+
+        var request domain.NewInvite
+        if err := c.ShouldBindJSON(&request); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        invite, err := ic.InviteUseCase.Invite(ctx, c.GetString("uid"), request)
+        if err != nil {
+            if inviteError(c, err) {
+                return
+            }
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "error occured while creating the invite"})
+            return
+        }
+
+        c.JSON(http.StatusCreated, invite)
+    }
+}
+
+func (ic *InviteController) GetInvites() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if err := infrastructure.CheckUserType(c, "ADMIN"); err != nil {
+            c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+            return
+        }
+
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        invites, err := ic.InviteUseCase.List(ctx)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "error occured while listing invites"})
+            return
+        }
+
+        c.JSON(http.StatusOK, invites)
+    }
+}
+
+func (ic *InviteController) RevokeInvite() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if err := infrastructure.CheckUserType(c, "ADMIN"); err != nil {
+            c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+            return
+        }
+
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        if err := ic.InviteUseCase.Revoke(ctx, c.Param("invite_id")); err != nil {
+            if inviteError(c, err) {
+                return
+            }
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "error occured while revoking the invite"})
+            return
+        }
+
+        c.Status(http.StatusNoContent)
+    }
+}
+
+func (ic *InviteController) ResendInvite() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if err := infrastructure.CheckUserType(c, "ADMIN"); err != nil {
+            c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+            return
+        }
+
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        invite, err := ic.InviteUseCase.Resend(ctx, c.Param("invite_id"))
+        if err != nil {
+            if inviteError(c, err) {
+                return
+            }
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "error occured while resending the invite"})
+            return
+        }
+
+        c.JSON(http.StatusOK, invite)
+    }
+}
+
+// AcceptInvite adds the holder of an invite token to its workspace. Without
+// an account for the invited address, the body carries the same fields as
+// /signup; the email and role come from the invite.
+func (ic *InviteController) AcceptInvite() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        token := c.Param("token")
+        invite, err := ic.InviteUseCase.Lookup(ctx, token)
+        if err != nil {
+            if inviteError(c, err) {
+                return
+            }
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "error occured while accepting the invite"})
+            return
+        }
+
+        var newUser *domain.User
+        var user domain.User
+        if err := c.ShouldBindJSON(&user); err != nil && !errors.Is(err, io.EOF) {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        } else if err == nil {
+            email := invite.Email
+            userType := "USER"
+            user.Email = &email
+            user.UserType = &userType
+            if err := validate.Struct(user); err != nil {
+                c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+                return
+            }
+            if status, err := hashNewPassword(ic.PasswordPolicy, ic.PasswordHasher, &user); err != nil {
+                c.JSON(status, gin.H{"error": err.Error()})
+                return
+            }
+            newUser = &user
+        }
+
+        member, err := ic.InviteUseCase.Accept(ctx, token, newUser)
+        if err != nil {
+            if inviteError(c, err) {
+                return
+            }
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "error occured while accepting the invite"})
+            return
+        }
+
+        c.JSON(http.StatusOK, gin.H{"workspace_id": invite.WorkspaceID, "user": NewSelfUserResponse(member)})
+    }
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/infrastructure"
+
+	"github.com/gin-gonic/gin"
+)
+
+// oidcStateCookie binds a login to the browser that started it, so nobody
+// can finish their own login in someone else's browser.
+const (
+	oidcStateCookie = "oidc_state"
+	oidcCookiePath  = "/login/oidc"
+)
+
+// secureRequest reports whether the client reached us over HTTPS, directly
+// or through a proxy.
+func secureRequest(c *gin.Context) bool {
+	return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+}
+
+func (uc *UserController) OIDCLogin() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        state, redirect, err := uc.OIDCUseCase.Begin(ctx)
+        if err != nil {
+            c.JSON(http.StatusBadGateway, gin.H{"error": "failed to reach the identity provider"})
+            return
+        }
+
+        // Lax, because the provider sends the browser back with a top-level GET
+        c.SetSameSite(http.SameSiteLaxMode)
+        c.SetCookie(oidcStateCookie, state, 600, oidcCookiePath, "", secureRequest(c), true)
+        c.Redirect(http.StatusFound, redirect)
+    }
+}
+
+func (uc *UserController) OIDCCallback() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        cookie, _ := c.Cookie(oidcStateCookie)
+        c.SetSameSite(http.SameSiteLaxMode)
+        c.SetCookie(oidcStateCookie, "", -1, oidcCookiePath, "", secureRequest(c), true)
+
+        if providerError := c.Query("error"); providerError != "" {
+            uc.Metrics.ObserveLogin(infrastructure.LoginFailure)
+            c.JSON(http.StatusUnauthorized, gin.H{"error": "identity provider refused the login: " + providerError})
+            return
+        }
+        state := c.Query("state")
+        code := c.Query("code")
+        if state == "" || code == "" {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "state and code are required"})
+            return
+        }
+        if subtle.ConstantTimeCompare([]byte(cookie), []byte(state)) != 1 {
+            c.JSON(http.StatusBadRequest, gin.H{"error": domain.ErrInvalidOIDCState.Error()})
+            return
+        }
+
+        user, err := uc.OIDCUseCase.Complete(ctx, state, code)
+        switch {
+        case errors.Is(err, domain.ErrInvalidOIDCState):
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        case errors.Is(err, domain.ErrOIDCEmailNotVerified), errors.Is(err, domain.ErrSignupClosed):
+            uc.Metrics.ObserveLogin(infrastructure.LoginFailure)
+            c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+            return
+        case err != nil:
+            c.JSON(http.StatusBadGateway, gin.H{"error": "failed to complete login with the identity provider"})
+            return
+        }
+        if user.Email == nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "user not found"})
+            return
+        }
+
+        // The provider stands in for the password only; our own second factor still applies
+        if user.TwoFactor.Enabled {
+            uc.challengeTwoFactor(c, ctx, user)
+            return
+        }
+
+        uc.completeLogin(c, ctx, user, false)
+    }
+}
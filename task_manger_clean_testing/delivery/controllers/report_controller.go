@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/infrastructure"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportController struct {
+	TaskReportUseCase domain.TaskReportUsecase
+}
+
+func (rc *ReportController) GetTaskReport() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if err := infrastructure.CheckUserType(c, "ADMIN"); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+
+        var ctx, cancel = requestContext(c)
+        defer cancel()
+
+        report, err := rc.TaskReportUseCase.TaskReport(ctx)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{
+                "error":   "Failed to build task report",
+                "message": err.Error(),
+            })
+            return
+        }
+
+        if c.Query("format") == "csv" {
+            // Write to a buffer first so a failure can still be answered with an error
+            var body bytes.Buffer
+            if err := csv.NewWriter(&body).WriteAll(taskReportRows(report)); err != nil {
+                c.JSON(http.StatusInternalServerError, gin.H{
+                    "error":   "Failed to write task report",
+                    "message": err.Error(),
+                })
+                return
+            }
+            c.Header("Content-Disposition", `attachment; filename="task_report.csv"`)
+            c.Data(http.StatusOK, "text/csv", body.Bytes())
+            return
+        }
+
+        c.JSON(http.StatusOK, report)
+    }
+}
+
+// csvText keeps a spreadsheet from running text users chose, such as a
+// status, as a formula: a leading quote makes the cell plain text.
+func csvText(s string) string {
+    if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+        return "'" + s
+    }
+    return s
+}
+
+// taskReportRows flattens a report into metric,dimension,value rows so it can
+// be pivoted in a spreadsheet. The statuses are sorted so the same report
+// always gives the same file.
+func taskReportRows(report *domain.TaskReport) [][]string {
+    count := func(n int64) string { return strconv.FormatInt(n, 10) }
+    number := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+
+    rows := [][]string{
+        {"metric", "dimension", "value"},
+        {"total", "", count(report.Total)},
+        {"overdue", "", count(report.Overdue)},
+        {"completion_rate", "", number(report.CompletionRate)},
+        {"average_completion_seconds", "", number(report.AverageCompletionSeconds)},
+    }
+    statuses := make([]string, 0, len(report.ByStatus))
+    for status := range report.ByStatus {
+        statuses = append(statuses, status)
+    }
+    sort.Strings(statuses)
+    for _, status := range statuses {
+        rows = append(rows, []string{"status", csvText(status), count(report.ByStatus[status])})
+    }
+    for _, window := range report.Windows {
+        rows = append(rows,
+            []string{"window_created", csvText(window.Name), count(window.Created)},
+            []string{"window_completed", csvText(window.Name), count(window.Completed)},
+            []string{"window_completion_rate", csvText(window.Name), number(window.CompletionRate)},
+        )
+    }
+    for _, user := range report.Users {
+        rows = append(rows,
+            []string{"user_total", csvText(user.UserID), count(user.Total)},
+            []string{"user_completed", csvText(user.UserID), count(user.Completed)},
+            []string{"user_overdue", csvText(user.UserID), count(user.Overdue)},
+        )
+    }
+    return rows
+}
@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"task_manger_clean_architecture/domain"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskReportRowsSortStatuses(t *testing.T) {
+	report := &domain.TaskReport{ByStatus: map[string]int64{"pending": 3, "completed": 2, "in progress": 1, "blocked": 4}}
+
+	var statuses []string
+	for _, row := range taskReportRows(report) {
+		if row[0] == "status" {
+			statuses = append(statuses, row[1])
+		}
+	}
+	assert.Equal(t, []string{"blocked", "completed", "in progress", "pending"}, statuses)
+}
+
+func TestTaskReportRowsEscapeFormulas(t *testing.T) {
+	report := &domain.TaskReport{
+		ByStatus: map[string]int64{"=HYPERLINK(\"http://evil\")": 1, "+1": 1, "-1": 1, "@SUM(A1)": 1, "\tx": 1, "\rx": 1, "done": 1},
+		Users:    []domain.UserTaskReport{{UserID: "=cmd"}},
+	}
+
+	var dimensions []string
+	for _, row := range taskReportRows(report) {
+		if row[0] == "status" || row[0] == "user_total" {
+			dimensions = append(dimensions, row[1])
+		}
+	}
+	assert.Equal(t, []string{"'\tx", "'\rx", "'+1", "'-1", "'=HYPERLINK(\"http://evil\")", "'@SUM(A1)", "done", "'=cmd"}, dimensions)
+}
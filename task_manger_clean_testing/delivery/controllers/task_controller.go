@@ -1,11 +1,11 @@
 package controllers
 
 import (
-	"context"
 	"net/http"
+	"strconv"
+	"strings"
 	"task_manger_clean_architecture/domain"
 	"task_manger_clean_architecture/infrastructure"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -17,7 +17,7 @@ type TaskController struct {
 
 func (tc *TaskController) GetTasks() gin.HandlerFunc {
     return func(c *gin.Context) {
-        var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+        var ctx, cancel = requestContext(c)
         defer cancel()
 
         // Call the use case to get tasks
@@ -38,7 +38,7 @@ func (tc *TaskController) GetTasksById() gin.HandlerFunc {
     return func(c *gin.Context) {
         id := c.Param("task_id")
 
-        var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+        var ctx, cancel = requestContext(c)
         defer cancel()
 
         // Call the use case to get the task by ID
@@ -84,11 +84,18 @@ func (tc *TaskController) UpdateTask() gin.HandlerFunc {
 
         id := c.Param("task_id")
 
-        var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+        var ctx, cancel = requestContext(c)
         defer cancel()
 
         // Call the use case to update the task
         if err := tc.TaskUseCase.UpdateTask(ctx, id, updatedTask); err != nil {
+            if err == mongo.ErrNoDocuments {
+                c.JSON(http.StatusNotFound, gin.H{
+                    "error":   "Task not found",
+                    "message": "No task with the provided ID exists",
+                })
+                return
+            }
             // If there's an error during the update, return a 500 Internal Server Error
             c.JSON(http.StatusInternalServerError, gin.H{
                 "error":   "Failed to update task in the database",
@@ -114,7 +121,7 @@ func (tc *TaskController) DeleteById() gin.HandlerFunc {
 
         id := c.Param("task_id")
 
-        var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+        var ctx, cancel = requestContext(c)
         defer cancel()
 
         // Call the use case to delete the task by ID
@@ -162,8 +169,10 @@ func (tc *TaskController) AddTask() gin.HandlerFunc {
             })
             return
         }
+        newTask.CreatedBy = c.GetString("uid")
+        newTask.WorkspaceID = c.GetString("workspace")
 
-        var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+        var ctx, cancel = requestContext(c)
         defer cancel()
 
         // Call the use case to add the new task
@@ -184,8 +193,65 @@ func (tc *TaskController) AddTask() gin.HandlerFunc {
     }
 }
 
+func (tc *TaskController) SearchTasks() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        query := strings.TrimSpace(c.Query("q"))
+        if query == "" {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error":   "Missing search query",
+                "message": "the q query parameter is required",
+            })
+            return
+        }
 
+        limit, err := strconv.Atoi(c.Query("limit"))
+        if err != nil || limit < 1 {
+            limit = 20
+        }
+        if limit > 100 {
+            limit = 100
+        }
 
+        var ctx, cancel = requestContext(c)
+        defer cancel()
 
+        // Call the use case to search the tasks, best matches first
+        results, err := tc.TaskUseCase.SearchTasks(ctx, query, limit)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{
+                "error":   "Failed to search tasks",
+                "message": err.Error(),
+            })
+            return
+        }
 
+        c.JSON(http.StatusOK, results)
+    }
+}
 
+func (tc *TaskController) ReindexTasks() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if err := infrastructure.CheckUserType(c, "ADMIN"); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+
+        var ctx, cancel = requestContext(c)
+        defer cancel()
+
+        // Rebuild the search index from the stored tasks
+        indexed, err := tc.TaskUseCase.ReindexTasks(ctx)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{
+                "error":   "Failed to rebuild the search index",
+                "message": err.Error(),
+            })
+            return
+        }
+
+        c.JSON(http.StatusOK, gin.H{
+            "message": "Search index rebuilt successfully",
+            "indexed": indexed,
+        })
+    }
+}
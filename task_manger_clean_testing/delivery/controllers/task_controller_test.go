@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"context"
 	"bytes"
 	"encoding/json"
 	"errors"
@@ -11,13 +12,16 @@ import (
 	"task_manger_clean_architecture/delivery/middleware"
 	"task_manger_clean_architecture/domain"
 	"task_manger_clean_architecture/domain/mocks"
+	"task_manger_clean_architecture/infrastructure"
 	"testing"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type TaskControllerTestSuite struct {
@@ -54,11 +58,12 @@ func (suite *TaskControllerTestSuite) SetupTest() {
   }
 
   suite.SingleTask = domain.Task{Title : "Title 1", Description : "this is title 1",Status : "pending",}
-  suite.router.GET("/task", middleware.Authenticate(), suite.taskController.GetTasks())
-  suite.router.POST("/task", middleware.Authenticate(), suite.taskController.AddTask())
-  suite.router.DELETE("/task/:task_id", middleware.Authenticate(), suite.taskController.DeleteById())
-  suite.router.PUT("/task/:task_id", middleware.Authenticate(), suite.taskController.UpdateTask())
-  suite.router.GET("/task/:task_id", middleware.Authenticate(), suite.taskController.GetTasksById())
+  suite.router.GET("/task", authenticate(), suite.taskController.GetTasks())
+  suite.router.GET("/task/search", authenticate(), suite.taskController.SearchTasks())
+  suite.router.POST("/task", authenticate(), suite.taskController.AddTask())
+  suite.router.DELETE("/task/:task_id", authenticate(), suite.taskController.DeleteById())
+  suite.router.PUT("/task/:task_id", authenticate(), suite.taskController.UpdateTask())
+  suite.router.GET("/task/:task_id", authenticate(), suite.taskController.GetTasksById())
 }
 type SignedDetails struct {
     Email     string
@@ -70,6 +75,13 @@ type SignedDetails struct {
 }
 
 var SECRET_KEY = os.Getenv("SECRET_KEY")
+
+// authenticate is the middleware of the protected routes, with metrics that
+// are not scraped.
+func authenticate() gin.HandlerFunc {
+    return middleware.Authenticate(infrastructure.NewJWT(SECRET_KEY), infrastructure.NewMetrics(prometheus.NewRegistry()))
+}
+
 func (suite *TaskControllerTestSuite) GenerateToken(email string, firstName string, lastName string, userType string, uid string) (string, error) {
     claims := &SignedDetails{
         Email:     email,
@@ -173,8 +185,14 @@ func (suite *TaskControllerTestSuite) TestAddTaskSuccess() {
     token, err := suite.GenerateToken("bisratbnegus@gmail.com","bisrat", "berhanu",  "ADMIN", "demoid", )
     suite.NoError(err)
 
+    // The controller records the caller as the creator of the task, in the
+    // workspace of their token, which is the default one for tokens without
+    createdTask := task
+    createdTask.CreatedBy = "demoid"
+    createdTask.WorkspaceID = domain.DefaultWorkspaceID
+
     // Update mock to expect a context and the task as arguments
-    suite.mockTaskUseCase.On("AddTask", mock.Anything, task).Return(nil)
+    suite.mockTaskUseCase.On("AddTask", mock.Anything, createdTask).Return(nil)
 
     body, err := json.Marshal(task)
     suite.NoError(err, "no error while marshalling task data")
@@ -203,7 +221,7 @@ func (suite *TaskControllerTestSuite) TestAddTaskSuccess() {
     suite.Equal("Task added successfully", responseBody["message"])
 
     // Ensure the AddTask method was called with the correct arguments
-    suite.mockTaskUseCase.AssertCalled(suite.T(), "AddTask", mock.Anything, task)
+    suite.mockTaskUseCase.AssertCalled(suite.T(), "AddTask", mock.Anything, createdTask)
 }
 
 
@@ -288,6 +306,24 @@ func (suite *TaskControllerTestSuite) TestUpdateTaskSuccess() {
     suite.mockTaskUseCase.AssertCalled(suite.T(), "UpdateTask", mock.Anything, "12345", task)
 }
 
+func (suite *TaskControllerTestSuite) TestUpdateMissingTask() {
+    token, err := suite.GenerateToken("bisratbnegus@gmail.com", "bisrat", "berhanu", "ADMIN", "demoid")
+    suite.NoError(err)
+    suite.mockTaskUseCase.On("UpdateTask", mock.Anything, "404", suite.SingleTask).Return(mongo.ErrNoDocuments)
+
+    body, err := json.Marshal(suite.SingleTask)
+    suite.NoError(err)
+    req, err := http.NewRequest(http.MethodPut, "/task/404", bytes.NewBuffer(body))
+    suite.NoError(err)
+    req.Header.Set("Content-Type", "application/json")
+    req.Header.Set("Authorization", "Bearer "+token)
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusNotFound, recorder.Code)
+}
+
 func (suite *TaskControllerTestSuite) TestGetTaskByIDSuccess() {
     // Create a new HTTP GET request to the /task/12345 endpoint without a request body
     returnedTask := suite.SingleTask // Assuming SingleTask is a pre-defined task in your test suite
@@ -326,6 +362,91 @@ func (suite *TaskControllerTestSuite) TestGetTaskByIDSuccess() {
 }
 
 
+func (suite *TaskControllerTestSuite) TestSearchTasksSuccess() {
+    results := []*domain.TaskSearchResult{
+        {
+            Task:       &suite.SingleTask,
+            Score:      3,
+            Highlights: map[string]string{"title": "<mark>Title</mark> 1"},
+        },
+    }
+    req, err := http.NewRequest(http.MethodGet, "/task/search?q=title&limit=5", nil)
+    suite.NoError(err)
+
+    token, err := suite.GenerateToken("bisratbnegus@gmail.com", "bisrat", "berhanu", "user", "demoid")
+    suite.NoError(err)
+
+    // Mock the SearchTasks method with the query and limit taken from the URL
+    suite.mockTaskUseCase.On("SearchTasks", mock.Anything, "title", 5).Return(results, nil)
+
+    req.Header.Set("Authorization", "Bearer "+token)
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    suite.Equal(http.StatusOK, recorder.Code)
+
+    var responseBody []*domain.TaskSearchResult
+    err = json.Unmarshal(recorder.Body.Bytes(), &responseBody)
+    suite.NoError(err)
+
+    suite.Len(responseBody, 1)
+    suite.Equal(suite.SingleTask.Title, responseBody[0].Task.Title)
+    suite.Equal("<mark>Title</mark> 1", responseBody[0].Highlights["title"])
+}
+
+func (suite *TaskControllerTestSuite) TestSearchTasks_MissingQuery() {
+    req, err := http.NewRequest(http.MethodGet, "/task/search", nil)
+    suite.NoError(err)
+
+    token, err := suite.GenerateToken("bisratbnegus@gmail.com", "bisrat", "berhanu", "user", "demoid")
+    suite.NoError(err)
+
+    req.Header.Set("Authorization", "Bearer "+token)
+
+    recorder := httptest.NewRecorder()
+    suite.router.ServeHTTP(recorder, req)
+
+    // A search without a query is rejected before reaching the use case
+    suite.Equal(http.StatusBadRequest, recorder.Code)
+    suite.mockTaskUseCase.AssertNotCalled(suite.T(), "SearchTasks", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// serveWithTimeout serves req on /task behind the timeout middleware, with
+// GetTasks failing once the request's context is done.
+func (suite *TaskControllerTestSuite) serveWithTimeout(req *http.Request, timeout time.Duration) *httptest.ResponseRecorder {
+    router := gin.New()
+    router.GET("/task", middleware.Timeout(timeout, nil), suite.taskController.GetTasks())
+    suite.mockTaskUseCase.On("GetTasks", mock.Anything).
+        Run(func(args mock.Arguments) { <-args.Get(0).(context.Context).Done() }).
+        Return(nil, errors.New("operation was interrupted")).Once()
+
+    recorder := httptest.NewRecorder()
+    router.ServeHTTP(recorder, req)
+    return recorder
+}
+
+func (suite *TaskControllerTestSuite) TestGetTasks_Timeout() {
+    req, err := http.NewRequest(http.MethodGet, "/task", nil)
+    suite.NoError(err)
+
+    recorder := suite.serveWithTimeout(req, 10*time.Millisecond)
+    suite.Equal(http.StatusGatewayTimeout, recorder.Code)
+    suite.JSONEq(`{"error": "request timed out"}`, recorder.Body.String())
+}
+
+func (suite *TaskControllerTestSuite) TestGetTasks_ClientGone() {
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/task", nil)
+    suite.NoError(err)
+
+    recorder := suite.serveWithTimeout(req, time.Minute)
+    suite.Equal(http.StatusServiceUnavailable, recorder.Code)
+    suite.JSONEq(`{"error": "request cancelled"}`, recorder.Body.String())
+}
+
+
 func TestControllerTestSuite(t *testing.T) {
   suite.Run(t, new(TaskControllerTestSuite))
 }
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"task_manger_clean_architecture/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorController struct {
+	TwoFactorUseCase domain.TwoFactorUsecase
+}
+
+type twoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// twoFactorError answers with the status that fits err.
+func twoFactorError(c *gin.Context, err error) {
+    switch {
+    case errors.Is(err, domain.ErrInvalidTwoFactorCode):
+        c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+    case errors.Is(err, domain.ErrTwoFactorRequired):
+        c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+    case errors.Is(err, domain.ErrTwoFactorAlreadyEnabled),
+        errors.Is(err, domain.ErrTwoFactorNotEnabled),
+        errors.Is(err, domain.ErrTwoFactorNotEnrolled):
+        c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+    default:
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "two-factor request failed"})
+    }
+}
+
+func (tc *TwoFactorController) Enroll() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        enrollment, err := tc.TwoFactorUseCase.Enroll(ctx, c.GetString("uid"))
+        if err != nil {
+            twoFactorError(c, err)
+            return
+        }
+
+        // The secret and recovery codes are never shown again
+        c.Header("Cache-Control", "no-store")
+        c.JSON(http.StatusOK, enrollment)
+    }
+}
+
+func (tc *TwoFactorController) Confirm() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        var request twoFactorCodeRequest
+        if err := c.ShouldBindJSON(&request); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        if err := tc.TwoFactorUseCase.Confirm(ctx, c.GetString("uid"), request.Code); err != nil {
+            twoFactorError(c, err)
+            return
+        }
+
+        c.JSON(http.StatusOK, gin.H{"message": "two-factor authentication enabled, please log in again"})
+    }
+}
+
+func (tc *TwoFactorController) Disable() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        var request twoFactorCodeRequest
+        if err := c.ShouldBindJSON(&request); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        if err := tc.TwoFactorUseCase.Disable(ctx, c.GetString("uid"), request.Code); err != nil {
+            twoFactorError(c, err)
+            return
+        }
+
+        c.JSON(http.StatusOK, gin.H{"message": "two-factor authentication disabled"})
+    }
+}
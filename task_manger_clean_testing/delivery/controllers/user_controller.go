@@ -2,7 +2,10 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"math"
 	"net/http"
 	"strconv"
 	"task_manger_clean_architecture/domain"
@@ -15,12 +18,37 @@ import (
 )
 var validate = validator.New()
 type UserController struct {
-	UserUseCase domain.UserUseCase
+	UserUseCase      domain.UserUseCase
+	AuditUseCase     domain.AuditUsecase
+	LoginThrottle    domain.LoginThrottleUsecase
+	AccountUseCase   domain.AccountUsecase
+	PasswordHasher   domain.PasswordHasher
+	PasswordPolicy   domain.PasswordPolicy
+	TwoFactorUseCase domain.TwoFactorUsecase
+	OIDCUseCase      domain.OIDCUsecase
+	JWT              *infrastructure.JWT
+	Logger           *slog.Logger
+	Metrics          *infrastructure.Metrics
+}
+
+// hashNewPassword checks the password of a new account against the policy
+// and replaces it with its hash. It returns the status to answer with when
+// it fails.
+func hashNewPassword(policy domain.PasswordPolicy, hasher domain.PasswordHasher, user *domain.User) (int, error) {
+    if err := policy.Validate(*user.Password); err != nil {
+        return http.StatusBadRequest, err
+    }
+    password, err := hasher.Hash(*user.Password)
+    if err != nil {
+        return http.StatusInternalServerError, errors.New("failed to hash password")
+    }
+    user.Password = &password
+    return http.StatusOK, nil
 }
 
 func (uc *UserController) Signup() gin.HandlerFunc {
     return func(c *gin.Context) {
-        var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+        var ctx, cancel = requestContext(c)
         defer cancel()
 
         var user domain.User
@@ -28,6 +56,9 @@ func (uc *UserController) Signup() gin.HandlerFunc {
             c.JSON(http.StatusBadRequest, gin.H{"error": "couldnt bind user to using bindJson"})
             return
         }
+        // Everyone signs up as a user; administrators are made by promotion
+        userType := "USER"
+        user.UserType = &userType
 
         validationError := validate.Struct(user)
         if validationError != nil {
@@ -37,27 +68,25 @@ func (uc *UserController) Signup() gin.HandlerFunc {
 
         // Check if the user already exists by email
         existingUser, err := uc.UserUseCase.GetUserByEmail(ctx, *user.Email)
-        fmt.Println(err)
-        fmt.Println(existingUser)
         if err == nil && existingUser.UserId != "" {
             c.JSON(http.StatusBadRequest, gin.H{"error": "user already exist "})
             return
         }
 
-        
-        
-        // Hash the password
-        password := infrastructure.HashPassword(*user.Password)
-        user.Password = &password
+        if status, err := hashNewPassword(uc.PasswordPolicy, uc.PasswordHasher, &user); err != nil {
+            c.JSON(status, gin.H{"error": err.Error()})
+            return
+        }
 
         // Set additional user fields
+        user.EmailVerified = false
         user.CreatedAt, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
         user.UpdatedAt, _ = time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
         user.ID = primitive.NewObjectID()
         user.UserId = user.ID.Hex()
 
-        // Generate tokens
-        token, refreshToken, _ := infrastructure.GenerateAllTokens(*user.Email, user.FirstName, user.LastName, user.UserType, &user.UserId)
+        // Generate tokens. The account is in no workspace yet
+        token, refreshToken, _ := uc.JWT.GenerateAllTokens(*user.Email, user.FirstName, user.LastName, user.UserType, &user.UserId, false, 0, false, "")
         user.Token = &token
         user.RefreshToken = &refreshToken
 
@@ -69,6 +98,11 @@ func (uc *UserController) Signup() gin.HandlerFunc {
             return
         }
 
+        // The account exists either way; a lost mail can be sent again from /verify/resend
+        if err := uc.AccountUseCase.SendVerification(ctx, user); err != nil {
+            uc.Logger.ErrorContext(ctx, "failed to send verification mail", "uid", user.UserId, "error", err)
+        }
+
         c.JSON(http.StatusOK, gin.H{"insertionnumber": resultInsertionNumber})
     }
 }
@@ -77,7 +111,7 @@ func (uc *UserController) Signup() gin.HandlerFunc {
 
 func (uc *UserController) Login() gin.HandlerFunc {
     return func(c *gin.Context) {
-        var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+        var ctx, cancel = requestContext(c)
         defer cancel()
 
         var user domain.User
@@ -85,42 +119,232 @@ func (uc *UserController) Login() gin.HandlerFunc {
             c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
             return
         }
+        if user.Email == nil || user.Password == nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "email and password are required"})
+            return
+        }
+
+        // Count the attempt and refuse throttled or locked out clients before paying
+        // for a bcrypt comparison
+        if err := uc.LoginThrottle.Reserve(ctx, *user.Email, c.ClientIP()); err != nil {
+            if refuseAttempt(c, err) {
+                uc.Metrics.ObserveLogin(infrastructure.LoginThrottled)
+            }
+            return
+        }
 
         // Call the use case to login the user
         foundUser, err := uc.UserUseCase.Login(ctx, *user.Email)
         if err != nil {
-            c.JSON(http.StatusInternalServerError, gin.H{"error": "email or password not found"})
+            uc.rejectLogin(c, ctx, *user.Email, http.StatusInternalServerError, "email or password not found")
             return
         }
 
         // Verify the password
-        passwordIsValid, msg := infrastructure.VerifyPassword(*user.Password, *foundUser.Password)
-        if !passwordIsValid {
-            c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+        passwordIsValid, needsRehash, err := uc.PasswordHasher.Verify(*user.Password, stringValue(foundUser.Password))
+        if err != nil || !passwordIsValid {
+            uc.rejectLogin(c, ctx, *user.Email, http.StatusInternalServerError, "password or email not found")
             return
         }
 
+        // The password is known right now, so move legacy hashes to the current algorithm
+        if needsRehash {
+            if err := uc.upgradePasswordHash(ctx, foundUser.UserId, *user.Password); err != nil {
+                uc.Logger.ErrorContext(ctx, "failed to upgrade password hash", "uid", foundUser.UserId, "error", err)
+            }
+        }
+
         if foundUser.Email == nil {
             c.JSON(http.StatusInternalServerError, gin.H{"message": "user not found"})
             return
         }
 
-        // Generate tokens
-        token, refreshToken, _ := infrastructure.GenerateAllTokens(*foundUser.Email, foundUser.FirstName, foundUser.LastName, foundUser.UserType, &foundUser.UserId)
+        // With two-factor authentication the password only earns a challenge for /login/2fa
+        if foundUser.TwoFactor.Enabled {
+            uc.challengeTwoFactor(c, ctx, *foundUser)
+            return
+        }
+
+        uc.completeLogin(c, ctx, *foundUser, false)
+    }
+}
+
+// challengeTwoFactor answers a login that passed its first factor with the
+// challenge to redeem at /login/2fa.
+func (uc *UserController) challengeTwoFactor(c *gin.Context, ctx context.Context, foundUser domain.User) {
+    challenge, err := uc.TwoFactorUseCase.IssueChallenge(ctx, foundUser.UserId)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start two-factor login"})
+        return
+    }
+    c.JSON(http.StatusOK, gin.H{"two_factor_required": true, "challenge": challenge})
+}
+
+type loginTwoFactorRequest struct {
+	Challenge string `json:"challenge" binding:"required"`
+	Code      string `json:"code" binding:"required"`
+}
+
+func (uc *UserController) LoginTwoFactor() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        var request loginTwoFactorRequest
+        if err := c.ShouldBindJSON(&request); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+
+        userId, err := uc.TwoFactorUseCase.CompleteChallenge(ctx, request.Challenge, request.Code)
+        if errors.Is(err, domain.ErrInvalidTwoFactorCode) {
+            // The challenge is spent, so every guess costs a password login as well
+            user, getErr := uc.UserUseCase.GetUser(ctx, userId)
+            if getErr != nil || user.Email == nil {
+                c.JSON(http.StatusInternalServerError, gin.H{"error": "user not found"})
+                return
+            }
+            uc.rejectLogin(c, ctx, *user.Email, http.StatusUnauthorized, "invalid two-factor code, please log in again")
+            return
+        }
+        if errors.Is(err, domain.ErrInvalidUserToken) {
+            uc.Metrics.ObserveLogin(infrastructure.LoginFailure)
+            c.JSON(http.StatusUnauthorized, gin.H{"error": "challenge is invalid or has expired, please log in again"})
+            return
+        }
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to complete two-factor login"})
+            return
+        }
+
+        user, err := uc.UserUseCase.GetUser(ctx, userId)
+        if err != nil || user.Email == nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "user not found"})
+            return
+        }
+        uc.completeLogin(c, ctx, user, true)
+    }
+}
+
+// completeLogin issues tokens to a user who passed every factor their
+// account requires.
+func (uc *UserController) completeLogin(c *gin.Context, ctx context.Context, foundUser domain.User, mfa bool) {
+    if err := uc.LoginThrottle.Success(ctx, *foundUser.Email, c.ClientIP()); err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reset login attempts"})
+        return
+    }
+
+    // A login starts in the user's first workspace
+    workspace := ""
+    if len(foundUser.Workspaces) > 0 {
+        workspace = foundUser.Workspaces[0].WorkspaceID
+    }
+    token, refreshToken, _ := generateTokens(uc.JWT, foundUser, workspace, mfa)
+
+    // Update tokens in the database
+    if err := uc.UserUseCase.UpdateAllTokens(ctx, token, refreshToken, foundUser.UserId); err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update tokens"})
+        return
+    }
+
+    // The user is known now, so record the login as their own action
+    loginCtx := domain.WithAuditActor(ctx, domain.AuditActor{
+        UID:       foundUser.UserId,
+        ClientIP:  c.ClientIP(),
+        UserAgent: c.Request.UserAgent(),
+    })
+    // The tokens are issued already, so a lost entry must not fail the login
+    if err := uc.AuditUseCase.Record(loginCtx, domain.AuditUserLogin, foundUser.UserId, nil, nil); err != nil {
+        uc.Logger.ErrorContext(ctx, "failed to record login", "uid", foundUser.UserId, "error", err)
+    }
+
+    // Update the foundUser object with the new tokens
+    foundUser.Token = &token
+    foundUser.RefreshToken = &refreshToken
+
+    uc.Metrics.ObserveLogin(infrastructure.LoginSuccess)
+    c.JSON(http.StatusOK, NewAuthenticatedUserResponse(inWorkspace(foundUser, workspace)))
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refreshtoken" binding:"required"`
+}
 
-        // Update tokens in the database
-        err = uc.UserUseCase.UpdateAllTokens(token, refreshToken, foundUser.UserId)
+// Refresh trades the latest refresh token of a session for new tokens in the
+// same workspace. Refresh tokens rotate, so each one works once, and none
+// survives a change to the user's token version.
+func (uc *UserController) Refresh() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        var request refreshRequest
+        if err := c.ShouldBindJSON(&request); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+
+        claims, msg := uc.JWT.ValidateToken(request.RefreshToken)
+        if msg != "" || !claims.Refresh {
+            c.JSON(http.StatusUnauthorized, gin.H{"error": "refresh token is invalid or has expired, please log in again"})
+            return
+        }
+        user, err := uc.UserUseCase.GetUser(ctx, claims.Uid)
+        if err != nil || stringValue(user.RefreshToken) != request.RefreshToken || user.TokenVersion != claims.TokenVersion {
+            c.JSON(http.StatusUnauthorized, gin.H{"error": "refresh token is invalid or has expired, please log in again"})
+            return
+        }
+        // Sessions of users in no workspace yet have none to leave
+        workspace := claims.WorkspaceID
+        if _, ok := user.Role(workspace); workspace != "" && !ok {
+            c.JSON(http.StatusUnauthorized, gin.H{"error": "you are no longer a member of the workspace, please log in again"})
+            return
+        }
+
+        user, err = uc.reissueTokens(ctx, user, workspace, claims.MFA)
         if err != nil {
             c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update tokens"})
             return
         }
+        c.JSON(http.StatusOK, NewAuthenticatedUserResponse(inWorkspace(user, workspace)))
+    }
+}
 
-        // Update the foundUser object with the new tokens
-        foundUser.Token = &token
-        foundUser.RefreshToken = &refreshToken
+// upgradePasswordHash rehashes password with the current algorithm.
+func (uc *UserController) upgradePasswordHash(ctx context.Context, userId string, password string) error {
+    hash, err := uc.PasswordHasher.Hash(password)
+    if err != nil {
+        return err
+    }
+    return uc.UserUseCase.UpgradePasswordHash(ctx, userId, hash)
+}
+
+
+// refuseAttempt answers a password check LoginThrottle did not reserve, and
+// reports whether it was throttled rather than failed.
+func refuseAttempt(c *gin.Context, err error) bool {
+    var throttled *domain.LoginThrottledError
+    if errors.As(err, &throttled) {
+        c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+        c.JSON(http.StatusTooManyRequests, gin.H{"error": throttled.Error()})
+        return true
+    }
+    c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check login attempts"})
+    return false
+}
 
-        c.JSON(http.StatusOK, foundUser)
+// rejectLogin counts a failed login against the account and the client
+// address, then answers with status and msg.
+func (uc *UserController) rejectLogin(c *gin.Context, ctx context.Context, email string, status int, msg string) {
+    uc.Metrics.ObserveLogin(infrastructure.LoginFailure)
+    if err := uc.AuditUseCase.Record(ctx, domain.AuditUserLoginFailed, email, nil, nil); err != nil {
+        uc.Logger.ErrorContext(ctx, "failed to record failed login", "email", email, "error", err)
+    }
+    if err := uc.LoginThrottle.Failure(ctx, email, c.ClientIP()); err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record login attempt"})
+        return
     }
+    c.JSON(status, gin.H{"error": msg})
 }
 
 
@@ -131,7 +355,7 @@ func (uc *UserController) GetUsers() gin.HandlerFunc {
             return
         }
 
-        ctx, cancel := context.WithTimeout(context.Background(), 100*time.Second)
+        ctx, cancel := requestContext(c)
         defer cancel()
 
         recordsPerPage, err := strconv.Atoi(c.Query("recordPerPage"))
@@ -148,12 +372,16 @@ func (uc *UserController) GetUsers() gin.HandlerFunc {
 
         users, err := uc.UserUseCase.GetUsers(ctx, startIndex, int64(recordsPerPage))
         if err != nil {
-            // Log the actual error for debugging
+            uc.Logger.ErrorContext(ctx, "failed to list users", "error", err)
             c.JSON(http.StatusInternalServerError, gin.H{"error": "error occurred while listing user items"})
             return
         }
 
-        c.JSON(http.StatusOK, users)
+        response := make([]AdminUserResponse, 0, len(users))
+        for _, user := range users {
+            response = append(response, NewAdminUserResponse(inWorkspace(*user, c.GetString("workspace"))))
+        }
+        c.JSON(http.StatusOK, response)
     }
 }
 
@@ -166,16 +394,24 @@ func (uc *UserController) GetUser() gin.HandlerFunc {
             return
         }
 
-        var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+        var ctx, cancel = requestContext(c)
         defer cancel()
 
-        user, err := uc.UserUseCase.GetUser(ctx, userId)
+        // Other users are only visible to members of the same workspace
+        var user domain.User
+        var err error
+        if userId == c.GetString("uid") {
+            user, err = uc.UserUseCase.GetUser(ctx, userId)
+        } else {
+            user, err = uc.UserUseCase.GetMember(ctx, userId)
+        }
         if err != nil {
             c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
             return
         }
 
-        c.JSON(http.StatusOK, user)
+        user = inWorkspace(user, c.GetString("workspace"))
+        c.JSON(http.StatusOK, newUserResponse(c.GetString("uid"), c.GetString("usertype"), user))
     }
 }
 
@@ -191,12 +427,11 @@ func (uc *UserController) Promote() gin.HandlerFunc {
         // Get the user ID from the request
         userId := c.Param("user_id")
 
-        var ctx, cancel = context.WithTimeout(context.Background(), 100*time.Second)
+        var ctx, cancel = requestContext(c)
         defer cancel()
 
         // Call the use case to promote the user
         err, matchedCount, modifiedCount := uc.UserUseCase.Promote(ctx, userId, "ADMIN")
-        fmt.Println(matchedCount, modifiedCount)
         if err != nil {
             c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating user type"})
             return
@@ -214,3 +449,174 @@ func (uc *UserController) Promote() gin.HandlerFunc {
     }
 }
 
+func (uc *UserController) Unlock() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if err := infrastructure.CheckUserType(c, "ADMIN"); err != nil {
+            c.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized to perform this action"})
+            return
+        }
+
+        userId := c.Param("user_id")
+
+        var ctx, cancel = requestContext(c)
+        defer cancel()
+
+        user, err := uc.UserUseCase.GetMember(ctx, userId)
+        if err != nil || user.Email == nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "user not found"})
+            return
+        }
+
+        // Clear the failed attempts and any lockout on the account
+        if err := uc.LoginThrottle.Unlock(ctx, *user.Email); err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Error unlocking user"})
+            return
+        }
+
+        c.JSON(http.StatusOK, gin.H{"message": "User unlocked successfully"})
+    }
+}
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// generateTokens signs tokens acting in workspace_id, carrying the user's
+// role there.
+func generateTokens(signer *infrastructure.JWT, user domain.User, workspace_id string, mfa bool) (string, string, error) {
+    role, _ := user.Role(workspace_id)
+    return signer.GenerateAllTokens(*user.Email, user.FirstName, user.LastName, &role, &user.UserId, user.EmailVerified, user.TokenVersion, mfa, workspace_id)
+}
+
+// reissueTokens replaces the stored tokens of user with fresh ones carrying
+// its current token version and returns the user with them set. The
+// workspace and mfa carry over from the token of the request.
+func (uc *UserController) reissueTokens(ctx context.Context, user domain.User, workspace_id string, mfa bool) (domain.User, error) {
+    token, refreshToken, err := generateTokens(uc.JWT, user, workspace_id, mfa)
+    if err != nil {
+        return user, err
+    }
+    if err := uc.UserUseCase.UpdateAllTokens(ctx, token, refreshToken, user.UserId); err != nil {
+        return user, err
+    }
+    user.Token = &token
+    user.RefreshToken = &refreshToken
+    return user, nil
+}
+
+func (uc *UserController) UpdateProfile() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        var update domain.ProfileUpdate
+        if err := c.ShouldBindJSON(&update); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+        if err := validate.Struct(update); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+        if len(update.Fields()) == 0 {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "no fields to update"})
+            return
+        }
+
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        user, err := uc.UserUseCase.UpdateProfile(ctx, c.GetString("uid"), c.GetString("usertype"), update)
+        if err != nil {
+            switch {
+            case errors.Is(err, domain.ErrProfileFieldNotEditable):
+                c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+            case errors.Is(err, domain.ErrEmailTaken):
+                c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+            default:
+                c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update profile"})
+            }
+            return
+        }
+
+        // A new email revoked the old tokens and has to be verified again
+        if user.TokenVersion != c.GetInt("tokenversion") {
+            if err := uc.AccountUseCase.SendVerification(ctx, user); err != nil {
+                uc.Logger.ErrorContext(ctx, "failed to send verification mail", "uid", user.UserId, "error", err)
+            }
+            if user, err = uc.reissueTokens(ctx, user, c.GetString("workspace"), c.GetBool("mfa")); err != nil {
+                c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update tokens"})
+                return
+            }
+        }
+
+        c.JSON(http.StatusOK, NewAuthenticatedUserResponse(inWorkspace(user, c.GetString("workspace"))))
+    }
+}
+
+func (uc *UserController) ChangePassword() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        var request changePasswordRequest
+        if err := c.ShouldBindJSON(&request); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        userId := c.GetString("uid")
+        user, err := uc.UserUseCase.GetUser(ctx, userId)
+        if err != nil || user.Password == nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "user not found"})
+            return
+        }
+        // A stolen session must not become a way around the login's throttle
+        email := stringValue(user.Email)
+        if err := uc.LoginThrottle.Reserve(ctx, email, c.ClientIP()); err != nil {
+            refuseAttempt(c, err)
+            return
+        }
+        if valid, _, err := uc.PasswordHasher.Verify(request.CurrentPassword, *user.Password); err != nil || !valid {
+            if err := uc.LoginThrottle.Failure(ctx, email, c.ClientIP()); err != nil {
+                c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record login attempt"})
+                return
+            }
+            c.JSON(http.StatusBadRequest, gin.H{"error": "current password is incorrect"})
+            return
+        }
+        if err := uc.LoginThrottle.Success(ctx, email, c.ClientIP()); err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reset login attempts"})
+            return
+        }
+        if err := uc.PasswordPolicy.Validate(request.NewPassword); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+
+        password, err := uc.PasswordHasher.Hash(request.NewPassword)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to hash password"})
+            return
+        }
+        if err := uc.UserUseCase.ChangePassword(ctx, userId, password); err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to change password"})
+            return
+        }
+
+        // Every earlier token is revoked now, including the one of this request
+        user, err = uc.UserUseCase.GetUser(ctx, userId)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "user not found"})
+            return
+        }
+        if user, err = uc.reissueTokens(ctx, user, c.GetString("workspace"), c.GetBool("mfa")); err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update tokens"})
+            return
+        }
+
+        c.JSON(http.StatusOK, gin.H{
+            "message":      "password changed",
+            "token":        *user.Token,
+            "refreshtoken": *user.RefreshToken,
+        })
+    }
+}
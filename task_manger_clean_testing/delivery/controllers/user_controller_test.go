@@ -0,0 +1,263 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"task_manger_clean_architecture/infrastructure"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type UserControllerTestSuite struct {
+	suite.Suite
+	router          *gin.Engine
+	mockUserUseCase *mocks.UserUseCase
+	user            domain.User
+}
+
+func (suite *UserControllerTestSuite) SetupTest() {
+	suite.router = gin.Default()
+	suite.mockUserUseCase = new(mocks.UserUseCase)
+	uc := &UserController{UserUseCase: suite.mockUserUseCase, JWT: infrastructure.NewJWT(SECRET_KEY)}
+
+	suite.user = domain.User{
+		UserId:       "user1",
+		FirstName:    stringPtr("Bisrat"),
+		LastName:     stringPtr("Berhanu"),
+		Email:        stringPtr("bisrat@example.com"),
+		Phone:        stringPtr("0911"),
+		UserType:     stringPtr("USER"),
+		Password:     stringPtr("$2a$14$hash"),
+		Token:        stringPtr("stored-token"),
+		RefreshToken: stringPtr("stored-refresh-token"),
+		Workspaces:   []domain.WorkspaceMembership{{WorkspaceID: domain.DefaultWorkspaceID, Role: "USER"}},
+	}
+
+	suite.router.GET("/users", authenticate(), uc.GetUsers())
+	suite.router.GET("/users/:user_id", authenticate(), uc.GetUser())
+	suite.router.POST("/login/refresh", uc.Refresh())
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func (suite *UserControllerTestSuite) get(path string, uid string, userType string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	claims := &SignedDetails{
+		Email:    "caller@example.com",
+		UserType: userType,
+		Uid:      uid,
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(SECRET_KEY))
+	suite.Require().NoError(err)
+
+	req, err := http.NewRequest(http.MethodGet, path, nil)
+	suite.Require().NoError(err)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	recorder := httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, req)
+
+	var body map[string]interface{}
+	if recorder.Body.Len() > 0 && recorder.Body.Bytes()[0] == '{' {
+		suite.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &body))
+	}
+	return recorder, body
+}
+
+func (suite *UserControllerTestSuite) assertNoSecrets(raw string) {
+	suite.NotContains(raw, "$2a$14$hash")
+	suite.NotContains(raw, "stored-token")
+	suite.NotContains(raw, "stored-refresh-token")
+}
+
+func (suite *UserControllerTestSuite) TestGetUsersReturnsAdminViews() {
+	suite.mockUserUseCase.On("GetUsers", mock.Anything, int64(0), int64(10)).Return([]*domain.User{&suite.user}, nil)
+
+	recorder, _ := suite.get("/users", "admin1", "ADMIN")
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.assertNoSecrets(recorder.Body.String())
+
+	var users []AdminUserResponse
+	suite.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &users))
+	suite.Equal([]AdminUserResponse{NewAdminUserResponse(suite.user)}, users)
+}
+
+func (suite *UserControllerTestSuite) TestGetUserSelfView() {
+	suite.mockUserUseCase.On("GetUser", mock.Anything, "user1").Return(suite.user, nil)
+
+	recorder, body := suite.get("/users/user1", "user1", "USER")
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.assertNoSecrets(recorder.Body.String())
+	suite.Equal("bisrat@example.com", body["email"])
+}
+
+func (suite *UserControllerTestSuite) TestGetUserPublicView() {
+	suite.mockUserUseCase.On("GetMember", mock.Anything, "user1").Return(suite.user, nil)
+
+	recorder, body := suite.get("/users/user1", "user2", "USER")
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.assertNoSecrets(recorder.Body.String())
+	suite.Equal(map[string]interface{}{"userid": "user1", "firstname": "Bisrat", "lastname": "Berhanu"}, body)
+}
+
+func (suite *UserControllerTestSuite) refresh(refreshToken string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	req, err := http.NewRequest(http.MethodPost, "/login/refresh", strings.NewReader(`{"refreshtoken":"`+refreshToken+`"}`))
+	suite.Require().NoError(err)
+	recorder := httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, req)
+
+	var body map[string]interface{}
+	suite.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &body))
+	return recorder, body
+}
+
+func (suite *UserControllerTestSuite) TestRefreshRotatesTokens() {
+	_, refreshToken, err := infrastructure.NewJWT(SECRET_KEY).GenerateAllTokens("bisrat@example.com", suite.user.FirstName, suite.user.LastName, suite.user.UserType, &suite.user.UserId, false, 0, true, domain.DefaultWorkspaceID)
+	suite.Require().NoError(err)
+	suite.user.RefreshToken = &refreshToken
+	suite.mockUserUseCase.On("GetUser", mock.Anything, "user1").Return(suite.user, nil)
+	suite.mockUserUseCase.On("UpdateAllTokens", mock.Anything, mock.Anything, mock.Anything, "user1").Return(nil)
+
+	recorder, body := suite.refresh(refreshToken)
+	suite.Equal(http.StatusOK, recorder.Code)
+	suite.NotEmpty(body["token"])
+	suite.Equal("USER", body["usertype"])
+
+	// The new access token keeps the session's second factor and workspace
+	claims, msg := infrastructure.NewJWT(SECRET_KEY).ValidateToken(body["token"].(string))
+	suite.Require().Empty(msg)
+	suite.False(claims.Refresh)
+	suite.True(claims.MFA)
+	suite.Equal(domain.DefaultWorkspaceID, claims.WorkspaceID)
+	refreshed, msg := infrastructure.NewJWT(SECRET_KEY).ValidateToken(body["refreshtoken"].(string))
+	suite.Require().Empty(msg)
+	suite.True(refreshed.Refresh)
+	suite.Equal("user1", refreshed.Uid)
+}
+
+func (suite *UserControllerTestSuite) TestRefreshAfterSignup() {
+	policy := new(mocks.PasswordPolicy)
+	hasher := new(mocks.PasswordHasher)
+	account := new(mocks.AccountUsecase)
+	uc := &UserController{UserUseCase: suite.mockUserUseCase, JWT: infrastructure.NewJWT(SECRET_KEY), PasswordPolicy: policy, PasswordHasher: hasher, AccountUseCase: account}
+	suite.router.POST("/signup", uc.Signup())
+
+	var signedUp domain.User
+	policy.On("Validate", mock.Anything).Return(nil)
+	hasher.On("Hash", mock.Anything).Return("$2a$14$hash", nil)
+	account.On("SendVerification", mock.Anything, mock.Anything).Return(nil)
+	suite.mockUserUseCase.On("GetUserByEmail", mock.Anything, "new@example.com").Return(domain.User{}, mongo.ErrNoDocuments)
+	suite.mockUserUseCase.On("Signup", mock.Anything, mock.AnythingOfType("domain.User")).
+		Run(func(args mock.Arguments) { signedUp = args.Get(1).(domain.User) }).
+		Return("inserted", nil)
+
+	body := `{"firstname":"New","lastname":"User","email":"new@example.com","password":"password123","phone":"0911"}`
+	req, err := http.NewRequest(http.MethodPost, "/signup", strings.NewReader(body))
+	suite.Require().NoError(err)
+	recorder := httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, req)
+	suite.Require().Equal(http.StatusOK, recorder.Code, recorder.Body.String())
+	suite.Require().Empty(signedUp.Workspaces)
+
+	// The session of an account in no workspace refreshes like any other
+	suite.mockUserUseCase.On("GetUser", mock.Anything, signedUp.UserId).Return(signedUp, nil)
+	suite.mockUserUseCase.On("UpdateAllTokens", mock.Anything, mock.Anything, mock.Anything, signedUp.UserId).Return(nil)
+	recorder, refreshed := suite.refresh(*signedUp.RefreshToken)
+	suite.Equal(http.StatusOK, recorder.Code)
+	claims, msg := infrastructure.NewJWT(SECRET_KEY).ValidateToken(refreshed["token"].(string))
+	suite.Require().Empty(msg)
+	suite.Equal("", claims.WorkspaceID)
+}
+
+// changePassword sends a password change as user1.
+func (suite *UserControllerTestSuite) changePassword(uc *UserController, current string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.POST("/users/me/password", func(c *gin.Context) { c.Set("uid", "user1") }, uc.ChangePassword())
+	body := `{"current_password":"` + current + `","new_password":"a much better password"}`
+	req, err := http.NewRequest(http.MethodPost, "/users/me/password", strings.NewReader(body))
+	suite.Require().NoError(err)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func (suite *UserControllerTestSuite) TestChangePasswordCountsWrongPasswords() {
+	throttle := new(mocks.LoginThrottleUsecase)
+	hasher := new(mocks.PasswordHasher)
+	uc := &UserController{UserUseCase: suite.mockUserUseCase, LoginThrottle: throttle, PasswordHasher: hasher}
+	suite.mockUserUseCase.On("GetUser", mock.Anything, "user1").Return(suite.user, nil)
+	throttle.On("Reserve", mock.Anything, "bisrat@example.com", mock.Anything).Return(nil).Once()
+	hasher.On("Verify", "guess", "$2a$14$hash").Return(false, false, nil).Once()
+	throttle.On("Failure", mock.Anything, "bisrat@example.com", mock.Anything).Return(nil).Once()
+
+	recorder := suite.changePassword(uc, "guess")
+	suite.Equal(http.StatusBadRequest, recorder.Code)
+	throttle.AssertExpectations(suite.T())
+	hasher.AssertExpectations(suite.T())
+}
+
+func (suite *UserControllerTestSuite) TestChangePasswordIsThrottledLikeLogin() {
+	throttle := new(mocks.LoginThrottleUsecase)
+	hasher := new(mocks.PasswordHasher)
+	uc := &UserController{UserUseCase: suite.mockUserUseCase, LoginThrottle: throttle, PasswordHasher: hasher}
+	suite.mockUserUseCase.On("GetUser", mock.Anything, "user1").Return(suite.user, nil)
+	throttle.On("Reserve", mock.Anything, "bisrat@example.com", mock.Anything).Return(&domain.LoginThrottledError{RetryAfter: 30 * time.Second}).Once()
+
+	recorder := suite.changePassword(uc, "guess")
+	suite.Equal(http.StatusTooManyRequests, recorder.Code)
+	suite.Equal("30", recorder.Header().Get("Retry-After"))
+	// The password is not even checked
+	hasher.AssertNotCalled(suite.T(), "Verify", mock.Anything, mock.Anything)
+}
+
+func (suite *UserControllerTestSuite) TestRefreshRejectsReplacedToken() {
+	_, refreshToken, err := infrastructure.NewJWT(SECRET_KEY).GenerateAllTokens("bisrat@example.com", suite.user.FirstName, suite.user.LastName, suite.user.UserType, &suite.user.UserId, false, 0, false, domain.DefaultWorkspaceID)
+	suite.Require().NoError(err)
+	// suite.user still stores a newer refresh token
+	suite.mockUserUseCase.On("GetUser", mock.Anything, "user1").Return(suite.user, nil)
+
+	recorder, _ := suite.refresh(refreshToken)
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+	suite.mockUserUseCase.AssertNotCalled(suite.T(), "UpdateAllTokens", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *UserControllerTestSuite) TestRefreshRejectsAccessTokens() {
+	token, _, err := infrastructure.NewJWT(SECRET_KEY).GenerateAllTokens("bisrat@example.com", suite.user.FirstName, suite.user.LastName, suite.user.UserType, &suite.user.UserId, false, 0, false, domain.DefaultWorkspaceID)
+	suite.Require().NoError(err)
+
+	recorder, _ := suite.refresh(token)
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+
+	recorder, _ = suite.refresh("not-a-jwt")
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+}
+
+func (suite *UserControllerTestSuite) TestRefreshTokensCannotAuthorizeRequests() {
+	_, refreshToken, err := infrastructure.NewJWT(SECRET_KEY).GenerateAllTokens("bisrat@example.com", suite.user.FirstName, suite.user.LastName, suite.user.UserType, &suite.user.UserId, false, 0, false, domain.DefaultWorkspaceID)
+	suite.Require().NoError(err)
+
+	req, err := http.NewRequest(http.MethodGet, "/users/user1", nil)
+	suite.Require().NoError(err)
+	req.Header.Set("Authorization", "Bearer "+refreshToken)
+	recorder := httptest.NewRecorder()
+	suite.router.ServeHTTP(recorder, req)
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+}
+
+func TestUserControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(UserControllerTestSuite))
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"task_manger_clean_architecture/domain"
+	"time"
+)
+
+// The user responses copy fields out of domain.User one by one, so a field
+// added to the stored model stays private until a view lists it here.
+
+// PublicUserResponse is what any signed in user may see of another user.
+type PublicUserResponse struct {
+	UserID    string `json:"userid"`
+	FirstName string `json:"firstname"`
+	LastName  string `json:"lastname"`
+}
+
+// SelfUserResponse is a user's view of their own account.
+type SelfUserResponse struct {
+	PublicUserResponse
+	Email         string    `json:"email"`
+	Phone         string    `json:"phone"`
+	UserType      string    `json:"usertype"`
+	EmailVerified bool      `json:"email_verified"`
+	CreatedAt     time.Time `json:"createdat"`
+	UpdatedAt     time.Time `json:"updatedat"`
+}
+
+// AdminUserResponse is an administrator's view of any account.
+type AdminUserResponse struct {
+	SelfUserResponse
+}
+
+// AuthenticatedUserResponse is returned when tokens are issued to the user.
+// The tokens are omitted when a response did not issue new ones.
+type AuthenticatedUserResponse struct {
+	SelfUserResponse
+	Token        string `json:"token,omitempty"`
+	RefreshToken string `json:"refreshtoken,omitempty"`
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// inWorkspace returns user as seen from workspace_id: the usertype of the
+// views is the role there, not the one the account signed up with.
+func inWorkspace(user domain.User, workspace_id string) domain.User {
+	role, _ := user.Role(workspace_id)
+	user.UserType = &role
+	return user
+}
+
+func NewPublicUserResponse(user domain.User) PublicUserResponse {
+	return PublicUserResponse{
+		UserID:    user.UserId,
+		FirstName: stringValue(user.FirstName),
+		LastName:  stringValue(user.LastName),
+	}
+}
+
+func NewSelfUserResponse(user domain.User) SelfUserResponse {
+	return SelfUserResponse{
+		PublicUserResponse: NewPublicUserResponse(user),
+		Email:              stringValue(user.Email),
+		Phone:              stringValue(user.Phone),
+		UserType:           stringValue(user.UserType),
+		EmailVerified:      user.EmailVerified,
+		CreatedAt:          user.CreatedAt,
+		UpdatedAt:          user.UpdatedAt,
+	}
+}
+
+func NewAdminUserResponse(user domain.User) AdminUserResponse {
+	return AdminUserResponse{
+		SelfUserResponse: NewSelfUserResponse(user),
+	}
+}
+
+func NewAuthenticatedUserResponse(user domain.User) AuthenticatedUserResponse {
+	return AuthenticatedUserResponse{
+		SelfUserResponse: NewSelfUserResponse(user),
+		Token:            stringValue(user.Token),
+		RefreshToken:     stringValue(user.RefreshToken),
+	}
+}
+
+// newUserResponse picks the view of user for the signed in caller.
+func newUserResponse(callerID string, callerType string, user domain.User) interface{} {
+	switch {
+	case callerType == "ADMIN":
+		return NewAdminUserResponse(user)
+	case callerID != "" && callerID == user.UserId:
+		return NewSelfUserResponse(user)
+	default:
+		return NewPublicUserResponse(user)
+	}
+}
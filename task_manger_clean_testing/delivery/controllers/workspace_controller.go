@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/infrastructure"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WorkspaceController struct {
+	WorkspaceUseCase domain.WorkspaceUsecase
+	UserUseCase      domain.UserUseCase
+	JWT              *infrastructure.JWT
+}
+
+type createWorkspaceRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+func (wc *WorkspaceController) CreateWorkspace() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        var request createWorkspaceRequest
+        if err := c.ShouldBindJSON(&request); err != nil {
+            c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        }
+
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        workspace, err := wc.WorkspaceUseCase.Create(ctx, c.GetString("uid"), request.Name)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "error occured while creating the workspace"})
+            return
+        }
+
+        c.JSON(http.StatusCreated, workspace)
+    }
+}
+
+func (wc *WorkspaceController) GetWorkspaces() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        workspaces, err := wc.WorkspaceUseCase.List(ctx, c.GetString("uid"))
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "error occured while listing workspaces"})
+            return
+        }
+
+        c.JSON(http.StatusOK, gin.H{"active": c.GetString("workspace"), "workspaces": workspaces})
+    }
+}
+
+// SwitchWorkspace issues tokens that act in another of the user's
+// workspaces.
+func (wc *WorkspaceController) SwitchWorkspace() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        ctx, cancel := requestContext(c)
+        defer cancel()
+
+        workspaceId := c.Param("workspace_id")
+        if _, err := wc.WorkspaceUseCase.Membership(ctx, c.GetString("uid"), workspaceId); err != nil {
+            if errors.Is(err, domain.ErrNotWorkspaceMember) {
+                c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+                return
+            }
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "error occured while switching workspace"})
+            return
+        }
+
+        user, err := wc.UserUseCase.GetUser(ctx, c.GetString("uid"))
+        if err != nil || user.Email == nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "user not found"})
+            return
+        }
+        token, refreshToken, err := generateTokens(wc.JWT, user, workspaceId, c.GetBool("mfa"))
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate tokens"})
+            return
+        }
+        if err := wc.UserUseCase.UpdateAllTokens(ctx, token, refreshToken, user.UserId); err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update tokens"})
+            return
+        }
+        user.Token = &token
+        user.RefreshToken = &refreshToken
+
+        c.JSON(http.StatusOK, NewAuthenticatedUserResponse(inWorkspace(user, workspaceId)))
+    }
+}
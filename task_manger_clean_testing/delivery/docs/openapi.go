@@ -0,0 +1,380 @@
+// Package docs builds the OpenAPI 3.1 description of the API. The schemas of
+// the bodies are derived from the Go types the handlers bind and answer with,
+// so they follow the code.
+package docs
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Document is an OpenAPI 3.1 document.
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Security   []SecurityRequirement `json:"security,omitempty"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps the lower-case HTTP methods of a path to their operations.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Description string              `json:"description,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+	// Security is empty, not absent, on the public operations.
+	Security *[]SecurityRequirement `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is the subset of JSON Schema the API needs.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// SecurityRequirement names the schemes an operation accepts.
+type SecurityRequirement map[string][]string
+
+// BearerAuth is the name of the security scheme of the signed in routes.
+const BearerAuth = "bearerAuth"
+
+// Object returns the schema of an object with the given properties, all of
+// them required. It describes the gin.H bodies, which have no Go type.
+func Object(properties map[string]*Schema) *Schema {
+	schema := &Schema{Type: "object", Properties: properties}
+	for name := range properties {
+		schema.Required = append(schema.Required, name)
+	}
+	sort.Strings(schema.Required)
+	return schema
+}
+
+// String, Integer, Boolean and ArrayOf return the schemas of plain values.
+func String(description string) *Schema  { return &Schema{Type: "string", Description: description} }
+func Integer(description string) *Schema { return &Schema{Type: "integer", Description: description} }
+func Boolean(description string) *Schema { return &Schema{Type: "boolean", Description: description} }
+func ArrayOf(items *Schema) *Schema      { return &Schema{Type: "array", Items: items} }
+
+// Text is a response body that is not JSON, such as the metrics or a CSV
+// report, by media type.
+type Text string
+
+// Query is a query parameter of a route.
+type Query struct {
+	Name        string
+	Description string
+	Required    bool
+	// Type is "string" when it is empty.
+	Type string
+}
+
+// Route documents one route. Body and the values of Responses are a Go value
+// whose type is the JSON body, a *Schema, a Text or nil for no body.
+type Route struct {
+	Method string
+	// Path uses gin's syntax, such as /task/:task_id.
+	Path        string
+	OperationID string
+	Summary     string
+	Description string
+	Tag         string
+	// Public routes need no bearer token.
+	Public bool
+	Query  []Query
+	Body   any
+	// BodyOptional routes also accept requests without a body.
+	BodyOptional bool
+	Responses    map[int]any
+}
+
+// Spec accumulates routes into a Document.
+type Spec struct {
+	document *Document
+	// names are the component names of the Go types described so far.
+	names map[reflect.Type]string
+}
+
+// New returns a Spec without routes. The signed in routes take a bearer token:
+// a JWT from /login or a personal access token.
+func New(info Info, bearerDescription string) *Spec {
+	return &Spec{
+		document: &Document{
+			OpenAPI:  "3.1.0",
+			Info:     info,
+			Security: []SecurityRequirement{{BearerAuth: {}}},
+			Paths:    map[string]PathItem{},
+			Components: Components{
+				Schemas: map[string]*Schema{
+					"Error": Object(map[string]*Schema{
+						"error":      String("what went wrong"),
+						"request_id": String("the X-Request-ID of the request, to find it in the logs"),
+					}),
+				},
+				SecuritySchemes: map[string]SecurityScheme{
+					BearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: bearerDescription},
+				},
+			},
+		},
+		names: map[reflect.Type]string{},
+	}
+}
+
+// OpenAPIPath turns the gin path of a route into its OpenAPI path.
+func OpenAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Add documents route.
+func (s *Spec) Add(route Route) {
+	operation := &Operation{
+		OperationID: route.OperationID,
+		Summary:     route.Summary,
+		Description: route.Description,
+		Responses:   map[string]Response{},
+	}
+	if route.Tag != "" {
+		operation.Tags = []string{route.Tag}
+	}
+	if route.Public {
+		operation.Security = &[]SecurityRequirement{}
+	}
+	for _, segment := range strings.Split(route.Path, "/") {
+		if strings.HasPrefix(segment, ":") {
+			operation.Parameters = append(operation.Parameters, Parameter{Name: segment[1:], In: "path", Required: true, Schema: String("")})
+		}
+	}
+	for _, query := range route.Query {
+		schemaType := query.Type
+		if schemaType == "" {
+			schemaType = "string"
+		}
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:        query.Name,
+			In:          "query",
+			Description: query.Description,
+			Required:    query.Required,
+			Schema:      &Schema{Type: schemaType},
+		})
+	}
+	if route.Body != nil {
+		operation.RequestBody = &RequestBody{Required: !route.BodyOptional, Content: s.content(route.Body)}
+	}
+	for status, body := range route.Responses {
+		operation.Responses[strconv.Itoa(status)] = Response{Description: http.StatusText(status), Content: s.content(body)}
+	}
+	// Every error is answered with the same body
+	operation.Responses["default"] = Response{
+		Description: "Error",
+		Content:     map[string]MediaType{"application/json": {Schema: &Schema{Ref: "#/components/schemas/Error"}}},
+	}
+
+	path := OpenAPIPath(route.Path)
+	if s.document.Paths[path] == nil {
+		s.document.Paths[path] = PathItem{}
+	}
+	s.document.Paths[path][strings.ToLower(route.Method)] = operation
+}
+
+func (s *Spec) content(body any) map[string]MediaType {
+	switch body := body.(type) {
+	case nil:
+		return nil
+	case Text:
+		return map[string]MediaType{string(body): {Schema: &Schema{Type: "string"}}}
+	case *Schema:
+		return map[string]MediaType{"application/json": {Schema: body}}
+	}
+	return map[string]MediaType{"application/json": {Schema: s.SchemaOf(body)}}
+}
+
+// Document returns the document of the routes added so far.
+func (s *Spec) Document() *Document {
+	return s.document
+}
+
+// JSON returns the document as indented JSON.
+func (s *Spec) JSON() ([]byte, error) {
+	return json.MarshalIndent(s.document, "", "  ")
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+)
+
+// SchemaOf returns the schema of the JSON encoding of v. Named structs become
+// components referenced by their type name.
+func (s *Spec) SchemaOf(v any) *Schema {
+	return s.schema(reflect.TypeOf(v))
+}
+
+func (s *Spec) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawType:
+		return &Schema{Description: "any JSON value"}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return ArrayOf(s.schema(t.Elem()))
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		name, ok := s.names[t]
+		if !ok {
+			name = t.Name()
+			s.names[t] = name
+			s.document.Components.Schemas[name] = s.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	// Interfaces can hold anything
+	return &Schema{}
+}
+
+// Without returns the schema of the JSON encoding of v without the named
+// properties, for the requests that bind a type of which they read only
+// some fields.
+func (s *Spec) Without(v any, names ...string) *Schema {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	schema := s.object(t)
+	for _, name := range names {
+		delete(schema.Properties, name)
+	}
+	kept := schema.Required[:0]
+	for _, name := range schema.Required {
+		if schema.Properties[name] != nil {
+			kept = append(kept, name)
+		}
+	}
+	schema.Required = kept
+	return schema
+}
+
+// object describes the fields of a struct as encoding/json writes them:
+// embedded structs without a name are flattened into their parent.
+func (s *Spec) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	var add func(t reflect.Type)
+	add = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, options, _ := strings.Cut(tag, ",")
+			if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+				add(field.Type)
+				continue
+			}
+			if !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			schema.Properties[name] = s.schema(field.Type)
+			if strings.Contains(options, "omitempty") {
+				continue
+			}
+			if required(field.Tag.Get("binding")) || required(field.Tag.Get("validate")) {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	}
+	add(t)
+	return schema
+}
+
+func required(rules string) bool {
+	for _, rule := range strings.Split(rules, ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package docs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type base struct {
+	ID string `json:"id"`
+}
+
+type item struct {
+	base
+	Name    string            `json:"name" binding:"required"`
+	Note    string            `json:"note,omitempty" validate:"required"`
+	Due     time.Time         `json:"due"`
+	Tags    []string          `json:"tags"`
+	Labels  map[string]string `json:"labels"`
+	Secret  string            `json:"-"`
+	private string
+}
+
+func TestSchemaOf(t *testing.T) {
+	spec := New(Info{Title: "test", Version: "1"}, "")
+
+	assert.Equal(t, &Schema{Ref: "#/components/schemas/item"}, spec.SchemaOf(item{}))
+	schema := spec.Document().Components.Schemas["item"]
+	assert.ElementsMatch(t, []string{"id", "name", "note", "due", "tags", "labels"}, keys(schema.Properties))
+	assert.Equal(t, []string{"name"}, schema.Required)
+	assert.Equal(t, "date-time", schema.Properties["due"].Format)
+	assert.Equal(t, "string", schema.Properties["tags"].Items.Type)
+	assert.Equal(t, "string", schema.Properties["labels"].AdditionalProperties.Type)
+}
+
+func TestWithout(t *testing.T) {
+	spec := New(Info{Title: "test", Version: "1"}, "")
+
+	schema := spec.Without(&item{}, "name", "labels")
+
+	assert.ElementsMatch(t, []string{"id", "note", "due", "tags"}, keys(schema.Properties))
+	assert.Empty(t, schema.Required)
+}
+
+func TestAdd(t *testing.T) {
+	spec := New(Info{Title: "test", Version: "1"}, "")
+
+	spec.Add(Route{Method: "DELETE", Path: "/items/:item_id", OperationID: "deleteItem", Responses: map[int]any{204: nil}})
+	spec.Add(Route{Method: "GET", Path: "/items", OperationID: "listItems", Public: true, Query: []Query{{Name: "page", Type: "integer"}}, Responses: map[int]any{200: []item{}}})
+
+	remove := spec.Document().Paths["/items/{item_id}"]["delete"]
+	assert.Equal(t, []Parameter{{Name: "item_id", In: "path", Required: true, Schema: String("")}}, remove.Parameters)
+	assert.Nil(t, remove.Security)
+	assert.Nil(t, remove.Responses["204"].Content)
+	assert.Contains(t, remove.Responses, "default")
+	list := spec.Document().Paths["/items"]["get"]
+	assert.Empty(t, *list.Security)
+	assert.Equal(t, "integer", list.Parameters[0].Schema.Type)
+	assert.Equal(t, "#/components/schemas/item", list.Responses["200"].Content["application/json"].Schema.Items.Ref)
+}
+
+func TestOpenAPIPath(t *testing.T) {
+	assert.Equal(t, "/invites/pending/{invite_id}/resend", OpenAPIPath("/invites/pending/:invite_id/resend"))
+	assert.Equal(t, "/task", OpenAPIPath("/task"))
+}
+
+func keys(m map[string]*Schema) []string {
+	var names []string
+	for name := range m {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,80 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"task_manger_clean_architecture/domain"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type callerKey struct{}
+
+// Caller is the signed in user making a request, with their current role in
+// the workspace their token acts in.
+type Caller struct {
+	UID       string
+	Role      string
+	Workspace string
+}
+
+// WithCaller returns a copy of ctx carrying the caller, which every request
+// to the handler needs.
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+func callerFrom(ctx context.Context) Caller {
+	c, _ := ctx.Value(callerKey{}).(Caller)
+	return c
+}
+
+// The codes in the extensions of the errors the API returns.
+const (
+	codeForbidden = "FORBIDDEN"
+	codeNotFound  = "NOT_FOUND"
+	codeConflict  = "CONFLICT"
+	codeInvalid   = "BAD_USER_INPUT"
+	codeTimeout   = "TIMEOUT"
+	codeInternal  = "INTERNAL_SERVER_ERROR"
+)
+
+func newError(ctx context.Context, code string, message string) *gqlerror.Error {
+	return &gqlerror.Error{
+		Message:    message,
+		Path:       graphql.GetPath(ctx),
+		Extensions: map[string]interface{}{"code": code},
+	}
+}
+
+// requireAdmin rejects callers who are not administrators of the workspace.
+func requireAdmin(ctx context.Context) error {
+	if callerFrom(ctx).Role != "ADMIN" {
+		return newError(ctx, codeForbidden, "unauthorized to access")
+	}
+	return nil
+}
+
+// toError returns the error of a failed use case call. Unexpected errors are
+// logged and only message reaches the client.
+func toError(ctx context.Context, logger *slog.Logger, message string, err error) error {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return newError(ctx, codeNotFound, message+": not found")
+	case errors.Is(err, domain.ErrNoWorkspace), errors.Is(err, domain.ErrNotWorkspaceMember):
+		return newError(ctx, codeForbidden, err.Error())
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return newError(ctx, codeTimeout, message+": "+err.Error())
+	}
+	logger.ErrorContext(ctx, message, "error", err)
+	return newError(ctx, codeInternal, message)
+}
+
+// isMissing reports whether err means there is no such task or member in the
+// workspace, which the single object queries answer with null.
+func isMissing(err error) bool {
+	return errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, domain.ErrNotWorkspaceMember)
+}
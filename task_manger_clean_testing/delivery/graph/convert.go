@@ -0,0 +1,110 @@
+package graph
+
+import (
+	"sort"
+	"task_manger_clean_architecture/delivery/graph/model"
+	"task_manger_clean_architecture/domain"
+)
+
+// toUser returns user as seen from workspace_id. Only administrators and the
+// user themself see the full view; other members see the ID and the name.
+func toUser(user domain.User, workspace_id string, full bool) *model.User {
+	view := &model.User{
+		ID:        user.UserId,
+		FirstName: stringValue(user.FirstName),
+		LastName:  stringValue(user.LastName),
+	}
+	if full {
+		// The user type is the role in the workspace, not the one the
+		// account signed up with
+		role, _ := user.Role(workspace_id)
+		view.Email = user.Email
+		view.Phone = user.Phone
+		view.UserType = &role
+		view.EmailVerified = &user.EmailVerified
+		view.CreatedAt = &user.CreatedAt
+		view.UpdatedAt = &user.UpdatedAt
+	}
+	return view
+}
+
+// canSeeAll reports whether the caller may see the full view of user_id.
+func canSeeAll(caller Caller, user_id string) bool {
+	return caller.Role == "ADMIN" || caller.UID == user_id
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// toTask returns the task the input describes. Fields left out are empty, as
+// in the JSON bodies of the REST API.
+func toTask(input model.TaskInput) domain.Task {
+	task := domain.Task{Title: input.Title}
+	if input.Description != nil {
+		task.Description = *input.Description
+	}
+	if input.DueDate != nil {
+		task.DueDate = *input.DueDate
+	}
+	if input.Status != nil {
+		task.Status = *input.Status
+	}
+	return task
+}
+
+// matches reports whether task matches every field of filter that is set.
+func matches(task *domain.Task, filter *model.TaskFilter) bool {
+	switch {
+	case filter == nil:
+		return true
+	case filter.Status != nil && task.Status != *filter.Status:
+		return false
+	case filter.CreatedByID != nil && task.CreatedBy != *filter.CreatedByID:
+		return false
+	case filter.DueAfter != nil && task.DueDate.Before(*filter.DueAfter):
+		return false
+	case filter.DueBefore != nil && !task.DueDate.Before(*filter.DueBefore):
+		return false
+	}
+	return true
+}
+
+// taskPage returns the page of the tasks matching filter, by due date and
+// then ID so that pages do not overlap.
+func taskPage(tasks []*domain.Task, filter *model.TaskFilter, offset int, limit int) *model.TaskPage {
+	matching := make([]*domain.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if matches(task, filter) {
+			matching = append(matching, task)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		if !matching[i].DueDate.Equal(matching[j].DueDate) {
+			return matching[i].DueDate.Before(matching[j].DueDate)
+		}
+		return matching[i].ID < matching[j].ID
+	})
+
+	page := &model.TaskPage{Nodes: []*domain.Task{}, TotalCount: len(matching)}
+	if offset < len(matching) {
+		end := min(offset+limit, len(matching))
+		page.Nodes = matching[offset:end]
+		page.HasNextPage = end < len(matching)
+	}
+	return page
+}
+
+func intValue(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"task_manger_clean_architecture/delivery/graph/model"
+	"task_manger_clean_architecture/domain"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+)
+
+// Limits on what a single query may ask for. Each field costs one and a list
+// costs its page size times its fields, so a full page of tasks with their
+// creators fits while aliasing it many times over does not.
+const (
+	MaxComplexity      = 2500
+	maxPageSize        = 100
+	defaultTasksPage   = 20
+	defaultMembersPage = 10
+	queryCacheSize     = 1000
+)
+
+// NewHandler returns the HTTP handler of the GraphQL API. Every request must
+// carry its caller, see WithCaller, and the workspace the caller acts in.
+func NewHandler(tasks domain.TaskUsecase, users domain.UserUseCase, logger *slog.Logger) http.Handler {
+	config := Config{Resolvers: &Resolver{TaskUseCase: tasks, UserUseCase: users, Logger: logger}}
+	config.Complexity.Query.Tasks = func(childComplexity int, filter *model.TaskFilter, offset *int, limit *int) int {
+		return 1 + pageSize(limit, defaultTasksPage)*childComplexity
+	}
+	config.Complexity.Query.Users = func(childComplexity int, page *int, recordsPerPage *int) int {
+		return 1 + pageSize(recordsPerPage, defaultMembersPage)*childComplexity
+	}
+
+	server := handler.New(NewExecutableSchema(config))
+	server.AddTransport(transport.POST{})
+	server.SetQueryCache(lru.New(queryCacheSize))
+	server.Use(extension.Introspection{})
+	server.Use(extension.FixedComplexityLimit(MaxComplexity))
+	server.SetRecoverFunc(func(ctx context.Context, err interface{}) error {
+		logger.ErrorContext(ctx, "panic while resolving", "error", fmt.Sprint(err))
+		return newError(ctx, codeInternal, "internal server error")
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		server.ServeHTTP(w, r.WithContext(withLoaders(r.Context(), users)))
+	})
+}
+
+// pageSize returns the requested size of a page, or fallback when there is
+// none, capped at maxPageSize.
+func pageSize(size *int, fallback int) int {
+	switch {
+	case size == nil || *size < 1:
+		return fallback
+	case *size > maxPageSize:
+		return maxPageSize
+	}
+	return *size
+}
@@ -0,0 +1,174 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+// HandlerTestSuite sends queries to the handler over HTTP, with the use
+// cases mocked.
+type HandlerTestSuite struct {
+	suite.Suite
+	tasks   *mocks.TaskUsecase
+	users   *mocks.UserUseCase
+	handler http.Handler
+	admin   domain.User
+	member  domain.User
+}
+
+func (suite *HandlerTestSuite) SetupTest() {
+	suite.tasks = new(mocks.TaskUsecase)
+	suite.users = new(mocks.UserUseCase)
+	suite.handler = NewHandler(suite.tasks, suite.users, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	suite.admin = domain.User{
+		UserId:        "admin1",
+		FirstName:     stringPtr("Abebe"),
+		LastName:      stringPtr("Kebede"),
+		Email:         stringPtr("abebe@example.com"),
+		EmailVerified: true,
+		Workspaces:    []domain.WorkspaceMembership{{WorkspaceID: domain.DefaultWorkspaceID, Role: "ADMIN"}},
+	}
+	suite.member = domain.User{
+		UserId:        "user1",
+		FirstName:     stringPtr("Bisrat"),
+		LastName:      stringPtr("Berhanu"),
+		Email:         stringPtr("bisrat@example.com"),
+		Phone:         stringPtr("0911"),
+		EmailVerified: true,
+		Workspaces:    []domain.WorkspaceMembership{{WorkspaceID: domain.DefaultWorkspaceID, Role: "USER"}},
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+// as returns the caller the controller makes of user's token.
+func (suite *HandlerTestSuite) as(user domain.User) Caller {
+	role, _ := user.Role(domain.DefaultWorkspaceID)
+	return Caller{UID: user.UserId, Role: role, Workspace: domain.DefaultWorkspaceID}
+}
+
+type gqlError struct {
+	Message    string         `json:"message"`
+	Extensions map[string]any `json:"extensions"`
+}
+
+// query runs document as caller, decodes the data into data and returns the
+// errors.
+func (suite *HandlerTestSuite) query(caller Caller, document string, variables map[string]any, data any) []gqlError {
+	body, err := json.Marshal(map[string]any{"query": document, "variables": variables})
+	suite.Require().NoError(err)
+	request := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	ctx := domain.WithWorkspace(context.Background(), caller.Workspace)
+	request = request.WithContext(WithCaller(ctx, caller))
+
+	recorder := httptest.NewRecorder()
+	suite.handler.ServeHTTP(recorder, request)
+
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []gqlError      `json:"errors"`
+	}
+	suite.Require().NoError(json.Unmarshal(recorder.Body.Bytes(), &response), recorder.Body.String())
+	if data != nil && len(response.Data) > 0 && string(response.Data) != "null" {
+		suite.Require().NoError(json.Unmarshal(response.Data, data))
+	}
+	return response.Errors
+}
+
+// assertCode asserts that there is a single error with code.
+func (suite *HandlerTestSuite) assertCode(code string, errs []gqlError) {
+	if suite.Len(errs, 1) {
+		suite.Equal(code, errs[0].Extensions["code"], errs[0].Message)
+	}
+}
+
+func (suite *HandlerTestSuite) TestCreatorsAreLoadedInOneBatch() {
+	tasks := make([]*domain.Task, 0, 30)
+	for i := range 30 {
+		creator := []string{"admin1", "user1", "gone"}[i%3]
+		tasks = append(tasks, &domain.Task{ID: fmt.Sprint(i), Title: "Task", CreatedBy: creator})
+	}
+	suite.tasks.On("GetTasks", mock.Anything).Return(tasks, nil)
+	suite.users.On("GetMembers", mock.Anything, mock.MatchedBy(func(user_ids []string) bool {
+		return len(user_ids) == 3
+	})).Return([]domain.User{suite.admin, suite.member}, nil).Once()
+
+	var data struct {
+		Tasks struct {
+			Nodes []struct {
+				CreatedByID string `json:"createdById"`
+				CreatedBy   *struct {
+					ID    string  `json:"id"`
+					Email *string `json:"email"`
+				} `json:"createdBy"`
+			} `json:"nodes"`
+		} `json:"tasks"`
+	}
+	errs := suite.query(suite.as(suite.member), `{ tasks(limit: 30) { nodes { createdById createdBy { id email } } } }`, nil, &data)
+	suite.Require().Empty(errs)
+	suite.Require().Len(data.Tasks.Nodes, 30)
+	suite.users.AssertNumberOfCalls(suite.T(), "GetMembers", 1)
+
+	for _, node := range data.Tasks.Nodes {
+		switch node.CreatedByID {
+		case "gone":
+			suite.Nil(node.CreatedBy)
+		case "admin1":
+			// Members only see the name of the others
+			suite.Require().NotNil(node.CreatedBy)
+			suite.Nil(node.CreatedBy.Email)
+		case "user1":
+			suite.Require().NotNil(node.CreatedBy)
+			suite.Equal("bisrat@example.com", *node.CreatedBy.Email)
+		}
+	}
+}
+
+func (suite *HandlerTestSuite) TestRejectsQueriesOverTheComplexityLimit() {
+	var aliases strings.Builder
+	for i := range 5 {
+		fmt.Fprintf(&aliases, "page%d: tasks(limit: 100) { nodes { id title createdBy { id firstName } } } ", i)
+	}
+
+	errs := suite.query(suite.as(suite.admin), "{ "+aliases.String()+"}", nil, nil)
+	suite.assertCode("COMPLEXITY_LIMIT_EXCEEDED", errs)
+	suite.tasks.AssertNotCalled(suite.T(), "GetTasks", mock.Anything)
+}
+
+func (suite *HandlerTestSuite) TestInternalErrorsHideTheCause() {
+	suite.tasks.On("GetTasksById", mock.Anything, "1").Return(nil, fmt.Errorf("connection refused to mongo-0"))
+
+	errs := suite.query(suite.as(suite.member), `{ task(id: "1") { id } }`, nil, nil)
+	suite.assertCode(codeInternal, errs)
+	suite.NotContains(errs[0].Message, "mongo-0")
+}
+
+func (suite *HandlerTestSuite) TestResolversGetTheRequestContext() {
+	suite.tasks.On("GetTasks", mock.MatchedBy(func(ctx context.Context) bool {
+		workspace_id, err := domain.WorkspaceFrom(ctx)
+		return err == nil && workspace_id == domain.DefaultWorkspaceID
+	})).Return([]*domain.Task{}, nil)
+
+	errs := suite.query(suite.as(suite.member), `{ tasks { totalCount } }`, nil, nil)
+	suite.Empty(errs)
+}
+
+func TestHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"context"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"github.com/vikstrous/dataloadgen"
+)
+
+type loadersKey struct{}
+
+// loaders batch the lookups the resolvers of one request make, so a page of
+// tasks fetches its creators in a single query. They live for one request
+// and cache what they fetched for its lifetime.
+type loaders struct {
+	members *dataloadgen.Loader[string, *domain.User]
+}
+
+// loaderWait is how long a loader collects keys before it fetches them.
+const loaderWait = 2 * time.Millisecond
+
+func withLoaders(ctx context.Context, users domain.UserUseCase) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaders{
+		members: dataloadgen.NewLoader(fetchMembers(users), dataloadgen.WithWait(loaderWait)),
+	})
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// fetchMembers returns the members with the IDs, in their order. Users who
+// are not members of the workspace are nil.
+func fetchMembers(users domain.UserUseCase) func(ctx context.Context, user_ids []string) ([]*domain.User, []error) {
+	return func(ctx context.Context, user_ids []string) ([]*domain.User, []error) {
+		found, err := users.GetMembers(ctx, user_ids)
+		if err != nil {
+			return nil, []error{err}
+		}
+		byID := make(map[string]*domain.User, len(found))
+		for i := range found {
+			byID[found[i].UserId] = &found[i]
+		}
+		members := make([]*domain.User, len(user_ids))
+		for i, user_id := range user_ids {
+			members[i] = byID[user_id]
+		}
+		return members, nil
+	}
+}
@@ -0,0 +1,53 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package model
+
+import (
+	"task_manger_clean_architecture/domain"
+	"time"
+)
+
+type Mutation struct {
+}
+
+type Query struct {
+}
+
+// Tasks match a filter when they match all of its fields that are set.
+type TaskFilter struct {
+	Status      *string `json:"status,omitempty"`
+	CreatedByID *string `json:"createdById,omitempty"`
+	// Tasks due at or after this time.
+	DueAfter *time.Time `json:"dueAfter,omitempty"`
+	// Tasks due before this time.
+	DueBefore *time.Time `json:"dueBefore,omitempty"`
+}
+
+type TaskInput struct {
+	Title       string     `json:"title"`
+	Description *string    `json:"description,omitempty"`
+	DueDate     *time.Time `json:"dueDate,omitempty"`
+	Status      *string    `json:"status,omitempty"`
+}
+
+type TaskPage struct {
+	Nodes []*domain.Task `json:"nodes"`
+	// Tasks matching the filter across all pages.
+	TotalCount  int  `json:"totalCount"`
+	HasNextPage bool `json:"hasNextPage"`
+}
+
+// A member of the workspace. Administrators and the user themself see every
+// field; other members only see the ID and the name, the rest being null.
+type User struct {
+	ID        string  `json:"id"`
+	FirstName string  `json:"firstName"`
+	LastName  string  `json:"lastName"`
+	Email     *string `json:"email,omitempty"`
+	Phone     *string `json:"phone,omitempty"`
+	// The user's role in the workspace: ADMIN or USER.
+	UserType      *string    `json:"userType,omitempty"`
+	EmailVerified *bool      `json:"emailVerified,omitempty"`
+	CreatedAt     *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt     *time.Time `json:"updatedAt,omitempty"`
+}
@@ -0,0 +1,16 @@
+package graph
+
+import (
+	"log/slog"
+	"task_manger_clean_architecture/domain"
+)
+
+//go:generate go run github.com/99designs/gqlgen generate
+
+// Resolver is the root of the resolvers. It runs on the use cases of the
+// REST API; the caller and the loaders of a request are in its context.
+type Resolver struct {
+	TaskUseCase domain.TaskUsecase
+	UserUseCase domain.UserUseCase
+	Logger      *slog.Logger
+}
@@ -0,0 +1,199 @@
+package graph
+
+import (
+	"errors"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type userData struct {
+	ID        string  `json:"id"`
+	FirstName string  `json:"firstName"`
+	Email     *string `json:"email"`
+	Phone     *string `json:"phone"`
+	UserType  *string `json:"userType"`
+}
+
+const userFields = `id firstName email phone userType`
+
+func (suite *HandlerTestSuite) TestMe() {
+	suite.users.On("GetUser", mock.Anything, "user1").Return(suite.member, nil)
+
+	var data struct{ Me userData }
+	errs := suite.query(suite.as(suite.member), `{ me { `+userFields+` } }`, nil, &data)
+	suite.Require().Empty(errs)
+	suite.Equal("user1", data.Me.ID)
+	suite.Equal("bisrat@example.com", *data.Me.Email)
+	suite.Equal("USER", *data.Me.UserType)
+}
+
+func (suite *HandlerTestSuite) TestUserViews() {
+	suite.users.On("GetUser", mock.Anything, "user1").Return(suite.member, nil)
+	suite.users.On("GetMember", mock.Anything, "user1").Return(suite.member, nil)
+	suite.users.On("GetMember", mock.Anything, "admin1").Return(suite.admin, nil)
+	suite.users.On("GetMember", mock.Anything, "gone").Return(domain.User{}, domain.ErrNotWorkspaceMember)
+
+	var data struct{ User *userData }
+	document := `query($id: ID!) { user(id: $id) { ` + userFields + ` } }`
+	suite.Require().Empty(suite.query(suite.as(suite.member), document, map[string]any{"id": "user1"}, &data))
+	suite.Equal("0911", *data.User.Phone)
+
+	data.User = nil
+	suite.Require().Empty(suite.query(suite.as(suite.member), document, map[string]any{"id": "admin1"}, &data))
+	suite.Equal(&userData{ID: "admin1", FirstName: "Abebe"}, data.User)
+
+	data.User = nil
+	suite.Require().Empty(suite.query(suite.as(suite.admin), document, map[string]any{"id": "user1"}, &data))
+	suite.Equal("0911", *data.User.Phone)
+
+	data.User = &userData{}
+	suite.Require().Empty(suite.query(suite.as(suite.member), document, map[string]any{"id": "gone"}, &data))
+	suite.Nil(data.User)
+}
+
+func (suite *HandlerTestSuite) TestUsersAreForAdministrators() {
+	suite.users.On("GetUsers", mock.Anything, int64(5), int64(5)).Return([]*domain.User{&suite.member}, nil)
+
+	errs := suite.query(suite.as(suite.member), `{ users { id } }`, nil, nil)
+	suite.assertCode(codeForbidden, errs)
+
+	var data struct{ Users []userData }
+	errs = suite.query(suite.as(suite.admin), `{ users(page: 2, recordsPerPage: 5) { `+userFields+` } }`, nil, &data)
+	suite.Require().Empty(errs)
+	suite.Require().Len(data.Users, 1)
+	suite.Equal("USER", *data.Users[0].UserType)
+}
+
+func (suite *HandlerTestSuite) TestTasksAreFilteredAndPaged() {
+	day := func(d int) time.Time { return time.Date(2024, 8, d, 0, 0, 0, 0, time.UTC) }
+	suite.tasks.On("GetTasks", mock.Anything).Return([]*domain.Task{
+		{ID: "4", DueDate: day(4), Status: "pending", CreatedBy: "user1"},
+		{ID: "1", DueDate: day(1), Status: "pending", CreatedBy: "user1"},
+		{ID: "3", DueDate: day(3), Status: "done", CreatedBy: "user1"},
+		{ID: "2", DueDate: day(2), Status: "pending", CreatedBy: "admin1"},
+		{ID: "5", DueDate: day(5), Status: "pending", CreatedBy: "user1"},
+	}, nil)
+
+	type page struct {
+		Tasks struct {
+			Nodes       []struct{ ID string }
+			TotalCount  int
+			HasNextPage bool
+		}
+	}
+	document := `query($filter: TaskFilter, $offset: Int, $limit: Int) {
+		tasks(filter: $filter, offset: $offset, limit: $limit) { nodes { id } totalCount hasNextPage }
+	}`
+	filter := map[string]any{"status": "pending", "createdById": "user1", "dueBefore": day(5)}
+
+	var first page
+	suite.Require().Empty(suite.query(suite.as(suite.member), document, map[string]any{"filter": filter, "limit": 1}, &first))
+	suite.Equal(2, first.Tasks.TotalCount)
+	suite.True(first.Tasks.HasNextPage)
+	suite.Require().Len(first.Tasks.Nodes, 1)
+	suite.Equal("1", first.Tasks.Nodes[0].ID)
+
+	var second page
+	suite.Require().Empty(suite.query(suite.as(suite.member), document, map[string]any{"filter": filter, "offset": 1, "limit": 1}, &second))
+	suite.False(second.Tasks.HasNextPage)
+	suite.Require().Len(second.Tasks.Nodes, 1)
+	suite.Equal("4", second.Tasks.Nodes[0].ID)
+
+	var all page
+	suite.Require().Empty(suite.query(suite.as(suite.member), document, map[string]any{"offset": 3}, &all))
+	suite.Equal(5, all.Tasks.TotalCount)
+	suite.Len(all.Tasks.Nodes, 2)
+
+	errs := suite.query(suite.as(suite.member), document, map[string]any{"limit": 0}, nil)
+	suite.assertCode(codeInvalid, errs)
+}
+
+func (suite *HandlerTestSuite) TestMissingTaskIsNull() {
+	suite.tasks.On("GetTasksById", mock.Anything, "2").Return(nil, mongo.ErrNoDocuments)
+
+	data := struct{ Task *struct{ ID string } }{Task: &struct{ ID string }{}}
+	suite.Require().Empty(suite.query(suite.as(suite.member), `{ task(id: "2") { id } }`, nil, &data))
+	suite.Nil(data.Task)
+}
+
+func (suite *HandlerTestSuite) TestMutationsAreForAdministrators() {
+	for _, document := range []string{
+		`mutation { addTask(input: {title: "Task"}) { id } }`,
+		`mutation { updateTask(id: "1", input: {title: "Task"}) { id } }`,
+		`mutation { deleteTask(id: "1") }`,
+		`mutation { promoteUser(id: "user1") { id } }`,
+	} {
+		errs := suite.query(suite.as(suite.member), document, nil, nil)
+		suite.assertCode(codeForbidden, errs)
+	}
+	suite.Empty(suite.tasks.Calls)
+	suite.Empty(suite.users.Calls)
+}
+
+func (suite *HandlerTestSuite) TestAddTaskSetsCreatorAndWorkspace() {
+	var added domain.Task
+	suite.tasks.On("AddTask", mock.Anything, mock.AnythingOfType("domain.Task")).
+		Run(func(args mock.Arguments) { added = args.Get(1).(domain.Task) }).
+		Return(nil)
+	suite.tasks.On("GetTasksById", mock.Anything, mock.Anything).Return(nil, errors.New("timeout"))
+
+	var data struct {
+		AddTask struct {
+			ID          string
+			CreatedByID string `json:"createdById"`
+			Status      string
+		}
+	}
+	errs := suite.query(suite.as(suite.admin), `mutation { addTask(input: {title: "Task", status: "pending"}) { id createdById status } }`, nil, &data)
+	suite.Require().Empty(errs)
+	suite.NotEmpty(added.ID)
+	suite.Equal("admin1", added.CreatedBy)
+	suite.Equal(domain.DefaultWorkspaceID, added.WorkspaceID)
+	suite.Equal(added.ID, data.AddTask.ID)
+	suite.Equal("pending", data.AddTask.Status)
+}
+
+func (suite *HandlerTestSuite) TestUpdateAndDeleteTasks() {
+	updated := &domain.Task{ID: "1", Title: "Renamed", Status: "done"}
+	suite.tasks.On("UpdateTask", mock.Anything, "1", domain.Task{Title: "Renamed", Status: "done"}).Return(nil)
+	suite.tasks.On("UpdateTask", mock.Anything, "2", mock.Anything).Return(mongo.ErrNoDocuments)
+	suite.tasks.On("GetTasksById", mock.Anything, "1").Return(updated, nil)
+	suite.tasks.On("DeleteById", mock.Anything, "1").Return(int64(1), nil)
+	suite.tasks.On("DeleteById", mock.Anything, "2").Return(int64(0), nil)
+
+	var data struct{ UpdateTask struct{ Title string } }
+	errs := suite.query(suite.as(suite.admin), `mutation { updateTask(id: "1", input: {title: "Renamed", status: "done"}) { title } }`, nil, &data)
+	suite.Require().Empty(errs)
+	suite.Equal("Renamed", data.UpdateTask.Title)
+
+	errs = suite.query(suite.as(suite.admin), `mutation { updateTask(id: "2", input: {title: "Renamed"}) { title } }`, nil, nil)
+	suite.assertCode(codeNotFound, errs)
+
+	var deleted struct{ DeleteTask bool }
+	suite.Require().Empty(suite.query(suite.as(suite.admin), `mutation { deleteTask(id: "1") }`, nil, &deleted))
+	suite.True(deleted.DeleteTask)
+	suite.Require().Empty(suite.query(suite.as(suite.admin), `mutation { deleteTask(id: "2") }`, nil, &deleted))
+	suite.False(deleted.DeleteTask)
+}
+
+func (suite *HandlerTestSuite) TestPromoteUser() {
+	promoted := suite.member
+	promoted.Workspaces = []domain.WorkspaceMembership{{WorkspaceID: domain.DefaultWorkspaceID, Role: "ADMIN"}}
+	suite.users.On("Promote", mock.Anything, "user1", "ADMIN").Return(nil, int64(1), int64(1))
+	suite.users.On("Promote", mock.Anything, "admin1", "ADMIN").Return(nil, int64(1), int64(0))
+	suite.users.On("Promote", mock.Anything, "gone", "ADMIN").Return(nil, int64(0), int64(0))
+	suite.users.On("GetMember", mock.Anything, "user1").Return(promoted, nil)
+
+	var data struct{ PromoteUser userData }
+	errs := suite.query(suite.as(suite.admin), `mutation { promoteUser(id: "user1") { `+userFields+` } }`, nil, &data)
+	suite.Require().Empty(errs)
+	suite.Equal("ADMIN", *data.PromoteUser.UserType)
+
+	errs = suite.query(suite.as(suite.admin), `mutation { promoteUser(id: "admin1") { id } }`, nil, nil)
+	suite.assertCode(codeConflict, errs)
+	errs = suite.query(suite.as(suite.admin), `mutation { promoteUser(id: "gone") { id } }`, nil, nil)
+	suite.assertCode(codeNotFound, errs)
+}
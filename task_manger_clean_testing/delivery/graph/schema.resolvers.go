@@ -0,0 +1,195 @@
+package graph
+
+import (
+	"context"
+	"task_manger_clean_architecture/delivery/graph/model"
+	"task_manger_clean_architecture/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AddTask is the resolver for the addTask field.
+func (r *mutationResolver) AddTask(ctx context.Context, input model.TaskInput, id *string) (*domain.Task, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	newTask := toTask(input)
+	if id != nil && *id != "" {
+		newTask.ID = *id
+	} else {
+		newTask.ID = primitive.NewObjectID().Hex()
+	}
+	newTask.CreatedBy = callerFrom(ctx).UID
+	newTask.WorkspaceID = callerFrom(ctx).Workspace
+	if err := r.TaskUseCase.AddTask(ctx, newTask); err != nil {
+		return nil, toError(ctx, r.Logger, "failed to add task", err)
+	}
+
+	// The stored task has the creation time; the one sent will do without it
+	if stored, err := r.TaskUseCase.GetTasksById(ctx, newTask.ID); err == nil && stored != nil {
+		return stored, nil
+	}
+	return &newTask, nil
+}
+
+// UpdateTask is the resolver for the updateTask field.
+func (r *mutationResolver) UpdateTask(ctx context.Context, id string, input model.TaskInput) (*domain.Task, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := r.TaskUseCase.UpdateTask(ctx, id, toTask(input)); err != nil {
+		return nil, toError(ctx, r.Logger, "failed to update task", err)
+	}
+	task, err := r.TaskUseCase.GetTasksById(ctx, id)
+	if err != nil {
+		return nil, toError(ctx, r.Logger, "failed to retrieve task", err)
+	}
+	return task, nil
+}
+
+// DeleteTask is the resolver for the deleteTask field.
+func (r *mutationResolver) DeleteTask(ctx context.Context, id string) (bool, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return false, err
+	}
+
+	deletedCount, err := r.TaskUseCase.DeleteById(ctx, id)
+	if err != nil {
+		return false, toError(ctx, r.Logger, "failed to delete task", err)
+	}
+	return deletedCount > 0, nil
+}
+
+// PromoteUser is the resolver for the promoteUser field.
+func (r *mutationResolver) PromoteUser(ctx context.Context, id string) (*model.User, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	err, matchedCount, modifiedCount := r.UserUseCase.Promote(ctx, id, "ADMIN")
+	if err != nil {
+		return nil, toError(ctx, r.Logger, "error updating user type", err)
+	}
+	if matchedCount == 0 {
+		return nil, newError(ctx, codeNotFound, "user not found")
+	}
+	if modifiedCount == 0 {
+		return nil, newError(ctx, codeConflict, "user is already an administrator")
+	}
+
+	user, err := r.UserUseCase.GetMember(ctx, id)
+	if err != nil {
+		return nil, toError(ctx, r.Logger, "failed to retrieve user", err)
+	}
+	return toUser(user, callerFrom(ctx).Workspace, true), nil
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
+	caller := callerFrom(ctx)
+	user, err := r.UserUseCase.GetUser(ctx, caller.UID)
+	if err != nil {
+		return nil, toError(ctx, r.Logger, "failed to retrieve user", err)
+	}
+	return toUser(user, caller.Workspace, true), nil
+}
+
+// Task is the resolver for the task field.
+func (r *queryResolver) Task(ctx context.Context, id string) (*domain.Task, error) {
+	task, err := r.TaskUseCase.GetTasksById(ctx, id)
+	if isMissing(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, toError(ctx, r.Logger, "failed to retrieve task", err)
+	}
+	return task, nil
+}
+
+// Tasks is the resolver for the tasks field.
+func (r *queryResolver) Tasks(ctx context.Context, filter *model.TaskFilter, offset *int, limit *int) (*model.TaskPage, error) {
+	if offset != nil && *offset < 0 {
+		return nil, newError(ctx, codeInvalid, "offset must not be negative")
+	}
+	if limit != nil && *limit < 1 {
+		return nil, newError(ctx, codeInvalid, "limit must be positive")
+	}
+
+	tasks, err := r.TaskUseCase.GetTasks(ctx)
+	if err != nil {
+		return nil, toError(ctx, r.Logger, "failed to retrieve tasks", err)
+	}
+	// The creators of a page are fetched together when they are asked for
+	page := taskPage(tasks, filter, intValue(offset), pageSize(limit, defaultTasksPage))
+	return page, nil
+}
+
+// User is the resolver for the user field.
+func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error) {
+	caller := callerFrom(ctx)
+	self := id == caller.UID
+
+	var user domain.User
+	var err error
+	if self {
+		user, err = r.UserUseCase.GetUser(ctx, id)
+	} else {
+		user, err = r.UserUseCase.GetMember(ctx, id)
+	}
+	if isMissing(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, toError(ctx, r.Logger, "failed to retrieve user", err)
+	}
+	return toUser(user, caller.Workspace, canSeeAll(caller, id)), nil
+}
+
+// Users is the resolver for the users field.
+func (r *queryResolver) Users(ctx context.Context, page *int, recordsPerPage *int) ([]*model.User, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	recordsPerPage = ptr(pageSize(recordsPerPage, defaultMembersPage))
+	if page == nil || *page < 1 {
+		page = ptr(1)
+	}
+
+	users, err := r.UserUseCase.GetUsers(ctx, int64((*page-1)**recordsPerPage), int64(*recordsPerPage))
+	if err != nil {
+		return nil, toError(ctx, r.Logger, "failed to list users", err)
+	}
+	members := make([]*model.User, 0, len(users))
+	for _, user := range users {
+		members = append(members, toUser(*user, callerFrom(ctx).Workspace, true))
+	}
+	return members, nil
+}
+
+// CreatedBy is the resolver for the createdBy field.
+func (r *taskResolver) CreatedBy(ctx context.Context, obj *domain.Task) (*model.User, error) {
+	member, err := loadersFrom(ctx).members.Load(ctx, obj.CreatedBy)
+	if err != nil {
+		return nil, toError(ctx, r.Logger, "failed to retrieve user", err)
+	}
+	if member == nil {
+		return nil, nil
+	}
+	caller := callerFrom(ctx)
+	return toUser(*member, caller.Workspace, canSeeAll(caller, member.UserId)), nil
+}
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+// Task returns TaskResolver implementation.
+func (r *Resolver) Task() TaskResolver { return &taskResolver{r} }
+
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+type taskResolver struct{ *Resolver }
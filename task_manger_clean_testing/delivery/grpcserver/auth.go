@@ -0,0 +1,195 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/infrastructure"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type callerKey struct{}
+
+// caller is the signed in user making a call, with their current role in the
+// workspace their token acts in.
+type caller struct {
+	uid       string
+	role      string
+	workspace string
+}
+
+func callerFrom(ctx context.Context) caller {
+	c, _ := ctx.Value(callerKey{}).(caller)
+	return c
+}
+
+// requireAdmin rejects callers who are not administrators of the workspace.
+func requireAdmin(ctx context.Context) error {
+	if callerFrom(ctx).role != "ADMIN" {
+		return status.Error(codes.PermissionDenied, "unauthorized to access")
+	}
+	return nil
+}
+
+// streamRecheckInterval is how often an open stream checks again that its
+// token was not revoked and its user still belongs to the workspace.
+const streamRecheckInterval = time.Minute
+
+// authenticator checks the bearer token of every call like the middleware of
+// the REST routes that need a workspace: Authenticate, RejectRevokedTokens,
+// RequireVerifiedEmail, RequireWorkspace and RequireAdminMFA. Personal access
+// tokens are not accepted. The tokens it refuses are counted in metrics.
+// Streams are checked again while they are open and end with the token.
+type authenticator struct {
+	signer       *infrastructure.JWT
+	users        domain.UserUseCase
+	metrics      *infrastructure.Metrics
+	recheckEvery time.Duration
+}
+
+func (a *authenticator) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, _, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *authenticator) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, claims, err := a.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+
+	// A stream can outlive its token, and the user can be signed out or
+	// removed from the workspace while it is open
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	if claims.ExpiresAt != 0 {
+		var stop context.CancelFunc
+		ctx, stop = context.WithDeadlineCause(ctx, time.Unix(claims.ExpiresAt, 0), status.Error(codes.Unauthenticated, "token has expired"))
+		defer stop()
+	}
+	go a.recheck(ctx, cancel, claims)
+
+	err = handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	// Tell the client why the stream ended rather than that it was cancelled
+	if cause := context.Cause(ctx); cause != nil {
+		if _, ok := status.FromError(cause); ok {
+			return cause
+		}
+	}
+	return err
+}
+
+// recheck cancels ctx with the reason once claims no longer pass check.
+func (a *authenticator) recheck(ctx context.Context, cancel context.CancelCauseFunc, claims *infrastructure.SignedDetails) {
+	ticker := time.NewTicker(a.recheckEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := a.check(ctx, claims); err != nil {
+				cancel(err)
+				return
+			}
+		}
+	}
+}
+
+// authenticate returns ctx with the caller, the audit actor and the
+// workspace of the call, which scopes tenant data, and the claims of the
+// token.
+func (a *authenticator) authenticate(ctx context.Context) (context.Context, *infrastructure.SignedDetails, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 || values[0] == "" {
+		a.metrics.ObserveTokenRejected(infrastructure.TokenMissing)
+		return nil, nil, status.Error(codes.Unauthenticated, "not Authorized")
+	}
+
+	// Remove "Bearer " prefix from the token string
+	tokenParts := strings.Split(values[0], " ")
+	if len(tokenParts) != 2 {
+		a.metrics.ObserveTokenRejected(infrastructure.TokenMalformed)
+		return nil, nil, status.Error(codes.Unauthenticated, "invalid token format")
+	}
+
+	claims, msg := a.signer.ValidateToken(tokenParts[1])
+	if msg != "" {
+		if strings.Contains(msg, "expired") {
+			a.metrics.ObserveTokenRejected(infrastructure.TokenExpired)
+		} else {
+			a.metrics.ObserveTokenRejected(infrastructure.TokenInvalid)
+		}
+		return nil, nil, status.Error(codes.Unauthenticated, msg)
+	}
+	if claims.Refresh {
+		a.metrics.ObserveTokenRejected(infrastructure.TokenInvalid)
+		return nil, nil, status.Error(codes.Unauthenticated, "refresh tokens cannot authorize requests")
+	}
+
+	who, err := a.check(ctx, claims)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	actor := domain.AuditActor{UID: claims.Uid, ClientIP: clientIP(ctx)}
+	if agents := md.Get("user-agent"); len(agents) > 0 {
+		actor.UserAgent = agents[0]
+	}
+	ctx = context.WithValue(ctx, callerKey{}, who)
+	return domain.WithWorkspace(domain.WithAuditActor(ctx, actor), who.workspace), claims, nil
+}
+
+// check returns the caller claims are the token of, unless it was revoked
+// or does not let its user into its workspace.
+func (a *authenticator) check(ctx context.Context, claims *infrastructure.SignedDetails) (caller, error) {
+	user, err := a.users.GetUser(ctx, claims.Uid)
+	if err != nil {
+		a.metrics.ObserveTokenRejected(infrastructure.TokenInvalid)
+		return caller{}, status.Error(codes.Unauthenticated, "not Authorized")
+	}
+	if user.TokenVersion != claims.TokenVersion {
+		a.metrics.ObserveTokenRejected(infrastructure.TokenRevoked)
+		return caller{}, status.Error(codes.Unauthenticated, "token has been revoked")
+	}
+	if !user.EmailVerified {
+		return caller{}, status.Error(codes.PermissionDenied, "email address is not verified")
+	}
+
+	workspace := claims.WorkspaceID
+	if workspace == "" {
+		workspace = domain.DefaultWorkspaceID
+	}
+	role, ok := user.Role(workspace)
+	if !ok {
+		return caller{}, status.Error(codes.PermissionDenied, domain.ErrNotWorkspaceMember.Error())
+	}
+	if role == "ADMIN" && !claims.MFA {
+		return caller{}, status.Error(codes.PermissionDenied, "administrators must sign in with two-factor authentication")
+	}
+	return caller{uid: claims.Uid, role: role, workspace: workspace}, nil
+}
+
+// clientIP returns the address the call came from, without the port.
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -0,0 +1,153 @@
+package grpcserver
+
+import (
+	"context"
+	"strings"
+	"task_manger_clean_architecture/delivery/grpcserver/pb"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/infrastructure"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func (suite *ServerTestSuite) TestRejectsMissingAndMalformedTokens() {
+	_, err := suite.taskClient.ListTasks(context.Background(), &pb.ListTasksRequest{})
+	suite.assertCode(codes.Unauthenticated, err)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "token-without-scheme")
+	_, err = suite.taskClient.ListTasks(ctx, &pb.ListTasksRequest{})
+	suite.assertCode(codes.Unauthenticated, err)
+
+	_, err = suite.taskClient.ListTasks(suite.withToken("not-a-jwt"), &pb.ListTasksRequest{})
+	suite.assertCode(codes.Unauthenticated, err)
+	suite.tasks.AssertNotCalled(suite.T(), "GetTasks", mock.Anything)
+}
+
+func (suite *ServerTestSuite) TestRejectsRefreshTokens() {
+	role := "USER"
+	_, refreshToken, err := infrastructure.NewJWT(secretKey).GenerateAllTokens(*suite.member.Email, suite.member.FirstName, suite.member.LastName, &role, &suite.member.UserId, true, 0, false, domain.DefaultWorkspaceID)
+	suite.Require().NoError(err)
+
+	_, err = suite.taskClient.ListTasks(suite.withToken(refreshToken), &pb.ListTasksRequest{})
+	suite.assertCode(codes.Unauthenticated, err)
+}
+
+func (suite *ServerTestSuite) TestRejectsRevokedTokens() {
+	token := suite.token(suite.member, false, domain.DefaultWorkspaceID)
+	revoked := suite.member
+	revoked.TokenVersion = 1
+	suite.users.ExpectedCalls = nil
+	suite.users.On("GetUser", mock.Anything, "user1").Return(revoked, nil)
+
+	_, err := suite.taskClient.ListTasks(suite.withToken(token), &pb.ListTasksRequest{})
+	suite.assertCode(codes.Unauthenticated, err)
+}
+
+func (suite *ServerTestSuite) TestRequiresVerifiedMembersAndAdminMFA() {
+	// The stored flag counts, not the one in the token
+	unverified := suite.member
+	unverified.UserId = "user4"
+	unverified.EmailVerified = false
+	suite.users.On("GetUser", mock.Anything, "user4").Return(unverified, nil)
+	claimed := unverified
+	claimed.EmailVerified = true
+	_, err := suite.taskClient.ListTasks(suite.withToken(suite.token(claimed, false, domain.DefaultWorkspaceID)), &pb.ListTasksRequest{})
+	suite.assertCode(codes.PermissionDenied, err)
+
+	_, err = suite.taskClient.ListTasks(suite.withToken(suite.token(suite.member, false, "other")), &pb.ListTasksRequest{})
+	suite.assertCode(codes.PermissionDenied, err)
+
+	_, err = suite.taskClient.ListTasks(suite.withToken(suite.token(suite.admin, false, domain.DefaultWorkspaceID)), &pb.ListTasksRequest{})
+	suite.assertCode(codes.PermissionDenied, err)
+	suite.tasks.AssertNotCalled(suite.T(), "GetTasks", mock.Anything)
+}
+
+func (suite *ServerTestSuite) TestUsesTheRoleInTheWorkspace() {
+	// The token was issued while the user was an administrator
+	token := suite.token(suite.admin, true, domain.DefaultWorkspaceID)
+	demoted := suite.admin
+	demoted.Workspaces = []domain.WorkspaceMembership{{WorkspaceID: domain.DefaultWorkspaceID, Role: "USER"}}
+	suite.users.ExpectedCalls = nil
+	suite.users.On("GetUser", mock.Anything, "admin1").Return(demoted, nil)
+
+	_, err := suite.taskClient.DeleteTask(suite.withToken(token), &pb.DeleteTaskRequest{Id: "1"})
+	suite.assertCode(codes.PermissionDenied, err)
+	suite.tasks.AssertNotCalled(suite.T(), "DeleteById", mock.Anything, mock.Anything)
+}
+
+func (suite *ServerTestSuite) TestCallsCarryTheCallerAndWorkspace() {
+	suite.tasks.On("GetTasks", mock.MatchedBy(func(ctx context.Context) bool {
+		workspace_id, err := domain.WorkspaceFrom(ctx)
+		actor := domain.AuditActorFrom(ctx)
+		_, hasDeadline := ctx.Deadline()
+		return err == nil && workspace_id == domain.DefaultWorkspaceID &&
+			actor.UID == "user1" && actor.ClientIP != "" && strings.HasPrefix(actor.UserAgent, "taskctl/test") &&
+			domain.RequestIDFrom(ctx) != "" && hasDeadline
+	})).Return([]*domain.Task{}, nil)
+
+	var header metadata.MD
+	_, err := suite.taskClient.ListTasks(suite.as(suite.member), &pb.ListTasksRequest{}, grpc.Header(&header))
+	suite.NoError(err)
+	suite.NotEmpty(header.Get(domain.RequestIDHeader))
+}
+
+func (suite *ServerTestSuite) TestStreamsAreAuthenticated() {
+	stream, err := suite.taskClient.WatchTasks(context.Background(), &pb.WatchTasksRequest{})
+	suite.Require().NoError(err)
+	_, err = stream.Recv()
+	suite.assertCode(codes.Unauthenticated, err)
+}
+
+func (suite *ServerTestSuite) TestStreamsEndWhenTheTokenExpires() {
+	claims := &infrastructure.SignedDetails{
+		Email:          *suite.member.Email,
+		UserType:       "USER",
+		Uid:            suite.member.UserId,
+		EmailVerified:  true,
+		WorkspaceID:    domain.DefaultWorkspaceID,
+		StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(2 * time.Second).Unix()},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secretKey))
+	suite.Require().NoError(err)
+
+	stream, err := suite.taskClient.WatchTasks(suite.withToken(token), &pb.WatchTasksRequest{})
+	suite.Require().NoError(err)
+	_, err = stream.Recv()
+	suite.assertCode(codes.Unauthenticated, err)
+	suite.Contains(status.Convert(err).Message(), "expired")
+}
+
+func (suite *ServerTestSuite) TestStreamsEndWhenTheTokenIsRevoked() {
+	signedOut := suite.member
+	signedOut.UserId = "user2"
+	revoked := signedOut
+	revoked.TokenVersion++
+	suite.users.On("GetUser", mock.Anything, "user2").Return(signedOut, nil).Once()
+	suite.users.On("GetUser", mock.Anything, "user2").Return(revoked, nil)
+
+	stream, err := suite.taskClient.WatchTasks(suite.as(signedOut), &pb.WatchTasksRequest{})
+	suite.Require().NoError(err)
+	_, err = stream.Recv()
+	suite.assertCode(codes.Unauthenticated, err)
+	suite.Contains(status.Convert(err).Message(), "revoked")
+}
+
+func (suite *ServerTestSuite) TestStreamsEndWhenTheMemberIsRemoved() {
+	member := suite.member
+	member.UserId = "user3"
+	removed := member
+	removed.Workspaces = nil
+	suite.users.On("GetUser", mock.Anything, "user3").Return(member, nil).Once()
+	suite.users.On("GetUser", mock.Anything, "user3").Return(removed, nil)
+
+	stream, err := suite.taskClient.WatchTasks(suite.as(member), &pb.WatchTasksRequest{})
+	suite.Require().NoError(err)
+	_, err = stream.Recv()
+	suite.assertCode(codes.PermissionDenied, err)
+}
@@ -0,0 +1,106 @@
+package grpcserver
+
+import (
+	"task_manger_clean_architecture/delivery/grpcserver/pb"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// timestamp leaves unset times out of the message.
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// fromTimestamp returns the zero time for a missing timestamp.
+func fromTimestamp(t *timestamppb.Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.AsTime()
+}
+
+func toProtoTask(task *domain.Task) *pb.Task {
+	message := &pb.Task{
+		Id:          task.ID,
+		Title:       task.Title,
+		Description: task.Description,
+		DueDate:     timestamp(task.DueDate),
+		Status:      task.Status,
+		CreatedBy:   task.CreatedBy,
+		CreatedAt:   timestamp(task.CreatedAt),
+		WorkspaceId: task.WorkspaceID,
+	}
+	if task.CompletedAt != nil {
+		message.CompletedAt = timestamp(*task.CompletedAt)
+	}
+	return message
+}
+
+func toProtoTasks(tasks []*domain.Task) []*pb.Task {
+	messages := make([]*pb.Task, 0, len(tasks))
+	for _, task := range tasks {
+		messages = append(messages, toProtoTask(task))
+	}
+	return messages
+}
+
+// fromProtoTask returns the fields a client may set. The server sets the
+// creator, creation and completion times and the workspace.
+func fromProtoTask(message *pb.Task) domain.Task {
+	return domain.Task{
+		ID:          message.GetId(),
+		Title:       message.GetTitle(),
+		Description: message.GetDescription(),
+		DueDate:     fromTimestamp(message.GetDueDate()),
+		Status:      message.GetStatus(),
+	}
+}
+
+var eventTypes = map[string]pb.TaskEvent_Type{
+	domain.TaskCreated: pb.TaskEvent_CREATED,
+	domain.TaskUpdated: pb.TaskEvent_UPDATED,
+	domain.TaskDeleted: pb.TaskEvent_DELETED,
+}
+
+func toProtoEvent(event domain.TaskEvent) *pb.TaskEvent {
+	return &pb.TaskEvent{Type: eventTypes[event.Type], Task: toProtoTask(&event.Task)}
+}
+
+// toProtoUser copies the fields of the public view, and those of the full
+// view when full is set, like the REST user responses.
+func toProtoUser(user domain.User, full bool) *pb.User {
+	message := &pb.User{
+		UserId:    user.UserId,
+		FirstName: stringValue(user.FirstName),
+		LastName:  stringValue(user.LastName),
+	}
+	if full {
+		message.Email = stringValue(user.Email)
+		message.Phone = stringValue(user.Phone)
+		message.UserType = stringValue(user.UserType)
+		message.EmailVerified = user.EmailVerified
+		message.CreatedAt = timestamp(user.CreatedAt)
+		message.UpdatedAt = timestamp(user.UpdatedAt)
+	}
+	return message
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// inWorkspace returns user as seen from workspace_id: the user type is the
+// role there, not the one the account signed up with.
+func inWorkspace(user domain.User, workspace_id string) domain.User {
+	role, _ := user.Role(workspace_id)
+	user.UserType = &role
+	return user
+}
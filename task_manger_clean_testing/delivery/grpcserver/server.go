@@ -0,0 +1,170 @@
+// Package grpcserver serves the gRPC API described in proto/taskmanager.proto
+// on the same use cases as the REST API, with the same authorization rules.
+package grpcserver
+
+//go:generate protoc -I proto --go_out=pb --go_opt=paths=source_relative --go-grpc_out=pb --go-grpc_opt=paths=source_relative taskmanager.proto
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"task_manger_clean_architecture/delivery/grpcserver/pb"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/infrastructure"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Every unary call must be answered within the server's timeout unless the
+// client set an earlier deadline. Methods listed here get a budget of their
+// own. Streams last until the client cancels them.
+var methodTimeouts = map[string]time.Duration{
+	pb.TaskService_ReindexTasks_FullMethodName: 5 * time.Minute,
+}
+
+// NewServer returns a gRPC server of the task and user services. Every call
+// is traced, logged with its request ID and authenticated like the REST
+// routes behind middleware.Authenticate. The server is not listening yet.
+func NewServer(timeout time.Duration, tasks domain.TaskUsecase, users domain.UserUseCase, events domain.TaskEventBus, signer *infrastructure.JWT, metrics *infrastructure.Metrics, logger *slog.Logger, opts ...grpc.ServerOption) *grpc.Server {
+	auth := &authenticator{signer: signer, users: users, metrics: metrics, recheckEvery: streamRecheckInterval}
+	return newServer(timeout, tasks, users, events, auth, logger, opts...)
+}
+
+func newServer(timeout time.Duration, tasks domain.TaskUsecase, users domain.UserUseCase, events domain.TaskEventBus, auth *authenticator, logger *slog.Logger, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(logUnary(logger), recoverUnary(logger), timeoutUnary(timeout), auth.unary),
+		grpc.ChainStreamInterceptor(logStream(logger), recoverStream(logger), auth.stream),
+	}, opts...)
+	server := grpc.NewServer(opts...)
+	pb.RegisterTaskServiceServer(server, &taskServer{tasks: tasks, events: events, logger: logger})
+	pb.RegisterUserServiceServer(server, &userServer{users: users, logger: logger})
+	return server
+}
+
+// toStatus returns the status a handler answers err with. The errors the
+// client cannot act on are logged and answered with message alone.
+func toStatus(ctx context.Context, logger *slog.Logger, message string, err error) error {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return status.Error(codes.NotFound, message+": not found")
+	case errors.Is(err, domain.ErrNoWorkspace), errors.Is(err, domain.ErrNotWorkspaceMember):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return status.FromContextError(err).Err()
+	}
+	logger.ErrorContext(ctx, message, "error", err)
+	return status.Error(codes.Internal, message)
+}
+
+// timeoutUnary gives each unary call a single deadline, which every database
+// call it makes shares, unless the client's deadline is earlier.
+func timeoutUnary(budget time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		timeout := budget
+		if methodTimeout, ok := methodTimeouts[info.FullMethod]; ok {
+			timeout = methodTimeout
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// withRequestID takes the request ID from the x-request-id metadata or
+// generates one, and sends it back in the header.
+func withRequestID(ctx context.Context) context.Context {
+	var sent string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(domain.RequestIDHeader); len(values) > 0 {
+			sent = values[0]
+		}
+	}
+	request_id := domain.RequestIDOrNew(sent)
+	grpc.SetHeader(ctx, metadata.Pairs(domain.RequestIDHeader, request_id))
+	return domain.WithRequestID(ctx, request_id)
+}
+
+// logCall logs one JSON line per call once it is answered, at the level of
+// the access log for the matching HTTP status.
+func logCall(ctx context.Context, logger *slog.Logger, method string, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK:
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable, codes.Unimplemented:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+	attrs := []slog.Attr{
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+	}
+	logger.LogAttrs(ctx, level, "grpc call", attrs...)
+}
+
+func logUnary(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx = withRequestID(ctx)
+		resp, err := handler(ctx, req)
+		logCall(ctx, logger, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func logStream(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		ctx := withRequestID(ss.Context())
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, logger, info.FullMethod, start, err)
+		return err
+	}
+}
+
+// recoverUnary answers Internal to a handler that panicked and logs the panic.
+func recoverUnary(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				logger.ErrorContext(ctx, "handler panicked", "panic", p)
+				err = status.Error(codes.Internal, "internal server error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+func recoverStream(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				logger.ErrorContext(ss.Context(), "handler panicked", "panic", p)
+				err = status.Error(codes.Internal, "internal server error")
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// contextStream is a stream whose handler runs under ctx.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
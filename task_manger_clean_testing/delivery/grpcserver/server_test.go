@@ -0,0 +1,125 @@
+package grpcserver
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"task_manger_clean_architecture/delivery/grpcserver/pb"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"task_manger_clean_architecture/infrastructure"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const secretKey = "secret"
+
+// ServerTestSuite calls the server through an in-memory connection, with
+// the use cases mocked.
+type ServerTestSuite struct {
+	suite.Suite
+	tasks      *mocks.TaskUsecase
+	users      *mocks.UserUseCase
+	events     domain.TaskEventBus
+	taskClient pb.TaskServiceClient
+	userClient pb.UserServiceClient
+	admin      domain.User
+	member     domain.User
+}
+
+func (suite *ServerTestSuite) SetupTest() {
+	suite.tasks = new(mocks.TaskUsecase)
+	suite.users = new(mocks.UserUseCase)
+	suite.events = infrastructure.NewTaskEventBus(8)
+	suite.admin = domain.User{
+		UserId:        "admin1",
+		FirstName:     stringPtr("Abebe"),
+		LastName:      stringPtr("Kebede"),
+		Email:         stringPtr("abebe@example.com"),
+		EmailVerified: true,
+		Workspaces:    []domain.WorkspaceMembership{{WorkspaceID: domain.DefaultWorkspaceID, Role: "ADMIN"}},
+	}
+	suite.member = domain.User{
+		UserId:        "user1",
+		FirstName:     stringPtr("Bisrat"),
+		LastName:      stringPtr("Berhanu"),
+		Email:         stringPtr("bisrat@example.com"),
+		Phone:         stringPtr("0911"),
+		EmailVerified: true,
+		Workspaces:    []domain.WorkspaceMembership{{WorkspaceID: domain.DefaultWorkspaceID, Role: "USER"}},
+	}
+	// The interceptors look up the caller on every call
+	suite.users.On("GetUser", mock.Anything, "admin1").Return(suite.admin, nil).Maybe()
+	suite.users.On("GetUser", mock.Anything, "user1").Return(suite.member, nil).Maybe()
+	suite.serve(suite.events)
+}
+
+// serve starts a server publishing to events and connects the clients to it.
+func (suite *ServerTestSuite) serve(events domain.TaskEventBus) {
+	listener := bufconn.Listen(1 << 20)
+	metrics := infrastructure.NewMetrics(prometheus.NewRegistry())
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	// Open streams check their token far more often than in production
+	auth := &authenticator{signer: infrastructure.NewJWT(secretKey), users: suite.users, metrics: metrics, recheckEvery: 10 * time.Millisecond}
+	server := newServer(time.Second, suite.tasks, suite.users, events, auth, logger)
+	go server.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUserAgent("taskctl/test"),
+	)
+	suite.Require().NoError(err)
+	suite.T().Cleanup(func() {
+		conn.Close()
+		server.Stop()
+	})
+	suite.taskClient = pb.NewTaskServiceClient(conn)
+	suite.userClient = pb.NewUserServiceClient(conn)
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+// token returns an access token of user acting in workspace_id.
+func (suite *ServerTestSuite) token(user domain.User, mfa bool, workspace_id string) string {
+	role, _ := user.Role(workspace_id)
+	token, _, err := infrastructure.NewJWT(secretKey).GenerateAllTokens(*user.Email, user.FirstName, user.LastName, &role, &user.UserId, user.EmailVerified, user.TokenVersion, mfa, workspace_id)
+	suite.Require().NoError(err)
+	return token
+}
+
+// as returns a context calling as user in the default workspace, with a
+// second factor.
+func (suite *ServerTestSuite) as(user domain.User) context.Context {
+	return suite.withToken(suite.token(user, true, domain.DefaultWorkspaceID))
+}
+
+func (suite *ServerTestSuite) withToken(token string) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	suite.T().Cleanup(cancel)
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+// assertCode asserts that err is a status with code.
+func (suite *ServerTestSuite) assertCode(code codes.Code, err error) {
+	suite.T().Helper()
+	suite.Require().Error(err)
+	suite.Equal(code, status.Code(err), status.Convert(err).Message())
+}
+
+func TestServerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
@@ -0,0 +1,172 @@
+package grpcserver
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"task_manger_clean_architecture/delivery/grpcserver/pb"
+	"task_manger_clean_architecture/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type taskServer struct {
+	pb.UnimplementedTaskServiceServer
+	tasks  domain.TaskUsecase
+	events domain.TaskEventBus
+	logger *slog.Logger
+}
+
+// ListTasks implements pb.TaskServiceServer.
+func (s *taskServer) ListTasks(ctx context.Context, req *pb.ListTasksRequest) (*pb.ListTasksResponse, error) {
+	tasks, err := s.tasks.GetTasks(ctx)
+	if err != nil {
+		return nil, toStatus(ctx, s.logger, "failed to retrieve tasks", err)
+	}
+	return &pb.ListTasksResponse{Tasks: toProtoTasks(tasks)}, nil
+}
+
+// GetTask implements pb.TaskServiceServer.
+func (s *taskServer) GetTask(ctx context.Context, req *pb.GetTaskRequest) (*pb.Task, error) {
+	task, err := s.tasks.GetTasksById(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(ctx, s.logger, "failed to retrieve task", err)
+	}
+	return toProtoTask(task), nil
+}
+
+// AddTask implements pb.TaskServiceServer. The server picks the ID of a task
+// sent without one.
+func (s *taskServer) AddTask(ctx context.Context, req *pb.AddTaskRequest) (*pb.Task, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.GetTask() == nil {
+		return nil, status.Error(codes.InvalidArgument, "task is required")
+	}
+
+	newTask := fromProtoTask(req.GetTask())
+	if newTask.ID == "" {
+		newTask.ID = primitive.NewObjectID().Hex()
+	}
+	newTask.CreatedBy = callerFrom(ctx).uid
+	newTask.WorkspaceID = callerFrom(ctx).workspace
+	if err := s.tasks.AddTask(ctx, newTask); err != nil {
+		return nil, toStatus(ctx, s.logger, "failed to add task", err)
+	}
+
+	// The stored task has the creation time; the one sent will do without it
+	if stored, err := s.tasks.GetTasksById(ctx, newTask.ID); err == nil && stored != nil {
+		return toProtoTask(stored), nil
+	}
+	return toProtoTask(&newTask), nil
+}
+
+// UpdateTask implements pb.TaskServiceServer.
+func (s *taskServer) UpdateTask(ctx context.Context, req *pb.UpdateTaskRequest) (*emptypb.Empty, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if req.GetTask() == nil {
+		return nil, status.Error(codes.InvalidArgument, "task is required")
+	}
+
+	if err := s.tasks.UpdateTask(ctx, req.GetId(), fromProtoTask(req.GetTask())); err != nil {
+		return nil, toStatus(ctx, s.logger, "failed to update task", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// DeleteTask implements pb.TaskServiceServer.
+func (s *taskServer) DeleteTask(ctx context.Context, req *pb.DeleteTaskRequest) (*pb.DeleteTaskResponse, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	deletedCount, err := s.tasks.DeleteById(ctx, req.GetId())
+	if err != nil {
+		return nil, toStatus(ctx, s.logger, "failed to delete task", err)
+	}
+	if deletedCount == 0 {
+		return nil, status.Error(codes.NotFound, "task not found")
+	}
+	return &pb.DeleteTaskResponse{DeletedCount: deletedCount}, nil
+}
+
+// SearchTasks implements pb.TaskServiceServer.
+func (s *taskServer) SearchTasks(ctx context.Context, req *pb.SearchTasksRequest) (*pb.SearchTasksResponse, error) {
+	query := strings.TrimSpace(req.GetQuery())
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	limit := int(req.GetLimit())
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	results, err := s.tasks.SearchTasks(ctx, query, limit)
+	if err != nil {
+		return nil, toStatus(ctx, s.logger, "failed to search tasks", err)
+	}
+	response := &pb.SearchTasksResponse{Results: make([]*pb.TaskSearchResult, 0, len(results))}
+	for _, result := range results {
+		response.Results = append(response.Results, &pb.TaskSearchResult{
+			Task:       toProtoTask(result.Task),
+			Score:      result.Score,
+			Highlights: result.Highlights,
+		})
+	}
+	return response, nil
+}
+
+// ReindexTasks implements pb.TaskServiceServer.
+func (s *taskServer) ReindexTasks(ctx context.Context, req *pb.ReindexTasksRequest) (*pb.ReindexTasksResponse, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	indexed, err := s.tasks.ReindexTasks(ctx)
+	if err != nil {
+		return nil, toStatus(ctx, s.logger, "failed to rebuild the search index", err)
+	}
+	return &pb.ReindexTasksResponse{Indexed: int64(indexed)}, nil
+}
+
+// WatchTasks implements pb.TaskServiceServer. It subscribes before listing
+// the existing tasks, so a change made in between is sent twice rather than
+// missed.
+func (s *taskServer) WatchTasks(req *pb.WatchTasksRequest, stream pb.TaskService_WatchTasksServer) error {
+	ctx := stream.Context()
+	events, err := s.events.Subscribe(ctx)
+	if err != nil {
+		return toStatus(ctx, s.logger, "failed to watch tasks", err)
+	}
+
+	if req.GetIncludeExisting() {
+		tasks, err := s.tasks.GetTasks(ctx)
+		if err != nil {
+			return toStatus(ctx, s.logger, "failed to retrieve tasks", err)
+		}
+		for _, task := range tasks {
+			if err := stream.Send(&pb.TaskEvent{Type: pb.TaskEvent_EXISTING, Task: toProtoTask(task)}); err != nil {
+				return err
+			}
+		}
+	}
+
+	for event := range events {
+		if err := stream.Send(toProtoEvent(event)); err != nil {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return status.Error(codes.ResourceExhausted, "the watch fell behind the task changes and was dropped")
+}
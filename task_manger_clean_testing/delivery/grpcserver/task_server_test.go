@@ -0,0 +1,166 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"task_manger_clean_architecture/delivery/grpcserver/pb"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (suite *ServerTestSuite) TestListAndGetTasks() {
+	due := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	task := &domain.Task{ID: "1", Title: "Task", DueDate: due, Status: "pending", CreatedBy: "admin1", WorkspaceID: domain.DefaultWorkspaceID}
+	suite.tasks.On("GetTasks", mock.Anything).Return([]*domain.Task{task}, nil)
+	suite.tasks.On("GetTasksById", mock.Anything, "1").Return(task, nil)
+	suite.tasks.On("GetTasksById", mock.Anything, "2").Return(nil, mongo.ErrNoDocuments)
+
+	listed, err := suite.taskClient.ListTasks(suite.as(suite.member), &pb.ListTasksRequest{})
+	suite.Require().NoError(err)
+	suite.Require().Len(listed.Tasks, 1)
+	suite.Equal("Task", listed.Tasks[0].Title)
+	suite.Equal(due, listed.Tasks[0].DueDate.AsTime())
+	suite.Nil(listed.Tasks[0].CompletedAt)
+
+	got, err := suite.taskClient.GetTask(suite.as(suite.member), &pb.GetTaskRequest{Id: "1"})
+	suite.Require().NoError(err)
+	suite.Equal("admin1", got.CreatedBy)
+
+	_, err = suite.taskClient.GetTask(suite.as(suite.member), &pb.GetTaskRequest{Id: "2"})
+	suite.assertCode(codes.NotFound, err)
+}
+
+func (suite *ServerTestSuite) TestAddTaskSetsCreatorAndWorkspace() {
+	var added domain.Task
+	suite.tasks.On("AddTask", mock.Anything, mock.AnythingOfType("domain.Task")).
+		Run(func(args mock.Arguments) { added = args.Get(1).(domain.Task) }).
+		Return(nil)
+	suite.tasks.On("GetTasksById", mock.Anything, mock.Anything).Return(nil, errors.New("timeout"))
+
+	task, err := suite.taskClient.AddTask(suite.as(suite.admin), &pb.AddTaskRequest{Task: &pb.Task{
+		Title:     "Task",
+		CreatedBy: "someone-else",
+		DueDate:   timestamppb.New(time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)),
+	}})
+	suite.Require().NoError(err)
+	suite.NotEmpty(added.ID)
+	suite.Equal("admin1", added.CreatedBy)
+	suite.Equal(domain.DefaultWorkspaceID, added.WorkspaceID)
+	suite.Equal(added.ID, task.Id)
+	suite.Equal("admin1", task.CreatedBy)
+
+	_, err = suite.taskClient.AddTask(suite.as(suite.admin), &pb.AddTaskRequest{})
+	suite.assertCode(codes.InvalidArgument, err)
+}
+
+func (suite *ServerTestSuite) TestWritesAreForAdministrators() {
+	ctx := suite.as(suite.member)
+	_, err := suite.taskClient.AddTask(ctx, &pb.AddTaskRequest{Task: &pb.Task{Title: "Task"}})
+	suite.assertCode(codes.PermissionDenied, err)
+	_, err = suite.taskClient.UpdateTask(ctx, &pb.UpdateTaskRequest{Id: "1", Task: &pb.Task{Title: "Task"}})
+	suite.assertCode(codes.PermissionDenied, err)
+	_, err = suite.taskClient.DeleteTask(ctx, &pb.DeleteTaskRequest{Id: "1"})
+	suite.assertCode(codes.PermissionDenied, err)
+	_, err = suite.taskClient.ReindexTasks(ctx, &pb.ReindexTasksRequest{})
+	suite.assertCode(codes.PermissionDenied, err)
+
+	// Only the interceptors' lookups reached a use case
+	suite.tasks.AssertExpectations(suite.T())
+	suite.Empty(suite.tasks.Calls)
+}
+
+func (suite *ServerTestSuite) TestUpdateAndDeleteTasks() {
+	suite.tasks.On("UpdateTask", mock.Anything, "1", domain.Task{Title: "Renamed", Status: "done"}).Return(nil)
+	suite.tasks.On("UpdateTask", mock.Anything, "2", mock.Anything).Return(mongo.ErrNoDocuments)
+	suite.tasks.On("DeleteById", mock.Anything, "1").Return(int64(1), nil)
+	suite.tasks.On("DeleteById", mock.Anything, "2").Return(int64(0), nil)
+
+	_, err := suite.taskClient.UpdateTask(suite.as(suite.admin), &pb.UpdateTaskRequest{Id: "1", Task: &pb.Task{Title: "Renamed", Status: "done"}})
+	suite.NoError(err)
+	_, err = suite.taskClient.UpdateTask(suite.as(suite.admin), &pb.UpdateTaskRequest{Id: "2", Task: &pb.Task{Title: "Renamed"}})
+	suite.assertCode(codes.NotFound, err)
+
+	deleted, err := suite.taskClient.DeleteTask(suite.as(suite.admin), &pb.DeleteTaskRequest{Id: "1"})
+	suite.Require().NoError(err)
+	suite.Equal(int64(1), deleted.DeletedCount)
+	_, err = suite.taskClient.DeleteTask(suite.as(suite.admin), &pb.DeleteTaskRequest{Id: "2"})
+	suite.assertCode(codes.NotFound, err)
+}
+
+func (suite *ServerTestSuite) TestSearchTasks() {
+	suite.tasks.On("SearchTasks", mock.Anything, "report", 100).Return([]*domain.TaskSearchResult{{
+		Task:       &domain.Task{ID: "1", Title: "Write report"},
+		Score:      1.5,
+		Highlights: map[string]string{"title": "Write <mark>report</mark>"},
+	}}, nil)
+
+	_, err := suite.taskClient.SearchTasks(suite.as(suite.member), &pb.SearchTasksRequest{Query: "  "})
+	suite.assertCode(codes.InvalidArgument, err)
+
+	found, err := suite.taskClient.SearchTasks(suite.as(suite.member), &pb.SearchTasksRequest{Query: " report ", Limit: 500})
+	suite.Require().NoError(err)
+	suite.Require().Len(found.Results, 1)
+	suite.Equal(1.5, found.Results[0].Score)
+	suite.Equal("Write <mark>report</mark>", found.Results[0].Highlights["title"])
+}
+
+func (suite *ServerTestSuite) TestInternalErrorsHideTheCause() {
+	suite.tasks.On("ReindexTasks", mock.Anything).Return(0, errors.New("connection refused to mongo-0"))
+
+	_, err := suite.taskClient.ReindexTasks(suite.as(suite.admin), &pb.ReindexTasksRequest{})
+	suite.assertCode(codes.Internal, err)
+	suite.NotContains(status.Convert(err).Message(), "mongo-0")
+}
+
+func (suite *ServerTestSuite) TestWatchTasks() {
+	existing := &domain.Task{ID: "1", Title: "Existing", WorkspaceID: domain.DefaultWorkspaceID}
+	suite.tasks.On("GetTasks", mock.Anything).Return([]*domain.Task{existing}, nil)
+
+	ctx, cancel := context.WithCancel(suite.as(suite.member))
+	stream, err := suite.taskClient.WatchTasks(ctx, &pb.WatchTasksRequest{IncludeExisting: true})
+	suite.Require().NoError(err)
+
+	// The server subscribes before it lists the tasks
+	event, err := stream.Recv()
+	suite.Require().NoError(err)
+	suite.Equal(pb.TaskEvent_EXISTING, event.Type)
+	suite.Equal("Existing", event.Task.Title)
+
+	workspace := domain.WithWorkspace(context.Background(), domain.DefaultWorkspaceID)
+	suite.events.Publish(workspace, domain.TaskEvent{Type: domain.TaskCreated, Task: domain.Task{ID: "2", Title: "Elsewhere", WorkspaceID: "other"}})
+	suite.events.Publish(workspace, domain.TaskEvent{Type: domain.TaskCreated, Task: domain.Task{ID: "3", Title: "New", WorkspaceID: domain.DefaultWorkspaceID}})
+	suite.events.Publish(workspace, domain.TaskEvent{Type: domain.TaskDeleted, Task: domain.Task{ID: "1", WorkspaceID: domain.DefaultWorkspaceID}})
+
+	event, err = stream.Recv()
+	suite.Require().NoError(err)
+	suite.Equal(pb.TaskEvent_CREATED, event.Type)
+	suite.Equal("3", event.Task.Id)
+	event, err = stream.Recv()
+	suite.Require().NoError(err)
+	suite.Equal(pb.TaskEvent_DELETED, event.Type)
+	suite.Equal("1", event.Task.Id)
+
+	cancel()
+	_, err = stream.Recv()
+	suite.assertCode(codes.Canceled, err)
+}
+
+func (suite *ServerTestSuite) TestWatchTasksEndsWhenDropped() {
+	events := new(mocks.TaskEventBus)
+	dropped := make(chan domain.TaskEvent)
+	close(dropped)
+	events.On("Subscribe", mock.Anything).Return((<-chan domain.TaskEvent)(dropped), nil)
+	suite.serve(events)
+
+	stream, err := suite.taskClient.WatchTasks(suite.as(suite.member), &pb.WatchTasksRequest{})
+	suite.Require().NoError(err)
+	_, err = stream.Recv()
+	suite.assertCode(codes.ResourceExhausted, err)
+}
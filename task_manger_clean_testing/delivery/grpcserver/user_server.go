@@ -0,0 +1,80 @@
+package grpcserver
+
+import (
+	"context"
+	"log/slog"
+	"task_manger_clean_architecture/delivery/grpcserver/pb"
+	"task_manger_clean_architecture/domain"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type userServer struct {
+	pb.UnimplementedUserServiceServer
+	users  domain.UserUseCase
+	logger *slog.Logger
+}
+
+// ListUsers implements pb.UserServiceServer.
+func (s *userServer) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	recordsPerPage := req.GetRecordsPerPage()
+	if recordsPerPage < 1 {
+		recordsPerPage = 10
+	}
+	page := req.GetPage()
+	if page < 1 {
+		page = 1
+	}
+
+	users, err := s.users.GetUsers(ctx, (page-1)*recordsPerPage, recordsPerPage)
+	if err != nil {
+		return nil, toStatus(ctx, s.logger, "failed to list users", err)
+	}
+	response := &pb.ListUsersResponse{Users: make([]*pb.User, 0, len(users))}
+	for _, user := range users {
+		response.Users = append(response.Users, toProtoUser(inWorkspace(*user, callerFrom(ctx).workspace), true))
+	}
+	return response, nil
+}
+
+// GetUser implements pb.UserServiceServer. Other users are only visible to
+// members of the same workspace.
+func (s *userServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.User, error) {
+	caller := callerFrom(ctx)
+	self := req.GetUserId() == caller.uid
+
+	var user domain.User
+	var err error
+	if self {
+		user, err = s.users.GetUser(ctx, req.GetUserId())
+	} else {
+		user, err = s.users.GetMember(ctx, req.GetUserId())
+	}
+	if err != nil {
+		return nil, toStatus(ctx, s.logger, "failed to retrieve user", err)
+	}
+	return toProtoUser(inWorkspace(user, caller.workspace), self || caller.role == "ADMIN"), nil
+}
+
+// Promote implements pb.UserServiceServer.
+func (s *userServer) Promote(ctx context.Context, req *pb.PromoteRequest) (*pb.PromoteResponse, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	err, matchedCount, modifiedCount := s.users.Promote(ctx, req.GetUserId(), "ADMIN")
+	if err != nil {
+		return nil, toStatus(ctx, s.logger, "error updating user type", err)
+	}
+	if matchedCount == 0 {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	if modifiedCount == 0 {
+		return nil, status.Error(codes.AlreadyExists, "user is already an administrator")
+	}
+	return &pb.PromoteResponse{MatchedCount: matchedCount, ModifiedCount: modifiedCount}, nil
+}
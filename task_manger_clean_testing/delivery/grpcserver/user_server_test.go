@@ -0,0 +1,68 @@
+package grpcserver
+
+import (
+	"errors"
+	"task_manger_clean_architecture/delivery/grpcserver/pb"
+	"task_manger_clean_architecture/domain"
+
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+func (suite *ServerTestSuite) TestGetUserViews() {
+	suite.users.On("GetMember", mock.Anything, "user1").Return(suite.member, nil)
+	suite.users.On("GetMember", mock.Anything, "admin1").Return(suite.admin, nil)
+	suite.users.On("GetMember", mock.Anything, "gone").Return(domain.User{}, domain.ErrNotWorkspaceMember)
+
+	self, err := suite.userClient.GetUser(suite.as(suite.member), &pb.GetUserRequest{UserId: "user1"})
+	suite.Require().NoError(err)
+	suite.Equal("bisrat@example.com", self.Email)
+	suite.Equal("USER", self.UserType)
+
+	public, err := suite.userClient.GetUser(suite.as(suite.member), &pb.GetUserRequest{UserId: "admin1"})
+	suite.Require().NoError(err)
+	suite.True(proto.Equal(&pb.User{UserId: "admin1", FirstName: "Abebe", LastName: "Kebede"}, public), public.String())
+
+	viewed, err := suite.userClient.GetUser(suite.as(suite.admin), &pb.GetUserRequest{UserId: "user1"})
+	suite.Require().NoError(err)
+	suite.Equal("0911", viewed.Phone)
+	suite.True(viewed.EmailVerified)
+
+	_, err = suite.userClient.GetUser(suite.as(suite.member), &pb.GetUserRequest{UserId: "gone"})
+	suite.assertCode(codes.PermissionDenied, err)
+}
+
+func (suite *ServerTestSuite) TestListUsers() {
+	suite.users.On("GetUsers", mock.Anything, int64(5), int64(5)).Return([]*domain.User{&suite.member}, nil)
+
+	_, err := suite.userClient.ListUsers(suite.as(suite.member), &pb.ListUsersRequest{})
+	suite.assertCode(codes.PermissionDenied, err)
+
+	listed, err := suite.userClient.ListUsers(suite.as(suite.admin), &pb.ListUsersRequest{RecordsPerPage: 5, Page: 2})
+	suite.Require().NoError(err)
+	suite.Require().Len(listed.Users, 1)
+	suite.Equal("bisrat@example.com", listed.Users[0].Email)
+	suite.Equal("USER", listed.Users[0].UserType)
+}
+
+func (suite *ServerTestSuite) TestPromote() {
+	suite.users.On("Promote", mock.Anything, "user1", "ADMIN").Return(nil, int64(1), int64(1))
+	suite.users.On("Promote", mock.Anything, "admin1", "ADMIN").Return(nil, int64(1), int64(0))
+	suite.users.On("Promote", mock.Anything, "gone", "ADMIN").Return(nil, int64(0), int64(0))
+	suite.users.On("Promote", mock.Anything, "broken", "ADMIN").Return(errors.New("timeout"), int64(0), int64(0))
+
+	_, err := suite.userClient.Promote(suite.as(suite.member), &pb.PromoteRequest{UserId: "user1"})
+	suite.assertCode(codes.PermissionDenied, err)
+
+	promoted, err := suite.userClient.Promote(suite.as(suite.admin), &pb.PromoteRequest{UserId: "user1"})
+	suite.Require().NoError(err)
+	suite.Equal(int64(1), promoted.ModifiedCount)
+
+	_, err = suite.userClient.Promote(suite.as(suite.admin), &pb.PromoteRequest{UserId: "admin1"})
+	suite.assertCode(codes.AlreadyExists, err)
+	_, err = suite.userClient.Promote(suite.as(suite.admin), &pb.PromoteRequest{UserId: "gone"})
+	suite.assertCode(codes.NotFound, err)
+	_, err = suite.userClient.Promote(suite.as(suite.admin), &pb.PromoteRequest{UserId: "broken"})
+	suite.assertCode(codes.Internal, err)
+}
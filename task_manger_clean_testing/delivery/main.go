@@ -2,50 +2,167 @@ package main
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"flag"
+	"io/fs"
 	"log"
+	"log/slog"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"task_manger_clean_architecture/config"
 	"task_manger_clean_architecture/delivery/routers"
+	"task_manger_clean_architecture/infrastructure"
+	"task_manger_clean_architecture/repositories"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 func main() {
-    err := godotenv.Load(".env")
-    if err != nil {
-        log.Fatal("Error loading .env file")
+    // .env is optional: a config file, the environment or flags can set everything
+    if err := godotenv.Load(".env"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+        log.Fatal("Error loading .env file: ", err)
     }
 
-    mongoDb := os.Getenv("MONGODB_URL")
-    client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(mongoDb))
+    cfg, err := config.Load(os.Args[1:], os.LookupEnv)
+    if errors.Is(err, flag.ErrHelp) {
+        return
+    }
     if err != nil {
         log.Fatal(err)
     }
-	port := os.Getenv("PORT")
-    if port == "" {
-        port = "8080"
+    if cfg.PrintConfig {
+        if err := cfg.Print(os.Stdout); err != nil {
+            log.Fatal(err)
+        }
+    }
+    if err := cfg.Validate(); err != nil {
+        log.Fatal(err)
+    }
+    if cfg.PrintConfig {
+        return
     }
 
-    err = client.Ping(context.TODO(), nil)
+    logger := infrastructure.NewLogger(os.Stdout, cfg.Log.SlogLevel())
+    slog.SetDefault(logger)
+    fatal := func(msg string, err error) {
+        logger.Error(msg, "error", err)
+        os.Exit(1)
+    }
+
+    tracerProvider, shutdownTracing, err := infrastructure.NewTracerProvider(context.Background(), infrastructure.TracingConfig{
+        Exporter:    cfg.Tracing.Exporter,
+        Endpoint:    cfg.Tracing.Endpoint,
+        ServiceName: cfg.Tracing.ServiceName,
+    }, os.Stdout)
     if err != nil {
-        log.Fatal(err)
+        fatal("setting up tracing", err)
     }
-	fmt.Println("database connected successfully")
-    contextTimeout, err := time.ParseDuration(os.Getenv("CONTEXT_TIMEOUT"))
+    otel.SetTracerProvider(tracerProvider)
+    // Callers passing a W3C traceparent header continue their trace here
+    otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+    // The monitor adds a span for every command the driver sends
+    client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(cfg.Mongo.URL).SetMonitor(otelmongo.NewMonitor()))
     if err != nil {
-        log.Fatal("Error parsing context timeout:", err)
+        fatal("connecting to the database", err)
     }
 
+    err = client.Ping(context.TODO(), nil)
+    if err != nil {
+        fatal("pinging the database", err)
+    }
+    logger.Info("database connected successfully")
 
     // Select the database
-    databaseName := os.Getenv("DATABASE_NAME")
-    db := client.Database(databaseName)
+    db := client.Database(cfg.Mongo.Database)
+    migrateCtx, cancelMigrate := context.WithTimeout(context.Background(), cfg.Server.ContextTimeout)
+    err = repositories.MigrateToWorkspaces(migrateCtx, db, "user", "task", "task_search", "workspaces")
+    if err != nil {
+        cancelMigrate()
+        fatal("migrating to workspaces", err)
+    }
+    err = repositories.MigrateEmailVerification(migrateCtx, db, "user")
+    cancelMigrate()
+    if err != nil {
+        fatal("migrating email verification", err)
+    }
+
+    // The access log replaces gin's text logger
+    gin.SetMode(gin.ReleaseMode)
+    router := gin.New()
+    grpcServer, err := routers.Setup(cfg, db, logger, router)
+    if err != nil {
+        fatal("setting up routes", err)
+    }
+
+    server := &http.Server{
+        Addr:              ":" + strconv.Itoa(cfg.Server.Port),
+        Handler:           router,
+        ReadHeaderTimeout: 10 * time.Second,
+        ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelError),
+    }
+    serveErr := make(chan error, 2)
+    go func() {
+        serveErr <- server.ListenAndServe()
+    }()
+    logger.Info("listening", "addr", server.Addr)
 
-    router := gin.Default()
-    routers.Setup(contextTimeout, db, router)
+    if cfg.Server.GRPCPort != 0 {
+        listener, err := net.Listen("tcp", ":"+strconv.Itoa(cfg.Server.GRPCPort))
+        if err != nil {
+            fatal("listening for gRPC", err)
+        }
+        go func() {
+            serveErr <- grpcServer.Serve(listener)
+        }()
+        logger.Info("serving gRPC", "addr", listener.Addr().String())
+    }
+
+    // Orchestrators send SIGTERM and wait before killing the process, so
+    // stop taking connections and let the in-flight requests finish
+    stop, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+    defer cancel()
+    select {
+    case err := <-serveErr:
+        fatal("serving", err)
+    case <-stop.Done():
+    }
+    cancel()
+    logger.Info("shutting down")
 
-router.Run(":" + port)}
\ No newline at end of file
+    shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+    defer cancelShutdown()
+    // Watches only end when their clients cancel them, so the calls still
+    // running at the shutdown timeout are cut off
+    grpcStopped := make(chan struct{})
+    go func() {
+        grpcServer.GracefulStop()
+        close(grpcStopped)
+    }()
+    if err := server.Shutdown(shutdownCtx); err != nil {
+        logger.Error("requests still in flight after the shutdown timeout", "error", err)
+    }
+    select {
+    case <-grpcStopped:
+    case <-shutdownCtx.Done():
+        logger.Error("gRPC calls still in flight after the shutdown timeout")
+        grpcServer.Stop()
+    }
+    if err := client.Disconnect(shutdownCtx); err != nil {
+        logger.Error("disconnecting from the database", "error", err)
+    }
+    if err := shutdownTracing(shutdownCtx); err != nil {
+        logger.Error("flushing the spans", "error", err)
+    }
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"task_manger_clean_architecture/infrastructure"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics records the count and latency of the requests by route template.
+// Requests matching no route share one label, so scanners cannot create a
+// series per path.
+func Metrics(metrics *infrastructure.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
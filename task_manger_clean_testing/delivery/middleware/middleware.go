@@ -1,19 +1,35 @@
 package middleware
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
 	"strings"
+	"task_manger_clean_architecture/domain"
 	"task_manger_clean_architecture/infrastructure"
 
 	"github.com/gin-gonic/gin"
 )
 
-func Authenticate() gin.HandlerFunc {
+// Authenticate accepts the JWTs of password sessions signed by signer. The
+// tokens it refuses are counted in metrics.
+func Authenticate(signer *infrastructure.JWT, metrics *infrastructure.Metrics) gin.HandlerFunc {
+	return authenticate(signer, nil, metrics)
+}
+
+// AuthenticateWithAccessTokens is Authenticate that also accepts personal
+// access tokens. A token request acts as its owner with the token's scopes,
+// which RequireScopes enforces. Only administrators who signed in with a
+// second factor can create tokens, so tokens count as MFA sessions.
+func AuthenticateWithAccessTokens(signer *infrastructure.JWT, tokens domain.AccessTokenUsecase, metrics *infrastructure.Metrics) gin.HandlerFunc {
+	return authenticate(signer, tokens, metrics)
+}
+
+func authenticate(signer *infrastructure.JWT, tokens domain.AccessTokenUsecase, metrics *infrastructure.Metrics) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		clientToken := c.Request.Header.Get("Authorization")
 		if clientToken == "" {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "not Authorized"})
+			metrics.ObserveTokenRejected(infrastructure.TokenMissing)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "not Authorized"})
 			c.Abort()
 			return
 		}
@@ -23,15 +39,43 @@ func Authenticate() gin.HandlerFunc {
 		if len(tokenParts) == 2 {
 			clientToken = tokenParts[1]
 		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid token format"})
+			metrics.ObserveTokenRejected(infrastructure.TokenMalformed)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token format"})
 			c.Abort()
 			return
 		}
 
-		claims, err := infrastructure.ValidateToken(clientToken)
+		if tokens != nil && strings.HasPrefix(clientToken, domain.AccessTokenPrefix) {
+			user, scopes, err := tokens.Authenticate(c.Request.Context(), clientToken)
+			if err != nil {
+				metrics.ObserveTokenRejected(infrastructure.TokenInvalidAccessKey)
+				c.JSON(http.StatusUnauthorized, gin.H{"error": domain.ErrInvalidAccessToken.Error()})
+				c.Abort()
+				return
+			}
+			setUser(c, user)
+			c.Set("mfa", true)
+			c.Set("scopes", scopes)
+			return
+		}
+
+		claims, err := signer.ValidateToken(clientToken)
 		if err != "" {
-			c.JSON(http.StatusInternalServerError, gin.H{"errorsss": err})
-			fmt.Println("check error happend")
+			// The access log reports why the token was refused
+			c.Error(errors.New(err))
+			if strings.Contains(err, "expired") {
+				metrics.ObserveTokenRejected(infrastructure.TokenExpired)
+			} else {
+				metrics.ObserveTokenRejected(infrastructure.TokenInvalid)
+			}
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err})
+			c.Abort()
+			return
+		}
+
+		if claims.Refresh {
+			metrics.ObserveTokenRejected(infrastructure.TokenInvalid)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "refresh tokens cannot authorize requests"})
 			c.Abort()
 			return
 		}
@@ -41,5 +85,131 @@ func Authenticate() gin.HandlerFunc {
 		c.Set("lastname", claims.LastName)
 		c.Set("uid", claims.Uid)
 		c.Set("usertype", claims.UserType)
+		c.Set("emailverified", claims.EmailVerified)
+		c.Set("tokenversion", claims.TokenVersion)
+		c.Set("mfa", claims.MFA)
+		workspace := claims.WorkspaceID
+		if workspace == "" {
+			workspace = domain.DefaultWorkspaceID
+		}
+		c.Set("workspace", workspace)
+	}
+}
+
+// setUser sets the same keys from a stored user that a JWT sets from its
+// claims. The user acts in their first workspace.
+func setUser(c *gin.Context, user domain.User) {
+	workspace, role := "", ""
+	if len(user.Workspaces) > 0 {
+		workspace, role = user.Workspaces[0].WorkspaceID, user.Workspaces[0].Role
+	}
+	c.Set("email", stringValue(user.Email))
+	c.Set("firstname", stringValue(user.FirstName))
+	c.Set("lastname", stringValue(user.LastName))
+	c.Set("uid", user.UserId)
+	c.Set("usertype", role)
+	c.Set("emailverified", user.EmailVerified)
+	c.Set("tokenversion", user.TokenVersion)
+	c.Set("workspace", workspace)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// RequireScopes limits personal access tokens to the routes in routes,
+// written as "METHOD /full/path", and only when the token has the scope the
+// route maps to. Sessions that signed in with a password are not limited.
+// It must run after AuthenticateWithAccessTokens.
+func RequireScopes(routes map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := c.Get("scopes")
+		if !ok {
+			return
+		}
+		scopes, _ := value.([]string)
+		required, ok := routes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			c.JSON(http.StatusForbidden, gin.H{"error": "route cannot be used with an access token"})
+			c.Abort()
+			return
+		}
+		for _, scope := range scopes {
+			if scope == required {
+				return
+			}
+		}
+		c.JSON(http.StatusForbidden, gin.H{"error": "access token lacks the " + required + " scope"})
+		c.Abort()
+	}
+}
+
+// RequireAdminMFA rejects administrators whose token was issued without a
+// second factor. Routes for enrolling one must not use it. It must run after
+// Authenticate.
+func RequireAdminMFA() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("usertype") == "ADMIN" && !c.GetBool("mfa") {
+			c.JSON(http.StatusForbidden, gin.H{"error": "administrators must sign in with two-factor authentication"})
+			c.Abort()
+			return
+		}
+	}
+}
+
+// RejectRevokedTokens rejects tokens issued before the user's last password
+// or email change. It also replaces the role in the token with the user's
+// current role in its workspace, and clears both when they have left it, and
+// the verification of the email with the stored one. It must run after
+// Authenticate.
+func RejectRevokedTokens(users domain.UserUseCase, metrics *infrastructure.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := users.GetUser(c.Request.Context(), c.GetString("uid"))
+		if err != nil {
+			metrics.ObserveTokenRejected(infrastructure.TokenInvalid)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "not Authorized"})
+			c.Abort()
+			return
+		}
+		if user.TokenVersion != c.GetInt("tokenversion") {
+			metrics.ObserveTokenRejected(infrastructure.TokenRevoked)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "token has been revoked"})
+			c.Abort()
+			return
+		}
+		role, ok := user.Role(c.GetString("workspace"))
+		if !ok {
+			c.Set("workspace", "")
+		}
+		c.Set("usertype", role)
+		c.Set("emailverified", user.EmailVerified)
+	}
+}
+
+// RequireWorkspace rejects users who are not a member of the workspace
+// their token acts in. It must run after RejectRevokedTokens.
+func RequireWorkspace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("workspace") == "" {
+			c.JSON(http.StatusForbidden, gin.H{"error": domain.ErrNotWorkspaceMember.Error()})
+			c.Abort()
+			return
+		}
+	}
+}
+
+// RequireVerifiedEmail rejects users who have not verified their email
+// address yet. It must run after RejectRevokedTokens: a token still claims
+// what was true when it was issued.
+func RequireVerifiedEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !c.GetBool("emailverified") {
+			c.JSON(http.StatusForbidden, gin.H{"error": "email address is not verified"})
+			c.Abort()
+			return
+		}
 	}
 }
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"task_manger_clean_architecture/infrastructure"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// verifiedEmailStatus answers a request whose token claims claimed for a user
+// stored with stored.
+func verifiedEmailStatus(t *testing.T, claimed bool, stored bool) int {
+	gin.SetMode(gin.TestMode)
+	users := new(mocks.UserUseCase)
+	users.On("GetUser", mock.Anything, "user1").Return(domain.User{UserId: "user1", EmailVerified: stored}, nil)
+
+	router := gin.New()
+	authenticate := func(c *gin.Context) {
+		c.Set("uid", "user1")
+		c.Set("emailverified", claimed)
+		c.Set("workspace", domain.DefaultWorkspaceID)
+	}
+	metrics := infrastructure.NewMetrics(prometheus.NewRegistry())
+	router.GET("/", authenticate, RejectRevokedTokens(users, metrics), RequireVerifiedEmail(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	return w.Code
+}
+
+func TestRequireVerifiedEmailUsesTheStoredFlag(t *testing.T) {
+	// Verifying the address does not wait for a new token
+	assert.Equal(t, http.StatusOK, verifiedEmailStatus(t, false, true))
+	// Nor does a token keep a verification the account no longer has
+	assert.Equal(t, http.StatusForbidden, verifiedEmailStatus(t, true, false))
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimiter enforces token bucket limits per client. Routes maps a route,
+// written as "METHOD /full/path" the way gin registered it, to its own limit
+// and bucket; every other route shares the Default bucket.
+type RateLimiter struct {
+	Store   domain.RateLimitStore
+	Default domain.RateLimit
+	Routes  map[string]domain.RateLimit
+	now     func() time.Time
+}
+
+func NewRateLimiter(store domain.RateLimitStore, defaultLimit domain.RateLimit, routes map[string]domain.RateLimit) *RateLimiter {
+	return &RateLimiter{
+		Store:   store,
+		Default: defaultLimit,
+		Routes:  routes,
+		now:     time.Now,
+	}
+}
+
+// ByIP limits requests per client address, for routes without a user.
+func (r *RateLimiter) ByIP() gin.HandlerFunc {
+	return r.limit(func(c *gin.Context) string {
+		return "ip:" + c.ClientIP()
+	})
+}
+
+// ByUser limits requests per authenticated user. It must run after
+// Authenticate; requests without a uid are limited by address instead.
+func (r *RateLimiter) ByUser() gin.HandlerFunc {
+	return r.limit(func(c *gin.Context) string {
+		if uid := c.GetString("uid"); uid != "" {
+			return "uid:" + uid
+		}
+		return "anon:" + c.ClientIP()
+	})
+}
+
+func (r *RateLimiter) limit(client func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.Request.Method + " " + c.FullPath()
+		limit, ok := r.Routes[route]
+		if !ok {
+			limit = r.Default
+			route = "*"
+		}
+
+		result, err := r.Store.Take(c.Request.Context(), client(c)+"|"+route, limit, r.now())
+		if err != nil {
+			// An unavailable store should not take the whole API down with it
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"task_manger_clean_architecture/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestID takes the request ID from the X-Request-ID header or generates
+// one. It puts it in the request context, the response header and the
+// body of every JSON error response. It must run before the handlers that
+// log.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		request_id := domain.RequestIDOrNew(c.GetHeader(domain.RequestIDHeader))
+		c.Set("request_id", request_id)
+		c.Request = c.Request.WithContext(domain.WithRequestID(c.Request.Context(), request_id))
+		c.Header(domain.RequestIDHeader, request_id)
+		c.Writer = &requestIDWriter{ResponseWriter: c.Writer, request_id: request_id}
+		c.Next()
+	}
+}
+
+// requestIDWriter adds request_id to JSON error bodies, which gin writes in
+// a single call.
+type requestIDWriter struct {
+	gin.ResponseWriter
+	request_id string
+}
+
+func (w *requestIDWriter) Write(data []byte) (int, error) {
+	if w.Status() < http.StatusBadRequest || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		return w.ResponseWriter.Write(data)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(data, &body); err != nil {
+		return w.ResponseWriter.Write(data)
+	}
+	body["request_id"] = w.request_id
+	tagged, err := json.Marshal(body)
+	if err != nil {
+		return w.ResponseWriter.Write(data)
+	}
+	if _, err := w.ResponseWriter.Write(tagged); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+func (w *requestIDWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout gives each request a single deadline, which every database call it
+// makes shares. Routes listed in routes, keyed like "GET /task", get their
+// own budget instead of budget. A request whose handler fails after the
+// deadline passed is answered 504 Gateway Timeout, and one cancelled before
+// it, because the client went away or the server is stopping, 503 Service
+// Unavailable, instead of the handler's 500. It must run before the
+// middleware and handlers that call the database.
+func Timeout(budget time.Duration, routes map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := budget
+		if routeTimeout, ok := routes[c.Request.Method+" "+c.FullPath()]; ok {
+			timeout = routeTimeout
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Writer = &timeoutWriter{ResponseWriter: c.Writer, ctx: ctx}
+
+		c.Next()
+
+		if !c.Writer.Written() && ctx.Err() != nil {
+			status, message := timeoutStatus(ctx)
+			c.AbortWithStatusJSON(status, gin.H{"error": message})
+		}
+	}
+}
+
+func timeoutStatus(ctx context.Context) (int, string) {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return http.StatusGatewayTimeout, "request timed out"
+	}
+	return http.StatusServiceUnavailable, "request cancelled"
+}
+
+// timeoutWriter replaces the server errors answered once the context of the
+// request is done, which the deadline or cancellation caused, with the
+// status of the timeout.
+type timeoutWriter struct {
+	gin.ResponseWriter
+	ctx context.Context
+	// replaced is set once the status of an error response has been
+	// replaced, and bodyWritten once its body has.
+	replaced    bool
+	bodyWritten bool
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	if code >= http.StatusInternalServerError && w.ctx.Err() != nil {
+		code, _ = timeoutStatus(w.ctx)
+		w.replaced = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	if !w.replaced {
+		return w.ResponseWriter.Write(data)
+	}
+	// The handler's body explains its own failure, not the timeout
+	if w.bodyWritten {
+		return len(data), nil
+	}
+	w.bodyWritten = true
+	_, message := timeoutStatus(w.ctx)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	body, _ := json.Marshal(gin.H{"error": message})
+	if _, err := w.ResponseWriter.Write(body); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
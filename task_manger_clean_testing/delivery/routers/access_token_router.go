@@ -0,0 +1,17 @@
+package routers
+
+import (
+	"task_manger_clean_architecture/delivery/controllers"
+	"task_manger_clean_architecture/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewAccessTokenRouter(tokens domain.AccessTokenUsecase, group *gin.RouterGroup) {
+	ac := &controllers.AccessTokenController{
+		AccessTokenUseCase: tokens,
+	}
+	group.POST("/users/me/tokens", ac.CreateToken())
+	group.GET("/users/me/tokens", ac.GetTokens())
+	group.DELETE("/users/me/tokens/:token_id", ac.RevokeToken())
+}
@@ -0,0 +1,20 @@
+package routers
+
+import (
+	"task_manger_clean_architecture/delivery/controllers"
+	"task_manger_clean_architecture/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewAccountRouter(account domain.AccountUsecase, hasher domain.PasswordHasher, policy domain.PasswordPolicy, group *gin.RouterGroup) {
+	ac := &controllers.AccountController{
+		AccountUseCase: account,
+		PasswordHasher: hasher,
+		PasswordPolicy: policy,
+	}
+	group.GET("/verify", ac.VerifyEmail())
+	group.POST("/verify/resend", ac.ResendVerification())
+	group.POST("/password/forgot", ac.ForgotPassword())
+	group.POST("/password/reset", ac.ResetPassword())
+}
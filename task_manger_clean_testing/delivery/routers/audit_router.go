@@ -0,0 +1,17 @@
+package routers
+
+import (
+	"task_manger_clean_architecture/delivery/controllers"
+	"task_manger_clean_architecture/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewAuditRouter(audit domain.AuditUsecase, operators []string, group *gin.RouterGroup) {
+	ac := &controllers.AuditController{
+		AuditUseCase: audit,
+		Operators:    operators,
+	}
+	group.GET("/audit", ac.GetAuditLog())
+	group.GET("/audit/verify", ac.VerifyAuditLog())
+}
@@ -0,0 +1,22 @@
+package routers
+
+import (
+	"task_manger_clean_architecture/delivery/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewDocsRouter registers the OpenAPI document and the page that renders it.
+// Like the probes they are not rate limited.
+func NewDocsRouter(router gin.IRoutes) error {
+	spec, err := controllers.APISpec().JSON()
+	if err != nil {
+		return err
+	}
+	dc := &controllers.DocsController{
+		Spec: spec,
+	}
+	router.GET("/openapi.json", dc.OpenAPI())
+	router.GET("/docs", dc.Docs())
+	return nil
+}
@@ -0,0 +1,102 @@
+package routers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"task_manger_clean_architecture/config"
+	"task_manger_clean_architecture/delivery/docs"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// setupRoutes registers every route, OIDC login included, on a fresh engine.
+// Setup does not touch the database, so a client that never connects will do.
+// configure changes the configuration first.
+func setupRoutes(t *testing.T, configure ...func(cfg *config.Config)) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI("mongodb://localhost:1"))
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+
+	cfg := config.Default()
+	cfg.Auth.SecretKey = "secret"
+	cfg.OIDC.Issuer = "https://accounts.example.com"
+	cfg.OIDC.ClientID = "task-manager"
+	for _, change := range configure {
+		change(cfg)
+	}
+	engine := gin.New()
+	grpcServer, err := Setup(cfg, client.Database("test"), slog.New(slog.NewTextHandler(io.Discard, nil)), engine)
+	require.NoError(t, err)
+	require.Contains(t, grpcServer.GetServiceInfo(), "taskmanager.v1.TaskService")
+	require.Contains(t, grpcServer.GetServiceInfo(), "taskmanager.v1.UserService")
+	return engine
+}
+
+func fetchSpec(t *testing.T, engine *gin.Engine) docs.Document {
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var document docs.Document
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &document))
+	return document
+}
+
+func TestOpenAPI_DocumentsEveryRoute(t *testing.T) {
+	engine := setupRoutes(t)
+	document := fetchSpec(t, engine)
+	assert.Equal(t, "3.1.0", document.OpenAPI)
+
+	registered := map[string]bool{}
+	for _, route := range engine.Routes() {
+		path := docs.OpenAPIPath(route.Path)
+		registered[route.Method+" "+path] = true
+		assert.NotNil(t, document.Paths[path][strings.ToLower(route.Method)], "%s %s is not in the OpenAPI document", route.Method, route.Path)
+	}
+	for path, item := range document.Paths {
+		for method := range item {
+			assert.True(t, registered[strings.ToUpper(method)+" "+path], "%s %s is documented but not registered", method, path)
+		}
+	}
+}
+
+func TestOpenAPI_Security(t *testing.T) {
+	document := fetchSpec(t, setupRoutes(t))
+
+	assert.Equal(t, "bearer", document.Components.SecuritySchemes[docs.BearerAuth].Scheme)
+	assert.Equal(t, []docs.SecurityRequirement{{docs.BearerAuth: {}}}, document.Security)
+	login := document.Paths["/login"]["post"]
+	require.NotNil(t, login.Security)
+	assert.Empty(t, *login.Security)
+	assert.Nil(t, document.Paths["/task/{task_id}"]["get"].Security)
+}
+
+func TestOpenAPI_Schemas(t *testing.T) {
+	document := fetchSpec(t, setupRoutes(t))
+
+	task := document.Components.Schemas["Task"]
+	require.NotNil(t, task)
+	assert.Contains(t, task.Properties, "title")
+	assert.Equal(t, "#/components/schemas/Task", document.Paths["/task/{task_id}"]["get"].Responses["200"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/Error", document.Paths["/task"]["post"].Responses["default"].Content["application/json"].Schema.Ref)
+}
+
+func TestDocs_Page(t *testing.T) {
+	w := httptest.NewRecorder()
+	setupRoutes(t).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), `spec-url="/openapi.json"`)
+}
@@ -0,0 +1,27 @@
+package routers
+
+import (
+	"log/slog"
+	"task_manger_clean_architecture/delivery/controllers"
+	"task_manger_clean_architecture/delivery/graph"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/infrastructure"
+	"task_manger_clean_architecture/repositories"
+	"task_manger_clean_architecture/usecases"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func NewGraphQLRouter(timeout time.Duration, db *mongo.Database, metrics *infrastructure.Metrics, audit domain.AuditUsecase, events domain.TaskEventBus, logger *slog.Logger, group *gin.RouterGroup) {
+	ur := repositories.NewInstrumentedUserRepository(repositories.NewUserRepository(db, "user"), metrics)
+	gc := &controllers.GraphQLController{
+		Handler: graph.NewHandler(
+			newTaskUseCase(timeout, db, metrics, audit, events, logger),
+			usecases.NewTracedUserUseCase(usecases.NewAuditedUserUseCase(usecases.NewUserUseCase(ur, timeout), audit, logger)),
+			logger,
+		),
+	}
+	group.POST("/graphql", gc.Query())
+}
@@ -0,0 +1,18 @@
+package routers
+
+import (
+	"task_manger_clean_architecture/delivery/controllers"
+	"task_manger_clean_architecture/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NewHealthRouter registers the probes of the orchestrator. They are not
+// rate limited, so frequent probing cannot fail them.
+func NewHealthRouter(checkers []domain.HealthChecker, router gin.IRoutes) {
+	hc := &controllers.HealthController{
+		HealthCheckers: checkers,
+	}
+	router.GET("/healthz", hc.Liveness())
+	router.GET("/readyz", hc.Readiness())
+}
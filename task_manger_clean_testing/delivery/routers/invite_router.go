@@ -0,0 +1,45 @@
+package routers
+
+import (
+	"log/slog"
+	"task_manger_clean_architecture/delivery/controllers"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/infrastructure"
+	"task_manger_clean_architecture/repositories"
+	"task_manger_clean_architecture/usecases"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func newInviteController(timeout time.Duration, db *mongo.Database, metrics *infrastructure.Metrics, audit domain.AuditUsecase, logger *slog.Logger, mailer domain.Mailer, hasher domain.PasswordHasher, policy domain.PasswordPolicy, baseURL string) *controllers.InviteController {
+	return &controllers.InviteController{
+		InviteUseCase: usecases.NewInviteUseCase(
+			repositories.NewInviteRepository(db, "invites"),
+			repositories.NewInstrumentedUserRepository(repositories.NewUserRepository(db, "user"), metrics),
+			repositories.NewWorkspaceRepository(db, "workspaces"),
+			mailer,
+			audit,
+			logger,
+			baseURL,
+			timeout,
+		),
+		PasswordHasher: hasher,
+		PasswordPolicy: policy,
+	}
+}
+
+// NewInviteRouter registers the management of a workspace's invites.
+func NewInviteRouter(ic *controllers.InviteController, group *gin.RouterGroup) {
+	group.GET("/invites", ic.GetInvites())
+	group.POST("/invites", ic.CreateInvite())
+	group.DELETE("/invites/pending/:invite_id", ic.RevokeInvite())
+	group.POST("/invites/pending/:invite_id/resend", ic.ResendInvite())
+}
+
+// NewAcceptInviteRouter registers accepting an invite, which needs no
+// account yet.
+func NewAcceptInviteRouter(ic *controllers.InviteController, group *gin.RouterGroup) {
+	group.POST("/invites/:token/accept", ic.AcceptInvite())
+}
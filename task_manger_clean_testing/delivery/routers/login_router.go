@@ -1,7 +1,10 @@
 package routers
 
 import (
+	"log/slog"
 	"task_manger_clean_architecture/delivery/controllers"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/infrastructure"
 	"task_manger_clean_architecture/repositories"
 	"task_manger_clean_architecture/usecases"
 	"time"
@@ -10,10 +13,24 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func NewLoginRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
-	ur := repositories.NewUserRepository(db, "user")
+func NewLoginRouter(timeout time.Duration, db *mongo.Database, metrics *infrastructure.Metrics, audit domain.AuditUsecase, throttle domain.LoginThrottleUsecase, hasher domain.PasswordHasher, twoFactor domain.TwoFactorUsecase, oidc domain.OIDCUsecase, jwt *infrastructure.JWT, logger *slog.Logger, group *gin.RouterGroup) {
+	ur := repositories.NewInstrumentedUserRepository(repositories.NewUserRepository(db, "user"), metrics)
 	uc := &controllers.UserController{
-		UserUseCase: usecases.NewUserUseCase(ur, timeout),
+		UserUseCase:      usecases.NewTracedUserUseCase(usecases.NewAuditedUserUseCase(usecases.NewUserUseCase(ur, timeout), audit, logger)),
+		AuditUseCase:     audit,
+		LoginThrottle:    throttle,
+		PasswordHasher:   hasher,
+		TwoFactorUseCase: twoFactor,
+		OIDCUseCase:      oidc,
+		JWT:              jwt,
+		Logger:           logger,
+		Metrics:          metrics,
 	}
 	group.POST("/login", uc.Login())
-}
\ No newline at end of file
+	group.POST("/login/2fa", uc.LoginTwoFactor())
+	group.POST("/login/refresh", uc.Refresh())
+	if oidc != nil {
+		group.GET("/login/oidc", uc.OIDCLogin())
+		group.GET("/login/oidc/callback", uc.OIDCCallback())
+	}
+}
@@ -0,0 +1,13 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// NewMetricsRouter registers the Prometheus scrape endpoint for the metrics
+// in registry. Like the probes it is not rate limited.
+func NewMetricsRouter(registry *prometheus.Registry, router gin.IRoutes) {
+	router.GET("/metrics", gin.WrapH(promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})))
+}
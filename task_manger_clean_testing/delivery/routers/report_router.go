@@ -0,0 +1,19 @@
+package routers
+
+import (
+	"task_manger_clean_architecture/delivery/controllers"
+	"task_manger_clean_architecture/repositories"
+	"task_manger_clean_architecture/usecases"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func NewReportRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
+	rr := repositories.NewTaskReportRepository(db, "task")
+	rc := &controllers.ReportController{
+		TaskReportUseCase: usecases.NewTaskReportUseCase(rr, timeout),
+	}
+	group.GET("/reports/tasks", rc.GetTaskReport())
+}
@@ -1,20 +1,232 @@
 package routers
 
 import (
+	"fmt"
+	"log/slog"
+	"task_manger_clean_architecture/config"
+	"task_manger_clean_architecture/delivery/grpcserver"
 	"task_manger_clean_architecture/delivery/middleware"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/infrastructure"
+	"task_manger_clean_architecture/repositories"
+	"task_manger_clean_architecture/usecases"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"google.golang.org/grpc"
 )
 
-func Setup(timeout time.Duration, db *mongo.Database, gin *gin.Engine){
+// Public routes are limited per client address and protected routes per
+// user. Routes listed here get a bucket of their own.
+var (
+	publicRateLimit   = domain.RateLimit{Rate: 30, Period: time.Minute, Burst: 30}
+	publicRouteLimits = map[string]domain.RateLimit{
+		"POST /login":                 {Rate: 10, Period: time.Minute, Burst: 10},
+		"POST /login/2fa":             {Rate: 10, Period: time.Minute, Burst: 10},
+		"GET /login/oidc":             {Rate: 10, Period: time.Minute, Burst: 10},
+		"POST /signup":                {Rate: 5, Period: time.Minute, Burst: 5},
+		"POST /verify/resend":         {Rate: 3, Period: time.Minute, Burst: 3},
+		"POST /password/forgot":       {Rate: 3, Period: time.Minute, Burst: 3},
+		"POST /password/reset":        {Rate: 5, Period: time.Minute, Burst: 5},
+		"POST /invites/:token/accept": {Rate: 5, Period: time.Minute, Burst: 5},
+	}
+	protectedRateLimit   = domain.RateLimit{Rate: 120, Period: time.Minute, Burst: 60}
+	protectedRouteLimits = map[string]domain.RateLimit{
+		"GET /task":                               {Rate: 60, Period: time.Minute, Burst: 20},
+		"GET /task/search":                        {Rate: 30, Period: time.Minute, Burst: 10},
+		"POST /task/search/reindex":               {Rate: 1, Period: time.Minute, Burst: 1},
+		"GET /reports/tasks":                      {Rate: 10, Period: time.Minute, Burst: 5},
+		"POST /graphql":                           {Rate: 60, Period: time.Minute, Burst: 20},
+		"POST /users/me/password":                 {Rate: 5, Period: time.Minute, Burst: 5},
+		"POST /users/me/2fa/disable":              {Rate: 5, Period: time.Minute, Burst: 5},
+		"POST /users/me/tokens":                   {Rate: 5, Period: time.Minute, Burst: 5},
+		"POST /workspaces":                        {Rate: 5, Period: time.Minute, Burst: 5},
+		"POST /invites":                           {Rate: 10, Period: time.Minute, Burst: 10},
+		"POST /invites/pending/:invite_id/resend": {Rate: 5, Period: time.Minute, Burst: 5},
+	}
+)
+
+// Every request must be answered within server.context_timeout. Routes
+// listed here get a budget of their own.
+var routeTimeouts = map[string]time.Duration{
+	"POST /task/search/reindex": 5 * time.Minute,
+	"GET /reports/tasks":        2 * time.Minute,
+}
+
+// accessTokenScopes maps the routes personal access tokens may call to the
+// scope each needs. Account self-service, such as managing the tokens
+// themselves, needs a password session.
+var accessTokenScopes = map[string]string{
+	"GET /task":                               domain.ScopeTaskRead,
+	"GET /task/search":                        domain.ScopeTaskRead,
+	"GET /task/:task_id":                      domain.ScopeTaskRead,
+	"POST /task":                              domain.ScopeTaskWrite,
+	"PUT /task/:task_id":                      domain.ScopeTaskWrite,
+	"DELETE /task/:task_id":                   domain.ScopeTaskWrite,
+	"POST /task/search/reindex":               domain.ScopeTaskWrite,
+	"GET /reports/tasks":                      domain.ScopeReportRead,
+	"GET /audit":                              domain.ScopeAuditRead,
+	"GET /audit/verify":                       domain.ScopeAuditRead,
+	"GET /users":                              domain.ScopeUserRead,
+	"GET /users/:user_id":                     domain.ScopeUserRead,
+	"POST /promote/:user_id":                  domain.ScopeUserAdmin,
+	"POST /unlock/:user_id":                   domain.ScopeUserAdmin,
+	"GET /invites":                            domain.ScopeUserAdmin,
+	"POST /invites":                           domain.ScopeUserAdmin,
+	"DELETE /invites/pending/:invite_id":      domain.ScopeUserAdmin,
+	"POST /invites/pending/:invite_id/resend": domain.ScopeUserAdmin,
+}
+
+// newOIDC returns the login through the configured OpenID Connect provider,
+// or nil when there is none.
+func newOIDC(cfg *config.Config, db *mongo.Database, users domain.UserRepository, audit domain.AuditUsecase, logger *slog.Logger) domain.OIDCUsecase {
+	if cfg.OIDC.Issuer == "" {
+		return nil
+	}
+	provider := infrastructure.NewOIDCProvider(infrastructure.OIDCConfig{
+		Issuer:       cfg.OIDC.Issuer,
+		ClientID:     cfg.OIDC.ClientID,
+		ClientSecret: cfg.OIDC.ClientSecret,
+		RedirectURL:  cfg.OIDCRedirectURL(),
+		Scopes:       cfg.OIDC.Scopes,
+	}, nil)
+	return usecases.NewOIDCUseCase(
+		provider,
+		repositories.NewOIDCLoginRepository(db, "oidc_logins"),
+		users,
+		audit,
+		logger,
+		!cfg.Auth.InviteOnly,
+		cfg.Server.ContextTimeout,
+	)
+}
+
+// newMailer writes mail to the configured file when there is one and to the
+// log otherwise.
+func newMailer(cfg *config.Config, logger *slog.Logger) domain.Mailer {
+	if cfg.Mail.File != "" {
+		return infrastructure.NewFileMailer(cfg.Mail.File)
+	}
+	return infrastructure.NewLogMailer(logger)
+}
+
+// taskEventBuffer is how many task events a watcher may fall behind before
+// it is dropped.
+const taskEventBuffer = 64
+
+// Setup registers every route on gin without calling the database, which
+// main has migrated already. The tracing, request ID, access log and
+// metrics middleware run first, so every request is traced, logged with its
+// IDs and counted. The timeout then sets the deadline the rest of the request
+// runs under. It returns the gRPC server of the same use cases, for main to
+// serve on its own port.
+func Setup(cfg *config.Config, db *mongo.Database, logger *slog.Logger, gin *gin.Engine) (*grpc.Server, error) {
+	timeout := cfg.Server.ContextTimeout
+	registry := prometheus.NewRegistry()
+	metrics := infrastructure.NewMetrics(registry)
+	infrastructure.RegisterTaskCounts(registry, repositories.NewTaskStatsRepository(db, "task"), timeout, logger)
+
+	// Without this gin trusts X-Forwarded-For from anyone, so every client
+	// could pick the address its rate limits are counted under
+	if err := gin.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("setting the trusted proxies: %w", err)
+	}
+	gin.Use(otelgin.Middleware(cfg.Tracing.ServiceName), middleware.RequestID(), middleware.AccessLog(logger), middleware.Metrics(metrics), middleware.Recovery(logger), middleware.Timeout(timeout, routeTimeouts))
+
+	// A single audit use case is shared by every router so all entries are
+	// appended to the same hash chain.
+	audit := usecases.NewAuditUseCase(repositories.NewAuditRepository(db, "audit"), timeout)
+	throttle := usecases.NewLoginThrottleUseCase(repositories.NewLoginAttemptRepository(db, "login_attempts"), audit, logger, timeout)
+	hasher := infrastructure.NewPasswordHasher(infrastructure.DefaultPasswordHasherConfig)
+	breached, err := infrastructure.LoadBreachedPasswords(cfg.Auth.BreachedPasswordsFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading breached passwords: %w", err)
+	}
+	policy := infrastructure.NewPasswordPolicy(cfg.Auth.MinPasswordLength, breached)
+	jwt := infrastructure.NewJWT(cfg.Auth.SecretKey)
+	userTokens := repositories.NewUserTokenRepository(db, "user_tokens")
+	mailer := newMailer(cfg, logger)
+	users := repositories.NewInstrumentedUserRepository(repositories.NewUserRepository(db, "user"), metrics)
+	account := usecases.NewAccountUseCase(
+		users,
+		userTokens,
+		mailer,
+		audit,
+		logger,
+		cfg.Server.BaseURL,
+		timeout,
+	)
+	twoFactor := usecases.NewTwoFactorUseCase(
+		users,
+		userTokens,
+		infrastructure.NewTOTP(cfg.Auth.TOTPIssuer),
+		audit,
+		logger,
+		timeout,
+	)
+	accessTokens := usecases.NewAccessTokenUseCase(
+		repositories.NewAccessTokenRepository(db, "access_tokens"),
+		users,
+		audit,
+		logger,
+		timeout,
+	)
+
+	NewHealthRouter([]domain.HealthChecker{repositories.NewMongoHealthChecker(db)}, gin)
+	NewMetricsRouter(registry, gin)
+	if err := NewDocsRouter(gin); err != nil {
+		return nil, fmt.Errorf("building the OpenAPI document: %w", err)
+	}
+
+	rateLimits := repositories.NewInMemoryRateLimitStore()
+	// Task changes made through either API reach the watchers of both
+	events := infrastructure.NewTaskEventBus(taskEventBuffer)
+
 	publicRouter:= gin.Group("")
-	NewSignUPRouter( timeout,db, publicRouter)
-	NewLoginRouter(timeout,db,publicRouter)
-	protectedRouter:= gin.Group("")
-	protectedRouter.Use(middleware.Authenticate())
-	NewUserRouter(timeout, db, protectedRouter)
+	publicRouter.Use(middleware.NewRateLimiter(rateLimits, publicRateLimit, publicRouteLimits).ByIP())
+	if !cfg.Auth.InviteOnly {
+		NewSignUPRouter( timeout,db, metrics, audit, account, hasher, policy, jwt, logger, publicRouter)
+	}
+	invites := newInviteController(timeout, db, metrics, audit, logger, mailer, hasher, policy, cfg.Server.BaseURL)
+	NewAcceptInviteRouter(invites, publicRouter)
+	NewLoginRouter(timeout,db, metrics, audit, throttle, hasher, twoFactor, newOIDC(cfg, db, users, audit, logger), jwt, logger, publicRouter)
+	NewAccountRouter(account, hasher, policy, publicRouter)
+	signedInRouter:= gin.Group("")
+	signedInRouter.Use(middleware.AuthenticateWithAccessTokens(jwt, accessTokens, metrics))
+	// Limit the user before the checks that read the database on every request
+	signedInRouter.Use(middleware.NewRateLimiter(rateLimits, protectedRateLimit, protectedRouteLimits).ByUser())
+	signedInRouter.Use(middleware.RequireScopes(accessTokenScopes))
+	signedInRouter.Use(middleware.RejectRevokedTokens(usecases.NewTracedUserUseCase(usecases.NewUserUseCase(users, timeout)), metrics))
+	signedInRouter.Use(middleware.RequireVerifiedEmail())
+	// Administrators without a second factor can only reach the routes that set one up
+	NewTwoFactorRouter(twoFactor, signedInRouter)
+	// Users who left the workspace of their token can still move to another
+	NewWorkspaceRouter(timeout, db, metrics, audit, jwt, logger, signedInRouter)
+	accountRouter:= signedInRouter.Group("")
+	accountRouter.Use(middleware.RequireAdminMFA())
+	// Accounts in no workspace yet still manage themselves
+	NewAccessTokenRouter(accessTokens, accountRouter)
+	protectedRouter:= accountRouter.Group("")
+	protectedRouter.Use(middleware.RequireWorkspace())
+	NewUserRouter(timeout, db, metrics, audit, throttle, account, hasher, policy, jwt, logger, accountRouter, protectedRouter)
+	NewInviteRouter(invites, protectedRouter)
 	
-	NewTaskRouter(timeout, db, protectedRouter)
+	NewTaskRouter(timeout, db, metrics, audit, events, logger, protectedRouter)
+	NewReportRouter(timeout, db, protectedRouter)
+	NewAuditRouter(audit, cfg.Auth.Operators, protectedRouter)
+	NewGraphQLRouter(timeout, db, metrics, audit, events, logger, protectedRouter)
+
+	grpcServer := grpcserver.NewServer(
+		timeout,
+		newTaskUseCase(timeout, db, metrics, audit, events, logger),
+		usecases.NewTracedUserUseCase(usecases.NewAuditedUserUseCase(usecases.NewUserUseCase(users, timeout), audit, logger)),
+		events,
+		jwt,
+		metrics,
+		logger,
+	)
+	return grpcServer, nil
 } 
\ No newline at end of file
@@ -0,0 +1,76 @@
+package routers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"task_manger_clean_architecture/config"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/infrastructure"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// login sends a login without credentials, which is refused before the
+// database is called, from remoteAddr with the X-Forwarded-For header.
+func login(engine *gin.Engine, remoteAddr string, forwardedFor string) int {
+	request := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("{}"))
+	request.RemoteAddr = remoteAddr
+	request.Header.Set("X-Forwarded-For", forwardedFor)
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, request)
+	return w.Code
+}
+
+func TestRateLimitIgnoresForwardedForFromClients(t *testing.T) {
+	engine := setupRoutes(t)
+
+	burst := publicRouteLimits["POST /login"].Burst
+	for i := 0; i < burst; i++ {
+		assert.Equal(t, http.StatusBadRequest, login(engine, "203.0.113.7:4000", fmt.Sprintf("198.51.100.%d", i)))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, login(engine, "203.0.113.7:4000", "198.51.100.99"))
+}
+
+func TestRateLimitUsesForwardedForFromTrustedProxies(t *testing.T) {
+	engine := setupRoutes(t, func(cfg *config.Config) {
+		cfg.Server.TrustedProxies = []string{"10.0.0.0/8"}
+	})
+
+	burst := publicRouteLimits["POST /login"].Burst
+	for i := 0; i < burst; i++ {
+		login(engine, "10.0.0.2:4000", "198.51.100.1")
+	}
+	assert.Equal(t, http.StatusTooManyRequests, login(engine, "10.0.0.2:4000", "198.51.100.1"))
+	// Behind the proxy, each client has a bucket of its own
+	assert.Equal(t, http.StatusBadRequest, login(engine, "10.0.0.2:4000", "198.51.100.2"))
+}
+
+func TestRateLimitRunsBeforeTheDatabaseIsRead(t *testing.T) {
+	// Every database call fails fast, as the database is unreachable
+	engine := setupRoutes(t, func(cfg *config.Config) {
+		cfg.Server.ContextTimeout = 20 * time.Millisecond
+	})
+	userType, uid := "USER", "user1"
+	token, _, err := infrastructure.NewJWT("secret").GenerateAllTokens("bisrat@example.com", &uid, &uid, &userType, &uid, true, 0, false, domain.DefaultWorkspaceID)
+	require.NoError(t, err)
+
+	createWorkspace := func() int {
+		request := httptest.NewRequest(http.MethodPost, "/workspaces", strings.NewReader(`{"name":"Acme"}`))
+		request.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, request)
+		return w.Code
+	}
+	burst := protectedRouteLimits["POST /workspaces"].Burst
+	for i := 0; i < burst; i++ {
+		assert.NotEqual(t, http.StatusTooManyRequests, createWorkspace())
+	}
+	assert.Equal(t, http.StatusTooManyRequests, createWorkspace())
+}
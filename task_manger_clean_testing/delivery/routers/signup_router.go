@@ -1,7 +1,10 @@
 package routers
 
 import (
+	"log/slog"
 	"task_manger_clean_architecture/delivery/controllers"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/infrastructure"
 	"task_manger_clean_architecture/repositories"
 	"task_manger_clean_architecture/usecases"
 	"time"
@@ -10,10 +13,17 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func NewSignUPRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
-	ur := repositories.NewUserRepository(db, "user")
+func NewSignUPRouter(timeout time.Duration, db *mongo.Database, metrics *infrastructure.Metrics, audit domain.AuditUsecase, account domain.AccountUsecase, hasher domain.PasswordHasher, policy domain.PasswordPolicy, jwt *infrastructure.JWT, logger *slog.Logger, group *gin.RouterGroup) {
+	ur := repositories.NewInstrumentedUserRepository(repositories.NewUserRepository(db, "user"), metrics)
 	uc := &controllers.UserController{
-		UserUseCase: usecases.NewUserUseCase(ur, timeout),
+		UserUseCase:    usecases.NewTracedUserUseCase(usecases.NewAuditedUserUseCase(usecases.NewUserUseCase(ur, timeout), audit, logger)),
+		AuditUseCase:   audit,
+		AccountUseCase: account,
+		PasswordHasher: hasher,
+		PasswordPolicy: policy,
+		JWT:            jwt,
+		Logger:         logger,
+		Metrics:        metrics,
 	}
 	group.POST("/signup", uc.Signup())
 }
\ No newline at end of file
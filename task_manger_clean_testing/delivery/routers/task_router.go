@@ -1,7 +1,10 @@
 package routers
 
 import (
+	"log/slog"
 	"task_manger_clean_architecture/delivery/controllers"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/infrastructure"
 	"task_manger_clean_architecture/repositories"
 	"task_manger_clean_architecture/usecases"
 	"time"
@@ -10,13 +13,22 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func NewTaskRouter( timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
-	tr := repositories.NewTaskRepository(db, "task")
+// newTaskUseCase returns the task use case of the REST and gRPC APIs. Its
+// writes are audited and published to the watchers of the workspace.
+func newTaskUseCase(timeout time.Duration, db *mongo.Database, metrics *infrastructure.Metrics, audit domain.AuditUsecase, events domain.TaskEventBus, logger *slog.Logger) domain.TaskUsecase {
+	tr := repositories.NewInstrumentedTaskRepository(repositories.NewTaskRepository(db, "task"), metrics)
+	ts := repositories.NewTaskSearchRepository(db, "task_search")
+	return usecases.NewTracedTaskUseCase(usecases.NewAuditedTaskUseCase(usecases.NewPublishingTaskUseCase(usecases.NewTaskUseCase(tr, ts, timeout), events), audit, logger))
+}
+
+func NewTaskRouter( timeout time.Duration, db *mongo.Database, metrics *infrastructure.Metrics, audit domain.AuditUsecase, events domain.TaskEventBus, logger *slog.Logger, group *gin.RouterGroup) {
 	tc := &controllers.TaskController{
-		TaskUseCase: usecases.NewTaskUseCase(tr, timeout),
+		TaskUseCase: newTaskUseCase(timeout, db, metrics, audit, events, logger),
 	}
 	group.POST("/task", tc.AddTask())
 	group.GET("/task", tc.GetTasks())
+	group.GET("/task/search", tc.SearchTasks())
+	group.POST("/task/search/reindex", tc.ReindexTasks())
 	group.GET("/task/:task_id", tc.GetTasksById())
 	group.DELETE("/task/:task_id", tc.DeleteById())
 	group.PUT("/task/:task_id", tc.UpdateTask())
@@ -0,0 +1,17 @@
+package routers
+
+import (
+	"task_manger_clean_architecture/delivery/controllers"
+	"task_manger_clean_architecture/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+func NewTwoFactorRouter(twoFactor domain.TwoFactorUsecase, group *gin.RouterGroup) {
+	tc := &controllers.TwoFactorController{
+		TwoFactorUseCase: twoFactor,
+	}
+	group.POST("/users/me/2fa/enroll", tc.Enroll())
+	group.POST("/users/me/2fa/confirm", tc.Confirm())
+	group.POST("/users/me/2fa/disable", tc.Disable())
+}
@@ -1,7 +1,10 @@
 package routers
 
 import (
+	"log/slog"
 	"task_manger_clean_architecture/delivery/controllers"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/infrastructure"
 	"task_manger_clean_architecture/repositories"
 	"task_manger_clean_architecture/usecases"
 	"time"
@@ -10,12 +13,23 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func NewUserRouter(timeout time.Duration, db *mongo.Database, group *gin.RouterGroup) {
-	ur := repositories.NewUserRepository(db, "user")
+func NewUserRouter(timeout time.Duration, db *mongo.Database, metrics *infrastructure.Metrics, audit domain.AuditUsecase, throttle domain.LoginThrottleUsecase, account domain.AccountUsecase, hasher domain.PasswordHasher, policy domain.PasswordPolicy, jwt *infrastructure.JWT, logger *slog.Logger, accountGroup *gin.RouterGroup, group *gin.RouterGroup) {
+	ur := repositories.NewInstrumentedUserRepository(repositories.NewUserRepository(db, "user"), metrics)
 	uc := &controllers.UserController{
-		UserUseCase: usecases.NewUserUseCase(ur, timeout),
+		UserUseCase:    usecases.NewTracedUserUseCase(usecases.NewAuditedUserUseCase(usecases.NewUserUseCase(ur, timeout), audit, logger)),
+		AuditUseCase:   audit,
+		LoginThrottle:  throttle,
+		AccountUseCase: account,
+		PasswordHasher: hasher,
+		PasswordPolicy: policy,
+		JWT:            jwt,
+		Logger:         logger,
+		Metrics:        metrics,
 	}
+	accountGroup.PATCH("/users/me", uc.UpdateProfile())
+	accountGroup.POST("/users/me/password", uc.ChangePassword())
 	group.GET("/users", uc.GetUsers())
 	group.GET("/users/:user_id", uc.GetUser())
 	group.POST("/promote/:user_id", uc.Promote())
+	group.POST("/unlock/:user_id", uc.Unlock())
 }
\ No newline at end of file
@@ -0,0 +1,26 @@
+package routers
+
+import (
+	"log/slog"
+	"task_manger_clean_architecture/delivery/controllers"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/infrastructure"
+	"task_manger_clean_architecture/repositories"
+	"task_manger_clean_architecture/usecases"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func NewWorkspaceRouter(timeout time.Duration, db *mongo.Database, metrics *infrastructure.Metrics, audit domain.AuditUsecase, jwt *infrastructure.JWT, logger *slog.Logger, group *gin.RouterGroup) {
+	ur := repositories.NewInstrumentedUserRepository(repositories.NewUserRepository(db, "user"), metrics)
+	wc := &controllers.WorkspaceController{
+		WorkspaceUseCase: usecases.NewWorkspaceUseCase(repositories.NewWorkspaceRepository(db, "workspaces"), ur, audit, logger, timeout),
+		UserUseCase:      usecases.NewTracedUserUseCase(usecases.NewAuditedUserUseCase(usecases.NewUserUseCase(ur, timeout), audit, logger)),
+		JWT:              jwt,
+	}
+	group.GET("/workspaces", wc.GetWorkspaces())
+	group.POST("/workspaces", wc.CreateWorkspace())
+	group.POST("/workspaces/:workspace_id/switch", wc.SwitchWorkspace())
+}
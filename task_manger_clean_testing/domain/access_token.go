@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// AccessTokenPrefix starts every personal access token, so Authenticate can
+// tell them from JWTs and secret scanners can spot leaked ones.
+const AccessTokenPrefix = "tm_pat_"
+
+// Scopes a personal access token can be granted. Each API route a token may
+// call needs one of them; sessions that signed in with a password have all
+// the scopes their role allows.
+const (
+	ScopeTaskRead   = "task:read"
+	ScopeTaskWrite  = "task:write"
+	ScopeReportRead = "report:read"
+	ScopeAuditRead  = "audit:read"
+	ScopeUserRead   = "user:read"
+	ScopeUserAdmin  = "user:admin"
+)
+
+// Scopes lists every scope, in the order they are documented.
+var Scopes = []string{
+	ScopeTaskRead,
+	ScopeTaskWrite,
+	ScopeReportRead,
+	ScopeAuditRead,
+	ScopeUserRead,
+	ScopeUserAdmin,
+}
+
+var (
+	ErrInvalidAccessToken  = errors.New("invalid or expired access token")
+	ErrAccessTokenNotFound = errors.New("access token not found")
+	ErrUnknownScope        = errors.New("unknown scope")
+	// ErrScopeNotAllowed is returned when a token asks for a scope its
+	// owner's role does not have.
+	ErrScopeNotAllowed = errors.New("scope not allowed for this role")
+	ErrExpiryInPast    = errors.New("expiry must be in the future")
+)
+
+// AccessToken is a personal access token as stored. Only the hash of the
+// token is kept; the token itself is shown once, when it is created.
+type AccessToken struct {
+	ID     string   `json:"id" bson:"id"`
+	UserID string   `json:"-" bson:"userid"`
+	Name   string   `json:"name" bson:"name"`
+	Hash   string   `json:"-" bson:"hash"`
+	Scopes []string `json:"scopes" bson:"scopes"`
+	// WorkspaceID is the workspace the token was created in and acts in.
+	WorkspaceID string     `json:"workspace_id" bson:"workspaceid"`
+	CreatedAt   time.Time  `json:"created_at" bson:"createdat"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" bson:"expiresat,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty" bson:"lastusedat,omitempty"`
+}
+
+// NewAccessToken is what a user asks for when creating a token. A nil
+// ExpiresAt makes a token that never expires.
+type NewAccessToken struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// CreatedAccessToken is returned once, when a token is created.
+type CreatedAccessToken struct {
+	AccessToken
+	Token string `json:"token"`
+}
+
+type AccessTokenRepository interface {
+	Create(c context.Context, token AccessToken) error
+	ListForUser(c context.Context, user_id string) ([]AccessToken, error)
+	// GetByHash returns ErrInvalidAccessToken when no token has the hash.
+	GetByHash(c context.Context, hash string) (*AccessToken, error)
+	// Delete returns ErrAccessTokenNotFound unless user_id owns the token.
+	Delete(c context.Context, user_id string, id string) error
+	SetLastUsed(c context.Context, id string, now time.Time) error
+}
+
+type AccessTokenUsecase interface {
+	Create(c context.Context, user_id string, role string, request NewAccessToken) (*CreatedAccessToken, error)
+	List(c context.Context, user_id string) ([]AccessToken, error)
+	Revoke(c context.Context, user_id string, id string) error
+	// Authenticate returns the owner of token and the scopes the token has
+	// now: those it was granted that the owner's current role in the token's
+	// workspace still allows. The owner's Workspaces holds only that
+	// membership.
+	Authenticate(c context.Context, token string) (User, []string, error)
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Purposes of a UserToken. A token only works for the flow it was issued for.
+const (
+	UserTokenVerifyEmail   = "verify_email"
+	UserTokenPasswordReset = "password_reset"
+)
+
+// ErrInvalidUserToken is returned for a token that is unknown, expired,
+// already used or issued for another purpose.
+var ErrInvalidUserToken = errors.New("token is invalid or has expired")
+
+// UserToken is a single-use token mailed to a user. Only the SHA-256 of the
+// token is stored, so a leaked collection cannot be used to take over
+// accounts.
+type UserToken struct {
+	Hash      string     `json:"-" bson:"hash"`
+	UserID    string     `json:"user_id" bson:"userid"`
+	Purpose   string     `json:"purpose" bson:"purpose"`
+	CreatedAt time.Time  `json:"created_at" bson:"createdat"`
+	ExpiresAt time.Time  `json:"expires_at" bson:"expiresat"`
+	UsedAt    *time.Time `json:"used_at,omitempty" bson:"usedat"`
+}
+
+type UserTokenRepository interface {
+	Create(c context.Context, token UserToken) error
+	// Consume marks the unused, unexpired token with the given hash and
+	// purpose as used and returns it, or returns ErrInvalidUserToken.
+	Consume(c context.Context, hash string, purpose string, now time.Time) (*UserToken, error)
+	// DeleteForUser removes the user's tokens for purpose, used or not.
+	DeleteForUser(c context.Context, user_id string, purpose string) error
+}
+
+// Mail is a plain text message to a single recipient.
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(c context.Context, mail Mail) error
+}
+
+type AccountUsecase interface {
+	// SendVerification mails user a link that verifies their address.
+	SendVerification(c context.Context, user User) error
+	ResendVerification(c context.Context, email string) error
+	VerifyEmail(c context.Context, token string) error
+	// ForgotPassword mails a reset link if the address belongs to a user and
+	// otherwise does nothing, so callers cannot probe for accounts.
+	ForgotPassword(c context.Context, email string) error
+	// ResetPassword replaces the password of the token's user with the
+	// already hashed password.
+	ResetPassword(c context.Context, token string, hashedPassword string) error
+}
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Audited actions.
+const (
+	AuditUserSignup           = "user.signup"
+	AuditUserLogin            = "user.login"
+	AuditUserLoginFailed      = "user.login_failed"
+	AuditUserPromote          = "user.promote"
+	AuditUserTokensUpdate     = "user.tokens_update"
+	AuditUserLockout          = "user.lockout"
+	AuditUserUnlock           = "user.unlock"
+	AuditUserVerifyEmail      = "user.verify_email"
+	AuditUserPasswordReset    = "user.password_reset"
+	AuditUserProfileUpdate    = "user.profile_update"
+	AuditUserPasswordChange   = "user.password_change"
+	AuditUserTwoFactorEnable  = "user.2fa_enable"
+	AuditUserTwoFactorDisable = "user.2fa_disable"
+	AuditAccessTokenCreate    = "access_token.create"
+	AuditAccessTokenRevoke    = "access_token.revoke"
+	AuditWorkspaceCreate      = "workspace.create"
+	AuditInviteCreate         = "invite.create"
+	AuditInviteRevoke         = "invite.revoke"
+	AuditInviteResend         = "invite.resend"
+	AuditInviteAccept         = "invite.accept"
+	AuditTaskCreate           = "task.create"
+	AuditTaskUpdate           = "task.update"
+	AuditTaskDelete           = "task.delete"
+	AuditTaskReindex          = "task.reindex"
+)
+
+// AuditEntry is one record of the append-only audit log. Every entry stores
+// the hash of the previous one and its own hash covers all of its fields, so
+// editing or removing an entry breaks the chain from that point on.
+type AuditEntry struct {
+	Seq       int64           `json:"seq" bson:"seq"`
+	ActorID   string          `json:"actor_uid" bson:"actoruid"`
+	Action    string          `json:"action" bson:"action"`
+	Target    string          `json:"target" bson:"target"`
+	Before    json.RawMessage `json:"before,omitempty" bson:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty" bson:"after,omitempty"`
+	ClientIP  string          `json:"client_ip" bson:"clientip"`
+	UserAgent string          `json:"user_agent" bson:"useragent"`
+	Timestamp time.Time       `json:"timestamp" bson:"timestamp"`
+	PrevHash  string          `json:"prev_hash" bson:"prevhash"`
+	Hash      string          `json:"hash" bson:"hash"`
+}
+
+// ComputeHash returns the hex SHA-256 of every field of the entry but Hash.
+func (e AuditEntry) ComputeHash() string {
+	fields := []string{
+		strconv.FormatInt(e.Seq, 10),
+		e.ActorID,
+		e.Action,
+		e.Target,
+		string(e.Before),
+		string(e.After),
+		e.ClientIP,
+		e.UserAgent,
+		e.Timestamp.UTC().Format(time.RFC3339Nano),
+		e.PrevHash,
+	}
+	sum := sha256.New()
+	for _, field := range fields {
+		// Length-prefix each field so moving bytes between fields changes the hash.
+		sum.Write([]byte(strconv.Itoa(len(field)) + ":" + field + "\n"))
+	}
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// AuditActor identifies who made a request. The delivery layer puts it in the
+// request context so the use cases can attribute the changes they make.
+type AuditActor struct {
+	UID       string
+	ClientIP  string
+	UserAgent string
+}
+
+type auditActorKey struct{}
+
+func WithAuditActor(c context.Context, actor AuditActor) context.Context {
+	return context.WithValue(c, auditActorKey{}, actor)
+}
+
+func AuditActorFrom(c context.Context) AuditActor {
+	actor, _ := c.Value(auditActorKey{}).(AuditActor)
+	return actor
+}
+
+// AuditFilter selects audit entries. Zero fields match everything; PerPage 0
+// returns every matching entry.
+type AuditFilter struct {
+	ActorID string
+	Action  string
+	Target  string
+	From    time.Time
+	To      time.Time
+	Page    int64
+	PerPage int64
+}
+
+// Matches reports whether entry satisfies every set field of the filter.
+func (f AuditFilter) Matches(entry *AuditEntry) bool {
+	if f.ActorID != "" && entry.ActorID != f.ActorID {
+		return false
+	}
+	if f.Action != "" && !strings.EqualFold(entry.Action, f.Action) {
+		return false
+	}
+	if f.Target != "" && entry.Target != f.Target {
+		return false
+	}
+	if !f.From.IsZero() && entry.Timestamp.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && entry.Timestamp.After(f.To) {
+		return false
+	}
+	return true
+}
+
+// AuditVerification is the outcome of walking the hash chain. BrokenAt is the
+// sequence number of the first entry that does not match its hash or its
+// predecessor, or 0 when the chain is intact.
+type AuditVerification struct {
+	Valid    bool  `json:"valid"`
+	Entries  int64 `json:"entries"`
+	BrokenAt int64 `json:"broken_at,omitempty"`
+}
+
+// ErrAuditSeqTaken is returned by AuditRepository.Append when another writer
+// already holds the entry's position in the chain.
+var ErrAuditSeqTaken = errors.New("audit sequence number already taken")
+
+type AuditRepository interface {
+	Append(c context.Context, entry AuditEntry) error
+	Last(c context.Context) (*AuditEntry, error)
+	// Find returns the page of matching entries, newest first, and the total
+	// number of matching entries.
+	Find(c context.Context, filter AuditFilter) ([]*AuditEntry, int64, error)
+	// Scan calls fn with every entry in sequence order.
+	Scan(c context.Context, fn func(entry *AuditEntry) error) error
+}
+
+type AuditUsecase interface {
+	Record(c context.Context, action string, target string, before interface{}, after interface{}) error
+	Query(c context.Context, filter AuditFilter) ([]*AuditEntry, int64, error)
+	Verify(c context.Context) (*AuditVerification, error)
+}
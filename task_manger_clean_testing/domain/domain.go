@@ -7,11 +7,14 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// User is the stored account. It is never written to API responses as is;
+// the delivery layer picks the fields each caller may see.
 type User struct{
-	ID				primitive.ObjectID	`bson: "_id"`
-	FirstName		*string				`json: "firstname" validate: "required,min=2,max=100"`
-	LastName		*string				`json: "lastname" validate:"required min=2,max=100"`
-	Password		*string				`json: "password" validate:"required min=6"`
+	// ID has always been stored as "id"; Mongo assigns the document _id.
+	ID				primitive.ObjectID	`json:"-" bson:"id"`
+	FirstName		*string				`json:"firstname" validate:"required,min=2,max=100"`
+	LastName		*string				`json:"lastname" validate:"required,min=2,max=100"`
+	Password		*string				`json:"password" validate:"required,min=6"`
 	Email			*string				`json:"email" validate:"email,required"`
 	Phone			*string				`json:"phone" validate:"required"`
 	Token			*string				`json:"token"`
@@ -20,18 +23,54 @@ type User struct{
 	CreatedAt		time.Time			`json:"createdat"`
 	UpdatedAt		time.Time			`json:"updatedat"`
 	UserId			string				`json:"userid"`
+	EmailVerified	bool				`json:"email_verified"`
+	// TokenVersion is carried in every JWT issued to the user. Bumping it
+	// revokes all of the user's existing tokens.
+	TokenVersion	int					`json:"-"`
+	TwoFactor		TwoFactorSettings	`json:"-"`
+	// Workspaces are the user's memberships, the first being the one a login
+	// starts in. UserType is only the role the account signed up with.
+	Workspaces		[]WorkspaceMembership	`json:"-"`
+}
+
+// Role returns the user's role in workspace_id and false if they are not a
+// member.
+func (u User) Role(workspace_id string) (string, bool) {
+	for _, membership := range u.Workspaces {
+		if membership.WorkspaceID == workspace_id {
+			return membership.Role, true
+		}
+	}
+	return "", false
+}
+
+// HasRole reports whether the user has role in any of their workspaces.
+func (u User) HasRole(role string) bool {
+	for _, membership := range u.Workspaces {
+		if membership.Role == role {
+			return true
+		}
+	}
+	return false
 }
 
 
 type Task struct {
-	ID          string    `json:"id" bson:"id"` 
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	DueDate     time.Time `json:"due_date"`
-	Status      string    `json:"status"`
+	ID          string     `json:"id" bson:"id"` 
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	DueDate     time.Time  `json:"due_date"`
+	Status      string     `json:"status"`
+	CreatedBy   string     `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// WorkspaceID is set by the repository from the request's workspace.
+	WorkspaceID string     `json:"workspace_id" bson:"workspaceid"`
 }
 
 
+// TaskRepository is tenant-scoped: every method only sees the tasks of the
+// workspace in its context and fails with ErrNoWorkspace without one.
 type TaskRepository interface {
 GetTasks(c context.Context) ([]*Task, error) 
 	GetTasksById(c context.Context,id string) (*Task, error)
@@ -46,18 +85,47 @@ type TaskUsecase interface {
 	 DeleteById(c context.Context,id string) (int64, error) 
 	 UpdateTask(c context.Context,id string, updatedTask Task) error
 	 AddTask(c context.Context,newTask Task) error
+	 SearchTasks(c context.Context, query string, limit int) ([]*TaskSearchResult, error)
+	 ReindexTasks(c context.Context) (int, error)
 	
 }
 
 
+// UserRepository holds accounts, which exist across workspaces. The methods
+// that look at other users, GetUsers, GetMember and Promote, are
+// tenant-scoped like TaskRepository; the rest act on an account its owner
+// has already identified, by token, email or id.
 type UserRepository interface {
     Signup(ctx context.Context, user User) (interface{}, error)
     Login(ctx context.Context, email string) (*User, error)
     GetUsers(ctx context.Context, startIndex int64, recordsPerPage int64) ([]*User, error)
     GetUser(ctx context.Context, user_id string) (User, error)
+    // GetMember returns the user if they belong to the context's workspace.
+    GetMember(ctx context.Context, user_id string) (User, error)
+    // GetMembers returns those of the users that belong to the context's
+    // workspace, in no particular order.
+    GetMembers(ctx context.Context, user_ids []string) ([]User, error)
+    // Promote sets the user's role in the context's workspace.
     Promote(ctx context.Context, user_id string, userType string) (error, int64, int64)
-	UpdateAllTokens(token string, refreshToken string, user_id string) error
+    AddMembership(ctx context.Context, user_id string, membership WorkspaceMembership) error
+	UpdateAllTokens(c context.Context, token string, refreshToken string, user_id string) error
 	GetUserByEmail(c context.Context, email string) (User, error)
+	SetEmailVerified(c context.Context, user_id string) error
+	// UpdatePassword sets the hashed password and revokes the user's tokens.
+	UpdatePassword(c context.Context, user_id string, password string) error
+	// SetPasswordHash replaces the stored hash of an unchanged password, for
+	// upgrading legacy hashes. The user's tokens stay valid.
+	SetPasswordHash(c context.Context, user_id string, password string) error
+	SetTwoFactor(c context.Context, user_id string, settings TwoFactorSettings) error
+	// UseTOTPStep records step as used and reports false if it, or a later
+	// step, was used already.
+	UseTOTPStep(c context.Context, user_id string, step int64) (bool, error)
+	// UseRecoveryCode removes the recovery code with the given hash and
+	// reports false if the user had no such code.
+	UseRecoveryCode(c context.Context, user_id string, hash string) (bool, error)
+	// UpdateProfile sets the fields of update. Changing the email marks it
+	// unverified and revokes the user's tokens.
+	UpdateProfile(c context.Context, user_id string, update ProfileUpdate) error
 
 }
 type UserUseCase interface {
@@ -65,8 +133,13 @@ type UserUseCase interface {
     Login(c context.Context, email string) (*User, error)
     GetUsers(c context.Context, startIndex int64, recordsPerPage int64) ([]*User, error)
     GetUser(c context.Context, user_id string) (User, error)
+    GetMember(c context.Context, user_id string) (User, error)
+    GetMembers(c context.Context, user_ids []string) ([]User, error)
     Promote(c context.Context, user_id string, userType string) (error, int64, int64)
-	UpdateAllTokens(token string, refreshToken string, user_id string) error
+	UpdateAllTokens(c context.Context, token string, refreshToken string, user_id string) error
 	GetUserByEmail(c context.Context,email string) (User, error) 
+	UpdateProfile(c context.Context, user_id string, role string, update ProfileUpdate) (User, error)
+	ChangePassword(c context.Context, user_id string, password string) error
+	UpgradePasswordHash(c context.Context, user_id string, password string) error
 
 }
\ No newline at end of file
@@ -0,0 +1,12 @@
+package domain
+
+import "context"
+
+// HealthChecker is implemented by every backend the service needs to serve
+// requests, so readiness can tell whether it is reachable.
+type HealthChecker interface {
+	// Name identifies the backend in the readiness report.
+	Name() string
+	// Check returns an error when the backend cannot serve requests.
+	Check(c context.Context) error
+}
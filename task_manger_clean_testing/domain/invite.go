@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInvalidInvite is returned for an invite token that is unknown,
+	// expired, revoked or already accepted.
+	ErrInvalidInvite  = errors.New("invite is invalid or has expired")
+	ErrInviteNotFound = errors.New("invite not found")
+	ErrAlreadyMember  = errors.New("user is already a member of this workspace")
+	// ErrInviteNeedsAccount is returned when accepting an invite for an
+	// address without an account, and no account details came with it.
+	ErrInviteNeedsAccount = errors.New("no account exists for the invited address, sign up with the invite")
+	// ErrInviteUnverifiedAccount is returned when the invited address
+	// belongs to an account that never verified it. Anyone could have
+	// signed up with the address, so the invite does not attach to it.
+	ErrInviteUnverifiedAccount = errors.New("verify the email address of your account before accepting the invite")
+	// ErrSignupClosed is returned for a new account outside an invite on
+	// invite-only deployments.
+	ErrSignupClosed = errors.New("signup is by invitation only")
+)
+
+// Invite asks the owner of Email to join a workspace with Role. Only the
+// SHA-256 of the mailed token is stored, like UserToken.
+type Invite struct {
+	ID          string     `json:"id" bson:"id"`
+	WorkspaceID string     `json:"workspace_id" bson:"workspaceid"`
+	Email       string     `json:"email" bson:"email"`
+	Role        string     `json:"role" bson:"role"`
+	Hash        string     `json:"-" bson:"hash"`
+	InvitedBy   string     `json:"invited_by" bson:"invitedby"`
+	CreatedAt   time.Time  `json:"created_at" bson:"createdat"`
+	ExpiresAt   time.Time  `json:"expires_at" bson:"expiresat"`
+	AcceptedAt  *time.Time `json:"accepted_at,omitempty" bson:"acceptedat"`
+}
+
+// NewInvite is what an administrator asks for when inviting someone.
+type NewInvite struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=ADMIN USER"`
+}
+
+// InviteRepository is tenant-scoped like TaskRepository, except for the
+// lookups by token hash: whoever holds the token has no workspace yet.
+type InviteRepository interface {
+	// Create stores invite in the context's workspace.
+	Create(c context.Context, invite Invite) error
+	ListPending(c context.Context, now time.Time) ([]Invite, error)
+	// DeletePending removes the pending invites for email, so only the
+	// newest invite works.
+	DeletePending(c context.Context, email string) error
+	// Delete removes a pending invite or returns ErrInviteNotFound.
+	Delete(c context.Context, id string) error
+	// Renew gives a pending invite a new token hash and expiry and returns
+	// it, or returns ErrInviteNotFound.
+	Renew(c context.Context, id string, hash string, expiresAt time.Time) (*Invite, error)
+	// GetByHash returns the pending, unexpired invite with the given token
+	// hash, or ErrInvalidInvite.
+	GetByHash(c context.Context, hash string, now time.Time) (*Invite, error)
+	// Consume marks the invite accepted and returns it, or returns
+	// ErrInvalidInvite. Only one caller can consume an invite.
+	Consume(c context.Context, hash string, now time.Time) (*Invite, error)
+}
+
+type InviteUsecase interface {
+	// Invite mails an invite to the context's workspace.
+	Invite(c context.Context, invitedBy string, request NewInvite) (*Invite, error)
+	List(c context.Context) ([]Invite, error)
+	Revoke(c context.Context, id string) error
+	// Resend mails a new token for the invite; the old one stops working.
+	Resend(c context.Context, id string) (*Invite, error)
+	// Lookup returns the pending invite of token, or ErrInvalidInvite.
+	Lookup(c context.Context, token string) (*Invite, error)
+	// Accept adds the owner of the invited address to the workspace. An
+	// existing account joins it; otherwise newUser, already validated and
+	// with a hashed password, is signed up. newUser may be nil when an
+	// account exists.
+	Accept(c context.Context, token string, newUser *User) (User, error)
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// LoginAttempt tracks the recent logins for one key, either an account
+// ("account:<email>") or a client address ("ip:<address>"). Failures counts
+// the attempts that failed or are still being checked, and LastFailure is
+// when the latest of them started.
+type LoginAttempt struct {
+	Key         string    `json:"key" bson:"key"`
+	Failures    int       `json:"failures" bson:"failures"`
+	LastFailure time.Time `json:"last_failure" bson:"lastfailure"`
+	LockedUntil time.Time `json:"locked_until" bson:"lockeduntil"`
+}
+
+// LoginThrottledError is returned while a key must wait before trying again,
+// either because of the progressive delay or because it is locked out.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("too many failed login attempts, locked for %s", e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("too many failed login attempts, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+type LoginAttemptStore interface {
+	// Get returns nil when the key has no recorded failures.
+	Get(c context.Context, key string) (*LoginAttempt, error)
+	// Reserve atomically counts an attempt at now and returns the key as it
+	// was before, nil if it had none. Failures older than window are
+	// forgotten first, so the count restarts at one.
+	Reserve(c context.Context, key string, now time.Time, window time.Duration) (*LoginAttempt, error)
+	// Release takes back one reserved attempt that did not fail.
+	Release(c context.Context, key string) error
+	Lock(c context.Context, key string, until time.Time) error
+	Reset(c context.Context, key string) error
+}
+
+type LoginThrottleUsecase interface {
+	// Reserve counts an attempt against the account and the address before
+	// the password is checked, or returns a *LoginThrottledError if either
+	// must not attempt a login yet. Every reserved attempt ends in Failure
+	// or Success.
+	Reserve(c context.Context, email string, ip string) error
+	Failure(c context.Context, email string, ip string) error
+	Success(c context.Context, email string, ip string) error
+	Unlock(c context.Context, email string) error
+}
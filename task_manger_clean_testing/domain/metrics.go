@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// RepositoryMetrics records the calls to the repositories for monitoring.
+type RepositoryMetrics interface {
+	// ObserveRepositoryCall records one call of method on repository and the
+	// error it returned, nil when it succeeded or found nothing.
+	ObserveRepositoryCall(repository string, method string, duration time.Duration, err error)
+}
+
+// TaskStatsRepository counts the tasks of every workspace. Unlike
+// TaskRepository it is not tenant-scoped: only monitoring uses it.
+type TaskStatsRepository interface {
+	CountByStatus(c context.Context) (map[string]int64, error)
+}
@@ -0,0 +1,145 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manger_clean_architecture/domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// AccessTokenRepository is an autogenerated mock type for the AccessTokenRepository type
+type AccessTokenRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c, token
+func (_m *AccessTokenRepository) Create(c context.Context, token domain.AccessToken) error {
+	ret := _m.Called(c, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AccessToken) error); ok {
+		r0 = rf(c, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: c, user_id, id
+func (_m *AccessTokenRepository) Delete(c context.Context, user_id string, id string) error {
+	ret := _m.Called(c, user_id, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, user_id, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByHash provides a mock function with given fields: c, hash
+func (_m *AccessTokenRepository) GetByHash(c context.Context, hash string) (*domain.AccessToken, error) {
+	ret := _m.Called(c, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByHash")
+	}
+
+	var r0 *domain.AccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.AccessToken, error)); ok {
+		return rf(c, hash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.AccessToken); ok {
+		r0 = rf(c, hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AccessToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListForUser provides a mock function with given fields: c, user_id
+func (_m *AccessTokenRepository) ListForUser(c context.Context, user_id string) ([]domain.AccessToken, error) {
+	ret := _m.Called(c, user_id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListForUser")
+	}
+
+	var r0 []domain.AccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.AccessToken, error)); ok {
+		return rf(c, user_id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.AccessToken); ok {
+		r0 = rf(c, user_id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AccessToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, user_id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetLastUsed provides a mock function with given fields: c, id, now
+func (_m *AccessTokenRepository) SetLastUsed(c context.Context, id string, now time.Time) error {
+	ret := _m.Called(c, id, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetLastUsed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(c, id, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAccessTokenRepository creates a new instance of AccessTokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAccessTokenRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AccessTokenRepository {
+	mock := &AccessTokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,144 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manger_clean_architecture/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AccessTokenUsecase is an autogenerated mock type for the AccessTokenUsecase type
+type AccessTokenUsecase struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: c, token
+func (_m *AccessTokenUsecase) Authenticate(c context.Context, token string) (domain.User, []string, error) {
+	ret := _m.Called(c, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authenticate")
+	}
+
+	var r0 domain.User
+	var r1 []string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.User, []string, error)); ok {
+		return rf(c, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(c, token)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) []string); ok {
+		r1 = rf(c, token)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(c, token)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Create provides a mock function with given fields: c, user_id, role, request
+func (_m *AccessTokenUsecase) Create(c context.Context, user_id string, role string, request domain.NewAccessToken) (*domain.CreatedAccessToken, error) {
+	ret := _m.Called(c, user_id, role, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *domain.CreatedAccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.NewAccessToken) (*domain.CreatedAccessToken, error)); ok {
+		return rf(c, user_id, role, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.NewAccessToken) *domain.CreatedAccessToken); ok {
+		r0 = rf(c, user_id, role, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.CreatedAccessToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, domain.NewAccessToken) error); ok {
+		r1 = rf(c, user_id, role, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: c, user_id
+func (_m *AccessTokenUsecase) List(c context.Context, user_id string) ([]domain.AccessToken, error) {
+	ret := _m.Called(c, user_id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []domain.AccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.AccessToken, error)); ok {
+		return rf(c, user_id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.AccessToken); ok {
+		r0 = rf(c, user_id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AccessToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, user_id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revoke provides a mock function with given fields: c, user_id, id
+func (_m *AccessTokenUsecase) Revoke(c context.Context, user_id string, id string) error {
+	ret := _m.Called(c, user_id, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revoke")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, user_id, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAccessTokenUsecase creates a new instance of AccessTokenUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAccessTokenUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AccessTokenUsecase {
+	mock := &AccessTokenUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
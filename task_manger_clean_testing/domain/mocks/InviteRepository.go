@@ -0,0 +1,205 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manger_clean_architecture/domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// InviteRepository is an autogenerated mock type for the InviteRepository type
+type InviteRepository struct {
+	mock.Mock
+}
+
+// Consume provides a mock function with given fields: c, hash, now
+func (_m *InviteRepository) Consume(c context.Context, hash string, now time.Time) (*domain.Invite, error) {
+	ret := _m.Called(c, hash, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Consume")
+	}
+
+	var r0 *domain.Invite
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (*domain.Invite, error)); ok {
+		return rf(c, hash, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *domain.Invite); ok {
+		r0 = rf(c, hash, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Invite)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(c, hash, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: c, invite
+func (_m *InviteRepository) Create(c context.Context, invite domain.Invite) error {
+	ret := _m.Called(c, invite)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Invite) error); ok {
+		r0 = rf(c, invite)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: c, id
+func (_m *InviteRepository) Delete(c context.Context, id string) error {
+	ret := _m.Called(c, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeletePending provides a mock function with given fields: c, email
+func (_m *InviteRepository) DeletePending(c context.Context, email string) error {
+	ret := _m.Called(c, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePending")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByHash provides a mock function with given fields: c, hash, now
+func (_m *InviteRepository) GetByHash(c context.Context, hash string, now time.Time) (*domain.Invite, error) {
+	ret := _m.Called(c, hash, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByHash")
+	}
+
+	var r0 *domain.Invite
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (*domain.Invite, error)); ok {
+		return rf(c, hash, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *domain.Invite); ok {
+		r0 = rf(c, hash, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Invite)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(c, hash, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPending provides a mock function with given fields: c, now
+func (_m *InviteRepository) ListPending(c context.Context, now time.Time) ([]domain.Invite, error) {
+	ret := _m.Called(c, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPending")
+	}
+
+	var r0 []domain.Invite
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]domain.Invite, error)); ok {
+		return rf(c, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []domain.Invite); ok {
+		r0 = rf(c, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Invite)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(c, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Renew provides a mock function with given fields: c, id, hash, expiresAt
+func (_m *InviteRepository) Renew(c context.Context, id string, hash string, expiresAt time.Time) (*domain.Invite, error) {
+	ret := _m.Called(c, id, hash, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Renew")
+	}
+
+	var r0 *domain.Invite
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (*domain.Invite, error)); ok {
+		return rf(c, id, hash, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) *domain.Invite); ok {
+		r0 = rf(c, id, hash, expiresAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Invite)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(c, id, hash, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewInviteRepository creates a new instance of InviteRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewInviteRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *InviteRepository {
+	mock := &InviteRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
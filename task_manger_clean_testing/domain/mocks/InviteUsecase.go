@@ -0,0 +1,195 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manger_clean_architecture/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// InviteUsecase is an autogenerated mock type for the InviteUsecase type
+type InviteUsecase struct {
+	mock.Mock
+}
+
+// Accept provides a mock function with given fields: c, token, newUser
+func (_m *InviteUsecase) Accept(c context.Context, token string, newUser *domain.User) (domain.User, error) {
+	ret := _m.Called(c, token, newUser)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Accept")
+	}
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.User) (domain.User, error)); ok {
+		return rf(c, token, newUser)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.User) domain.User); ok {
+		r0 = rf(c, token, newUser)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.User) error); ok {
+		r1 = rf(c, token, newUser)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Invite provides a mock function with given fields: c, invitedBy, request
+func (_m *InviteUsecase) Invite(c context.Context, invitedBy string, request domain.NewInvite) (*domain.Invite, error) {
+	ret := _m.Called(c, invitedBy, request)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Invite")
+	}
+
+	var r0 *domain.Invite
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.NewInvite) (*domain.Invite, error)); ok {
+		return rf(c, invitedBy, request)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.NewInvite) *domain.Invite); ok {
+		r0 = rf(c, invitedBy, request)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Invite)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.NewInvite) error); ok {
+		r1 = rf(c, invitedBy, request)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: c
+func (_m *InviteUsecase) List(c context.Context) ([]domain.Invite, error) {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []domain.Invite
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Invite, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Invite); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Invite)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Lookup provides a mock function with given fields: c, token
+func (_m *InviteUsecase) Lookup(c context.Context, token string) (*domain.Invite, error) {
+	ret := _m.Called(c, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Lookup")
+	}
+
+	var r0 *domain.Invite
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Invite, error)); ok {
+		return rf(c, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Invite); ok {
+		r0 = rf(c, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Invite)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Resend provides a mock function with given fields: c, id
+func (_m *InviteUsecase) Resend(c context.Context, id string) (*domain.Invite, error) {
+	ret := _m.Called(c, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resend")
+	}
+
+	var r0 *domain.Invite
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Invite, error)); ok {
+		return rf(c, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Invite); ok {
+		r0 = rf(c, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Invite)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revoke provides a mock function with given fields: c, id
+func (_m *InviteUsecase) Revoke(c context.Context, id string) error {
+	ret := _m.Called(c, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revoke")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewInviteUsecase creates a new instance of InviteUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewInviteUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *InviteUsecase {
+	mock := &InviteUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,100 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LoginThrottleUsecase is an autogenerated mock type for the LoginThrottleUsecase type
+type LoginThrottleUsecase struct {
+	mock.Mock
+}
+
+// Failure provides a mock function with given fields: c, email, ip
+func (_m *LoginThrottleUsecase) Failure(c context.Context, email string, ip string) error {
+	ret := _m.Called(c, email, ip)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Failure")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, email, ip)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Reserve provides a mock function with given fields: c, email, ip
+func (_m *LoginThrottleUsecase) Reserve(c context.Context, email string, ip string) error {
+	ret := _m.Called(c, email, ip)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reserve")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, email, ip)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Success provides a mock function with given fields: c, email, ip
+func (_m *LoginThrottleUsecase) Success(c context.Context, email string, ip string) error {
+	ret := _m.Called(c, email, ip)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Success")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, email, ip)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Unlock provides a mock function with given fields: c, email
+func (_m *LoginThrottleUsecase) Unlock(c context.Context, email string) error {
+	ret := _m.Called(c, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unlock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewLoginThrottleUsecase creates a new instance of LoginThrottleUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLoginThrottleUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LoginThrottleUsecase {
+	mock := &LoginThrottleUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
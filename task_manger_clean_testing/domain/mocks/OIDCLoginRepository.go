@@ -0,0 +1,79 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manger_clean_architecture/domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OIDCLoginRepository is an autogenerated mock type for the OIDCLoginRepository type
+type OIDCLoginRepository struct {
+	mock.Mock
+}
+
+// Consume provides a mock function with given fields: c, stateHash, now
+func (_m *OIDCLoginRepository) Consume(c context.Context, stateHash string, now time.Time) (*domain.OIDCLogin, error) {
+	ret := _m.Called(c, stateHash, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Consume")
+	}
+
+	var r0 *domain.OIDCLogin
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (*domain.OIDCLogin, error)); ok {
+		return rf(c, stateHash, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *domain.OIDCLogin); ok {
+		r0 = rf(c, stateHash, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OIDCLogin)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(c, stateHash, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: c, login
+func (_m *OIDCLoginRepository) Create(c context.Context, login domain.OIDCLogin) error {
+	ret := _m.Called(c, login)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.OIDCLogin) error); ok {
+		r0 = rf(c, login)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewOIDCLoginRepository creates a new instance of OIDCLoginRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOIDCLoginRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OIDCLoginRepository {
+	mock := &OIDCLoginRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,61 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manger_clean_architecture/domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// RateLimitStore is an autogenerated mock type for the RateLimitStore type
+type RateLimitStore struct {
+	mock.Mock
+}
+
+// Take provides a mock function with given fields: c, key, limit, now
+func (_m *RateLimitStore) Take(c context.Context, key string, limit domain.RateLimit, now time.Time) (*domain.RateLimitResult, error) {
+	ret := _m.Called(c, key, limit, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Take")
+	}
+
+	var r0 *domain.RateLimitResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.RateLimit, time.Time) (*domain.RateLimitResult, error)); ok {
+		return rf(c, key, limit, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.RateLimit, time.Time) *domain.RateLimitResult); ok {
+		r0 = rf(c, key, limit, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.RateLimitResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.RateLimit, time.Time) error); ok {
+		r1 = rf(c, key, limit, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRateLimitStore creates a new instance of RateLimitStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRateLimitStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RateLimitStore {
+	mock := &RateLimitStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,33 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RepositoryMetrics is an autogenerated mock type for the RepositoryMetrics type
+type RepositoryMetrics struct {
+	mock.Mock
+}
+
+// ObserveRepositoryCall provides a mock function with given fields: repository, method, duration, err
+func (_m *RepositoryMetrics) ObserveRepositoryCall(repository string, method string, duration time.Duration, err error) {
+	_m.Called(repository, method, duration, err)
+}
+
+// NewRepositoryMetrics creates a new instance of RepositoryMetrics. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRepositoryMetrics(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RepositoryMetrics {
+	mock := &RepositoryMetrics{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,64 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manger_clean_architecture/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TaskEventBus is an autogenerated mock type for the TaskEventBus type
+type TaskEventBus struct {
+	mock.Mock
+}
+
+// Publish provides a mock function with given fields: c, event
+func (_m *TaskEventBus) Publish(c context.Context, event domain.TaskEvent) {
+	_m.Called(c, event)
+}
+
+// Subscribe provides a mock function with given fields: c
+func (_m *TaskEventBus) Subscribe(c context.Context) (<-chan domain.TaskEvent, error) {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscribe")
+	}
+
+	var r0 <-chan domain.TaskEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (<-chan domain.TaskEvent, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) <-chan domain.TaskEvent); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan domain.TaskEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTaskEventBus creates a new instance of TaskEventBus. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTaskEventBus(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TaskEventBus {
+	mock := &TaskEventBus{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
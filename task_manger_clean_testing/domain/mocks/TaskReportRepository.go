@@ -0,0 +1,61 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manger_clean_architecture/domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// TaskReportRepository is an autogenerated mock type for the TaskReportRepository type
+type TaskReportRepository struct {
+	mock.Mock
+}
+
+// TaskReport provides a mock function with given fields: c, now, windows
+func (_m *TaskReportRepository) TaskReport(c context.Context, now time.Time, windows []domain.ReportWindow) (*domain.TaskReport, error) {
+	ret := _m.Called(c, now, windows)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaskReport")
+	}
+
+	var r0 *domain.TaskReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, []domain.ReportWindow) (*domain.TaskReport, error)); ok {
+		return rf(c, now, windows)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, []domain.ReportWindow) *domain.TaskReport); ok {
+		r0 = rf(c, now, windows)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, []domain.ReportWindow) error); ok {
+		r1 = rf(c, now, windows)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTaskReportRepository creates a new instance of TaskReportRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTaskReportRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TaskReportRepository {
+	mock := &TaskReportRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,59 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manger_clean_architecture/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TaskReportUsecase is an autogenerated mock type for the TaskReportUsecase type
+type TaskReportUsecase struct {
+	mock.Mock
+}
+
+// TaskReport provides a mock function with given fields: c
+func (_m *TaskReportUsecase) TaskReport(c context.Context) (*domain.TaskReport, error) {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaskReport")
+	}
+
+	var r0 *domain.TaskReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*domain.TaskReport, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *domain.TaskReport); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TaskReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTaskReportUsecase creates a new instance of TaskReportUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTaskReportUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TaskReportUsecase {
+	mock := &TaskReportUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
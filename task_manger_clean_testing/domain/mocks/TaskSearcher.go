@@ -0,0 +1,125 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manger_clean_architecture/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TaskSearcher is an autogenerated mock type for the TaskSearcher type
+type TaskSearcher struct {
+	mock.Mock
+}
+
+// IndexTask provides a mock function with given fields: c, task
+func (_m *TaskSearcher) IndexTask(c context.Context, task domain.Task) error {
+	ret := _m.Called(c, task)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IndexTask")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Task) error); ok {
+		r0 = rf(c, task)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IndexedTaskIDs provides a mock function with given fields: c
+func (_m *TaskSearcher) IndexedTaskIDs(c context.Context) ([]string, error) {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IndexedTaskIDs")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]string, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
+		r0 = rf(c)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveTask provides a mock function with given fields: c, id
+func (_m *TaskSearcher) RemoveTask(c context.Context, id string) error {
+	ret := _m.Called(c, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveTask")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SearchTasks provides a mock function with given fields: c, query, limit
+func (_m *TaskSearcher) SearchTasks(c context.Context, query string, limit int) ([]*domain.TaskSearchResult, error) {
+	ret := _m.Called(c, query, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchTasks")
+	}
+
+	var r0 []*domain.TaskSearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]*domain.TaskSearchResult, error)); ok {
+		return rf(c, query, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []*domain.TaskSearchResult); ok {
+		r0 = rf(c, query, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.TaskSearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(c, query, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTaskSearcher creates a new instance of TaskSearcher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTaskSearcher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TaskSearcher {
+	mock := &TaskSearcher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
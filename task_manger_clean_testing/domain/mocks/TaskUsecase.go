@@ -120,6 +120,64 @@ func (_m *TaskUsecase) GetTasksById(c context.Context, id string) (*domain.Task,
 	return r0, r1
 }
 
+// ReindexTasks provides a mock function with given fields: c
+func (_m *TaskUsecase) ReindexTasks(c context.Context) (int, error) {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReindexTasks")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(c)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(c)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(c)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchTasks provides a mock function with given fields: c, query, limit
+func (_m *TaskUsecase) SearchTasks(c context.Context, query string, limit int) ([]*domain.TaskSearchResult, error) {
+	ret := _m.Called(c, query, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchTasks")
+	}
+
+	var r0 []*domain.TaskSearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]*domain.TaskSearchResult, error)); ok {
+		return rf(c, query, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []*domain.TaskSearchResult); ok {
+		r0 = rf(c, query, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.TaskSearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(c, query, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateTask provides a mock function with given fields: c, id, updatedTask
 func (_m *TaskUsecase) UpdateTask(c context.Context, id string, updatedTask domain.Task) error {
 	ret := _m.Called(c, id, updatedTask)
@@ -14,6 +14,82 @@ type UserRepository struct {
 	mock.Mock
 }
 
+// AddMembership provides a mock function with given fields: ctx, user_id, membership
+func (_m *UserRepository) AddMembership(ctx context.Context, user_id string, membership domain.WorkspaceMembership) error {
+	ret := _m.Called(ctx, user_id, membership)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddMembership")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.WorkspaceMembership) error); ok {
+		r0 = rf(ctx, user_id, membership)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetMember provides a mock function with given fields: ctx, user_id
+func (_m *UserRepository) GetMember(ctx context.Context, user_id string) (domain.User, error) {
+	ret := _m.Called(ctx, user_id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMember")
+	}
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.User, error)); ok {
+		return rf(ctx, user_id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(ctx, user_id)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, user_id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMembers provides a mock function with given fields: ctx, user_ids
+func (_m *UserRepository) GetMembers(ctx context.Context, user_ids []string) ([]domain.User, error) {
+	ret := _m.Called(ctx, user_ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMembers")
+	}
+
+	var r0 []domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]domain.User, error)); ok {
+		return rf(ctx, user_ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []domain.User); ok {
+		r0 = rf(ctx, user_ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, user_ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUser provides a mock function with given fields: ctx, user_id
 func (_m *UserRepository) GetUser(ctx context.Context, user_id string) (domain.User, error) {
 	ret := _m.Called(ctx, user_id)
@@ -165,6 +241,60 @@ func (_m *UserRepository) Promote(ctx context.Context, user_id string, userType
 	return r0, r1, r2
 }
 
+// SetEmailVerified provides a mock function with given fields: c, user_id
+func (_m *UserRepository) SetEmailVerified(c context.Context, user_id string) error {
+	ret := _m.Called(c, user_id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetEmailVerified")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, user_id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPasswordHash provides a mock function with given fields: c, user_id, password
+func (_m *UserRepository) SetPasswordHash(c context.Context, user_id string, password string) error {
+	ret := _m.Called(c, user_id, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPasswordHash")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, user_id, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetTwoFactor provides a mock function with given fields: c, user_id, settings
+func (_m *UserRepository) SetTwoFactor(c context.Context, user_id string, settings domain.TwoFactorSettings) error {
+	ret := _m.Called(c, user_id, settings)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTwoFactor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.TwoFactorSettings) error); ok {
+		r0 = rf(c, user_id, settings)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Signup provides a mock function with given fields: ctx, user
 func (_m *UserRepository) Signup(ctx context.Context, user domain.User) (interface{}, error) {
 	ret := _m.Called(ctx, user)
@@ -195,17 +325,53 @@ func (_m *UserRepository) Signup(ctx context.Context, user domain.User) (interfa
 	return r0, r1
 }
 
-// UpdateAllTokens provides a mock function with given fields: token, refreshToken, user_id
-func (_m *UserRepository) UpdateAllTokens(token string, refreshToken string, user_id string) error {
-	ret := _m.Called(token, refreshToken, user_id)
+// UpdateAllTokens provides a mock function with given fields: c, token, refreshToken, user_id
+func (_m *UserRepository) UpdateAllTokens(c context.Context, token string, refreshToken string, user_id string) error {
+	ret := _m.Called(c, token, refreshToken, user_id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateAllTokens")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = rf(token, refreshToken, user_id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(c, token, refreshToken, user_id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: c, user_id, password
+func (_m *UserRepository) UpdatePassword(c context.Context, user_id string, password string) error {
+	ret := _m.Called(c, user_id, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, user_id, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateProfile provides a mock function with given fields: c, user_id, update
+func (_m *UserRepository) UpdateProfile(c context.Context, user_id string, update domain.ProfileUpdate) error {
+	ret := _m.Called(c, user_id, update)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ProfileUpdate) error); ok {
+		r0 = rf(c, user_id, update)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -213,6 +379,62 @@ func (_m *UserRepository) UpdateAllTokens(token string, refreshToken string, use
 	return r0
 }
 
+// UseRecoveryCode provides a mock function with given fields: c, user_id, hash
+func (_m *UserRepository) UseRecoveryCode(c context.Context, user_id string, hash string) (bool, error) {
+	ret := _m.Called(c, user_id, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (bool, error)); ok {
+		return rf(c, user_id, hash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(c, user_id, hash)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(c, user_id, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseTOTPStep provides a mock function with given fields: c, user_id, step
+func (_m *UserRepository) UseTOTPStep(c context.Context, user_id string, step int64) (bool, error) {
+	ret := _m.Called(c, user_id, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseTOTPStep")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (bool, error)); ok {
+		return rf(c, user_id, step)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) bool); ok {
+		r0 = rf(c, user_id, step)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(c, user_id, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewUserRepository creates a new instance of UserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserRepository(t interface {
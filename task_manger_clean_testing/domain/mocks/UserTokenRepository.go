@@ -0,0 +1,97 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manger_clean_architecture/domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// UserTokenRepository is an autogenerated mock type for the UserTokenRepository type
+type UserTokenRepository struct {
+	mock.Mock
+}
+
+// Consume provides a mock function with given fields: c, hash, purpose, now
+func (_m *UserTokenRepository) Consume(c context.Context, hash string, purpose string, now time.Time) (*domain.UserToken, error) {
+	ret := _m.Called(c, hash, purpose, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Consume")
+	}
+
+	var r0 *domain.UserToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (*domain.UserToken, error)); ok {
+		return rf(c, hash, purpose, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time) *domain.UserToken); ok {
+		r0 = rf(c, hash, purpose, now)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = rf(c, hash, purpose, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: c, token
+func (_m *UserTokenRepository) Create(c context.Context, token domain.UserToken) error {
+	ret := _m.Called(c, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserToken) error); ok {
+		r0 = rf(c, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteForUser provides a mock function with given fields: c, user_id, purpose
+func (_m *UserTokenRepository) DeleteForUser(c context.Context, user_id string, purpose string) error {
+	ret := _m.Called(c, user_id, purpose)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteForUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, user_id, purpose)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewUserTokenRepository creates a new instance of UserTokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserTokenRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserTokenRepository {
+	mock := &UserTokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -14,6 +14,82 @@ type UserUseCase struct {
 	mock.Mock
 }
 
+// ChangePassword provides a mock function with given fields: c, user_id, password
+func (_m *UserUseCase) ChangePassword(c context.Context, user_id string, password string) error {
+	ret := _m.Called(c, user_id, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, user_id, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetMember provides a mock function with given fields: c, user_id
+func (_m *UserUseCase) GetMember(c context.Context, user_id string) (domain.User, error) {
+	ret := _m.Called(c, user_id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMember")
+	}
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.User, error)); ok {
+		return rf(c, user_id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(c, user_id)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, user_id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMembers provides a mock function with given fields: c, user_ids
+func (_m *UserUseCase) GetMembers(c context.Context, user_ids []string) ([]domain.User, error) {
+	ret := _m.Called(c, user_ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMembers")
+	}
+
+	var r0 []domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]domain.User, error)); ok {
+		return rf(c, user_ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []domain.User); ok {
+		r0 = rf(c, user_ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(c, user_ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUser provides a mock function with given fields: c, user_id
 func (_m *UserUseCase) GetUser(c context.Context, user_id string) (domain.User, error) {
 	ret := _m.Called(c, user_id)
@@ -195,17 +271,63 @@ func (_m *UserUseCase) Signup(c context.Context, user domain.User) (interface{},
 	return r0, r1
 }
 
-// UpdateAllTokens provides a mock function with given fields: token, refreshToken, user_id
-func (_m *UserUseCase) UpdateAllTokens(token string, refreshToken string, user_id string) error {
-	ret := _m.Called(token, refreshToken, user_id)
+// UpdateAllTokens provides a mock function with given fields: c, token, refreshToken, user_id
+func (_m *UserUseCase) UpdateAllTokens(c context.Context, token string, refreshToken string, user_id string) error {
+	ret := _m.Called(c, token, refreshToken, user_id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateAllTokens")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
-		r0 = rf(token, refreshToken, user_id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(c, token, refreshToken, user_id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateProfile provides a mock function with given fields: c, user_id, role, update
+func (_m *UserUseCase) UpdateProfile(c context.Context, user_id string, role string, update domain.ProfileUpdate) (domain.User, error) {
+	ret := _m.Called(c, user_id, role, update)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.ProfileUpdate) (domain.User, error)); ok {
+		return rf(c, user_id, role, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.ProfileUpdate) domain.User); ok {
+		r0 = rf(c, user_id, role, update)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, domain.ProfileUpdate) error); ok {
+		r1 = rf(c, user_id, role, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpgradePasswordHash provides a mock function with given fields: c, user_id, password
+func (_m *UserUseCase) UpgradePasswordHash(c context.Context, user_id string, password string) error {
+	ret := _m.Called(c, user_id, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpgradePasswordHash")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, user_id, password)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -0,0 +1,77 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manger_clean_architecture/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WorkspaceRepository is an autogenerated mock type for the WorkspaceRepository type
+type WorkspaceRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c, workspace
+func (_m *WorkspaceRepository) Create(c context.Context, workspace domain.Workspace) error {
+	ret := _m.Called(c, workspace)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Workspace) error); ok {
+		r0 = rf(c, workspace)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByIDs provides a mock function with given fields: c, ids
+func (_m *WorkspaceRepository) GetByIDs(c context.Context, ids []string) ([]domain.Workspace, error) {
+	ret := _m.Called(c, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByIDs")
+	}
+
+	var r0 []domain.Workspace
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]domain.Workspace, error)); ok {
+		return rf(c, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []domain.Workspace); ok {
+		r0 = rf(c, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Workspace)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(c, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewWorkspaceRepository creates a new instance of WorkspaceRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWorkspaceRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WorkspaceRepository {
+	mock := &WorkspaceRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,117 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "task_manger_clean_architecture/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WorkspaceUsecase is an autogenerated mock type for the WorkspaceUsecase type
+type WorkspaceUsecase struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: c, user_id, name
+func (_m *WorkspaceUsecase) Create(c context.Context, user_id string, name string) (*domain.Workspace, error) {
+	ret := _m.Called(c, user_id, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *domain.Workspace
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.Workspace, error)); ok {
+		return rf(c, user_id, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.Workspace); ok {
+		r0 = rf(c, user_id, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Workspace)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(c, user_id, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: c, user_id
+func (_m *WorkspaceUsecase) List(c context.Context, user_id string) ([]domain.MemberWorkspace, error) {
+	ret := _m.Called(c, user_id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []domain.MemberWorkspace
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.MemberWorkspace, error)); ok {
+		return rf(c, user_id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.MemberWorkspace); ok {
+		r0 = rf(c, user_id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.MemberWorkspace)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, user_id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Membership provides a mock function with given fields: c, user_id, workspace_id
+func (_m *WorkspaceUsecase) Membership(c context.Context, user_id string, workspace_id string) (domain.WorkspaceMembership, error) {
+	ret := _m.Called(c, user_id, workspace_id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Membership")
+	}
+
+	var r0 domain.WorkspaceMembership
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.WorkspaceMembership, error)); ok {
+		return rf(c, user_id, workspace_id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.WorkspaceMembership); ok {
+		r0 = rf(c, user_id, workspace_id)
+	} else {
+		r0 = ret.Get(0).(domain.WorkspaceMembership)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(c, user_id, workspace_id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewWorkspaceUsecase creates a new instance of WorkspaceUsecase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWorkspaceUsecase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WorkspaceUsecase {
+	mock := &WorkspaceUsecase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
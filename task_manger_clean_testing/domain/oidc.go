@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInvalidOIDCState is returned for a callback that does not match a
+	// login this server started, or one that has expired or was used.
+	ErrInvalidOIDCState = errors.New("invalid or expired login state")
+	// ErrOIDCEmailNotVerified is returned when the identity provider does
+	// not vouch for the email address, which is what accounts are matched on.
+	ErrOIDCEmailNotVerified = errors.New("identity provider has not verified the email address")
+)
+
+// OIDCIdentity is what a verified ID token says about the user.
+type OIDCIdentity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+}
+
+// IdentityProvider is an external OpenID Connect provider users can log in
+// through with the authorization code flow and PKCE.
+type IdentityProvider interface {
+	// AuthCodeURL returns the provider URL that starts a login. The code
+	// challenge is the S256 challenge of the login's code verifier.
+	AuthCodeURL(c context.Context, state string, nonce string, codeChallenge string) (string, error)
+	// Exchange redeems code and returns the identity in the ID token, after
+	// checking its signature, issuer, audience, expiry and nonce.
+	Exchange(c context.Context, code string, codeVerifier string, nonce string) (OIDCIdentity, error)
+}
+
+// OIDCLogin is a login in progress, stored until the provider redirects the
+// user back. State is stored hashed, like the other single-use tokens.
+type OIDCLogin struct {
+	StateHash    string    `bson:"statehash"`
+	Nonce        string    `bson:"nonce"`
+	CodeVerifier string    `bson:"codeverifier"`
+	CreatedAt    time.Time `bson:"createdat"`
+	ExpiresAt    time.Time `bson:"expiresat"`
+}
+
+type OIDCLoginRepository interface {
+	Create(c context.Context, login OIDCLogin) error
+	// Consume removes and returns the unexpired login with the state hash,
+	// or returns ErrInvalidOIDCState.
+	Consume(c context.Context, stateHash string, now time.Time) (*OIDCLogin, error)
+}
+
+type OIDCUsecase interface {
+	// Begin starts a login. It returns the state, which the caller binds to
+	// the browser, and the provider URL to send the browser to.
+	Begin(c context.Context) (string, string, error)
+	// Complete finishes the login started with state and returns the user
+	// with the identity's email address, creating one on the first login.
+	Complete(c context.Context, state string, code string) (User, error)
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrUnknownPasswordHash is returned for a stored hash in a format no
+	// configured algorithm understands.
+	ErrUnknownPasswordHash = errors.New("unknown password hash format")
+	ErrPasswordTooShort    = errors.New("password is too short")
+	ErrPasswordBreached    = errors.New("password appears in a list of breached passwords")
+)
+
+// PasswordHasher hashes passwords into self-describing strings that carry
+// the algorithm and its parameters, so older hashes keep verifying after the
+// configuration changes.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Verify reports whether password matches hash, and whether hash should
+	// be replaced because it was made with another algorithm or weaker
+	// parameters than the hasher now uses.
+	Verify(password string, hash string) (match bool, needsRehash bool, err error)
+}
+
+// PasswordPolicy decides whether a new password may be set. It is not applied
+// to passwords that are already stored.
+type PasswordPolicy interface {
+	Validate(password string) error
+}
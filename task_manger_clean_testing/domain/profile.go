@@ -0,0 +1,38 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrProfileFieldNotEditable is returned when an update sets a field the
+	// user's role may not change.
+	ErrProfileFieldNotEditable = errors.New("field cannot be edited")
+	ErrEmailTaken              = errors.New("email is already in use")
+)
+
+// ProfileUpdate holds the profile fields a user can change themselves. Nil
+// fields are left unchanged.
+type ProfileUpdate struct {
+	FirstName *string `json:"firstname" validate:"omitempty,min=2,max=100"`
+	LastName  *string `json:"lastname" validate:"omitempty,min=2,max=100"`
+	Phone     *string `json:"phone" validate:"omitempty,min=1"`
+	Email     *string `json:"email" validate:"omitempty,email"`
+}
+
+// Fields returns the names of the fields the update sets, using the same
+// names as the user document.
+func (p ProfileUpdate) Fields() []string {
+	var fields []string
+	if p.FirstName != nil {
+		fields = append(fields, "firstname")
+	}
+	if p.LastName != nil {
+		fields = append(fields, "lastname")
+	}
+	if p.Phone != nil {
+		fields = append(fields, "phone")
+	}
+	if p.Email != nil {
+		fields = append(fields, "email")
+	}
+	return fields
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimit is a token bucket: it holds at most Burst tokens and refills
+// Rate tokens every Period. Each request takes one token.
+type RateLimit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+}
+
+// RateLimitResult describes a bucket after one request was taken from it.
+// ResetAfter is how long until the bucket is full again and RetryAfter, set
+// only when the request was denied, how long until the next token.
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
+
+type RateLimitStore interface {
+	// Take removes one token from the bucket for key if it has any.
+	Take(c context.Context, key string, limit RateLimit, now time.Time) (*RateLimitResult, error)
+}
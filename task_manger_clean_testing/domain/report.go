@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// CompletedTaskStatuses are the lower-cased task statuses that count as done.
+var CompletedTaskStatuses = []string{"completed", "done"}
+
+// IsTaskCompleted reports whether status is one of CompletedTaskStatuses,
+// ignoring case.
+func IsTaskCompleted(status string) bool {
+	for _, completed := range CompletedTaskStatuses {
+		if strings.EqualFold(status, completed) {
+			return true
+		}
+	}
+	return false
+}
+
+// TaskReportWindow summarises the tasks created and completed since Since.
+// CompletionRate is the share of the tasks created in the window that are
+// completed now.
+type TaskReportWindow struct {
+	Name           string    `json:"name"`
+	Since          time.Time `json:"since"`
+	Created        int64     `json:"created"`
+	Completed      int64     `json:"completed"`
+	CompletionRate float64   `json:"completion_rate"`
+}
+
+// UserTaskReport is the per-user breakdown of a TaskReport, keyed by the uid
+// of the user who created the tasks.
+type UserTaskReport struct {
+	UserID    string `json:"user_id"`
+	Total     int64  `json:"total"`
+	Completed int64  `json:"completed"`
+	Overdue   int64  `json:"overdue"`
+}
+
+type TaskReport struct {
+	GeneratedAt              time.Time          `json:"generated_at"`
+	Total                    int64              `json:"total"`
+	ByStatus                 map[string]int64   `json:"by_status"`
+	Overdue                  int64              `json:"overdue"`
+	CompletionRate           float64            `json:"completion_rate"`
+	AverageCompletionSeconds float64            `json:"average_completion_seconds"`
+	Windows                  []TaskReportWindow `json:"windows"`
+	Users                    []UserTaskReport   `json:"users"`
+}
+
+// ReportWindow names a time window of a TaskReport, e.g. "7d".
+type ReportWindow struct {
+	Name     string
+	Duration time.Duration
+}
+
+// TaskReportRepository reports on the tasks of the context's workspace.
+type TaskReportRepository interface {
+	TaskReport(c context.Context, now time.Time, windows []ReportWindow) (*TaskReport, error)
+}
+
+type TaskReportUsecase interface {
+	TaskReport(c context.Context) (*TaskReport, error)
+}
@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// RequestIDHeader carries the ID correlating the log lines and responses of
+// one request, including across services.
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+func WithRequestID(c context.Context, request_id string) context.Context {
+	return context.WithValue(c, requestIDKey{}, request_id)
+}
+
+// RequestIDFrom returns the ID of the request c serves, or "" outside one.
+func RequestIDFrom(c context.Context) string {
+	request_id, _ := c.Value(requestIDKey{}).(string)
+	return request_id
+}
+
+// RequestIDOrNew returns the ID a caller sent, or a new random one when it
+// sent none. The IDs of other services and proxies come in many formats and
+// are kept as long as they are safe to log and echo.
+func RequestIDOrNew(request_id string) string {
+	if validRequestID(request_id) {
+		return request_id
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func validRequestID(request_id string) bool {
+	if request_id == "" || len(request_id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range request_id {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "context"
+
+// TaskSearchResult is a single hit returned by a TaskSearcher, ordered by Score.
+// Highlights holds a snippet per matched field ("title", "description") with
+// the matching words wrapped in <mark></mark>.
+type TaskSearchResult struct {
+	Task       *Task             `json:"task"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+// TaskSearcher maintains a full-text index over task titles and descriptions.
+//
+// Queries are made of plain terms, "quoted phrases" and prefix* terms. Every
+// phrase and prefix term must match. At least one plain term must match unless
+// the query contains a phrase, in which case plain terms only affect ranking.
+//
+// It is tenant-scoped like TaskRepository: tasks are indexed into, removed
+// from and searched in the workspace of the context.
+type TaskSearcher interface {
+	IndexTask(c context.Context, task Task) error
+	RemoveTask(c context.Context, id string) error
+	// IndexedTaskIDs returns the IDs of every indexed task.
+	IndexedTaskIDs(c context.Context) ([]string, error)
+	SearchTasks(c context.Context, query string, limit int) ([]*TaskSearchResult, error)
+}
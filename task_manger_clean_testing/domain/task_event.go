@@ -0,0 +1,30 @@
+package domain
+
+import "context"
+
+// Task event types.
+const (
+	TaskCreated = "task.created"
+	TaskUpdated = "task.updated"
+	TaskDeleted = "task.deleted"
+)
+
+// TaskEvent is a change to a task, published once it is stored. The task of
+// a TaskDeleted event only has its ID and WorkspaceID set.
+type TaskEvent struct {
+	Type string `json:"type"`
+	Task Task   `json:"task"`
+}
+
+// TaskEventBus delivers task events to the watchers of the task's workspace.
+// Events are not stored: a watcher only gets those published while it is
+// subscribed.
+type TaskEventBus interface {
+	// Publish sends event to the subscribers of event.Task.WorkspaceID. It
+	// does not wait for them: a subscriber whose buffer is full is dropped.
+	Publish(c context.Context, event TaskEvent)
+	// Subscribe returns the events of the context's workspace. The channel is
+	// closed when c is done, or earlier if the subscriber fell behind and was
+	// dropped. It fails with ErrNoWorkspace without a workspace.
+	Subscribe(c context.Context) (<-chan TaskEvent, error)
+}
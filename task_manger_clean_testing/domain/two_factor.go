@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// UserTokenLoginChallenge is the purpose of the token a password login
+// returns when the account still has to pass its second factor.
+const UserTokenLoginChallenge = "login_challenge"
+
+var (
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("two-factor authentication has not been enrolled")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+	// ErrTwoFactorRequired is returned when an administrator tries to turn
+	// two-factor authentication off.
+	ErrTwoFactorRequired = errors.New("two-factor authentication is required for administrators")
+)
+
+// TwoFactorSettings is the TOTP state of an account. Secret is set at
+// enrollment and only used for logins once a code confirmed it.
+type TwoFactorSettings struct {
+	Enabled bool   `bson:"enabled"`
+	Secret  string `bson:"secret"`
+	// LastStep is the last TOTP time step accepted, so a code cannot be
+	// replayed within its validity window.
+	LastStep int64 `bson:"laststep"`
+	// RecoveryCodes are the SHA-256 hashes of the unused recovery codes.
+	RecoveryCodes []string `bson:"recoverycodes"`
+}
+
+// TwoFactorEnrollment is shown to the user once, when they enroll.
+type TwoFactorEnrollment struct {
+	Secret        string   `json:"secret"`
+	URI           string   `json:"otpauth_uri"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TOTP generates and checks RFC 6238 time-based one-time passwords.
+type TOTP interface {
+	GenerateSecret() (string, error)
+	// URI returns the otpauth:// URI authenticator apps enroll from.
+	URI(secret string, account string) string
+	// Validate returns the time step code belongs to, allowing one step of
+	// clock skew either way, and false if it matches none.
+	Validate(secret string, code string, now time.Time) (int64, bool)
+}
+
+type TwoFactorUsecase interface {
+	// Enroll starts over with a new secret and recovery codes. Two-factor
+	// authentication is not enabled until Confirm succeeds.
+	Enroll(c context.Context, user_id string) (*TwoFactorEnrollment, error)
+	Confirm(c context.Context, user_id string, code string) error
+	Disable(c context.Context, user_id string, code string) error
+	// IssueChallenge returns a short-lived, single-use token that stands for
+	// a correct password until the second factor is checked.
+	IssueChallenge(c context.Context, user_id string) (string, error)
+	// CompleteChallenge redeems challenge with a TOTP or recovery code and
+	// returns the user it was issued for. The challenge is used up even when
+	// the code is wrong.
+	CompleteChallenge(c context.Context, challenge string, code string) (string, error)
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultWorkspaceID is the workspace every account joins on signup and that
+// held all data before workspaces existed.
+const DefaultWorkspaceID = "default"
+
+var (
+	// ErrNoWorkspace is returned by tenant-scoped repositories when the
+	// context carries no workspace, so a forgotten scope fails closed.
+	ErrNoWorkspace        = errors.New("no active workspace")
+	ErrNotWorkspaceMember = errors.New("not a member of this workspace")
+	ErrWorkspaceNotFound  = errors.New("workspace not found")
+)
+
+// Workspace is a tenant. Tasks belong to exactly one workspace and users
+// belong to any number of them, with a role in each.
+type Workspace struct {
+	ID        string    `json:"id" bson:"id"`
+	Name      string    `json:"name" bson:"name"`
+	CreatedBy string    `json:"created_by" bson:"createdby"`
+	CreatedAt time.Time `json:"created_at" bson:"createdat"`
+}
+
+// WorkspaceMembership is a user's role in one workspace, ADMIN or USER.
+type WorkspaceMembership struct {
+	WorkspaceID string `json:"workspace_id" bson:"workspaceid"`
+	Role        string `json:"role" bson:"role"`
+}
+
+// MemberWorkspace is a workspace as listed for one of its members.
+type MemberWorkspace struct {
+	Workspace `bson:",inline"`
+	Role      string `json:"role"`
+}
+
+type workspaceKey struct{}
+
+// WithWorkspace returns a context scoped to workspace_id. The delivery layer
+// sets it from the caller's token; tenant-scoped repositories read it.
+func WithWorkspace(c context.Context, workspace_id string) context.Context {
+	return context.WithValue(c, workspaceKey{}, workspace_id)
+}
+
+// WorkspaceFrom returns the workspace of c, or ErrNoWorkspace.
+func WorkspaceFrom(c context.Context) (string, error) {
+	workspace_id, _ := c.Value(workspaceKey{}).(string)
+	if workspace_id == "" {
+		return "", ErrNoWorkspace
+	}
+	return workspace_id, nil
+}
+
+type WorkspaceRepository interface {
+	Create(c context.Context, workspace Workspace) error
+	// GetByIDs returns the workspaces with the given IDs that exist.
+	GetByIDs(c context.Context, ids []string) ([]Workspace, error)
+}
+
+type WorkspaceUsecase interface {
+	// Create makes a workspace with user_id as its first ADMIN.
+	Create(c context.Context, user_id string, name string) (*Workspace, error)
+	List(c context.Context, user_id string) ([]MemberWorkspace, error)
+	// Membership returns user_id's membership of workspace_id, or
+	// ErrNotWorkspaceMember.
+	Membership(c context.Context, user_id string, workspace_id string) (WorkspaceMembership, error)
+}
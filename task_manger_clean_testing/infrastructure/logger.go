@@ -0,0 +1,39 @@
+package infrastructure
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"task_manger_clean_architecture/domain"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewLogger returns a logger writing JSON lines to w. Lines logged with the
+// context of a request carry its request_id, and its trace_id and span_id
+// when it is traced.
+func NewLogger(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(contextHandler{slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level})})
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(c context.Context, record slog.Record) error {
+	if request_id := domain.RequestIDFrom(c); request_id != "" {
+		record.AddAttrs(slog.String("request_id", request_id))
+	}
+	if span := trace.SpanContextFromContext(c); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(c, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
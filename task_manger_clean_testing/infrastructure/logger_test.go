@@ -0,0 +1,67 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"task_manger_clean_architecture/domain"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type LoggerTestSuite struct {
+	suite.Suite
+	out    bytes.Buffer
+	logger *slog.Logger
+}
+
+func (suite *LoggerTestSuite) SetupTest() {
+	suite.out.Reset()
+	suite.logger = NewLogger(&suite.out, slog.LevelInfo)
+}
+
+func (suite *LoggerTestSuite) line() map[string]any {
+	var line map[string]any
+	suite.Require().NoError(json.Unmarshal(suite.out.Bytes(), &line))
+	return line
+}
+
+func (suite *LoggerTestSuite) TestAddsRequestID() {
+	ctx := domain.WithRequestID(context.Background(), "req-1")
+	suite.logger.With("component", "test").InfoContext(ctx, "hello", "n", 1)
+
+	line := suite.line()
+	suite.Equal("hello", line["msg"])
+	suite.Equal("req-1", line["request_id"])
+	suite.Equal("test", line["component"])
+	suite.Equal(float64(1), line["n"])
+}
+
+func (suite *LoggerTestSuite) TestOmitsRequestIDOutsideRequests() {
+	suite.logger.Info("started")
+	suite.NotContains(suite.line(), "request_id")
+}
+
+func (suite *LoggerTestSuite) TestAddsTraceIDs() {
+	span := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	})
+	suite.logger.InfoContext(trace.ContextWithSpanContext(context.Background(), span), "traced")
+
+	line := suite.line()
+	suite.Equal("4bf92f3577b34da6a3ce929d0e0e4736", line["trace_id"])
+	suite.Equal("00f067aa0ba902b7", line["span_id"])
+}
+
+func (suite *LoggerTestSuite) TestFiltersLevel() {
+	suite.logger.Debug("noise")
+	suite.Empty(suite.out.String())
+}
+
+func TestLoggerTestSuite(t *testing.T) {
+	suite.Run(t, new(LoggerTestSuite))
+}
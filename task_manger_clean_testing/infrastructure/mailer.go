@@ -0,0 +1,50 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"task_manger_clean_architecture/domain"
+	"time"
+)
+
+type fileMailer struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileMailer returns a Mailer that appends every message to the file at
+// path instead of sending it, for development and offline tests.
+func NewFileMailer(path string) domain.Mailer {
+	return &fileMailer{path: path}
+}
+
+func (m *fileMailer) Send(c context.Context, mail domain.Mail) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	file, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n", time.Now().Format(time.RFC1123Z), mail.To, mail.Subject, mail.Body)
+	return err
+}
+
+type logMailer struct {
+	logger *slog.Logger
+}
+
+// NewLogMailer returns a Mailer that logs every message to logger.
+func NewLogMailer(logger *slog.Logger) domain.Mailer {
+	return logMailer{logger: logger}
+}
+
+func (m logMailer) Send(c context.Context, mail domain.Mail) error {
+	m.logger.InfoContext(c, "mail", "to", mail.To, "subject", mail.Subject, "body", mail.Body)
+	return nil
+}
@@ -0,0 +1,157 @@
+package infrastructure
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const metricsNamespace = "task_manager"
+
+// Metrics are the Prometheus metrics of the service. It implements
+// domain.RepositoryMetrics.
+type Metrics struct {
+	httpRequests        *prometheus.CounterVec
+	httpRequestDuration *prometheus.HistogramVec
+	repositoryDuration  *prometheus.HistogramVec
+	repositoryErrors    *prometheus.CounterVec
+	logins              *prometheus.CounterVec
+	tokenRejections     *prometheus.CounterVec
+}
+
+// NewMetrics registers the metrics of the service, and those of the Go
+// runtime and the process, with registry.
+func NewMetrics(registry prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		httpRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of the HTTP requests by method, route template and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		repositoryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "repository_call_duration_seconds",
+			Help:      "Latency of the repository calls by repository and method.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		}, []string{"repository", "method"}),
+		repositoryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "repository_errors_total",
+			Help:      "Repository calls that failed, by repository and method.",
+		}, []string{"repository", "method"}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "auth_logins_total",
+			Help:      "Login attempts by outcome: success, failure or throttled.",
+		}, []string{"outcome"}),
+		tokenRejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "auth_token_rejections_total",
+			Help:      "Bearer tokens refused, by reason.",
+		}, []string{"reason"}),
+	}
+	registry.MustRegister(
+		m.httpRequests,
+		m.httpRequestDuration,
+		m.repositoryDuration,
+		m.repositoryErrors,
+		m.logins,
+		m.tokenRejections,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// ObserveHTTPRequest records an answered request. route is the template,
+// such as /task/:task_id, so the IDs in paths do not multiply the series.
+func (m *Metrics) ObserveHTTPRequest(method string, route string, status int, duration time.Duration) {
+	labels := prometheus.Labels{"method": method, "route": route, "status": strconv.Itoa(status)}
+	m.httpRequests.With(labels).Inc()
+	m.httpRequestDuration.With(labels).Observe(duration.Seconds())
+}
+
+// ObserveRepositoryCall implements domain.RepositoryMetrics.
+func (m *Metrics) ObserveRepositoryCall(repository string, method string, duration time.Duration, err error) {
+	m.repositoryDuration.WithLabelValues(repository, method).Observe(duration.Seconds())
+	if err != nil {
+		m.repositoryErrors.WithLabelValues(repository, method).Inc()
+	}
+}
+
+// Login outcomes.
+const (
+	LoginSuccess   = "success"
+	LoginFailure   = "failure"
+	LoginThrottled = "throttled"
+)
+
+// ObserveLogin records a login attempt with one of the login outcomes.
+func (m *Metrics) ObserveLogin(outcome string) {
+	m.logins.WithLabelValues(outcome).Inc()
+}
+
+// Reasons for refusing a bearer token.
+const (
+	TokenMissing          = "missing"
+	TokenMalformed        = "malformed"
+	TokenInvalid          = "invalid"
+	TokenExpired          = "expired"
+	TokenRevoked          = "revoked"
+	TokenInvalidAccessKey = "invalid_access_token"
+)
+
+// ObserveTokenRejected records a refused bearer token with one of the
+// reasons above.
+func (m *Metrics) ObserveTokenRejected(reason string) {
+	m.tokenRejections.WithLabelValues(reason).Inc()
+}
+
+// taskCollector counts the tasks by status whenever it is scraped, so the
+// gauges are never stale.
+type taskCollector struct {
+	tasks   domain.TaskStatsRepository
+	timeout time.Duration
+	logger  *slog.Logger
+	desc    *prometheus.Desc
+}
+
+// RegisterTaskCounts adds the gauges of the tasks of every workspace by
+// status to registry. Each scrape counts them in tasks within timeout.
+func RegisterTaskCounts(registry prometheus.Registerer, tasks domain.TaskStatsRepository, timeout time.Duration, logger *slog.Logger) {
+	registry.MustRegister(&taskCollector{
+		tasks:   tasks,
+		timeout: timeout,
+		logger:  logger,
+		desc:    prometheus.NewDesc(metricsNamespace+"_tasks", "Tasks of every workspace by status.", []string{"status"}, nil),
+	})
+}
+
+func (t *taskCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- t.desc
+}
+
+func (t *taskCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	defer cancel()
+	counts, err := t.tasks.CountByStatus(ctx)
+	if err != nil {
+		// A scrape without the gauges is better than a failed scrape
+		t.logger.Error("counting tasks for metrics", "error", err)
+		return
+	}
+	for status, count := range counts {
+		ch <- prometheus.MustNewConstMetric(t.desc, prometheus.GaugeValue, float64(count), status)
+	}
+}
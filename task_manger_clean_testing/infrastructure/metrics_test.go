@@ -0,0 +1,85 @@
+package infrastructure
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"task_manger_clean_architecture/domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MetricsTestSuite struct {
+	suite.Suite
+	registry *prometheus.Registry
+	metrics  *Metrics
+}
+
+func (suite *MetricsTestSuite) SetupTest() {
+	suite.registry = prometheus.NewRegistry()
+	suite.metrics = NewMetrics(suite.registry)
+}
+
+func (suite *MetricsTestSuite) TestHTTPRequestsByRouteAndStatus() {
+	suite.metrics.ObserveHTTPRequest("GET", "/task/:task_id", 200, 20*time.Millisecond)
+	suite.metrics.ObserveHTTPRequest("GET", "/task/:task_id", 200, 30*time.Millisecond)
+	suite.metrics.ObserveHTTPRequest("GET", "/task/:task_id", 404, time.Millisecond)
+
+	assert.Equal(suite.T(), 2.0, testutil.ToFloat64(suite.metrics.httpRequests.WithLabelValues("GET", "/task/:task_id", "200")))
+	assert.Equal(suite.T(), 1.0, testutil.ToFloat64(suite.metrics.httpRequests.WithLabelValues("GET", "/task/:task_id", "404")))
+	assert.Equal(suite.T(), 2, testutil.CollectAndCount(suite.metrics.httpRequestDuration))
+}
+
+func (suite *MetricsTestSuite) TestRepositoryErrors() {
+	suite.metrics.ObserveRepositoryCall("task", "GetTasks", time.Millisecond, nil)
+	suite.metrics.ObserveRepositoryCall("task", "AddTask", time.Millisecond, errors.New("timeout"))
+
+	assert.Equal(suite.T(), 2, testutil.CollectAndCount(suite.metrics.repositoryDuration))
+	assert.Equal(suite.T(), 0.0, testutil.ToFloat64(suite.metrics.repositoryErrors.WithLabelValues("task", "GetTasks")))
+	assert.Equal(suite.T(), 1.0, testutil.ToFloat64(suite.metrics.repositoryErrors.WithLabelValues("task", "AddTask")))
+}
+
+func (suite *MetricsTestSuite) TestAuth() {
+	suite.metrics.ObserveLogin(LoginSuccess)
+	suite.metrics.ObserveLogin(LoginFailure)
+	suite.metrics.ObserveLogin(LoginFailure)
+	suite.metrics.ObserveTokenRejected(TokenExpired)
+
+	assert.NoError(suite.T(), testutil.CollectAndCompare(suite.metrics.logins, strings.NewReader(`
+# HELP task_manager_auth_logins_total Login attempts by outcome: success, failure or throttled.
+# TYPE task_manager_auth_logins_total counter
+task_manager_auth_logins_total{outcome="failure"} 2
+task_manager_auth_logins_total{outcome="success"} 1
+`)))
+	assert.Equal(suite.T(), 1.0, testutil.ToFloat64(suite.metrics.tokenRejections.WithLabelValues(TokenExpired)))
+}
+
+func (suite *MetricsTestSuite) TestTaskCounts() {
+	tasks := new(mocks.TaskStatsRepository)
+	tasks.On("CountByStatus", mock.Anything).Return(map[string]int64{"pending": 3, "completed": 5}, nil).Once()
+	RegisterTaskCounts(suite.registry, tasks, time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	assert.NoError(suite.T(), testutil.GatherAndCompare(suite.registry, strings.NewReader(`
+# HELP task_manager_tasks Tasks of every workspace by status.
+# TYPE task_manager_tasks gauge
+task_manager_tasks{status="completed"} 5
+task_manager_tasks{status="pending"} 3
+`), "task_manager_tasks"))
+
+	// A failed count leaves the gauges out of the scrape
+	tasks.On("CountByStatus", mock.Anything).Return(nil, errors.New("timeout")).Once()
+	count, err := testutil.GatherAndCount(suite.registry, "task_manager_tasks")
+	suite.Require().NoError(err)
+	assert.Zero(suite.T(), count)
+}
+
+func TestMetricsTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsTestSuite))
+}
@@ -0,0 +1,303 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// oidcClockSkew is how far the provider's clock may be off from ours when
+// checking the times in an ID token.
+const oidcClockSkew = time.Minute
+
+// OIDCConfig describes the provider and how this server is registered with
+// it. ClientSecret is empty for public clients, which PKCE alone protects.
+type OIDCConfig struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcProvider struct {
+	config OIDCConfig
+	client *http.Client
+	now    func() time.Time
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]*rsa.PublicKey
+}
+
+// NewOIDCProvider returns the provider at config.Issuer. Its discovery
+// document is fetched on first use, so the server starts even when the
+// provider is down.
+func NewOIDCProvider(config OIDCConfig, client *http.Client) domain.IdentityProvider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &oidcProvider{
+		config: config,
+		client: client,
+		now:    time.Now,
+	}
+}
+
+func (o *oidcProvider) getJSON(c context.Context, endpoint string, v interface{}) error {
+	req, err := http.NewRequestWithContext(c, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc: GET %s: %s", endpoint, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func (o *oidcProvider) discover(c context.Context) (*oidcDiscovery, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.discovery != nil {
+		return o.discovery, nil
+	}
+
+	var discovery oidcDiscovery
+	issuer := strings.TrimRight(o.config.Issuer, "/")
+	if err := o.getJSON(c, issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, err
+	}
+	if discovery.Issuer != o.config.Issuer {
+		return nil, fmt.Errorf("oidc: discovery document is for issuer %q", discovery.Issuer)
+	}
+	o.discovery = &discovery
+	return o.discovery, nil
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// key returns the provider's signing key kid. The key set is fetched again
+// when kid is unknown, which is how providers announce rotated keys.
+func (o *oidcProvider) key(c context.Context, jwksURI string, kid string) (*rsa.PublicKey, error) {
+	o.mu.Lock()
+	key, ok := o.keys[kid]
+	o.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := o.getJSON(c, jwksURI, &set); err != nil {
+		return nil, err
+	}
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+
+	o.mu.Lock()
+	o.keys = keys
+	o.mu.Unlock()
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("oidc: unknown signing key %q", kid)
+}
+
+// AuthCodeURL implements domain.IdentityProvider.
+func (o *oidcProvider) AuthCodeURL(c context.Context, state string, nonce string, codeChallenge string) (string, error) {
+	discovery, err := o.discover(c)
+	if err != nil {
+		return "", err
+	}
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", o.config.ClientID)
+	query.Set("redirect_uri", o.config.RedirectURL)
+	query.Set("scope", strings.Join(o.config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// audience accepts the aud claim as a single string or a list of them.
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+func (a audience) contains(clientID string) bool {
+	for _, aud := range a {
+		if aud == clientID {
+			return true
+		}
+	}
+	return false
+}
+
+type idTokenClaims struct {
+	Issuer        string   `json:"iss"`
+	Subject       string   `json:"sub"`
+	Audience      audience `json:"aud"`
+	ExpiresAt     int64    `json:"exp"`
+	IssuedAt      int64    `json:"iat"`
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+	GivenName     string   `json:"given_name"`
+	FamilyName    string   `json:"family_name"`
+}
+
+// Valid is checked in verify instead, against the provider's clock.
+func (claims *idTokenClaims) Valid() error {
+	return nil
+}
+
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Exchange implements domain.IdentityProvider.
+func (o *oidcProvider) Exchange(c context.Context, code string, codeVerifier string, nonce string) (domain.OIDCIdentity, error) {
+	discovery, err := o.discover(c)
+	if err != nil {
+		return domain.OIDCIdentity{}, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", o.config.RedirectURL)
+	form.Set("client_id", o.config.ClientID)
+	form.Set("code_verifier", codeVerifier)
+	if o.config.ClientSecret != "" {
+		form.Set("client_secret", o.config.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(c, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return domain.OIDCIdentity{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return domain.OIDCIdentity{}, err
+	}
+	defer resp.Body.Close()
+	var tokens tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return domain.OIDCIdentity{}, fmt.Errorf("oidc: token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || tokens.Error != "" {
+		return domain.OIDCIdentity{}, fmt.Errorf("oidc: token endpoint: %s %s", tokens.Error, tokens.ErrorDescription)
+	}
+	if tokens.IDToken == "" {
+		return domain.OIDCIdentity{}, errors.New("oidc: token response has no id_token")
+	}
+
+	claims, err := o.verify(c, discovery, tokens.IDToken, nonce)
+	if err != nil {
+		return domain.OIDCIdentity{}, err
+	}
+	return domain.OIDCIdentity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		GivenName:     claims.GivenName,
+		FamilyName:    claims.FamilyName,
+	}, nil
+}
+
+// verify checks the ID token as OpenID Connect Core 3.1.3.7 asks of a
+// client that got it straight from the token endpoint.
+func (o *oidcProvider) verify(c context.Context, discovery *oidcDiscovery, idToken string, nonce string) (*idTokenClaims, error) {
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(token *jwt.Token) (interface{}, error) {
+		if token.Method.Alg() != jwt.SigningMethodRS256.Alg() {
+			return nil, fmt.Errorf("oidc: unexpected signing method %s", token.Method.Alg())
+		}
+		kid, _ := token.Header["kid"].(string)
+		return o.key(c, discovery.JWKSURI, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("oidc: id_token: %w", err)
+	}
+
+	now := o.now()
+	switch {
+	case claims.Issuer != o.config.Issuer:
+		return nil, errors.New("oidc: id_token has the wrong issuer")
+	case !claims.Audience.contains(o.config.ClientID):
+		return nil, errors.New("oidc: id_token is for another client")
+	case time.Unix(claims.ExpiresAt, 0).Add(oidcClockSkew).Before(now):
+		return nil, errors.New("oidc: id_token has expired")
+	case time.Unix(claims.IssuedAt, 0).Add(-oidcClockSkew).After(now):
+		return nil, errors.New("oidc: id_token is issued in the future")
+	case claims.Nonce != nonce:
+		return nil, errors.New("oidc: id_token nonce does not match")
+	case claims.Subject == "":
+		return nil, errors.New("oidc: id_token has no subject")
+	}
+	return claims, nil
+}
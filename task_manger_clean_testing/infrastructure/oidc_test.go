@@ -0,0 +1,255 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeOIDCServer is a stand-in OpenID Connect provider. Its authorization
+// endpoint logs in the configured user without a page and redirects back
+// with a code; its token endpoint checks the PKCE verifier.
+type fakeOIDCServer struct {
+	*httptest.Server
+	key      *rsa.PrivateKey
+	kid      string
+	clientID string
+
+	mu    sync.Mutex
+	codes map[string]fakeAuthorization
+	// claims is applied on top of the ID token claims of the next login.
+	claims jwt.MapClaims
+}
+
+type fakeAuthorization struct {
+	challenge   string
+	nonce       string
+	redirectURI string
+}
+
+func newFakeOIDCServer(clientID string) *fakeOIDCServer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	f := &fakeOIDCServer{key: key, kid: "key-1", clientID: clientID, codes: map[string]fakeAuthorization{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 f.URL,
+			"authorization_endpoint": f.URL + "/authorize",
+			"token_endpoint":         f.URL + "/token",
+			"jwks_uri":               f.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"kid": f.kid,
+			"n":   base64.RawURLEncoding.EncodeToString(f.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(f.key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("client_id") != f.clientID || query.Get("code_challenge_method") != "S256" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		code := base64.RawURLEncoding.EncodeToString([]byte(query.Get("state")))
+		f.mu.Lock()
+		f.codes[code] = fakeAuthorization{
+			challenge:   query.Get("code_challenge"),
+			nonce:       query.Get("nonce"),
+			redirectURI: query.Get("redirect_uri"),
+		}
+		f.mu.Unlock()
+		http.Redirect(w, r, query.Get("redirect_uri")+"?code="+code+"&state="+url.QueryEscape(query.Get("state")), http.StatusFound)
+	})
+	mux.HandleFunc("/token", f.token)
+	f.Server = httptest.NewServer(mux)
+	return f
+}
+
+func (f *fakeOIDCServer) token(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	f.mu.Lock()
+	authorization, ok := f.codes[r.PostForm.Get("code")]
+	delete(f.codes, r.PostForm.Get("code"))
+	extra := f.claims
+	f.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || r.PostForm.Get("client_id") != f.clientID ||
+		r.PostForm.Get("redirect_uri") != authorization.redirectURI ||
+		base64.RawURLEncoding.EncodeToString(sum[:]) != authorization.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":            f.URL,
+		"sub":            "subject-1",
+		"aud":            f.clientID,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"nonce":          authorization.nonce,
+		"email":          "sso@example.com",
+		"email_verified": true,
+		"given_name":     "Sam",
+		"family_name":    "Sso",
+	}
+	for name, value := range extra {
+		claims[name] = value
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = f.kid
+	signed, err := token.SignedString(f.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"id_token": signed, "token_type": "Bearer"})
+}
+
+type OIDCProviderTestSuite struct {
+	suite.Suite
+	server   *fakeOIDCServer
+	provider *oidcProvider
+	client   *http.Client
+}
+
+func (suite *OIDCProviderTestSuite) SetupTest() {
+	suite.server = newFakeOIDCServer("task-manager")
+	suite.provider = NewOIDCProvider(OIDCConfig{
+		Issuer:      suite.server.URL,
+		ClientID:    "task-manager",
+		RedirectURL: "http://localhost:8080/login/oidc/callback",
+		Scopes:      []string{"openid", "email"},
+	}, nil).(*oidcProvider)
+	suite.client = &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+}
+
+func (suite *OIDCProviderTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+// login follows the provider's authorization endpoint and returns the code
+// it redirects back with.
+func (suite *OIDCProviderTestSuite) login(state, nonce, verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	authURL, err := suite.provider.AuthCodeURL(context.Background(), state, nonce, base64.RawURLEncoding.EncodeToString(sum[:]))
+	suite.Require().NoError(err)
+
+	resp, err := suite.client.Get(authURL)
+	suite.Require().NoError(err)
+	resp.Body.Close()
+	suite.Require().Equal(http.StatusFound, resp.StatusCode)
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	suite.Require().NoError(err)
+	suite.Equal(state, callback.Query().Get("state"))
+	return callback.Query().Get("code")
+}
+
+func (suite *OIDCProviderTestSuite) TestAuthorizationCodeFlow() {
+	code := suite.login("state", "nonce", "verifier")
+
+	identity, err := suite.provider.Exchange(context.Background(), code, "verifier", "nonce")
+	suite.Require().NoError(err)
+	suite.Equal("subject-1", identity.Subject)
+	suite.Equal("sso@example.com", identity.Email)
+	suite.True(identity.EmailVerified)
+	suite.Equal("Sam", identity.GivenName)
+	suite.Equal("Sso", identity.FamilyName)
+}
+
+func (suite *OIDCProviderTestSuite) TestWrongCodeVerifierIsRejected() {
+	code := suite.login("state", "nonce", "verifier")
+
+	_, err := suite.provider.Exchange(context.Background(), code, "someone else's verifier", "nonce")
+	suite.ErrorContains(err, "invalid_grant")
+}
+
+func (suite *OIDCProviderTestSuite) TestNonceMustMatch() {
+	code := suite.login("state", "nonce", "verifier")
+
+	_, err := suite.provider.Exchange(context.Background(), code, "verifier", "another nonce")
+	suite.ErrorContains(err, "nonce")
+}
+
+func (suite *OIDCProviderTestSuite) TestIDTokenChecks() {
+	cases := map[string]jwt.MapClaims{
+		"another client": {"aud": []string{"another-client"}},
+		"wrong issuer":   {"iss": "https://evil.example.com"},
+		"expired":        {"exp": time.Now().Add(-time.Hour).Unix()},
+	}
+	for name, claims := range cases {
+		suite.server.claims = claims
+		code := suite.login("state", "nonce", "verifier")
+		_, err := suite.provider.Exchange(context.Background(), code, "verifier", "nonce")
+		suite.Error(err, name)
+	}
+
+	// A list audience that names us is fine
+	suite.server.claims = jwt.MapClaims{"aud": []string{"other", "task-manager"}}
+	code := suite.login("state", "nonce", "verifier")
+	_, err := suite.provider.Exchange(context.Background(), code, "verifier", "nonce")
+	suite.NoError(err)
+}
+
+func (suite *OIDCProviderTestSuite) TestRotatedKeyIsFetched() {
+	code := suite.login("state", "nonce", "verifier")
+	_, err := suite.provider.Exchange(context.Background(), code, "verifier", "nonce")
+	suite.Require().NoError(err)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().NoError(err)
+	suite.server.mu.Lock()
+	suite.server.key, suite.server.kid = key, "key-2"
+	suite.server.mu.Unlock()
+
+	code = suite.login("state", "nonce", "verifier")
+	_, err = suite.provider.Exchange(context.Background(), code, "verifier", "nonce")
+	suite.NoError(err)
+}
+
+func (suite *OIDCProviderTestSuite) TestForgedSignatureIsRejected() {
+	code := suite.login("state", "nonce", "verifier")
+	_, err := suite.provider.Exchange(context.Background(), code, "verifier", "nonce")
+	suite.Require().NoError(err)
+
+	// Same key id, different key: the cached key must not verify it
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().NoError(err)
+	suite.server.mu.Lock()
+	suite.server.key = key
+	suite.server.mu.Unlock()
+
+	code = suite.login("state", "nonce", "verifier")
+	_, err = suite.provider.Exchange(context.Background(), code, "verifier", "nonce")
+	suite.Error(err)
+}
+
+func TestOIDCProviderTestSuite(t *testing.T) {
+	suite.Run(t, new(OIDCProviderTestSuite))
+}
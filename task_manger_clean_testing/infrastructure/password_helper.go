@@ -1,26 +1,151 @@
 package infrastructure
 
 import (
-	"log"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"task_manger_clean_architecture/domain"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-func HashPassword(password string) string {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
-	if err != nil {
-		log.Panic(err)
+// Password hash algorithms.
+const (
+	PasswordAlgorithmArgon2id = "argon2id"
+	PasswordAlgorithmBcrypt   = "bcrypt"
+)
+
+// Argon2idParams are the argon2id parameters; Memory is in KiB.
+type Argon2idParams struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+	SaltLen uint32
+	KeyLen  uint32
+}
+
+// PasswordHasherConfig selects the algorithm new hashes are made with. Hashes
+// of either algorithm are always verified.
+type PasswordHasherConfig struct {
+	Algorithm  string
+	Argon2id   Argon2idParams
+	BcryptCost int
+}
+
+var (
+	// DefaultPasswordHasherConfig follows the RFC 9106 recommendation for
+	// memory constrained servers. BcryptCost only matters for deciding when
+	// a legacy bcrypt hash is weak enough to rehash.
+	DefaultPasswordHasherConfig = PasswordHasherConfig{
+		Algorithm:  PasswordAlgorithmArgon2id,
+		Argon2id:   Argon2idParams{Time: 3, Memory: 64 * 1024, Threads: 2, SaltLen: 16, KeyLen: 32},
+		BcryptCost: 12,
+	}
+	// CheapPasswordHasherConfig is for tests only: it hashes in microseconds.
+	CheapPasswordHasherConfig = PasswordHasherConfig{
+		Algorithm:  PasswordAlgorithmBcrypt,
+		Argon2id:   Argon2idParams{Time: 1, Memory: 64, Threads: 1, SaltLen: 16, KeyLen: 32},
+		BcryptCost: bcrypt.MinCost,
+	}
+)
+
+type passwordHasher struct {
+	config PasswordHasherConfig
+}
+
+func NewPasswordHasher(config PasswordHasherConfig) domain.PasswordHasher {
+	return &passwordHasher{config: config}
+}
+
+// Hash implements domain.PasswordHasher.
+func (h *passwordHasher) Hash(password string) (string, error) {
+	switch h.config.Algorithm {
+	case PasswordAlgorithmArgon2id:
+		return hashArgon2id(password, h.config.Argon2id)
+	case PasswordAlgorithmBcrypt:
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.config.BcryptCost)
+		return string(hash), err
+	default:
+		return "", fmt.Errorf("unsupported password algorithm %q", h.config.Algorithm)
 	}
-	return string(bytes)
 }
 
-func VerifyPassword(userPassword string, providedPassword string) (bool, string) {
-	err := bcrypt.CompareHashAndPassword([]byte(providedPassword), []byte(userPassword))
-	check := true
-	msg := ""
+// Verify implements domain.PasswordHasher.
+func (h *passwordHasher) Verify(password string, hash string) (bool, bool, error) {
+	switch {
+	case strings.HasPrefix(hash, "$argon2id$"):
+		params, salt, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return false, false, err
+		}
+		other := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, uint32(len(key)))
+		if subtle.ConstantTimeCompare(key, other) != 1 {
+			return false, false, nil
+		}
+		wanted := h.config.Argon2id
+		weaker := params.Time < wanted.Time || params.Memory < wanted.Memory || params.Threads < wanted.Threads || uint32(len(key)) < wanted.KeyLen
+		return true, h.config.Algorithm != PasswordAlgorithmArgon2id || weaker, nil
+
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if err == bcrypt.ErrMismatchedHashAndPassword {
+			return false, false, nil
+		}
+		if err != nil {
+			return false, false, err
+		}
+		cost, err := bcrypt.Cost([]byte(hash))
+		if err != nil {
+			return false, false, err
+		}
+		return true, h.config.Algorithm != PasswordAlgorithmBcrypt || cost < h.config.BcryptCost, nil
+
+	default:
+		return false, false, domain.ErrUnknownPasswordHash
+	}
+}
+
+// hashArgon2id encodes the hash in the PHC string format,
+// $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>.
+func hashArgon2id(password string, params Argon2idParams) (string, error) {
+	salt := make([]byte, params.SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Threads, params.KeyLen)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, params.Memory, params.Time, params.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func decodeArgon2id(hash string) (Argon2idParams, []byte, []byte, error) {
+	var params Argon2idParams
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, domain.ErrUnknownPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, domain.ErrUnknownPasswordHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Threads); err != nil {
+		return params, nil, nil, domain.ErrUnknownPasswordHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
 	if err != nil {
-		check = false
-		msg = "password or email not found"
+		return params, nil, nil, domain.ErrUnknownPasswordHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, domain.ErrUnknownPasswordHash
 	}
-	return check, msg
-}
\ No newline at end of file
+	params.SaltLen = uint32(len(salt))
+	params.KeyLen = uint32(len(key))
+	return params, salt, key, nil
+}
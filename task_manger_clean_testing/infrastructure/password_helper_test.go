@@ -0,0 +1,89 @@
+package infrastructure
+
+import (
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type PasswordHasherTestSuite struct {
+	suite.Suite
+	argon2id PasswordHasherConfig
+}
+
+func (suite *PasswordHasherTestSuite) SetupTest() {
+	// Real algorithms with the smallest parameters, so the tests stay fast
+	suite.argon2id = CheapPasswordHasherConfig
+	suite.argon2id.Algorithm = PasswordAlgorithmArgon2id
+}
+
+func (suite *PasswordHasherTestSuite) TestArgon2idRoundTrip() {
+	hasher := NewPasswordHasher(suite.argon2id)
+
+	hash, err := hasher.Hash("correct horse")
+	suite.Require().NoError(err)
+	suite.True(strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"))
+
+	match, needsRehash, err := hasher.Verify("correct horse", hash)
+	suite.NoError(err)
+	suite.True(match)
+	suite.False(needsRehash)
+
+	match, _, err = hasher.Verify("wrong horse", hash)
+	suite.NoError(err)
+	suite.False(match)
+
+	other, err := hasher.Hash("correct horse")
+	suite.Require().NoError(err)
+	suite.NotEqual(hash, other)
+}
+
+func (suite *PasswordHasherTestSuite) TestLegacyBcryptNeedsRehash() {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	suite.Require().NoError(err)
+
+	match, needsRehash, err := NewPasswordHasher(suite.argon2id).Verify("correct horse", string(legacy))
+	suite.NoError(err)
+	suite.True(match)
+	suite.True(needsRehash)
+
+	match, needsRehash, err = NewPasswordHasher(CheapPasswordHasherConfig).Verify("correct horse", string(legacy))
+	suite.NoError(err)
+	suite.True(match)
+	suite.False(needsRehash)
+}
+
+func (suite *PasswordHasherTestSuite) TestWeakerArgon2idNeedsRehash() {
+	hash, err := NewPasswordHasher(suite.argon2id).Hash("correct horse")
+	suite.Require().NoError(err)
+
+	stronger := suite.argon2id
+	stronger.Argon2id.Time = 2
+	match, needsRehash, err := NewPasswordHasher(stronger).Verify("correct horse", hash)
+	suite.NoError(err)
+	suite.True(match)
+	suite.True(needsRehash)
+}
+
+func (suite *PasswordHasherTestSuite) TestUnknownHash() {
+	_, _, err := NewPasswordHasher(suite.argon2id).Verify("correct horse", "plaintext")
+	suite.ErrorIs(err, domain.ErrUnknownPasswordHash)
+}
+
+func (suite *PasswordHasherTestSuite) TestPolicy() {
+	breached, err := LoadBreachedPasswords("")
+	suite.Require().NoError(err)
+	suite.Contains(breached, "password123")
+
+	policy := NewPasswordPolicy(8, breached)
+	suite.ErrorIs(policy.Validate("short"), domain.ErrPasswordTooShort)
+	suite.ErrorIs(policy.Validate("Password123"), domain.ErrPasswordBreached)
+	suite.NoError(policy.Validate("correct horse battery"))
+}
+
+func TestPasswordHasherTestSuite(t *testing.T) {
+	suite.Run(t, new(PasswordHasherTestSuite))
+}
@@ -0,0 +1,70 @@
+package infrastructure
+
+import (
+	"bufio"
+	_ "embed"
+	"io"
+	"os"
+	"strings"
+	"task_manger_clean_architecture/domain"
+)
+
+//go:embed breached_passwords.txt
+var defaultBreachedPasswords string
+
+type passwordPolicy struct {
+	minLength int
+	breached  map[string]struct{}
+}
+
+// NewPasswordPolicy returns a policy that rejects passwords shorter than
+// minLength characters or found in breached, ignoring case.
+func NewPasswordPolicy(minLength int, breached []string) domain.PasswordPolicy {
+	policy := &passwordPolicy{
+		minLength: minLength,
+		breached:  make(map[string]struct{}, len(breached)),
+	}
+	for _, password := range breached {
+		policy.breached[strings.ToLower(password)] = struct{}{}
+	}
+	return policy
+}
+
+// Validate implements domain.PasswordPolicy.
+func (p *passwordPolicy) Validate(password string) error {
+	if len([]rune(password)) < p.minLength {
+		return domain.ErrPasswordTooShort
+	}
+	if _, ok := p.breached[strings.ToLower(password)]; ok {
+		return domain.ErrPasswordBreached
+	}
+	return nil
+}
+
+// LoadBreachedPasswords reads a list of passwords, one per line, from the
+// file at path, or the built-in list when path is empty. Blank lines and
+// lines starting with # are skipped.
+func LoadBreachedPasswords(path string) ([]string, error) {
+	if path == "" {
+		return readPasswordList(strings.NewReader(defaultBreachedPasswords))
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return readPasswordList(file)
+}
+
+func readPasswordList(r io.Reader) ([]string, error) {
+	var passwords []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords = append(passwords, line)
+	}
+	return passwords, scanner.Err()
+}
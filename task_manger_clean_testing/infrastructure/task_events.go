@@ -0,0 +1,79 @@
+package infrastructure
+
+import (
+	"context"
+	"sync"
+	"task_manger_clean_architecture/domain"
+)
+
+type taskSubscriber struct {
+	events chan domain.TaskEvent
+	stop   func() bool
+}
+
+type taskEventBus struct {
+	mu          sync.Mutex
+	buffer      int
+	subscribers map[string]map[*taskSubscriber]struct{}
+}
+
+// NewTaskEventBus returns a TaskEventBus local to this process, whose
+// subscribers may fall buffer events behind before they are dropped. Every
+// instance behind a load balancer only sees the changes made through it.
+func NewTaskEventBus(buffer int) domain.TaskEventBus {
+	return &taskEventBus{
+		buffer:      buffer,
+		subscribers: map[string]map[*taskSubscriber]struct{}{},
+	}
+}
+
+// Publish implements domain.TaskEventBus.
+func (b *taskEventBus) Publish(c context.Context, event domain.TaskEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for subscriber := range b.subscribers[event.Task.WorkspaceID] {
+		select {
+		case subscriber.events <- event:
+		default:
+			subscriber.stop()
+			b.remove(event.Task.WorkspaceID, subscriber)
+		}
+	}
+}
+
+// Subscribe implements domain.TaskEventBus.
+func (b *taskEventBus) Subscribe(c context.Context) (<-chan domain.TaskEvent, error) {
+	workspace_id, err := domain.WorkspaceFrom(c)
+	if err != nil {
+		return nil, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subscriber := &taskSubscriber{events: make(chan domain.TaskEvent, b.buffer)}
+	if b.subscribers[workspace_id] == nil {
+		b.subscribers[workspace_id] = map[*taskSubscriber]struct{}{}
+	}
+	b.subscribers[workspace_id][subscriber] = struct{}{}
+	subscriber.stop = context.AfterFunc(c, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(workspace_id, subscriber)
+	})
+	return subscriber.events, nil
+}
+
+// remove closes the channel of subscriber unless it was removed already. The
+// caller must hold mu.
+func (b *taskEventBus) remove(workspace_id string, subscriber *taskSubscriber) {
+	if _, ok := b.subscribers[workspace_id][subscriber]; !ok {
+		return
+	}
+	delete(b.subscribers[workspace_id], subscriber)
+	if len(b.subscribers[workspace_id]) == 0 {
+		delete(b.subscribers, workspace_id)
+	}
+	close(subscriber.events)
+}
@@ -0,0 +1,94 @@
+package infrastructure
+
+import (
+	"context"
+	"task_manger_clean_architecture/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TaskEventBusTestSuite struct {
+	suite.Suite
+	bus domain.TaskEventBus
+}
+
+func (suite *TaskEventBusTestSuite) SetupTest() {
+	suite.bus = NewTaskEventBus(2)
+}
+
+func event(event_type string, id string, workspace_id string) domain.TaskEvent {
+	return domain.TaskEvent{Type: event_type, Task: domain.Task{ID: id, WorkspaceID: workspace_id}}
+}
+
+// receive returns the next event or fails when none is published in time.
+func (suite *TaskEventBusTestSuite) receive(events <-chan domain.TaskEvent) (domain.TaskEvent, bool) {
+	select {
+	case e, ok := <-events:
+		return e, ok
+	case <-time.After(time.Second):
+		suite.FailNow("no event received")
+		return domain.TaskEvent{}, false
+	}
+}
+
+func (suite *TaskEventBusTestSuite) TestDeliversToTheWorkspace() {
+	ctx, cancel := context.WithCancel(domain.WithWorkspace(context.Background(), "ws1"))
+	defer cancel()
+	events, err := suite.bus.Subscribe(ctx)
+	suite.Require().NoError(err)
+
+	suite.bus.Publish(ctx, event(domain.TaskCreated, "t0", "ws2"))
+	suite.bus.Publish(ctx, event(domain.TaskCreated, "t1", "ws1"))
+
+	received, ok := suite.receive(events)
+	suite.True(ok)
+	suite.Equal(event(domain.TaskCreated, "t1", "ws1"), received)
+}
+
+func (suite *TaskEventBusTestSuite) TestClosesWhenTheContextIsDone() {
+	ctx, cancel := context.WithCancel(domain.WithWorkspace(context.Background(), "ws1"))
+	events, err := suite.bus.Subscribe(ctx)
+	suite.Require().NoError(err)
+
+	cancel()
+	_, ok := suite.receive(events)
+	suite.False(ok)
+	// Publishing to a workspace without subscribers is a no-op
+	suite.bus.Publish(context.Background(), event(domain.TaskCreated, "t1", "ws1"))
+}
+
+func (suite *TaskEventBusTestSuite) TestDropsSlowSubscribers() {
+	ctx, cancel := context.WithCancel(domain.WithWorkspace(context.Background(), "ws1"))
+	defer cancel()
+	slow, err := suite.bus.Subscribe(ctx)
+	suite.Require().NoError(err)
+
+	for _, id := range []string{"t1", "t2", "t3"} {
+		suite.bus.Publish(ctx, event(domain.TaskUpdated, id, "ws1"))
+	}
+
+	// The buffered events are still delivered before the channel closes
+	first, _ := suite.receive(slow)
+	second, _ := suite.receive(slow)
+	suite.Equal([]string{"t1", "t2"}, []string{first.Task.ID, second.Task.ID})
+	_, ok := suite.receive(slow)
+	suite.False(ok)
+
+	// A new subscription starts over
+	events, err := suite.bus.Subscribe(ctx)
+	suite.Require().NoError(err)
+	suite.bus.Publish(ctx, event(domain.TaskUpdated, "t4", "ws1"))
+	received, _ := suite.receive(events)
+	suite.Equal("t4", received.Task.ID)
+}
+
+func (suite *TaskEventBusTestSuite) TestRequiresAWorkspace() {
+	_, err := suite.bus.Subscribe(context.Background())
+	suite.ErrorIs(err, domain.ErrNoWorkspace)
+}
+
+func TestTaskEventBusTestSuite(t *testing.T) {
+	suite.Run(t, new(TaskEventBusTestSuite))
+}
@@ -1,9 +1,9 @@
 package infrastructure
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
-	"log"
-	"os"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
@@ -15,34 +15,63 @@ type SignedDetails struct{
 	LastName        string
 	Uid          	string
 	UserType        string
+	EmailVerified   bool
+	TokenVersion    int
+	// MFA is set when the login that issued the token passed a second factor.
+	MFA             bool
+	// WorkspaceID is the workspace the token acts in. Tokens from before
+	// workspaces have none and act in the default workspace.
+	WorkspaceID     string
+	// Refresh marks the refresh tokens, which only /login/refresh accepts.
+	Refresh         bool
 	jwt.StandardClaims
 	
 }
 
 
-var SECRET_KEY = os.Getenv("SECRET_KEY")
+// JWT signs and validates the tokens of password sessions with a secret key.
+type JWT struct {
+	secretKey []byte
+}
+
+func NewJWT(secretKey string) *JWT {
+	return &JWT{secretKey: []byte(secretKey)}
+}
 
-func GenerateAllTokens(email string, firstName *string , lastName *string, userType *string, uid *string)(signedToken string, signedRefreshToken string, err error ){
+func (j *JWT) GenerateAllTokens(email string, firstName *string , lastName *string, userType *string, uid *string, emailVerified bool, tokenVersion int, mfa bool, workspaceID string)(signedToken string, signedRefreshToken string, err error ){
 	claims:= &SignedDetails{
 		Email: email,
 		FirstName: *firstName,
 		LastName: *lastName,
 		UserType: *userType,
 		Uid: *uid,
+		EmailVerified: emailVerified,
+		TokenVersion: tokenVersion,
+		MFA: mfa,
+		WorkspaceID: workspaceID,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: time.Now().Local().Add(time.Hour * 24).Unix(),
 		},
 	}
+	// The refresh token names the session it renews, not the user's profile
 	refreshclaims:= &SignedDetails{
+		Uid: *uid,
+		TokenVersion: tokenVersion,
+		MFA: mfa,
+		WorkspaceID: workspaceID,
+		Refresh: true,
 		StandardClaims: jwt.StandardClaims{
+			// Refresh tokens rotate, so two issued in the same second must differ
+			Id: randomID(),
 			ExpiresAt: time.Now().Local().Add(time.Hour * time.Duration(168)).Unix(),
 		},
 	}
-	token,err:= jwt.NewWithClaims(jwt.SigningMethodHS256,claims).SignedString([]byte(SECRET_KEY))
-	refreshToken,err := jwt.NewWithClaims(jwt.SigningMethodHS256,refreshclaims).SignedString([]byte(SECRET_KEY))
-
+	token,err:= jwt.NewWithClaims(jwt.SigningMethodHS256,claims).SignedString(j.secretKey)
+	if err!=nil{
+		return
+	}
+	refreshToken,err := jwt.NewWithClaims(jwt.SigningMethodHS256,refreshclaims).SignedString(j.secretKey)
 	if err!=nil{
-		log.Panic()
 		return
 	}
 
@@ -51,15 +80,19 @@ func GenerateAllTokens(email string, firstName *string , lastName *string, userT
 
 
 
-func ValidateToken(signedToken string) (claims *SignedDetails, msg string){
+func (j *JWT) ValidateToken(signedToken string) (claims *SignedDetails, msg string){
 	token, err := jwt.ParseWithClaims(
 		signedToken,&SignedDetails{}, func(token *jwt.Token)(interface{},error){
-			return []byte(SECRET_KEY),nil
+			return j.secretKey,nil
 		},
 	)
 	if err!=nil{
 		msg = err.Error()
 	}
+	// Tokens that do not even parse come back without claims
+	if token == nil {
+		return nil, msg
+	}
 	claims,ok := token.Claims.(*SignedDetails)
 	if !ok{
 		msg = fmt.Sprintf("token is not valid")
@@ -68,11 +101,14 @@ func ValidateToken(signedToken string) (claims *SignedDetails, msg string){
 
 	}
 	if claims.ExpiresAt < time.Now().Local().Unix(){
-		fmt.Println(claims.ExpiresAt, time.Now().Local().Unix())
 		msg = fmt.Sprintf("token has expired ")
 		return
 	}
-	fmt.Println(msg)
 	return claims,msg
-} 
+}
 
+func randomID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
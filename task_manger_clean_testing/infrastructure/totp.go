@@ -0,0 +1,83 @@
+package infrastructure
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"time"
+)
+
+// The parameters every authenticator app supports: SHA-1, six digits and a
+// thirty second step.
+const (
+	totpDigits    = 6
+	totpPeriod    = 30
+	totpSecretLen = 20
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type totp struct {
+	issuer string
+}
+
+// NewTOTP returns a TOTP whose URIs name issuer in authenticator apps.
+func NewTOTP(issuer string) domain.TOTP {
+	return &totp{issuer: issuer}
+}
+
+// GenerateSecret implements domain.TOTP.
+func (t *totp) GenerateSecret() (string, error) {
+	secret := make([]byte, totpSecretLen)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// URI implements domain.TOTP.
+func (t *totp) URI(secret string, account string) string {
+	label := url.PathEscape(t.issuer + ":" + account)
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", t.issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// Validate implements domain.TOTP.
+func (t *totp) Validate(secret string, code string, now time.Time) (int64, bool) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+	step := now.Unix() / totpPeriod
+	for _, candidate := range []int64{step, step - 1, step + 1} {
+		if subtle.ConstantTimeCompare([]byte(TOTPCode(key, candidate)), []byte(code)) == 1 {
+			return candidate, true
+		}
+	}
+	return 0, false
+}
+
+// TOTPCode is the RFC 4226 HOTP value of key for the counter step.
+func TOTPCode(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
@@ -0,0 +1,69 @@
+package infrastructure
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TOTPTestSuite struct {
+	suite.Suite
+	totp   *totp
+	secret string
+}
+
+func (suite *TOTPTestSuite) SetupTest() {
+	suite.totp = NewTOTP("Task Manager").(*totp)
+	// The SHA-1 key of the RFC 6238 test vectors
+	suite.secret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+}
+
+func (suite *TOTPTestSuite) TestRFC6238Vectors() {
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, code := range vectors {
+		step, ok := suite.totp.Validate(suite.secret, code, time.Unix(unix, 0))
+		suite.True(ok, "time %d", unix)
+		suite.Equal(unix/totpPeriod, step)
+	}
+}
+
+func (suite *TOTPTestSuite) TestSkew() {
+	now := time.Unix(1111111111, 0)
+	key, _ := totpEncoding.DecodeString(strings.TrimRight(suite.secret, "="))
+	previous := TOTPCode(key, now.Unix()/totpPeriod-1)
+	tooOld := TOTPCode(key, now.Unix()/totpPeriod-2)
+
+	step, ok := suite.totp.Validate(suite.secret, previous, now)
+	suite.True(ok)
+	suite.Equal(now.Unix()/totpPeriod-1, step)
+
+	_, ok = suite.totp.Validate(suite.secret, tooOld, now)
+	suite.False(ok)
+	_, ok = suite.totp.Validate(suite.secret, "12345", now)
+	suite.False(ok)
+}
+
+func (suite *TOTPTestSuite) TestGeneratedSecretAndURI() {
+	secret, err := suite.totp.GenerateSecret()
+	suite.Require().NoError(err)
+	suite.Len(secret, 32)
+
+	uri := suite.totp.URI(secret, "a@example.com")
+	suite.True(strings.HasPrefix(uri, "otpauth://totp/Task%20Manager:a@example.com?"))
+	suite.Contains(uri, "secret="+secret)
+	suite.Contains(uri, "issuer=Task+Manager")
+}
+
+func TestTOTPTestSuite(t *testing.T) {
+	suite.Run(t, new(TOTPTestSuite))
+}
@@ -0,0 +1,47 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+type TracingTestSuite struct {
+	suite.Suite
+	out bytes.Buffer
+}
+
+func (suite *TracingTestSuite) SetupTest() {
+	suite.out.Reset()
+}
+
+func (suite *TracingTestSuite) TestNoneExportsNothing() {
+	provider, shutdown, err := NewTracerProvider(context.Background(), TracingConfig{Exporter: "none"}, &suite.out)
+	suite.Require().NoError(err)
+	suite.IsType(noop.TracerProvider{}, provider)
+	suite.NoError(shutdown(context.Background()))
+}
+
+func (suite *TracingTestSuite) TestStdoutWritesSpansOnShutdown() {
+	provider, shutdown, err := NewTracerProvider(context.Background(), TracingConfig{Exporter: "stdout", ServiceName: "tasks-test"}, &suite.out)
+	suite.Require().NoError(err)
+
+	_, span := provider.Tracer("test").Start(context.Background(), "GET /task")
+	span.End()
+	suite.Require().NoError(shutdown(context.Background()))
+
+	suite.Contains(suite.out.String(), `"Name":"GET /task"`)
+	suite.Contains(suite.out.String(), "tasks-test")
+}
+
+func (suite *TracingTestSuite) TestRejectsUnknownExporter() {
+	_, _, err := NewTracerProvider(context.Background(), TracingConfig{Exporter: "zipkin"}, &suite.out)
+	suite.ErrorContains(err, "zipkin")
+}
+
+func TestTracingTestSuite(t *testing.T) {
+	suite.Run(t, new(TracingTestSuite))
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type accessTokenRepository struct {
+	database   *mongo.Database
+	collection string
+
+	mu           sync.Mutex
+	indexesReady bool
+}
+
+// NewAccessTokenRepository returns an AccessTokenRepository kept in Mongo.
+// Tokens with an expiry are removed by a TTL index once it passes.
+func NewAccessTokenRepository(db *mongo.Database, collection string) domain.AccessTokenRepository {
+	return &accessTokenRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (a *accessTokenRepository) ensureIndexes(c context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.indexesReady {
+		return nil
+	}
+
+	_, err := a.database.Collection(a.collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "userid", Value: 1}, {Key: "createdat", Value: 1}}},
+		{Keys: bson.D{{Key: "expiresat", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	if err != nil {
+		return err
+	}
+	a.indexesReady = true
+	return nil
+}
+
+// Create implements domain.AccessTokenRepository.
+func (a *accessTokenRepository) Create(c context.Context, token domain.AccessToken) error {
+	if err := a.ensureIndexes(c); err != nil {
+		return err
+	}
+	_, err := a.database.Collection(a.collection).InsertOne(c, token)
+	return err
+}
+
+// ListForUser implements domain.AccessTokenRepository.
+func (a *accessTokenRepository) ListForUser(c context.Context, user_id string) ([]domain.AccessToken, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdat", Value: 1}})
+	cursor, err := a.database.Collection(a.collection).Find(c, bson.D{{Key: "userid", Value: user_id}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	tokens := []domain.AccessToken{}
+	if err := cursor.All(c, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// GetByHash implements domain.AccessTokenRepository.
+func (a *accessTokenRepository) GetByHash(c context.Context, hash string) (*domain.AccessToken, error) {
+	var token domain.AccessToken
+	err := a.database.Collection(a.collection).FindOne(c, bson.D{{Key: "hash", Value: hash}}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvalidAccessToken
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Delete implements domain.AccessTokenRepository.
+func (a *accessTokenRepository) Delete(c context.Context, user_id string, id string) error {
+	filter := bson.D{{Key: "id", Value: id}, {Key: "userid", Value: user_id}}
+	result, err := a.database.Collection(a.collection).DeleteOne(c, filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrAccessTokenNotFound
+	}
+	return nil
+}
+
+// SetLastUsed implements domain.AccessTokenRepository.
+func (a *accessTokenRepository) SetLastUsed(c context.Context, id string, now time.Time) error {
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "lastusedat", Value: now}}}}
+	_, err := a.database.Collection(a.collection).UpdateOne(c, bson.D{{Key: "id", Value: id}}, update)
+	return err
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"task_manger_clean_architecture/domain"
+)
+
+// fileAuditRepository writes the audit log as JSON lines to a single file
+// that is only ever appended to. Reads scan the whole file, which keeps the
+// sink dependency-free at the cost of query speed on large logs.
+type fileAuditRepository struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileAuditRepository returns an AuditRepository backed by the file at
+// path, which is created on the first Append.
+func NewFileAuditRepository(path string) domain.AuditRepository {
+	return &fileAuditRepository{path: path}
+}
+
+// Append implements domain.AuditRepository.
+func (f *fileAuditRepository) Append(c context.Context, entry domain.AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Last implements domain.AuditRepository.
+func (f *fileAuditRepository) Last(c context.Context) (*domain.AuditEntry, error) {
+	var last *domain.AuditEntry
+	err := f.Scan(c, func(entry *domain.AuditEntry) error {
+		last = entry
+		return nil
+	})
+	return last, err
+}
+
+// Find implements domain.AuditRepository.
+func (f *fileAuditRepository) Find(c context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, int64, error) {
+	var matches []*domain.AuditEntry
+	err := f.Scan(c, func(entry *domain.AuditEntry) error {
+		if filter.Matches(entry) {
+			matches = append(matches, entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Newest first, like the Mongo sink.
+	for i, j := 0, len(matches)-1; i < j; i, j = i+1, j-1 {
+		matches[i], matches[j] = matches[j], matches[i]
+	}
+	total := int64(len(matches))
+	entries := []*domain.AuditEntry{}
+	if filter.PerPage <= 0 {
+		return append(entries, matches...), total, nil
+	}
+	start := filter.Page * filter.PerPage
+	if start >= total {
+		return entries, total, nil
+	}
+	end := start + filter.PerPage
+	if end > total {
+		end = total
+	}
+	return append(entries, matches[start:end]...), total, nil
+}
+
+// Scan implements domain.AuditRepository.
+func (f *fileAuditRepository) Scan(c context.Context, fn func(entry *domain.AuditEntry) error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.Open(f.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		if err := c.Err(); err != nil {
+			return err
+		}
+		var entry domain.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return err
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"path/filepath"
+	"task_manger_clean_architecture/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type FileAuditRepositoryTestSuite struct {
+	suite.Suite
+	repo domain.AuditRepository
+}
+
+func (suite *FileAuditRepositoryTestSuite) SetupTest() {
+	suite.repo = NewFileAuditRepository(filepath.Join(suite.T().TempDir(), "audit.log"))
+}
+
+func (suite *FileAuditRepositoryTestSuite) TestEmptyLog() {
+	last, err := suite.repo.Last(context.Background())
+	suite.NoError(err)
+	suite.Nil(last)
+
+	entries, total, err := suite.repo.Find(context.Background(), domain.AuditFilter{})
+	suite.NoError(err)
+	suite.Empty(entries)
+	suite.Equal(int64(0), total)
+}
+
+func (suite *FileAuditRepositoryTestSuite) TestAppendAndFind() {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, action := range []string{domain.AuditTaskCreate, domain.AuditTaskUpdate, domain.AuditTaskUpdate} {
+		entry := domain.AuditEntry{
+			Seq:       int64(i + 1),
+			ActorID:   "admin",
+			Action:    action,
+			Target:    "1",
+			After:     []byte(`{"status":"pending"}`),
+			Timestamp: start.Add(time.Duration(i) * time.Hour),
+		}
+		entry.Hash = entry.ComputeHash()
+		suite.NoError(suite.repo.Append(context.Background(), entry))
+	}
+
+	last, err := suite.repo.Last(context.Background())
+	suite.NoError(err)
+	suite.Equal(int64(3), last.Seq)
+	suite.Equal(last.ComputeHash(), last.Hash)
+
+	// Newest first, paged
+	entries, total, err := suite.repo.Find(context.Background(), domain.AuditFilter{Action: domain.AuditTaskUpdate, PerPage: 1})
+	suite.NoError(err)
+	suite.Equal(int64(2), total)
+	suite.Len(entries, 1)
+	suite.Equal(int64(3), entries[0].Seq)
+
+	entries, total, err = suite.repo.Find(context.Background(), domain.AuditFilter{To: start.Add(30 * time.Minute)})
+	suite.NoError(err)
+	suite.Equal(int64(1), total)
+	suite.Equal(domain.AuditTaskCreate, entries[0].Action)
+}
+
+func TestFileAuditRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(FileAuditRepositoryTestSuite))
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"task_manger_clean_architecture/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type auditRepository struct {
+	database   *mongo.Database
+	collection string
+
+	mu           sync.Mutex
+	indexesReady bool
+}
+
+// NewAuditRepository returns an AuditRepository storing entries in a
+// dedicated collection. A unique index on seq makes a second writer that
+// races for the same position in the chain fail instead of forking it.
+func NewAuditRepository(db *mongo.Database, collection string) domain.AuditRepository {
+	return &auditRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (a *auditRepository) ensureIndexes(c context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.indexesReady {
+		return nil
+	}
+
+	_, err := a.database.Collection(a.collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "seq", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "actoruid", Value: 1}, {Key: "seq", Value: -1}}},
+		{Keys: bson.D{{Key: "target", Value: 1}, {Key: "seq", Value: -1}}},
+		{Keys: bson.D{{Key: "action", Value: 1}, {Key: "seq", Value: -1}}},
+	})
+	if err != nil {
+		return err
+	}
+	a.indexesReady = true
+	return nil
+}
+
+// Append implements domain.AuditRepository.
+func (a *auditRepository) Append(c context.Context, entry domain.AuditEntry) error {
+	if err := a.ensureIndexes(c); err != nil {
+		return err
+	}
+	_, err := a.database.Collection(a.collection).InsertOne(c, entry)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrAuditSeqTaken
+	}
+	return err
+}
+
+// Last implements domain.AuditRepository.
+func (a *auditRepository) Last(c context.Context) (*domain.AuditEntry, error) {
+	var entry domain.AuditEntry
+	opts := options.FindOne().SetSort(bson.D{{Key: "seq", Value: -1}})
+	err := a.database.Collection(a.collection).FindOne(c, bson.D{}, opts).Decode(&entry)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Find implements domain.AuditRepository.
+func (a *auditRepository) Find(c context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, int64, error) {
+	query := bson.D{}
+	if filter.ActorID != "" {
+		query = append(query, bson.E{Key: "actoruid", Value: filter.ActorID})
+	}
+	if filter.Action != "" {
+		query = append(query, bson.E{Key: "action", Value: filter.Action})
+	}
+	if filter.Target != "" {
+		query = append(query, bson.E{Key: "target", Value: filter.Target})
+	}
+	timestamp := bson.D{}
+	if !filter.From.IsZero() {
+		timestamp = append(timestamp, bson.E{Key: "$gte", Value: filter.From})
+	}
+	if !filter.To.IsZero() {
+		timestamp = append(timestamp, bson.E{Key: "$lte", Value: filter.To})
+	}
+	if len(timestamp) > 0 {
+		query = append(query, bson.E{Key: "timestamp", Value: timestamp})
+	}
+
+	collection := a.database.Collection(a.collection)
+	total, err := collection.CountDocuments(c, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "seq", Value: -1}})
+	if filter.PerPage > 0 {
+		opts.SetSkip(filter.Page * filter.PerPage).SetLimit(filter.PerPage)
+	}
+	cur, err := collection.Find(c, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cur.Close(c)
+
+	entries := []*domain.AuditEntry{}
+	if err := cur.All(c, &entries); err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// Scan implements domain.AuditRepository.
+func (a *auditRepository) Scan(c context.Context, fn func(entry *domain.AuditEntry) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "seq", Value: 1}})
+	cur, err := a.database.Collection(a.collection).Find(c, bson.D{}, opts)
+	if err != nil {
+		return err
+	}
+	defer cur.Close(c)
+
+	for cur.Next(c) {
+		var entry domain.AuditEntry
+		if err := cur.Decode(&entry); err != nil {
+			return err
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	return cur.Err()
+}
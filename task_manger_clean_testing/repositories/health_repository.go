@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"task_manger_clean_architecture/domain"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+type mongoHealthChecker struct {
+	database *mongo.Database
+}
+
+// NewMongoHealthChecker returns a HealthChecker that pings the primary of
+// the cluster holding db, where every write goes.
+func NewMongoHealthChecker(db *mongo.Database) domain.HealthChecker {
+	return &mongoHealthChecker{database: db}
+}
+
+// Name implements domain.HealthChecker.
+func (m *mongoHealthChecker) Name() string {
+	return "mongo"
+}
+
+// Check implements domain.HealthChecker.
+func (m *mongoHealthChecker) Check(c context.Context) error {
+	return m.database.Client().Ping(c, readpref.Primary())
+}
@@ -0,0 +1,192 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("task_manger_clean_architecture/repositories")
+
+// instrument starts the span of a repository call, under which the driver's
+// command spans nest, and returns the function ending it and recording the
+// call in metrics. Finding nothing is an answer, not a failure of the
+// repository, so it does not count as an error.
+func instrument(c context.Context, metrics domain.RepositoryMetrics, repository string, method string) (context.Context, func(error)) {
+	start := time.Now()
+	c, span := tracer.Start(c, repository+"Repository."+method,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attribute.String("repository", repository), attribute.String("method", method)))
+	return c, func(err error) {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			err = nil
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		metrics.ObserveRepositoryCall(repository, method, time.Since(start), err)
+	}
+}
+
+type instrumentedTaskRepository struct {
+	next    domain.TaskRepository
+	metrics domain.RepositoryMetrics
+}
+
+// NewInstrumentedTaskRepository returns next tracing every call and
+// recording its latency and errors in metrics.
+func NewInstrumentedTaskRepository(next domain.TaskRepository, metrics domain.RepositoryMetrics) domain.TaskRepository {
+	return &instrumentedTaskRepository{next: next, metrics: metrics}
+}
+
+func (r *instrumentedTaskRepository) GetTasks(c context.Context) (tasks []*domain.Task, err error) {
+	c, end := instrument(c, r.metrics, "task", "GetTasks")
+	defer func() { end(err) }()
+	return r.next.GetTasks(c)
+}
+
+func (r *instrumentedTaskRepository) GetTasksById(c context.Context, id string) (task *domain.Task, err error) {
+	c, end := instrument(c, r.metrics, "task", "GetTasksById")
+	defer func() { end(err) }()
+	return r.next.GetTasksById(c, id)
+}
+
+func (r *instrumentedTaskRepository) DeleteById(c context.Context, id string) (deleted int64, err error) {
+	c, end := instrument(c, r.metrics, "task", "DeleteById")
+	defer func() { end(err) }()
+	return r.next.DeleteById(c, id)
+}
+
+func (r *instrumentedTaskRepository) UpdateTask(c context.Context, id string, updatedTask domain.Task) (err error) {
+	c, end := instrument(c, r.metrics, "task", "UpdateTask")
+	defer func() { end(err) }()
+	return r.next.UpdateTask(c, id, updatedTask)
+}
+
+func (r *instrumentedTaskRepository) AddTask(c context.Context, newTask domain.Task) (err error) {
+	c, end := instrument(c, r.metrics, "task", "AddTask")
+	defer func() { end(err) }()
+	return r.next.AddTask(c, newTask)
+}
+
+type instrumentedUserRepository struct {
+	next    domain.UserRepository
+	metrics domain.RepositoryMetrics
+}
+
+// NewInstrumentedUserRepository returns next tracing every call and
+// recording its latency and errors in metrics.
+func NewInstrumentedUserRepository(next domain.UserRepository, metrics domain.RepositoryMetrics) domain.UserRepository {
+	return &instrumentedUserRepository{next: next, metrics: metrics}
+}
+
+func (r *instrumentedUserRepository) Signup(c context.Context, user domain.User) (result interface{}, err error) {
+	c, end := instrument(c, r.metrics, "user", "Signup")
+	defer func() { end(err) }()
+	return r.next.Signup(c, user)
+}
+
+func (r *instrumentedUserRepository) Login(c context.Context, email string) (user *domain.User, err error) {
+	c, end := instrument(c, r.metrics, "user", "Login")
+	defer func() { end(err) }()
+	return r.next.Login(c, email)
+}
+
+func (r *instrumentedUserRepository) GetUsers(c context.Context, startIndex int64, recordsPerPage int64) (users []*domain.User, err error) {
+	c, end := instrument(c, r.metrics, "user", "GetUsers")
+	defer func() { end(err) }()
+	return r.next.GetUsers(c, startIndex, recordsPerPage)
+}
+
+func (r *instrumentedUserRepository) GetUser(c context.Context, user_id string) (user domain.User, err error) {
+	c, end := instrument(c, r.metrics, "user", "GetUser")
+	defer func() { end(err) }()
+	return r.next.GetUser(c, user_id)
+}
+
+func (r *instrumentedUserRepository) GetMember(c context.Context, user_id string) (user domain.User, err error) {
+	c, end := instrument(c, r.metrics, "user", "GetMember")
+	defer func() { end(err) }()
+	return r.next.GetMember(c, user_id)
+}
+
+func (r *instrumentedUserRepository) GetMembers(c context.Context, user_ids []string) (users []domain.User, err error) {
+	c, end := instrument(c, r.metrics, "user", "GetMembers")
+	defer func() { end(err) }()
+	return r.next.GetMembers(c, user_ids)
+}
+
+func (r *instrumentedUserRepository) Promote(c context.Context, user_id string, userType string) (err error, matched int64, modified int64) {
+	c, end := instrument(c, r.metrics, "user", "Promote")
+	defer func() { end(err) }()
+	return r.next.Promote(c, user_id, userType)
+}
+
+func (r *instrumentedUserRepository) AddMembership(c context.Context, user_id string, membership domain.WorkspaceMembership) (err error) {
+	c, end := instrument(c, r.metrics, "user", "AddMembership")
+	defer func() { end(err) }()
+	return r.next.AddMembership(c, user_id, membership)
+}
+
+func (r *instrumentedUserRepository) UpdateAllTokens(c context.Context, token string, refreshToken string, user_id string) (err error) {
+	c, end := instrument(c, r.metrics, "user", "UpdateAllTokens")
+	defer func() { end(err) }()
+	return r.next.UpdateAllTokens(c, token, refreshToken, user_id)
+}
+
+func (r *instrumentedUserRepository) GetUserByEmail(c context.Context, email string) (user domain.User, err error) {
+	c, end := instrument(c, r.metrics, "user", "GetUserByEmail")
+	defer func() { end(err) }()
+	return r.next.GetUserByEmail(c, email)
+}
+
+func (r *instrumentedUserRepository) SetEmailVerified(c context.Context, user_id string) (err error) {
+	c, end := instrument(c, r.metrics, "user", "SetEmailVerified")
+	defer func() { end(err) }()
+	return r.next.SetEmailVerified(c, user_id)
+}
+
+func (r *instrumentedUserRepository) UpdatePassword(c context.Context, user_id string, password string) (err error) {
+	c, end := instrument(c, r.metrics, "user", "UpdatePassword")
+	defer func() { end(err) }()
+	return r.next.UpdatePassword(c, user_id, password)
+}
+
+func (r *instrumentedUserRepository) SetPasswordHash(c context.Context, user_id string, password string) (err error) {
+	c, end := instrument(c, r.metrics, "user", "SetPasswordHash")
+	defer func() { end(err) }()
+	return r.next.SetPasswordHash(c, user_id, password)
+}
+
+func (r *instrumentedUserRepository) SetTwoFactor(c context.Context, user_id string, settings domain.TwoFactorSettings) (err error) {
+	c, end := instrument(c, r.metrics, "user", "SetTwoFactor")
+	defer func() { end(err) }()
+	return r.next.SetTwoFactor(c, user_id, settings)
+}
+
+func (r *instrumentedUserRepository) UseTOTPStep(c context.Context, user_id string, step int64) (used bool, err error) {
+	c, end := instrument(c, r.metrics, "user", "UseTOTPStep")
+	defer func() { end(err) }()
+	return r.next.UseTOTPStep(c, user_id, step)
+}
+
+func (r *instrumentedUserRepository) UseRecoveryCode(c context.Context, user_id string, hash string) (used bool, err error) {
+	c, end := instrument(c, r.metrics, "user", "UseRecoveryCode")
+	defer func() { end(err) }()
+	return r.next.UseRecoveryCode(c, user_id, hash)
+}
+
+func (r *instrumentedUserRepository) UpdateProfile(c context.Context, user_id string, update domain.ProfileUpdate) (err error) {
+	c, end := instrument(c, r.metrics, "user", "UpdateProfile")
+	defer func() { end(err) }()
+	return r.next.UpdateProfile(c, user_id, update)
+}
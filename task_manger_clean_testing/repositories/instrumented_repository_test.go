@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type InstrumentedRepositoryTestSuite struct {
+	suite.Suite
+	mockMetrics  *mocks.RepositoryMetrics
+	mockTaskRepo *mocks.TaskRepository
+	mockUserRepo *mocks.UserRepository
+	taskRepo     domain.TaskRepository
+	userRepo     domain.UserRepository
+}
+
+func (suite *InstrumentedRepositoryTestSuite) SetupTest() {
+	suite.mockMetrics = new(mocks.RepositoryMetrics)
+	suite.mockTaskRepo = new(mocks.TaskRepository)
+	suite.mockUserRepo = new(mocks.UserRepository)
+	suite.taskRepo = NewInstrumentedTaskRepository(suite.mockTaskRepo, suite.mockMetrics)
+	suite.userRepo = NewInstrumentedUserRepository(suite.mockUserRepo, suite.mockMetrics)
+}
+
+func (suite *InstrumentedRepositoryTestSuite) TestRecordsCalls() {
+	task := &domain.Task{ID: "1"}
+	suite.mockTaskRepo.On("GetTasksById", mock.Anything, "1").Return(task, nil).Once()
+	suite.mockMetrics.On("ObserveRepositoryCall", "task", "GetTasksById", mock.AnythingOfType("time.Duration"), nil).Once()
+
+	got, err := suite.taskRepo.GetTasksById(context.Background(), "1")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), task, got)
+	suite.mockMetrics.AssertExpectations(suite.T())
+}
+
+func (suite *InstrumentedRepositoryTestSuite) TestRecordsErrors() {
+	failure := errors.New("timeout")
+	suite.mockTaskRepo.On("AddTask", mock.Anything, mock.Anything).Return(failure).Once()
+	suite.mockMetrics.On("ObserveRepositoryCall", "task", "AddTask", mock.Anything, failure).Once()
+
+	assert.ErrorIs(suite.T(), suite.taskRepo.AddTask(context.Background(), domain.Task{}), failure)
+	suite.mockMetrics.AssertExpectations(suite.T())
+}
+
+func (suite *InstrumentedRepositoryTestSuite) TestNotFoundIsNoError() {
+	suite.mockUserRepo.On("GetUserByEmail", mock.Anything, "a@example.com").Return(domain.User{}, mongo.ErrNoDocuments).Once()
+	suite.mockMetrics.On("ObserveRepositoryCall", "user", "GetUserByEmail", mock.Anything, nil).Once()
+
+	_, err := suite.userRepo.GetUserByEmail(context.Background(), "a@example.com")
+	assert.ErrorIs(suite.T(), err, mongo.ErrNoDocuments)
+	suite.mockMetrics.AssertExpectations(suite.T())
+}
+
+func TestInstrumentedRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(InstrumentedRepositoryTestSuite))
+}
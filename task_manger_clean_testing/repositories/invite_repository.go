@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type inviteRepository struct {
+	database   *mongo.Database
+	collection string
+
+	mu           sync.Mutex
+	indexesReady bool
+}
+
+// NewInviteRepository returns an InviteRepository kept in Mongo. Invites are
+// removed by a TTL index once they expire, accepted or not.
+func NewInviteRepository(db *mongo.Database, collection string) domain.InviteRepository {
+	return &inviteRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (i *inviteRepository) ensureIndexes(c context.Context) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.indexesReady {
+		return nil
+	}
+
+	_, err := i.database.Collection(i.collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "workspaceid", Value: 1}, {Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "workspaceid", Value: 1}, {Key: "email", Value: 1}}},
+		{Keys: bson.D{{Key: "expiresat", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	if err != nil {
+		return err
+	}
+	i.indexesReady = true
+	return nil
+}
+
+// pending matches the invites that have not been accepted.
+func pending(filter bson.D) bson.D {
+	return append(filter, bson.E{Key: "acceptedat", Value: nil})
+}
+
+// Create implements domain.InviteRepository.
+func (i *inviteRepository) Create(c context.Context, invite domain.Invite) error {
+	workspace_id, err := domain.WorkspaceFrom(c)
+	if err != nil {
+		return err
+	}
+	if err := i.ensureIndexes(c); err != nil {
+		return err
+	}
+	invite.WorkspaceID = workspace_id
+	_, err = i.database.Collection(i.collection).InsertOne(c, invite)
+	return err
+}
+
+// ListPending implements domain.InviteRepository.
+func (i *inviteRepository) ListPending(c context.Context, now time.Time) ([]domain.Invite, error) {
+	filter, err := workspaceFilter(c)
+	if err != nil {
+		return nil, err
+	}
+	filter = append(pending(filter), bson.E{Key: "expiresat", Value: bson.D{{Key: "$gt", Value: now}}})
+	opts := options.Find().SetSort(bson.D{{Key: "createdat", Value: -1}})
+	cursor, err := i.database.Collection(i.collection).Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	invites := []domain.Invite{}
+	if err := cursor.All(c, &invites); err != nil {
+		return nil, err
+	}
+	return invites, nil
+}
+
+// DeletePending implements domain.InviteRepository.
+func (i *inviteRepository) DeletePending(c context.Context, email string) error {
+	filter, err := workspaceFilter(c)
+	if err != nil {
+		return err
+	}
+	_, err = i.database.Collection(i.collection).DeleteMany(c, append(pending(filter), bson.E{Key: "email", Value: email}))
+	return err
+}
+
+// Delete implements domain.InviteRepository.
+func (i *inviteRepository) Delete(c context.Context, id string) error {
+	filter, err := workspaceFilter(c)
+	if err != nil {
+		return err
+	}
+	result, err := i.database.Collection(i.collection).DeleteOne(c, append(pending(filter), bson.E{Key: "id", Value: id}))
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return domain.ErrInviteNotFound
+	}
+	return nil
+}
+
+// Renew implements domain.InviteRepository.
+func (i *inviteRepository) Renew(c context.Context, id string, hash string, expiresAt time.Time) (*domain.Invite, error) {
+	filter, err := workspaceFilter(c)
+	if err != nil {
+		return nil, err
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "hash", Value: hash},
+		{Key: "expiresat", Value: expiresAt},
+	}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var invite domain.Invite
+	err = i.database.Collection(i.collection).FindOneAndUpdate(c, append(pending(filter), bson.E{Key: "id", Value: id}), update, opts).Decode(&invite)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInviteNotFound
+		}
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// redeemable matches the pending, unexpired invite with the token hash.
+func redeemable(hash string, now time.Time) bson.D {
+	return pending(bson.D{
+		{Key: "hash", Value: hash},
+		{Key: "expiresat", Value: bson.D{{Key: "$gt", Value: now}}},
+	})
+}
+
+// GetByHash implements domain.InviteRepository.
+func (i *inviteRepository) GetByHash(c context.Context, hash string, now time.Time) (*domain.Invite, error) {
+	var invite domain.Invite
+	err := i.database.Collection(i.collection).FindOne(c, redeemable(hash, now)).Decode(&invite)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvalidInvite
+		}
+		return nil, err
+	}
+	return &invite, nil
+}
+
+// Consume implements domain.InviteRepository. The invite is checked and
+// marked accepted in one update, so two requests cannot both accept it.
+func (i *inviteRepository) Consume(c context.Context, hash string, now time.Time) (*domain.Invite, error) {
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "acceptedat", Value: now}}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var invite domain.Invite
+	err := i.database.Collection(i.collection).FindOneAndUpdate(c, redeemable(hash, now), update, opts).Decode(&invite)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvalidInvite
+		}
+		return nil, err
+	}
+	return &invite, nil
+}
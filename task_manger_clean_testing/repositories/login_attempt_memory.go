@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"task_manger_clean_architecture/domain"
+	"time"
+)
+
+// maxInMemoryLoginAttempts bounds the map before stale keys are pruned, so a
+// flood of failures from many addresses cannot grow it without limit.
+const maxInMemoryLoginAttempts = 10000
+
+type inMemoryLoginAttemptStore struct {
+	mu       sync.Mutex
+	attempts map[string]domain.LoginAttempt
+}
+
+// NewInMemoryLoginAttemptStore returns a LoginAttemptStore local to this
+// process. Counts are lost on restart and not shared between instances.
+func NewInMemoryLoginAttemptStore() domain.LoginAttemptStore {
+	return &inMemoryLoginAttemptStore{
+		attempts: map[string]domain.LoginAttempt{},
+	}
+}
+
+// Get implements domain.LoginAttemptStore.
+func (s *inMemoryLoginAttemptStore) Get(c context.Context, key string) (*domain.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+	return &attempt, nil
+}
+
+// Reserve implements domain.LoginAttemptStore.
+func (s *inMemoryLoginAttemptStore) Reserve(c context.Context, key string, now time.Time, window time.Duration) (*domain.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.attempts) >= maxInMemoryLoginAttempts {
+		s.prune(now, window)
+	}
+
+	attempt, ok := s.attempts[key]
+	var before *domain.LoginAttempt
+	if ok {
+		copied := attempt
+		before = &copied
+	}
+	attempt.Key = key
+	if attempt.LastFailure.Before(now.Add(-window)) {
+		attempt.Failures = 0
+	}
+	attempt.Failures++
+	attempt.LastFailure = now
+	s.attempts[key] = attempt
+	return before, nil
+}
+
+// Release implements domain.LoginAttemptStore.
+func (s *inMemoryLoginAttemptStore) Release(c context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt, ok := s.attempts[key]
+	if !ok || attempt.Failures == 0 {
+		return nil
+	}
+	attempt.Failures--
+	s.attempts[key] = attempt
+	return nil
+}
+
+// Lock implements domain.LoginAttemptStore.
+func (s *inMemoryLoginAttemptStore) Lock(c context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt := s.attempts[key]
+	attempt.Key = key
+	attempt.LockedUntil = until
+	s.attempts[key] = attempt
+	return nil
+}
+
+// Reset implements domain.LoginAttemptStore.
+func (s *inMemoryLoginAttemptStore) Reset(c context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+	return nil
+}
+
+// prune drops the keys that are neither locked nor within the failure window.
+func (s *inMemoryLoginAttemptStore) prune(now time.Time, window time.Duration) {
+	for key, attempt := range s.attempts {
+		if attempt.LastFailure.Before(now.Add(-window)) && attempt.LockedUntil.Before(now) {
+			delete(s.attempts, key)
+		}
+	}
+}
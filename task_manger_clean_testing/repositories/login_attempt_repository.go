@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// loginAttemptTTL is how long Mongo keeps an attempt after its last failure.
+// It only needs to outlive the failure window and the lockout.
+const loginAttemptTTL = 24 * time.Hour
+
+type loginAttemptRepository struct {
+	database   *mongo.Database
+	collection string
+
+	mu           sync.Mutex
+	indexesReady bool
+}
+
+// NewLoginAttemptRepository returns a LoginAttemptStore kept in Mongo, so
+// every instance of the service sees the same failure counts.
+func NewLoginAttemptRepository(db *mongo.Database, collection string) domain.LoginAttemptStore {
+	return &loginAttemptRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (l *loginAttemptRepository) ensureIndexes(c context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.indexesReady {
+		return nil
+	}
+
+	_, err := l.database.Collection(l.collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "lastfailure", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(int32(loginAttemptTTL.Seconds()))},
+	})
+	if err != nil {
+		return err
+	}
+	l.indexesReady = true
+	return nil
+}
+
+// Get implements domain.LoginAttemptStore.
+func (l *loginAttemptRepository) Get(c context.Context, key string) (*domain.LoginAttempt, error) {
+	var attempt domain.LoginAttempt
+	err := l.database.Collection(l.collection).FindOne(c, bson.D{{Key: "key", Value: key}}).Decode(&attempt)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// Reserve implements domain.LoginAttemptStore. The count is updated with a
+// single pipeline update, so concurrent attempts each see a different count.
+func (l *loginAttemptRepository) Reserve(c context.Context, key string, now time.Time, window time.Duration) (*domain.LoginAttempt, error) {
+	if err := l.ensureIndexes(c); err != nil {
+		return nil, err
+	}
+
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{
+			{Key: "failures", Value: bson.D{{Key: "$cond", Value: bson.A{
+				bson.D{{Key: "$gte", Value: bson.A{"$lastfailure", now.Add(-window)}}},
+				bson.D{{Key: "$add", Value: bson.A{bson.D{{Key: "$ifNull", Value: bson.A{"$failures", 0}}}, 1}}},
+				1,
+			}}}},
+			{Key: "lastfailure", Value: now},
+		}}},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before)
+
+	var before domain.LoginAttempt
+	err := l.database.Collection(l.collection).FindOneAndUpdate(c, bson.D{{Key: "key", Value: key}}, update, opts).Decode(&before)
+	if err != nil {
+		// The upsert inserted the key, so there was nothing before
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &before, nil
+}
+
+// Release implements domain.LoginAttemptStore.
+func (l *loginAttemptRepository) Release(c context.Context, key string) error {
+	_, err := l.database.Collection(l.collection).UpdateOne(c,
+		bson.D{{Key: "key", Value: key}, {Key: "failures", Value: bson.D{{Key: "$gt", Value: 0}}}},
+		bson.D{{Key: "$inc", Value: bson.D{{Key: "failures", Value: -1}}}})
+	return err
+}
+
+// Lock implements domain.LoginAttemptStore.
+func (l *loginAttemptRepository) Lock(c context.Context, key string, until time.Time) error {
+	_, err := l.database.Collection(l.collection).UpdateOne(c,
+		bson.D{{Key: "key", Value: key}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "lockeduntil", Value: until}}}},
+		options.Update().SetUpsert(true))
+	return err
+}
+
+// Reset implements domain.LoginAttemptStore.
+func (l *loginAttemptRepository) Reset(c context.Context, key string) error {
+	_, err := l.database.Collection(l.collection).DeleteOne(c, bson.D{{Key: "key", Value: key}})
+	return err
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type oidcLoginRepository struct {
+	database   *mongo.Database
+	collection string
+
+	mu           sync.Mutex
+	indexesReady bool
+}
+
+// NewOIDCLoginRepository returns an OIDCLoginRepository kept in Mongo.
+// Abandoned logins are removed by a TTL index.
+func NewOIDCLoginRepository(db *mongo.Database, collection string) domain.OIDCLoginRepository {
+	return &oidcLoginRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (o *oidcLoginRepository) ensureIndexes(c context.Context) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.indexesReady {
+		return nil
+	}
+
+	_, err := o.database.Collection(o.collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "statehash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "expiresat", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	if err != nil {
+		return err
+	}
+	o.indexesReady = true
+	return nil
+}
+
+// Create implements domain.OIDCLoginRepository.
+func (o *oidcLoginRepository) Create(c context.Context, login domain.OIDCLogin) error {
+	if err := o.ensureIndexes(c); err != nil {
+		return err
+	}
+	_, err := o.database.Collection(o.collection).InsertOne(c, login)
+	return err
+}
+
+// Consume implements domain.OIDCLoginRepository. Deleting the login as it
+// is read keeps a callback from being replayed.
+func (o *oidcLoginRepository) Consume(c context.Context, stateHash string, now time.Time) (*domain.OIDCLogin, error) {
+	filter := bson.D{
+		{Key: "statehash", Value: stateHash},
+		{Key: "expiresat", Value: bson.D{{Key: "$gt", Value: now}}},
+	}
+
+	var login domain.OIDCLogin
+	err := o.database.Collection(o.collection).FindOneAndDelete(c, filter).Decode(&login)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvalidOIDCState
+		}
+		return nil, err
+	}
+	return &login, nil
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"sync"
+	"task_manger_clean_architecture/domain"
+	"time"
+)
+
+// maxInMemoryRateLimitBuckets bounds the buckets kept. Past it the least
+// recently used bucket is dropped, so a flood of new clients cannot grow the
+// store without bound.
+const maxInMemoryRateLimitBuckets = 10000
+
+type tokenBucket struct {
+	key    string
+	tokens float64
+	last   time.Time
+	limit  domain.RateLimit
+}
+
+// refill adds the tokens earned since the last request.
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed.Seconds()*tokensPerSecond(b.limit))
+	}
+	b.last = now
+}
+
+func tokensPerSecond(limit domain.RateLimit) float64 {
+	return float64(limit.Rate) / limit.Period.Seconds()
+}
+
+type inMemoryRateLimitStore struct {
+	mu      sync.Mutex
+	max     int
+	buckets map[string]*list.Element
+	// recent orders the buckets from the most recently used.
+	recent *list.List
+}
+
+// NewInMemoryRateLimitStore returns a RateLimitStore local to this process.
+// Every instance behind a load balancer keeps its own buckets.
+func NewInMemoryRateLimitStore() domain.RateLimitStore {
+	return newInMemoryRateLimitStore(maxInMemoryRateLimitBuckets)
+}
+
+func newInMemoryRateLimitStore(max int) *inMemoryRateLimitStore {
+	return &inMemoryRateLimitStore{
+		max:     max,
+		buckets: map[string]*list.Element{},
+		recent:  list.New(),
+	}
+}
+
+// Take implements domain.RateLimitStore.
+func (s *inMemoryRateLimitStore) Take(c context.Context, key string, limit domain.RateLimit, now time.Time) (*domain.RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket := s.bucket(key, limit, now)
+	bucket.refill(now)
+
+	rate := tokensPerSecond(limit)
+	result := &domain.RateLimitResult{Limit: limit.Burst}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - bucket.tokens) / rate * float64(time.Second))
+	}
+	result.Remaining = int(bucket.tokens)
+	result.ResetAfter = time.Duration((float64(limit.Burst) - bucket.tokens) / rate * float64(time.Second))
+	return result, nil
+}
+
+// bucket returns the bucket of key, marked as the most recently used. A new
+// one starts full and replaces the least recently used bucket when the
+// store is full.
+func (s *inMemoryRateLimitStore) bucket(key string, limit domain.RateLimit, now time.Time) *tokenBucket {
+	if element, ok := s.buckets[key]; ok {
+		s.recent.MoveToFront(element)
+		bucket := element.Value.(*tokenBucket)
+		if bucket.limit != limit {
+			*bucket = tokenBucket{key: key, tokens: float64(limit.Burst), last: now, limit: limit}
+		}
+		return bucket
+	}
+
+	if s.recent.Len() >= s.max {
+		oldest := s.recent.Back()
+		s.recent.Remove(oldest)
+		delete(s.buckets, oldest.Value.(*tokenBucket).key)
+	}
+	bucket := &tokenBucket{key: key, tokens: float64(limit.Burst), last: now, limit: limit}
+	s.buckets[key] = s.recent.PushFront(bucket)
+	return bucket
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"task_manger_clean_architecture/domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type InMemoryRateLimitStoreTestSuite struct {
+	suite.Suite
+	store domain.RateLimitStore
+	limit domain.RateLimit
+	now   time.Time
+}
+
+func (suite *InMemoryRateLimitStoreTestSuite) SetupTest() {
+	suite.store = NewInMemoryRateLimitStore()
+	suite.limit = domain.RateLimit{Rate: 1, Period: time.Second, Burst: 3}
+	suite.now = time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func (suite *InMemoryRateLimitStoreTestSuite) take(key string) *domain.RateLimitResult {
+	result, err := suite.store.Take(context.Background(), key, suite.limit, suite.now)
+	suite.Require().NoError(err)
+	return result
+}
+
+func (suite *InMemoryRateLimitStoreTestSuite) TestBurstThenDeny() {
+	for remaining := 2; remaining >= 0; remaining-- {
+		result := suite.take("a")
+		suite.True(result.Allowed)
+		suite.Equal(remaining, result.Remaining)
+		suite.Equal(3, result.Limit)
+	}
+
+	result := suite.take("a")
+	suite.False(result.Allowed)
+	suite.Equal(time.Second, result.RetryAfter)
+	suite.Equal(3*time.Second, result.ResetAfter)
+
+	// Other keys have their own bucket
+	suite.True(suite.take("b").Allowed)
+}
+
+func (suite *InMemoryRateLimitStoreTestSuite) TestRefillsOverTime() {
+	for i := 0; i < 3; i++ {
+		suite.take("a")
+	}
+	suite.now = suite.now.Add(1500 * time.Millisecond)
+
+	result := suite.take("a")
+	suite.True(result.Allowed)
+	suite.Equal(0, result.Remaining)
+
+	suite.now = suite.now.Add(time.Hour)
+	suite.Equal(2, suite.take("a").Remaining)
+}
+
+func (suite *InMemoryRateLimitStoreTestSuite) TestDropsTheLeastRecentlyUsedBuckets() {
+	store := newInMemoryRateLimitStore(2)
+	suite.store = store
+	for i := 0; i < 3; i++ {
+		suite.take("a")
+	}
+	suite.take("b")
+	suite.take("a")
+
+	// b was used less recently than a
+	suite.take("c")
+	suite.Contains(store.buckets, "a")
+	suite.NotContains(store.buckets, "b")
+	suite.False(suite.take("a").Allowed)
+
+	// A flood of new clients never grows the store past its bound
+	for _, key := range []string{"d", "e", "f", "g"} {
+		suite.take(key)
+		suite.Len(store.buckets, 2)
+		suite.Equal(2, store.recent.Len())
+	}
+	suite.Equal(2, suite.take("a").Remaining, "a was dropped, so it starts over")
+}
+
+func TestInMemoryRateLimitStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(InMemoryRateLimitStoreTestSuite))
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"task_manger_clean_architecture/domain"
+	"time"
+)
+
+type inMemoryTaskReportRepository struct {
+	taskRepository domain.TaskRepository
+}
+
+// NewInMemoryTaskReportRepository returns a TaskReportRepository that loads
+// every task from taskRepository and computes the same report as the Mongo
+// pipeline in Go. It suits the in-memory backends and small task sets.
+func NewInMemoryTaskReportRepository(taskRepository domain.TaskRepository) domain.TaskReportRepository {
+	return &inMemoryTaskReportRepository{
+		taskRepository: taskRepository,
+	}
+}
+
+// TaskReport implements domain.TaskReportRepository.
+func (r *inMemoryTaskReportRepository) TaskReport(c context.Context, now time.Time, windows []domain.ReportWindow) (*domain.TaskReport, error) {
+	tasks, err := r.taskRepository.GetTasks(c)
+	if err != nil {
+		return nil, err
+	}
+
+	report := newTaskReport(now)
+	var completed, timedCompletions int64
+	var completionTime time.Duration
+	users := map[string]*domain.UserTaskReport{}
+	windowCounts := make([]reportWindowCounts, len(windows))
+
+	for _, task := range tasks {
+		isCompleted := domain.IsTaskCompleted(task.Status)
+		isOverdue := !task.DueDate.IsZero() && task.DueDate.Before(now) && !isCompleted
+
+		report.Total++
+		report.ByStatus[task.Status]++
+		if isCompleted {
+			completed++
+			if task.CompletedAt != nil && !task.CreatedAt.IsZero() {
+				timedCompletions++
+				completionTime += task.CompletedAt.Sub(task.CreatedAt)
+			}
+		}
+		if isOverdue {
+			report.Overdue++
+		}
+
+		user, ok := users[task.CreatedBy]
+		if !ok {
+			user = &domain.UserTaskReport{UserID: task.CreatedBy}
+			users[task.CreatedBy] = user
+		}
+		user.Total++
+		if isCompleted {
+			user.Completed++
+		}
+		if isOverdue {
+			user.Overdue++
+		}
+
+		for i, window := range windows {
+			since := now.Add(-window.Duration)
+			createdInWindow := !task.CreatedAt.Before(since)
+			if createdInWindow {
+				windowCounts[i].Created++
+				if isCompleted {
+					windowCounts[i].CompletedOfCreated++
+				}
+			}
+			if task.CompletedAt != nil && !task.CompletedAt.Before(since) {
+				windowCounts[i].Completed++
+			}
+		}
+	}
+
+	report.CompletionRate = ratio(completed, report.Total)
+	if timedCompletions > 0 {
+		report.AverageCompletionSeconds = completionTime.Seconds() / float64(timedCompletions)
+	}
+	for i, window := range windows {
+		report.Windows = append(report.Windows, domain.TaskReportWindow{
+			Name:           window.Name,
+			Since:          now.Add(-window.Duration),
+			Created:        windowCounts[i].Created,
+			Completed:      windowCounts[i].Completed,
+			CompletionRate: ratio(windowCounts[i].CompletedOfCreated, windowCounts[i].Created),
+		})
+	}
+	for _, user := range users {
+		report.Users = append(report.Users, *user)
+	}
+	sort.Slice(report.Users, func(i, j int) bool {
+		return report.Users[i].UserID < report.Users[j].UserID
+	})
+
+	return report, nil
+}
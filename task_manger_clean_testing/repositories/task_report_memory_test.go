@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type InMemoryTaskReportTestSuite struct {
+	suite.Suite
+	mockTaskRepo *mocks.TaskRepository
+	reportRepo   domain.TaskReportRepository
+	now          time.Time
+}
+
+func (suite *InMemoryTaskReportTestSuite) SetupTest() {
+	suite.mockTaskRepo = new(mocks.TaskRepository)
+	suite.reportRepo = NewInMemoryTaskReportRepository(suite.mockTaskRepo)
+	suite.now = time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+}
+
+func (suite *InMemoryTaskReportTestSuite) TestTaskReport() {
+	day := 24 * time.Hour
+	completedAt := suite.now.Add(-2 * day)
+	tasks := []*domain.Task{
+		// Completed in two days, created inside the 7 day window
+		{ID: "1", Status: "Completed", CreatedBy: "alice", CreatedAt: suite.now.Add(-4 * day), CompletedAt: &completedAt},
+		// Overdue and still pending
+		{ID: "2", Status: "pending", CreatedBy: "alice", CreatedAt: suite.now.Add(-20 * day), DueDate: suite.now.Add(-day)},
+		// Pending but not due yet
+		{ID: "3", Status: "pending", CreatedBy: "bob", CreatedAt: suite.now.Add(-day), DueDate: suite.now.Add(day)},
+	}
+	suite.mockTaskRepo.On("GetTasks", mock.Anything).Return(tasks, nil)
+
+	windows := []domain.ReportWindow{
+		{Name: "7d", Duration: 7 * day},
+		{Name: "30d", Duration: 30 * day},
+	}
+	report, err := suite.reportRepo.TaskReport(context.Background(), suite.now, windows)
+	suite.NoError(err)
+
+	suite.Equal(int64(3), report.Total)
+	suite.Equal(map[string]int64{"Completed": 1, "pending": 2}, report.ByStatus)
+	suite.Equal(int64(1), report.Overdue)
+	suite.InDelta(1.0/3.0, report.CompletionRate, 1e-9)
+	suite.Equal((2 * day).Seconds(), report.AverageCompletionSeconds)
+
+	suite.Equal([]domain.TaskReportWindow{
+		{Name: "7d", Since: suite.now.Add(-7 * day), Created: 2, Completed: 1, CompletionRate: 0.5},
+		{Name: "30d", Since: suite.now.Add(-30 * day), Created: 3, Completed: 1, CompletionRate: 1.0 / 3.0},
+	}, report.Windows)
+
+	suite.Equal([]domain.UserTaskReport{
+		{UserID: "alice", Total: 2, Completed: 1, Overdue: 1},
+		{UserID: "bob", Total: 1},
+	}, report.Users)
+}
+
+func TestInMemoryTaskReportTestSuite(t *testing.T) {
+	suite.Run(t, new(InMemoryTaskReportTestSuite))
+}
@@ -0,0 +1,203 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type taskReportRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+// NewTaskReportRepository returns a TaskReportRepository that computes the
+// report with a single aggregation pipeline over the task collection.
+func NewTaskReportRepository(db *mongo.Database, collection string) domain.TaskReportRepository {
+	return &taskReportRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+type reportTotals struct {
+	Total           int64    `bson:"total"`
+	Completed       int64    `bson:"completed"`
+	Overdue         int64    `bson:"overdue"`
+	AvgCompletionMs *float64 `bson:"avgcompletionms"`
+}
+
+type reportStatusBucket struct {
+	Status string `bson:"_id"`
+	Count  int64  `bson:"count"`
+}
+
+type reportUserBucket struct {
+	UserID    string `bson:"_id"`
+	Total     int64  `bson:"total"`
+	Completed int64  `bson:"completed"`
+	Overdue   int64  `bson:"overdue"`
+}
+
+type reportWindowCounts struct {
+	Created            int64 `bson:"created"`
+	Completed          int64 `bson:"completed"`
+	CompletedOfCreated int64 `bson:"completedofcreated"`
+}
+
+func countIf(condition interface{}) bson.D {
+	return bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{condition, 1, 0}}}}}
+}
+
+// TaskReport implements domain.TaskReportRepository.
+func (t *taskReportRepository) TaskReport(c context.Context, now time.Time, windows []domain.ReportWindow) (*domain.TaskReport, error) {
+	filter, err := workspaceFilter(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Flag every task once, then let each $facet branch group the flags.
+	flagStage := bson.D{{Key: "$addFields", Value: bson.D{
+		{Key: "iscompleted", Value: bson.D{{Key: "$in", Value: bson.A{
+			bson.D{{Key: "$toLower", Value: "$status"}}, domain.CompletedTaskStatuses,
+		}}}},
+	}}}
+	overdueStage := bson.D{{Key: "$addFields", Value: bson.D{
+		{Key: "isoverdue", Value: bson.D{{Key: "$and", Value: bson.A{
+			bson.D{{Key: "$gt", Value: bson.A{"$duedate", time.Time{}}}},
+			bson.D{{Key: "$lt", Value: bson.A{"$duedate", now}}},
+			bson.D{{Key: "$not", Value: bson.A{"$iscompleted"}}},
+		}}}},
+	}}}
+
+	completionKnown := bson.D{{Key: "$and", Value: bson.A{
+		"$iscompleted",
+		bson.D{{Key: "$gt", Value: bson.A{"$completedat", nil}}},
+		bson.D{{Key: "$gt", Value: bson.A{"$createdat", time.Time{}}}},
+	}}}
+	facets := bson.D{
+		{Key: "totals", Value: bson.A{bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: nil},
+			{Key: "total", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "completed", Value: countIf("$iscompleted")},
+			{Key: "overdue", Value: countIf("$isoverdue")},
+			{Key: "avgcompletionms", Value: bson.D{{Key: "$avg", Value: bson.D{{Key: "$cond", Value: bson.A{
+				completionKnown, bson.D{{Key: "$subtract", Value: bson.A{"$completedat", "$createdat"}}}, nil,
+			}}}}}},
+		}}}}},
+		{Key: "bystatus", Value: bson.A{bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$status"},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}}}},
+		{Key: "users", Value: bson.A{
+			bson.D{{Key: "$group", Value: bson.D{
+				{Key: "_id", Value: "$createdby"},
+				{Key: "total", Value: bson.D{{Key: "$sum", Value: 1}}},
+				{Key: "completed", Value: countIf("$iscompleted")},
+				{Key: "overdue", Value: countIf("$isoverdue")},
+			}}},
+			bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		}},
+	}
+	for i, window := range windows {
+		since := now.Add(-window.Duration)
+		createdInWindow := bson.D{{Key: "$gte", Value: bson.A{"$createdat", since}}}
+		facets = append(facets, bson.E{Key: fmt.Sprintf("window%d", i), Value: bson.A{bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: nil},
+			{Key: "created", Value: countIf(createdInWindow)},
+			{Key: "completed", Value: countIf(bson.D{{Key: "$gte", Value: bson.A{"$completedat", since}}})},
+			{Key: "completedofcreated", Value: countIf(bson.D{{Key: "$and", Value: bson.A{createdInWindow, "$iscompleted"}}})},
+		}}}}})
+	}
+
+	matchStage := bson.D{{Key: "$match", Value: filter}}
+	pipeline := mongo.Pipeline{matchStage, flagStage, overdueStage, bson.D{{Key: "$facet", Value: facets}}}
+	cursor, err := t.database.Collection(t.collection).Aggregate(c, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("aggregation error: %v", err)
+	}
+	defer cursor.Close(c)
+
+	var result []map[string][]bson.Raw
+	if err := cursor.All(c, &result); err != nil {
+		return nil, fmt.Errorf("cursor error: %v", err)
+	}
+
+	report := newTaskReport(now)
+	if len(result) == 0 {
+		return report, nil
+	}
+	facetResult := result[0]
+
+	var totals reportTotals
+	for _, raw := range facetResult["totals"] {
+		if err := bson.Unmarshal(raw, &totals); err != nil {
+			return nil, err
+		}
+	}
+	report.Total = totals.Total
+	report.Overdue = totals.Overdue
+	report.CompletionRate = ratio(totals.Completed, totals.Total)
+	if totals.AvgCompletionMs != nil {
+		report.AverageCompletionSeconds = *totals.AvgCompletionMs / 1000
+	}
+
+	for _, raw := range facetResult["bystatus"] {
+		var bucket reportStatusBucket
+		if err := bson.Unmarshal(raw, &bucket); err != nil {
+			return nil, err
+		}
+		report.ByStatus[bucket.Status] = bucket.Count
+	}
+
+	for _, raw := range facetResult["users"] {
+		var bucket reportUserBucket
+		if err := bson.Unmarshal(raw, &bucket); err != nil {
+			return nil, err
+		}
+		report.Users = append(report.Users, domain.UserTaskReport{
+			UserID:    bucket.UserID,
+			Total:     bucket.Total,
+			Completed: bucket.Completed,
+			Overdue:   bucket.Overdue,
+		})
+	}
+
+	for i, window := range windows {
+		var counts reportWindowCounts
+		for _, raw := range facetResult[fmt.Sprintf("window%d", i)] {
+			if err := bson.Unmarshal(raw, &counts); err != nil {
+				return nil, err
+			}
+		}
+		report.Windows = append(report.Windows, domain.TaskReportWindow{
+			Name:           window.Name,
+			Since:          now.Add(-window.Duration),
+			Created:        counts.Created,
+			Completed:      counts.Completed,
+			CompletionRate: ratio(counts.CompletedOfCreated, counts.Created),
+		})
+	}
+
+	return report, nil
+}
+
+func newTaskReport(now time.Time) *domain.TaskReport {
+	return &domain.TaskReport{
+		GeneratedAt: now,
+		ByStatus:    map[string]int64{},
+		Windows:     []domain.TaskReportWindow{},
+		Users:       []domain.UserTaskReport{},
+	}
+}
+
+func ratio(part, whole int64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return float64(part) / float64(whole)
+}
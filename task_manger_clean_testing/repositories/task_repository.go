@@ -24,15 +24,23 @@ func NewTaskRepository(db *mongo.Database, collection string) domain.TaskReposit
 
 // AddTask implements domain.TaskRepository.
 func (t *taskRepository) AddTask(c context.Context, newTask domain.Task) error {
+    workspace_id, err := domain.WorkspaceFrom(c)
+    if err != nil {
+        return err
+    }
     task := bson.D{
         {Key: "id", Value: newTask.ID},
         {Key: "title", Value: newTask.Title},
         {Key: "description", Value: newTask.Description},
         {Key: "status", Value: newTask.Status},
         {Key: "duedate", Value: newTask.DueDate},
+        {Key: "createdby", Value: newTask.CreatedBy},
+        {Key: "createdat", Value: newTask.CreatedAt},
+        {Key: "completedat", Value: newTask.CompletedAt},
+        {Key: "workspaceid", Value: workspace_id},
     }
 	collection := t.database.Collection(t.collection)
-    _, err := collection.InsertOne(c, task)
+    _, err = collection.InsertOne(c, task)
     if err != nil {
         return err // Return the error to be handled by the controller
     }
@@ -42,10 +50,14 @@ func (t *taskRepository) AddTask(c context.Context, newTask domain.Task) error {
 
 // DeleteById implements domain.TaskRepository.
 func (t *taskRepository) DeleteById(c context.Context, id string) (int64, error) {
+    filter, err := workspaceFilter(c)
+    if err != nil {
+        return 0, err
+    }
     collection := t.database.Collection(t.collection)
     
     // Attempt to delete the task by ID
-    result, err := collection.DeleteMany(c, bson.D{{Key: "id", Value: id}})
+    result, err := collection.DeleteMany(c, append(filter, bson.E{Key: "id", Value: id}))
     if err != nil {
         return 0, err // Return 0 and the error if something goes wrong
     }
@@ -56,9 +68,13 @@ func (t *taskRepository) DeleteById(c context.Context, id string) (int64, error)
 
 // GetTasks implements domain.TaskRepository.
 func (t *taskRepository) GetTasks(c context.Context) ([]*domain.Task, error) {
+    filter, err := workspaceFilter(c)
+    if err != nil {
+        return nil, err
+    }
     collection := t.database.Collection(t.collection)
     
-    cur, err := collection.Find(c, bson.D{{}})
+    cur, err := collection.Find(c, filter)
     if err != nil {
         return nil, errors.New("error fetching tasks from database")
     }
@@ -82,11 +98,14 @@ func (t *taskRepository) GetTasks(c context.Context) ([]*domain.Task, error) {
 // GetTasksById implements domain.TaskRepository.
 
 func (t *taskRepository) GetTasksById(c context.Context, id string) (*domain.Task, error) {
+    filter, err := workspaceFilter(c)
+    if err != nil {
+        return nil, err
+    }
     collection := t.database.Collection(t.collection)
     
     var result domain.Task
-    filter := bson.D{{Key: "id", Value: id}}
-    err := collection.FindOne(c, filter).Decode(&result)
+    err = collection.FindOne(c, append(filter, bson.E{Key: "id", Value: id})).Decode(&result)
     if err != nil {
         if err == mongo.ErrNoDocuments {
             return nil, nil // No document found, return nil without an error
@@ -99,22 +118,30 @@ func (t *taskRepository) GetTasksById(c context.Context, id string) (*domain.Tas
 // UpdateTask implements domain.TaskRepository.
 
 func (t *taskRepository) UpdateTask(c context.Context, id string, updatedTask domain.Task) error {
+    filter, err := workspaceFilter(c)
+    if err != nil {
+        return err
+    }
+    filter = append(filter, bson.E{Key: "id", Value: id})
     collection := t.database.Collection(t.collection)
     
-    filter := bson.D{{Key: "id", Value: id}}
     update := bson.D{
         {Key: "$set", Value: bson.D{
             {Key: "title", Value: updatedTask.Title},
             {Key: "description", Value: updatedTask.Description},
             {Key: "status", Value: updatedTask.Status},
             {Key: "duedate", Value: updatedTask.DueDate},
+            {Key: "completedat", Value: updatedTask.CompletedAt},
         }},
     }
 
-    _, err := collection.UpdateOne(c, filter, update)
+    result, err := collection.UpdateOne(c, filter, update)
     if err != nil {
         return err // Return the error to be handled by the controller
     }
+    if result.MatchedCount == 0 {
+        return mongo.ErrNoDocuments // The task was deleted or is in another workspace
+    }
 
     return nil // No error occurred, so return nil
 }
@@ -18,6 +18,7 @@ type TaskRepositoryTestSuite struct {
     mongoClient   *mongo.Client
     testDatabase  *mongo.Database
     testCollection string
+    ctx           context.Context
 }
 
 func (suite *TaskRepositoryTestSuite) SetupSuite() {
@@ -37,6 +38,7 @@ func (suite *TaskRepositoryTestSuite) SetupSuite() {
 
     // Initialize the repository with the test database and collection
     suite.mockRepo = NewTaskRepository(suite.testDatabase, suite.testCollection)
+    suite.ctx = domain.WithWorkspace(context.Background(), "ws1")
 }
 
 func (suite *TaskRepositoryTestSuite) TearDownSuite() {
@@ -65,7 +67,7 @@ func (suite *TaskRepositoryTestSuite) TestAddTask() {
         DueDate:     time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
     }
 
-    err := suite.mockRepo.AddTask(context.Background(), newTask)
+    err := suite.mockRepo.AddTask(suite.ctx, newTask)
     suite.NoError(err)
 
     // Verify the task was added
@@ -86,7 +88,7 @@ func (suite *TaskRepositoryTestSuite) TestDeleteById() {
         DueDate:     time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
     }
 
-    err := suite.mockRepo.AddTask(context.Background(), newTask)
+    err := suite.mockRepo.AddTask(suite.ctx, newTask)
     suite.NoError(err)
 
     // Verify the task was added
@@ -97,7 +99,7 @@ func (suite *TaskRepositoryTestSuite) TestDeleteById() {
     suite.Equal(newTask.Title, result.Title)
 
     // Step 2: Delete the task by ID
-    deletedCount, err := suite.mockRepo.DeleteById(context.Background(), newTask.ID)
+    deletedCount, err := suite.mockRepo.DeleteById(suite.ctx, newTask.ID)
     suite.NoError(err)
     suite.Equal(int64(1), deletedCount)
 
@@ -127,12 +129,12 @@ func (suite *TaskRepositoryTestSuite) TestGetTasks() {
     }
 
     for _, task := range tasksToAdd {
-        err := suite.mockRepo.AddTask(context.Background(), task)
+        err := suite.mockRepo.AddTask(suite.ctx, task)
         suite.NoError(err)
     }
 
     // Step 2: Fetch all tasks
-    fetchedTasks, err := suite.mockRepo.GetTasks(context.Background())
+    fetchedTasks, err := suite.mockRepo.GetTasks(suite.ctx)
     suite.NoError(err)
 
     // Step 3: Verify the fetched tasks
@@ -156,11 +158,11 @@ func (suite *TaskRepositoryTestSuite) TestGetTasksById() {
         DueDate:     time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
     }
 
-    err := suite.mockRepo.AddTask(context.Background(), newTask)
+    err := suite.mockRepo.AddTask(suite.ctx, newTask)
     suite.NoError(err)
 
     // Step 2: Retrieve the task by ID
-    fetchedTask, err := suite.mockRepo.GetTasksById(context.Background(), newTask.ID)
+    fetchedTask, err := suite.mockRepo.GetTasksById(suite.ctx, newTask.ID)
     suite.NoError(err)
     suite.NotNil(fetchedTask)
     suite.Equal(newTask.ID, fetchedTask.ID)
@@ -170,7 +172,7 @@ func (suite *TaskRepositoryTestSuite) TestGetTasksById() {
     suite.Equal(newTask.DueDate, fetchedTask.DueDate)
 
     // Step 3: Test case where no task is found
-    fetchedTask, err = suite.mockRepo.GetTasksById(context.Background(), "nonexistent_id")
+    fetchedTask, err = suite.mockRepo.GetTasksById(suite.ctx, "nonexistent_id")
     suite.NoError(err)
     suite.Nil(fetchedTask)
 }
@@ -185,7 +187,7 @@ func (suite *TaskRepositoryTestSuite) TestUpdateTask() {
         DueDate:     time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
     }
 
-    err := suite.mockRepo.AddTask(context.Background(), newTask)
+    err := suite.mockRepo.AddTask(suite.ctx, newTask)
     suite.NoError(err)
 
     // Step 2: Update the task
@@ -196,11 +198,11 @@ func (suite *TaskRepositoryTestSuite) TestUpdateTask() {
         DueDate:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
     }
 
-    err = suite.mockRepo.UpdateTask(context.Background(), newTask.ID, updatedTask)
+    err = suite.mockRepo.UpdateTask(suite.ctx, newTask.ID, updatedTask)
     suite.NoError(err)
 
     // Step 3: Retrieve the updated task by ID
-    fetchedTask, err := suite.mockRepo.GetTasksById(context.Background(), newTask.ID)
+    fetchedTask, err := suite.mockRepo.GetTasksById(suite.ctx, newTask.ID)
     suite.NoError(err)
     suite.NotNil(fetchedTask)
     suite.Equal(updatedTask.Title, fetchedTask.Title)
@@ -212,6 +214,34 @@ func (suite *TaskRepositoryTestSuite) TestUpdateTask() {
 
 
 
+func (suite *TaskRepositoryTestSuite) TestWorkspacesAreIsolated() {
+    task := domain.Task{ID: "1", Title: "Task 1"}
+    suite.NoError(suite.mockRepo.AddTask(suite.ctx, task))
+
+    // Another workspace neither sees nor touches the task
+    other := domain.WithWorkspace(context.Background(), "ws2")
+    tasks, err := suite.mockRepo.GetTasks(other)
+    suite.NoError(err)
+    suite.Empty(tasks)
+    fetchedTask, err := suite.mockRepo.GetTasksById(other, task.ID)
+    suite.NoError(err)
+    suite.Nil(fetchedTask)
+    err = suite.mockRepo.UpdateTask(other, task.ID, domain.Task{Title: "Renamed"})
+    suite.ErrorIs(err, mongo.ErrNoDocuments)
+    deletedCount, err := suite.mockRepo.DeleteById(other, task.ID)
+    suite.NoError(err)
+    suite.Equal(int64(0), deletedCount)
+
+    fetchedTask, err = suite.mockRepo.GetTasksById(suite.ctx, task.ID)
+    suite.NoError(err)
+    suite.Require().NotNil(fetchedTask)
+    suite.Equal("ws1", fetchedTask.WorkspaceID)
+
+    // Without a workspace nothing is read at all
+    _, err = suite.mockRepo.GetTasks(context.Background())
+    suite.ErrorIs(err, domain.ErrNoWorkspace)
+}
+
 func TestTaskRepositoryTestSuite(t *testing.T) {
     suite.Run(t, new(TaskRepositoryTestSuite))
 }
\ No newline at end of file
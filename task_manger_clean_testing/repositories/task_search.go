@@ -0,0 +1,316 @@
+package repositories
+
+import (
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"unicode"
+)
+
+// Field weights shared by the Mongo text index and the in-memory index so both
+// backends rank title matches above description matches in the same way.
+const (
+	titleWeight       = 3
+	descriptionWeight = 1
+)
+
+const (
+	snippetContext = 8
+	snippetWords   = 30
+)
+
+// searchQuery is a parsed search string: plain terms, prefix* terms and
+// "quoted phrases", all lower-cased and split the same way task text is.
+type searchQuery struct {
+	terms    []string
+	prefixes []string
+	phrases  [][]string
+}
+
+type tokenSpan struct {
+	word       string
+	start, end int
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// tokenSpans splits text into lower-cased words and keeps their byte offsets
+// so matches can be highlighted in the original text.
+func tokenSpans(text string) []tokenSpan {
+	var spans []tokenSpan
+	start := -1
+	for i, r := range text {
+		if isWordRune(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			spans = append(spans, tokenSpan{word: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, tokenSpan{word: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return spans
+}
+
+func tokenize(text string) []string {
+	spans := tokenSpans(text)
+	words := make([]string, len(spans))
+	for i, span := range spans {
+		words[i] = span.word
+	}
+	return words
+}
+
+func uniqueTokens(texts ...string) []string {
+	seen := map[string]bool{}
+	var words []string
+	for _, text := range texts {
+		for _, word := range tokenize(text) {
+			if !seen[word] {
+				seen[word] = true
+				words = append(words, word)
+			}
+		}
+	}
+	return words
+}
+
+func parseSearchQuery(raw string) searchQuery {
+	var q searchQuery
+	rest := raw
+	for {
+		open := strings.IndexByte(rest, '"')
+		if open < 0 {
+			break
+		}
+		closing := strings.IndexByte(rest[open+1:], '"')
+		if closing < 0 {
+			// An unterminated quote is treated as plain text.
+			rest = rest[:open] + " " + rest[open+1:]
+			break
+		}
+		if phrase := tokenize(rest[open+1 : open+1+closing]); len(phrase) > 0 {
+			q.phrases = append(q.phrases, phrase)
+		}
+		rest = rest[:open] + " " + rest[open+closing+2:]
+	}
+
+	seen := map[string]bool{}
+	for _, field := range strings.Fields(rest) {
+		words := tokenize(field)
+		if len(words) == 0 {
+			continue
+		}
+		if strings.HasSuffix(field, "*") {
+			q.prefixes = append(q.prefixes, words[len(words)-1])
+			words = words[:len(words)-1]
+		}
+		for _, word := range words {
+			if !seen[word] {
+				seen[word] = true
+				q.terms = append(q.terms, word)
+			}
+		}
+	}
+	return q
+}
+
+func (q searchQuery) empty() bool {
+	return len(q.terms) == 0 && len(q.prefixes) == 0 && len(q.phrases) == 0
+}
+
+// textSearch renders the terms and phrases of q as a Mongo $text search string.
+func (q searchQuery) textSearch() string {
+	parts := append([]string{}, q.terms...)
+	for _, phrase := range q.phrases {
+		parts = append(parts, `"`+strings.Join(phrase, " ")+`"`)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (q searchQuery) matchesPrefix(word string) bool {
+	for _, prefix := range q.prefixes {
+		if strings.HasPrefix(word, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func phraseAt(words []string, i int, phrase []string) bool {
+	if i+len(phrase) > len(words) {
+		return false
+	}
+	for j, word := range phrase {
+		if words[i+j] != word {
+			return false
+		}
+	}
+	return true
+}
+
+func countPhrase(words []string, phrase []string) int {
+	count := 0
+	for i := range words {
+		if phraseAt(words, i, phrase) {
+			count++
+		}
+	}
+	return count
+}
+
+// score ranks a task's title and description words against q. idf weights
+// individual words; it reports false when a required clause of q is missing.
+func (q searchQuery) score(title, description []string, idf func(word string) float64) (float64, bool) {
+	phrasesFound := make([]bool, len(q.phrases))
+	prefixesFound := make([]bool, len(q.prefixes))
+	termFound := false
+	score := 0.0
+
+	fields := []struct {
+		words  []string
+		weight float64
+	}{
+		{title, titleWeight},
+		{description, descriptionWeight},
+	}
+	for _, field := range fields {
+		counts := map[string]int{}
+		for _, word := range field.words {
+			counts[word]++
+		}
+		for _, term := range q.terms {
+			if tf := counts[term]; tf > 0 {
+				termFound = true
+				score += field.weight * idf(term) * (1 + math.Log(float64(tf)))
+			}
+		}
+		for i, prefix := range q.prefixes {
+			for word, tf := range counts {
+				if strings.HasPrefix(word, prefix) {
+					prefixesFound[i] = true
+					score += field.weight * idf(word) * (1 + math.Log(float64(tf)))
+				}
+			}
+		}
+		for i, phrase := range q.phrases {
+			if n := countPhrase(field.words, phrase); n > 0 {
+				phrasesFound[i] = true
+				score += field.weight * float64(len(phrase)) * (1 + math.Log(float64(n)))
+			}
+		}
+	}
+
+	for _, found := range phrasesFound {
+		if !found {
+			return 0, false
+		}
+	}
+	for _, found := range prefixesFound {
+		if !found {
+			return 0, false
+		}
+	}
+	if len(q.phrases) == 0 && len(q.terms) > 0 && !termFound {
+		return 0, false
+	}
+	return score, true
+}
+
+// highlight returns a snippet of text around the first match of q with every
+// matching word wrapped in <mark></mark>, or "" when nothing in text matches.
+func highlight(text string, q searchQuery) string {
+	spans := tokenSpans(text)
+	words := make([]string, len(spans))
+	for i, span := range spans {
+		words[i] = span.word
+	}
+
+	terms := map[string]bool{}
+	for _, term := range q.terms {
+		terms[term] = true
+	}
+	marked := make([]bool, len(spans))
+	first := -1
+	for i, word := range words {
+		if terms[word] || q.matchesPrefix(word) {
+			marked[i] = true
+		}
+		for _, phrase := range q.phrases {
+			if phraseAt(words, i, phrase) {
+				for j := range phrase {
+					marked[i+j] = true
+				}
+			}
+		}
+		if marked[i] && first < 0 {
+			first = i
+		}
+	}
+	if first < 0 {
+		return ""
+	}
+
+	start := first - snippetContext
+	if start < 0 {
+		start = 0
+	}
+	end := start + snippetWords
+	if end > len(spans) {
+		end = len(spans)
+	}
+
+	var b strings.Builder
+	pos := 0
+	if start > 0 {
+		b.WriteString("…")
+		pos = spans[start].start
+	}
+	for i := start; i < end; i++ {
+		span := spans[i]
+		b.WriteString(html.EscapeString(text[pos:span.start]))
+		if marked[i] {
+			b.WriteString("<mark>" + html.EscapeString(text[span.start:span.end]) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(text[span.start:span.end]))
+		}
+		pos = span.end
+	}
+	if end < len(spans) {
+		b.WriteString("…")
+	} else {
+		b.WriteString(html.EscapeString(text[pos:]))
+	}
+	return b.String()
+}
+
+func highlights(title, description string, q searchQuery) map[string]string {
+	result := map[string]string{}
+	if snippet := highlight(title, q); snippet != "" {
+		result["title"] = snippet
+	}
+	if snippet := highlight(description, q); snippet != "" {
+		result["description"] = snippet
+	}
+	return result
+}
+
+// sortSearchResults orders results by descending score, breaking ties by task
+// ID so equal scores come back in a stable order.
+func sortSearchResults(results []*domain.TaskSearchResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Task.ID < results[j].Task.ID
+	})
+}
@@ -0,0 +1,215 @@
+package repositories
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"task_manger_clean_architecture/domain"
+)
+
+type indexedTask struct {
+	task        domain.Task
+	title       []string
+	description []string
+}
+
+// inMemoryTaskSearcher is an inverted index from words to the keys of the
+// tasks containing them. Task IDs are only unique within a workspace, so
+// tasks are keyed by searchKey. Only the tasks sharing a word with the query are
+// scored, so a search never walks the whole task set.
+type inMemoryTaskSearcher struct {
+	mu       sync.RWMutex
+	tasks    map[string]*indexedTask
+	postings map[string]map[string]struct{}
+
+	// vocabulary is the sorted list of indexed words used to expand prefix
+	// terms. It is rebuilt on the next prefix search after the index changes.
+	vocabulary      []string
+	vocabularyDirty bool
+}
+
+// NewInMemoryTaskSearcher returns a pure-Go TaskSearcher for running without
+// Mongo, e.g. in embedded mode or in tests. The index starts empty.
+func NewInMemoryTaskSearcher() domain.TaskSearcher {
+	return &inMemoryTaskSearcher{
+		tasks:    map[string]*indexedTask{},
+		postings: map[string]map[string]struct{}{},
+	}
+}
+
+func searchKey(workspace_id string, id string) string {
+	return workspace_id + "\x00" + id
+}
+
+// IndexTask implements domain.TaskSearcher.
+func (s *inMemoryTaskSearcher) IndexTask(c context.Context, task domain.Task) error {
+	workspace_id, err := domain.WorkspaceFrom(c)
+	if err != nil {
+		return err
+	}
+	task.WorkspaceID = workspace_id
+	key := searchKey(workspace_id, task.ID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(key)
+	indexed := &indexedTask{
+		task:        task,
+		title:       tokenize(task.Title),
+		description: tokenize(task.Description),
+	}
+	s.tasks[key] = indexed
+	for _, word := range uniqueTokens(task.Title, task.Description) {
+		ids, ok := s.postings[word]
+		if !ok {
+			ids = map[string]struct{}{}
+			s.postings[word] = ids
+			s.vocabularyDirty = true
+		}
+		ids[key] = struct{}{}
+	}
+	return nil
+}
+
+// RemoveTask implements domain.TaskSearcher.
+func (s *inMemoryTaskSearcher) RemoveTask(c context.Context, id string) error {
+	workspace_id, err := domain.WorkspaceFrom(c)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.remove(searchKey(workspace_id, id))
+	return nil
+}
+
+// IndexedTaskIDs implements domain.TaskSearcher.
+func (s *inMemoryTaskSearcher) IndexedTaskIDs(c context.Context) ([]string, error) {
+	workspace_id, err := domain.WorkspaceFrom(c)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := []string{}
+	for _, indexed := range s.tasks {
+		if indexed.task.WorkspaceID == workspace_id {
+			ids = append(ids, indexed.task.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (s *inMemoryTaskSearcher) remove(key string) {
+	indexed, ok := s.tasks[key]
+	if !ok {
+		return
+	}
+	delete(s.tasks, key)
+	for _, word := range uniqueTokens(indexed.task.Title, indexed.task.Description) {
+		delete(s.postings[word], key)
+		if len(s.postings[word]) == 0 {
+			delete(s.postings, word)
+			s.vocabularyDirty = true
+		}
+	}
+}
+
+// SearchTasks implements domain.TaskSearcher.
+func (s *inMemoryTaskSearcher) SearchTasks(c context.Context, query string, limit int) ([]*domain.TaskSearchResult, error) {
+	workspace_id, err := domain.WorkspaceFrom(c)
+	if err != nil {
+		return nil, err
+	}
+	results := []*domain.TaskSearchResult{}
+	q := parseSearchQuery(query)
+	if q.empty() {
+		return results, nil
+	}
+
+	if len(q.prefixes) > 0 {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.refreshVocabulary()
+	} else {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	}
+
+	candidates := map[string]struct{}{}
+	addPostings := func(word string) {
+		for key := range s.postings[word] {
+			candidates[key] = struct{}{}
+		}
+	}
+	for _, term := range q.terms {
+		addPostings(term)
+	}
+	for _, phrase := range q.phrases {
+		for _, word := range phrase {
+			addPostings(word)
+		}
+	}
+	for _, prefix := range q.prefixes {
+		for _, word := range s.expandPrefix(prefix) {
+			addPostings(word)
+		}
+	}
+
+	total := float64(len(s.tasks))
+	idf := func(word string) float64 {
+		return math.Log(1 + total/float64(1+len(s.postings[word])))
+	}
+	for key := range candidates {
+		indexed := s.tasks[key]
+		if indexed.task.WorkspaceID != workspace_id {
+			continue
+		}
+		score, ok := q.score(indexed.title, indexed.description, idf)
+		if !ok {
+			continue
+		}
+		task := indexed.task
+		results = append(results, &domain.TaskSearchResult{
+			Task:       &task,
+			Score:      score,
+			Highlights: highlights(task.Title, task.Description, q),
+		})
+	}
+
+	sortSearchResults(results)
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+func (s *inMemoryTaskSearcher) refreshVocabulary() {
+	if !s.vocabularyDirty {
+		return
+	}
+	s.vocabulary = s.vocabulary[:0]
+	for word := range s.postings {
+		s.vocabulary = append(s.vocabulary, word)
+	}
+	sort.Strings(s.vocabulary)
+	s.vocabularyDirty = false
+}
+
+func (s *inMemoryTaskSearcher) expandPrefix(prefix string) []string {
+	var words []string
+	for i := sort.SearchStrings(s.vocabulary, prefix); i < len(s.vocabulary); i++ {
+		if !strings.HasPrefix(s.vocabulary[i], prefix) {
+			break
+		}
+		words = append(words, s.vocabulary[i])
+	}
+	return words
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"task_manger_clean_architecture/domain"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type InMemoryTaskSearcherTestSuite struct {
+	suite.Suite
+	searcher domain.TaskSearcher
+	ctx      context.Context
+}
+
+func (suite *InMemoryTaskSearcherTestSuite) SetupTest() {
+	suite.searcher = NewInMemoryTaskSearcher()
+	suite.ctx = domain.WithWorkspace(context.Background(), "ws1")
+
+	tasks := []domain.Task{
+		{ID: "1", Title: "Quarterly report", Description: "Write the quarterly sales report for the board"},
+		{ID: "2", Title: "Fix login bug", Description: "Users report that the login page times out"},
+		{ID: "3", Title: "Plan offsite", Description: "Book a venue for the team offsite"},
+	}
+	for _, task := range tasks {
+		suite.Require().NoError(suite.searcher.IndexTask(suite.ctx, task))
+	}
+}
+
+func (suite *InMemoryTaskSearcherTestSuite) ids(results []*domain.TaskSearchResult) []string {
+	ids := []string{}
+	for _, result := range results {
+		ids = append(ids, result.Task.ID)
+	}
+	return ids
+}
+
+func (suite *InMemoryTaskSearcherTestSuite) TestRanksTitleMatchesFirst() {
+	results, err := suite.searcher.SearchTasks(suite.ctx, "report", 10)
+	suite.NoError(err)
+
+	// Both tasks mention "report", but only task 1 has it in the title
+	suite.Equal([]string{"1", "2"}, suite.ids(results))
+	suite.Greater(results[0].Score, results[1].Score)
+	suite.Equal("Quarterly <mark>report</mark>", results[0].Highlights["title"])
+}
+
+func (suite *InMemoryTaskSearcherTestSuite) TestPhraseQuery() {
+	results, err := suite.searcher.SearchTasks(suite.ctx, `"sales report"`, 10)
+	suite.NoError(err)
+
+	suite.Equal([]string{"1"}, suite.ids(results))
+	suite.Equal("Write the quarterly <mark>sales</mark> <mark>report</mark> for the board", results[0].Highlights["description"])
+}
+
+func (suite *InMemoryTaskSearcherTestSuite) TestPrefixQuery() {
+	results, err := suite.searcher.SearchTasks(suite.ctx, "off*", 10)
+	suite.NoError(err)
+
+	suite.Equal([]string{"3"}, suite.ids(results))
+	suite.Equal("Plan <mark>offsite</mark>", results[0].Highlights["title"])
+}
+
+func (suite *InMemoryTaskSearcherTestSuite) TestUpdateAndRemoveKeepIndexInSync() {
+	err := suite.searcher.IndexTask(suite.ctx, domain.Task{ID: "3", Title: "Plan retreat"})
+	suite.NoError(err)
+
+	results, err := suite.searcher.SearchTasks(suite.ctx, "offsite", 10)
+	suite.NoError(err)
+	suite.Empty(results)
+
+	suite.NoError(suite.searcher.RemoveTask(suite.ctx, "1"))
+
+	results, err = suite.searcher.SearchTasks(suite.ctx, "report", 10)
+	suite.NoError(err)
+	suite.Equal([]string{"2"}, suite.ids(results))
+}
+
+func (suite *InMemoryTaskSearcherTestSuite) TestLimit() {
+	results, err := suite.searcher.SearchTasks(suite.ctx, "the", 1)
+	suite.NoError(err)
+	suite.Len(results, 1)
+}
+
+func (suite *InMemoryTaskSearcherTestSuite) TestWorkspacesAreIsolated() {
+	other := domain.WithWorkspace(context.Background(), "ws2")
+	suite.NoError(suite.searcher.IndexTask(other, domain.Task{ID: "1", Title: "Other report"}))
+
+	results, err := suite.searcher.SearchTasks(other, "report", 10)
+	suite.NoError(err)
+	suite.Require().Len(results, 1)
+	suite.Equal("Other report", results[0].Task.Title)
+	suite.Equal("ws2", results[0].Task.WorkspaceID)
+
+	ids, err := suite.searcher.IndexedTaskIDs(other)
+	suite.NoError(err)
+	suite.Equal([]string{"1"}, ids)
+
+	// Removing a task only removes it from the caller's workspace
+	suite.NoError(suite.searcher.RemoveTask(other, "1"))
+	results, err = suite.searcher.SearchTasks(suite.ctx, "report", 10)
+	suite.NoError(err)
+	suite.Equal([]string{"1", "2"}, suite.ids(results))
+}
+
+func (suite *InMemoryTaskSearcherTestSuite) TestRequiresWorkspace() {
+	_, err := suite.searcher.SearchTasks(context.Background(), "report", 10)
+	suite.ErrorIs(err, domain.ErrNoWorkspace)
+	suite.ErrorIs(suite.searcher.IndexTask(context.Background(), domain.Task{ID: "4"}), domain.ErrNoWorkspace)
+}
+
+func TestInMemoryTaskSearcherTestSuite(t *testing.T) {
+	suite.Run(t, new(InMemoryTaskSearcherTestSuite))
+}
@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"task_manger_clean_architecture/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// taskSearchDocument is the shape of a task in the search collection. Terms
+// holds the distinct words of the title and description so prefix queries can
+// use an anchored regex on an indexed field instead of scanning the text.
+type taskSearchDocument struct {
+	domain.Task `bson:",inline"`
+	Terms       []string `bson:"terms"`
+	Score       float64  `bson:"score,omitempty"`
+}
+
+type taskSearchRepository struct {
+	database   *mongo.Database
+	collection string
+
+	mu           sync.Mutex
+	indexesReady bool
+}
+
+// NewTaskSearchRepository returns a TaskSearcher backed by a Mongo $text index
+// on its own collection, which the task usecase keeps in sync with the tasks.
+func NewTaskSearchRepository(db *mongo.Database, collection string) domain.TaskSearcher {
+	return &taskSearchRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (t *taskSearchRepository) ensureIndexes(c context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.indexesReady {
+		return nil
+	}
+
+	_, err := t.database.Collection(t.collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}},
+			Options: options.Index().
+				SetName("task_text").
+				SetDefaultLanguage("none").
+				SetWeights(bson.D{{Key: "title", Value: titleWeight}, {Key: "description", Value: descriptionWeight}}),
+		},
+		// Task IDs are only unique within a workspace
+		{Keys: bson.D{{Key: "workspaceid", Value: 1}, {Key: "id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "workspaceid", Value: 1}, {Key: "terms", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+	t.indexesReady = true
+	return nil
+}
+
+// IndexTask implements domain.TaskSearcher.
+func (t *taskSearchRepository) IndexTask(c context.Context, task domain.Task) error {
+	filter, err := workspaceFilter(c)
+	if err != nil {
+		return err
+	}
+	if err := t.ensureIndexes(c); err != nil {
+		return err
+	}
+
+	task.WorkspaceID, _ = domain.WorkspaceFrom(c)
+	doc := taskSearchDocument{Task: task, Terms: uniqueTokens(task.Title, task.Description)}
+	_, err = t.database.Collection(t.collection).ReplaceOne(c,
+		append(filter, bson.E{Key: "id", Value: task.ID}), doc, options.Replace().SetUpsert(true))
+	return err
+}
+
+// RemoveTask implements domain.TaskSearcher.
+func (t *taskSearchRepository) RemoveTask(c context.Context, id string) error {
+	filter, err := workspaceFilter(c)
+	if err != nil {
+		return err
+	}
+	_, err = t.database.Collection(t.collection).DeleteMany(c, append(filter, bson.E{Key: "id", Value: id}))
+	return err
+}
+
+// IndexedTaskIDs implements domain.TaskSearcher.
+func (t *taskSearchRepository) IndexedTaskIDs(c context.Context) ([]string, error) {
+	filter, err := workspaceFilter(c)
+	if err != nil {
+		return nil, err
+	}
+	var docs []struct {
+		ID string `bson:"id"`
+	}
+	cursor, err := t.database.Collection(t.collection).Find(c, filter, options.Find().SetProjection(bson.D{{Key: "id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	if err := cursor.All(c, &docs); err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		ids = append(ids, doc.ID)
+	}
+	return ids, nil
+}
+
+// SearchTasks implements domain.TaskSearcher.
+func (t *taskSearchRepository) SearchTasks(c context.Context, query string, limit int) ([]*domain.TaskSearchResult, error) {
+	filter, err := workspaceFilter(c)
+	if err != nil {
+		return nil, err
+	}
+	results := []*domain.TaskSearchResult{}
+	q := parseSearchQuery(query)
+	if q.empty() {
+		return results, nil
+	}
+	if err := t.ensureIndexes(c); err != nil {
+		return nil, err
+	}
+
+	clauses := bson.A{filter}
+	// The limit must cut a fixed order, or the same query could return
+	// different tasks each time. Without terms there is no score to rank by
+	// in the database, so the task ID decides, as it breaks ties below.
+	byID := bson.E{Key: "id", Value: 1}
+	opts := options.Find().SetLimit(int64(limit)).SetSort(bson.D{byID})
+	useText := len(q.terms) > 0 || len(q.phrases) > 0
+	if useText {
+		clauses = append(clauses, bson.D{{Key: "$text", Value: bson.D{{Key: "$search", Value: q.textSearch()}}}})
+		textScore := bson.E{Key: "score", Value: bson.D{{Key: "$meta", Value: "textScore"}}}
+		opts.SetProjection(bson.D{textScore}).SetSort(bson.D{textScore, byID})
+	}
+	for _, prefix := range q.prefixes {
+		clauses = append(clauses, bson.D{{Key: "terms", Value: bson.D{{Key: "$regex", Value: "^" + regexp.QuoteMeta(prefix)}}}})
+	}
+
+	cur, err := t.database.Collection(t.collection).Find(c, bson.D{{Key: "$and", Value: clauses}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(c)
+
+	for cur.Next(c) {
+		var doc taskSearchDocument
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		score := doc.Score
+		if !useText {
+			score, _ = q.score(tokenize(doc.Title), tokenize(doc.Description), func(string) float64 { return 1 })
+		}
+		task := doc.Task
+		results = append(results, &domain.TaskSearchResult{
+			Task:       &task,
+			Score:      score,
+			Highlights: highlights(task.Title, task.Description, q),
+		})
+	}
+	if err := cur.Err(); err != nil {
+		return nil, err
+	}
+
+	if !useText {
+		sortSearchResults(results)
+	}
+	return results, nil
+}
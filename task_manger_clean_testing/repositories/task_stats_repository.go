@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"task_manger_clean_architecture/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type taskStatsRepository struct {
+	database   *mongo.Database
+	collection string
+}
+
+func NewTaskStatsRepository(db *mongo.Database, collection string) domain.TaskStatsRepository {
+	return &taskStatsRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+// CountByStatus implements domain.TaskStatsRepository.
+func (t *taskStatsRepository) CountByStatus(c context.Context) (map[string]int64, error) {
+	group := bson.D{{Key: "$group", Value: bson.D{
+		{Key: "_id", Value: "$status"},
+		{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+	}}}
+	cursor, err := t.database.Collection(t.collection).Aggregate(c, mongo.Pipeline{group})
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		Status string `bson:"_id"`
+		Count  int64  `bson:"count"`
+	}
+	if err := cursor.All(c, &rows); err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] += row.Count
+	}
+	return counts, nil
+}
@@ -49,96 +49,44 @@ func (u *userRepository) GetUserByEmail(c context.Context, email string) (domain
 func (u *userRepository) GetUsers(ctx context.Context, startIndex int64, recordsPerPage int64) ([]*domain.User, error) {
     var allUsers []*domain.User
 
+    members, err := memberFilter(ctx)
+    if err != nil {
+        return nil, err
+    }
     collection := u.database.Collection(u.collection)
 
-    matchStage := bson.D{{"$match", bson.D{}}}
-    groupStage := bson.D{{"$group", bson.D{
-        {"_id", "null"},
-        {"total_count", bson.D{{"$sum", 1}}},
-        {"data", bson.D{{"$push", "$$ROOT"}}},
+    matchStage := bson.D{{Key: "$match", Value: members}}
+    groupStage := bson.D{{Key: "$group", Value: bson.D{
+        {Key: "_id", Value: "null"},
+        {Key: "total_count", Value: bson.D{{Key: "$sum", Value: 1}}},
+        {Key: "data", Value: bson.D{{Key: "$push", Value: "$$ROOT"}}},
     }}}
     projectStage := bson.D{
-        {"$project", bson.D{
-            {"_id", 0},
-            {"total_count", 1},
-            {"user_items", bson.D{{"$slice", []interface{}{"$data", startIndex, recordsPerPage}}}},
+        {Key: "$project", Value: bson.D{
+            {Key: "_id", Value: 0},
+            {Key: "total_count", Value: 1},
+            {Key: "user_items", Value: bson.D{{Key: "$slice", Value: []interface{}{"$data", startIndex, recordsPerPage}}}},
         }},
     }
 
     cursor, err := collection.Aggregate(ctx, mongo.Pipeline{matchStage, groupStage, projectStage})
     if err != nil {
-        fmt.Println("Aggregation error: ", err)
-        return nil, fmt.Errorf("aggregation error: %v", err)
+        return nil, fmt.Errorf("aggregation error: %w", err)
     }
     defer cursor.Close(ctx)
 
-    var result []bson.M
+    var result []struct {
+        TotalCount int64          `bson:"total_count"`
+        UserItems  []*domain.User `bson:"user_items"`
+    }
     if err := cursor.All(ctx, &result); err != nil {
-        fmt.Println("Cursor error: ", err)
-        return nil, fmt.Errorf("cursor error: %v", err)
+        return nil, fmt.Errorf("cursor error: %w", err)
     }
 
-    if len(result) == 0 || len(result[0]["user_items"].(primitive.A))==0{
-        fmt.Println("No users found")
+    if len(result) == 0 || len(result[0].UserItems) == 0 {
         return nil, fmt.Errorf("no users found")
     }
-
-    userItems, ok := result[0]["user_items"].(primitive.A)
-    if !ok {
-        fmt.Println("Unexpected result format: 'user_items' is not of type primitive.A")
-        fmt.Printf("Actual type of 'user_items': %T\n", result[0]["user_items"])  // Log actual type
-        return nil, fmt.Errorf("unexpected result format")
-    }
-
-    for _, item := range userItems {
-        userMap, ok := item.(primitive.M)
-        if !ok {
-            fmt.Println("Unexpected user item format")  // Log unexpected format error
-            fmt.Printf("Item type: %T, Item: %+v\n", item, item)  // Inspect the item
-            return nil, fmt.Errorf("unexpected user item format")
-        }
-
-        var user domain.User
-        // Manually map fields from userMap to user
-        if id, ok := userMap["_id"].(primitive.ObjectID); ok {
-            user.ID = id
-        }
-        if firstname, ok := userMap["firstname"].(string); ok {
-            user.FirstName = &firstname
-        }
-        if lastname, ok := userMap["lastname"].(string); ok {
-            user.LastName = &lastname
-        }
-        if password, ok := userMap["password"].(string); ok {
-            user.Password = &password
-        }
-        if email, ok := userMap["email"].(string); ok {
-            user.Email = &email
-        }
-        if phone, ok := userMap["phone"].(string); ok {
-            user.Phone = &phone
-        }
-        if token, ok := userMap["token"].(string); ok {
-            user.Token = &token
-        }
-        if usertype, ok := userMap["usertype"].(string); ok {
-            user.UserType = &usertype
-        }
-        if refreshtoken, ok := userMap["refreshtoken"].(string); ok {
-            user.RefreshToken = &refreshtoken
-        }
-        if createdat, ok := userMap["createdat"].(int64); ok {
-            user.CreatedAt = time.UnixMilli(createdat)
-        }
-        if updatedat, ok := userMap["updatedat"].(int64); ok {
-            user.UpdatedAt = time.UnixMilli(updatedat)
-        }
-        if userid, ok := userMap["userid"].(string); ok {
-            user.UserId = userid
-        }
-
-        allUsers = append(allUsers, &user)
-    }
+    allUsers = result[0].UserItems
 
     return allUsers, nil
 }
@@ -148,6 +96,58 @@ func (u *userRepository) GetUsers(ctx context.Context, startIndex int64, records
 
 
 
+// memberFilter matches the members of the context's workspace.
+func memberFilter(c context.Context) (bson.D, error) {
+	workspace_id, err := domain.WorkspaceFrom(c)
+	if err != nil {
+		return nil, err
+	}
+	return bson.D{{Key: "workspaces.workspaceid", Value: workspace_id}}, nil
+}
+
+// GetMember implements domain.UserRepository.
+func (u *userRepository) GetMember(c context.Context, user_id string) (domain.User, error) {
+	filter, err := memberFilter(c)
+	if err != nil {
+		return domain.User{}, err
+	}
+	var user domain.User
+	err = u.database.Collection(u.collection).FindOne(c, append(filter, bson.E{Key: "userid", Value: user_id})).Decode(&user)
+	if err != nil {
+		return domain.User{}, err
+	}
+	return user, nil
+}
+
+// GetMembers implements domain.UserRepository.
+func (u *userRepository) GetMembers(c context.Context, user_ids []string) ([]domain.User, error) {
+	filter, err := memberFilter(c)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := u.database.Collection(u.collection).Find(c, append(filter, bson.E{Key: "userid", Value: bson.M{"$in": user_ids}}))
+	if err != nil {
+		return nil, err
+	}
+	users := []domain.User{}
+	if err := cursor.All(c, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// AddMembership implements domain.UserRepository. A user who is already a
+// member keeps their role.
+func (u *userRepository) AddMembership(c context.Context, user_id string, membership domain.WorkspaceMembership) error {
+	filter := bson.M{"userid": user_id, "workspaces.workspaceid": bson.M{"$ne": membership.WorkspaceID}}
+	update := bson.M{
+		"$push": bson.M{"workspaces": membership},
+		"$set":  bson.M{"updatedat": time.Now()},
+	}
+	_, err := u.database.Collection(u.collection).UpdateOne(c, filter, update)
+	return err
+}
+
 // Login implements domain.UserRepository.
 func (u *userRepository) Login(ctx context.Context, email string) (*domain.User, error) {
 	collection := u.database.Collection(u.collection)
@@ -164,11 +164,15 @@ func (u *userRepository) Login(ctx context.Context, email string) (*domain.User,
 	return &user, nil
 }
 func (u *userRepository) Promote(ctx context.Context, user_id string, userType string) (error, int64, int64) {
+    workspace_id, err := domain.WorkspaceFrom(ctx)
+    if err != nil {
+        return err, 0, 0
+    }
     collection := u.database.Collection(u.collection)
 
-    // Update the user type to ADMIN
-    filter := bson.M{"userid": user_id}
-    update := bson.M{"$set": bson.M{"usertype": userType, "updatedat": time.Now()}}
+    // Update the user's role in the workspace
+    filter := bson.M{"userid": user_id, "workspaces.workspaceid": workspace_id}
+    update := bson.M{"$set": bson.M{"workspaces.$.role": userType, "updatedat": time.Now()}}
 
     res, err := collection.UpdateOne(ctx, filter, update)
     if err != nil {
@@ -197,8 +201,7 @@ func (u *userRepository) Signup(ctx context.Context, user domain.User) (interfac
 
 
 // UpdateAllToken implements domain.UserRepository.
-func (u *userRepository) UpdateAllTokens(token string, refreshToken string, user_id string) error {
-	ctx, cancel := context.WithTimeout(context.TODO(), time.Second * 100)
+func (u *userRepository) UpdateAllTokens(c context.Context, token string, refreshToken string, user_id string) error {
 	var updateObject primitive.D
 	updatedAt,_ := time.Parse(time.RFC3339, time.Now().Format(time.RFC3339))
 	updateObject = append(updateObject, bson.E{Key: "updatedat",  Value: updatedAt })
@@ -208,9 +211,128 @@ func (u *userRepository) UpdateAllTokens(token string, refreshToken string, user
 	filter:= bson.M{"userid": user_id}
 	opt:= options.UpdateOptions{Upsert: &upsert,}
     collection := u.database.Collection(u.collection)
-    _,err := collection.UpdateOne(ctx, filter,bson.D{{"$set", updateObject}},&opt)
-	defer cancel()
-	
+    _,err := collection.UpdateOne(c, filter,bson.D{{Key: "$set", Value: updateObject}},&opt)
+
     return err
 }
 
+
+// SetEmailVerified implements domain.UserRepository.
+func (u *userRepository) SetEmailVerified(c context.Context, user_id string) error {
+	collection := u.database.Collection(u.collection)
+	update := bson.M{"$set": bson.M{"emailverified": true, "updatedat": time.Now()}}
+	res, err := collection.UpdateOne(c, bson.M{"userid": user_id}, update)
+	if err != nil {
+		return fmt.Errorf("error verifying email: %v", err)
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// MigrateEmailVerification marks the users who signed up before email
+// verification existed as verified, so RequireVerifiedEmail does not lock
+// them out. Later users always store the field, verified or not. It is
+// idempotent and cheap once every user has it.
+func MigrateEmailVerification(c context.Context, db *mongo.Database, users string) error {
+	_, err := db.Collection(users).UpdateMany(c,
+		bson.D{{Key: "emailverified", Value: bson.D{{Key: "$exists", Value: false}}}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "emailverified", Value: true}}}})
+	return err
+}
+
+// UpdatePassword implements domain.UserRepository.
+func (u *userRepository) UpdatePassword(c context.Context, user_id string, password string) error {
+	collection := u.database.Collection(u.collection)
+	update := bson.M{
+		"$set": bson.M{"password": password, "updatedat": time.Now()},
+		"$inc": bson.M{"tokenversion": 1},
+	}
+	res, err := collection.UpdateOne(c, bson.M{"userid": user_id}, update)
+	if err != nil {
+		return fmt.Errorf("error updating password: %v", err)
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// UpdateProfile implements domain.UserRepository.
+func (u *userRepository) UpdateProfile(c context.Context, user_id string, profile domain.ProfileUpdate) error {
+	set := bson.M{"updatedat": time.Now()}
+	if profile.FirstName != nil {
+		set["firstname"] = *profile.FirstName
+	}
+	if profile.LastName != nil {
+		set["lastname"] = *profile.LastName
+	}
+	if profile.Phone != nil {
+		set["phone"] = *profile.Phone
+	}
+	update := bson.M{"$set": set}
+	if profile.Email != nil {
+		// The tokens carry the old address and its verified flag
+		set["email"] = *profile.Email
+		set["emailverified"] = false
+		update["$inc"] = bson.M{"tokenversion": 1}
+	}
+
+	collection := u.database.Collection(u.collection)
+	res, err := collection.UpdateOne(c, bson.M{"userid": user_id}, update)
+	if err != nil {
+		return fmt.Errorf("error updating profile: %v", err)
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// SetPasswordHash implements domain.UserRepository.
+func (u *userRepository) SetPasswordHash(c context.Context, user_id string, password string) error {
+	collection := u.database.Collection(u.collection)
+	_, err := collection.UpdateOne(c, bson.M{"userid": user_id}, bson.M{"$set": bson.M{"password": password}})
+	if err != nil {
+		return fmt.Errorf("error updating password hash: %v", err)
+	}
+	return nil
+}
+
+// SetTwoFactor implements domain.UserRepository.
+func (u *userRepository) SetTwoFactor(c context.Context, user_id string, settings domain.TwoFactorSettings) error {
+	collection := u.database.Collection(u.collection)
+	update := bson.M{"$set": bson.M{"twofactor": settings, "updatedat": time.Now()}}
+	res, err := collection.UpdateOne(c, bson.M{"userid": user_id}, update)
+	if err != nil {
+		return fmt.Errorf("error updating two-factor settings: %v", err)
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// UseTOTPStep implements domain.UserRepository. The check and the update are
+// one operation, so a code cannot be accepted twice by concurrent requests.
+func (u *userRepository) UseTOTPStep(c context.Context, user_id string, step int64) (bool, error) {
+	collection := u.database.Collection(u.collection)
+	filter := bson.M{"userid": user_id, "twofactor.laststep": bson.M{"$lt": step}}
+	res, err := collection.UpdateOne(c, filter, bson.M{"$set": bson.M{"twofactor.laststep": step}})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+// UseRecoveryCode implements domain.UserRepository.
+func (u *userRepository) UseRecoveryCode(c context.Context, user_id string, hash string) (bool, error) {
+	collection := u.database.Collection(u.collection)
+	filter := bson.M{"userid": user_id, "twofactor.recoverycodes": hash}
+	res, err := collection.UpdateOne(c, filter, bson.M{"$pull": bson.M{"twofactor.recoverycodes": hash}})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount == 1, nil
+}
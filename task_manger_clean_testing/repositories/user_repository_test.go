@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -198,6 +199,7 @@ func (suite *UserRepositoryTestSuite) TestGetUsers() {
         Email:     &email1,
         Phone:     &phone1,
         UserType:  &userType1,
+        Workspaces: []domain.WorkspaceMembership{{WorkspaceID: "ws1", Role: userType1}},
         CreatedAt: time.Now(),
         UpdatedAt: time.Now(),
         UserId:    primitive.NewObjectID().Hex(),
@@ -217,6 +219,7 @@ func (suite *UserRepositoryTestSuite) TestGetUsers() {
         Email:     &email2,
         Phone:     &phone2,
         UserType:  &userType2,
+        Workspaces: []domain.WorkspaceMembership{{WorkspaceID: "ws1", Role: userType2}},
         CreatedAt: time.Now(),
         UpdatedAt: time.Now(),
         UserId:    primitive.NewObjectID().Hex(),
@@ -227,8 +230,9 @@ func (suite *UserRepositoryTestSuite) TestGetUsers() {
     _, err = suite.mockRepo.Signup(context.Background(), newUser2)
     suite.NoError(err)
 
-    // Step 2: Retrieve users with pagination
-    users, err := suite.mockRepo.GetUsers(context.Background(), 0, 2)
+    // Step 2: Retrieve the members of the workspace with pagination
+    ctx := domain.WithWorkspace(context.Background(), "ws1")
+    users, err := suite.mockRepo.GetUsers(ctx, 0, 2)
     suite.NoError(err)
     suite.Len(users, 2)
 
@@ -239,6 +243,7 @@ func (suite *UserRepositoryTestSuite) TestGetUsers() {
     suite.Equal(*newUser1.Email, *user1.Email)
     suite.Equal(*newUser1.Phone, *user1.Phone)
     suite.Equal(*newUser1.UserType, *user1.UserType)
+    suite.Equal(newUser1.Workspaces, user1.Workspaces)
 
     user2 := users[1]
     suite.Equal(newUser2.UserId, user2.UserId)
@@ -249,13 +254,68 @@ func (suite *UserRepositoryTestSuite) TestGetUsers() {
     suite.Equal(*newUser2.UserType, *user2.UserType)
 
     // Step 3: Test case where no users are found
-    users, err = suite.mockRepo.GetUsers(context.Background(), 10, 2)
+    users, err = suite.mockRepo.GetUsers(ctx, 10, 2)
+    suite.Error(err)
+    suite.Nil(users)
+
+    // Step 4: Members of other workspaces are not listed
+    users, err = suite.mockRepo.GetUsers(domain.WithWorkspace(context.Background(), "ws2"), 0, 2)
     suite.Error(err)
     suite.Nil(users)
 }
 
 
 
+func (suite *UserRepositoryTestSuite) TestGetUsersReturnsEveryField() {
+    stamp := time.Date(2024, 8, 1, 12, 30, 0, 123e6, time.UTC)
+    text := func(s string) *string { return &s }
+    user := domain.User{
+        ID:            primitive.NewObjectID(),
+        FirstName:     text("Test"),
+        LastName:      text("User"),
+        Password:      text("hash"),
+        Email:         text("testuser@example.com"),
+        Phone:         text("1234567890"),
+        Token:         text("token"),
+        UserType:      text("USER"),
+        RefreshToken:  text("refresh"),
+        CreatedAt:     stamp,
+        UpdatedAt:     stamp.Add(time.Hour),
+        UserId:        primitive.NewObjectID().Hex(),
+        EmailVerified: true,
+        TokenVersion:  3,
+        TwoFactor:     domain.TwoFactorSettings{Enabled: true, Secret: "secret", LastStep: 42, RecoveryCodes: []string{"code"}},
+        Workspaces:    []domain.WorkspaceMembership{{WorkspaceID: "ws1", Role: "ADMIN"}},
+    }
+    _, err := suite.mockRepo.Signup(context.Background(), user)
+    suite.Require().NoError(err)
+
+    users, err := suite.mockRepo.GetUsers(domain.WithWorkspace(context.Background(), "ws1"), 0, 1)
+    suite.Require().NoError(err)
+    suite.Require().Len(users, 1)
+    suite.Equal(user, *users[0])
+}
+
+func (suite *UserRepositoryTestSuite) TestMigrateEmailVerification() {
+    collection := suite.testDatabase.Collection(suite.testCollection)
+    _, err := collection.InsertMany(context.Background(), []interface{}{
+        bson.M{"userid": "before"},
+        bson.M{"userid": "unverified", "emailverified": false},
+    })
+    suite.Require().NoError(err)
+
+    // Running it twice changes nothing more
+    suite.Require().NoError(MigrateEmailVerification(context.Background(), suite.testDatabase, suite.testCollection))
+    suite.Require().NoError(MigrateEmailVerification(context.Background(), suite.testDatabase, suite.testCollection))
+
+    before, err := suite.mockRepo.GetUser(context.Background(), "before")
+    suite.Require().NoError(err)
+    suite.True(before.EmailVerified)
+    unverified, err := suite.mockRepo.GetUser(context.Background(), "unverified")
+    suite.Require().NoError(err)
+    suite.False(unverified.EmailVerified)
+}
+
 func (suite *UserRepositoryTestSuite) TestLogin() {
     // Step 1: Add a new user to the database
     firstName := "Test"
@@ -315,6 +375,10 @@ func (suite *UserRepositoryTestSuite) TestPromote() {
         Email:     &email,
         Phone:     &phone,
         UserType:  &userType,
+        Workspaces: []domain.WorkspaceMembership{
+            {WorkspaceID: "ws1", Role: "USER"},
+            {WorkspaceID: "ws2", Role: "USER"},
+        },
         CreatedAt: time.Now(),
         UpdatedAt: time.Now(),
         UserId:    primitive.NewObjectID().Hex(),
@@ -323,24 +387,33 @@ func (suite *UserRepositoryTestSuite) TestPromote() {
     _, err := suite.mockRepo.Signup(context.Background(), newUser)
     suite.NoError(err)
 
-    // Step 2: Promote the user to ADMIN
-    err, matchedCount, modifiedCount := suite.mockRepo.Promote(context.Background(), newUser.UserId, "ADMIN")
+    // Step 2: Promote the user to ADMIN in one workspace
+    ctx := domain.WithWorkspace(context.Background(), "ws1")
+    err, matchedCount, modifiedCount := suite.mockRepo.Promote(ctx, newUser.UserId, "ADMIN")
     suite.NoError(err)
     suite.Equal(int64(1), matchedCount)
     suite.Equal(int64(1), modifiedCount)
 
-    // Step 3: Verify the user type has been updated
+    // Step 3: Verify only the role in that workspace has been updated
     user, err := suite.mockRepo.Login(context.Background(), email)
     suite.NoError(err)
     suite.NotNil(user)
-    suite.Equal("ADMIN", *user.UserType)
+    suite.Equal("USER", *user.UserType)
+    suite.Equal([]domain.WorkspaceMembership{
+        {WorkspaceID: "ws1", Role: "ADMIN"},
+        {WorkspaceID: "ws2", Role: "USER"},
+    }, user.Workspaces)
 
     // Step 4: Test promoting a non-existent user
-    err, matchedCount, modifiedCount = suite.mockRepo.Promote(context.Background(), "nonexistent_user_id", "ADMIN")
+    err, matchedCount, modifiedCount = suite.mockRepo.Promote(ctx, "nonexistent_user_id", "ADMIN")
     suite.Error(err)
     suite.Equal(int64(0), matchedCount)
     suite.Equal(int64(0), modifiedCount)
     suite.EqualError(err, "user not found")
+
+    // Step 5: Users of other workspaces cannot be promoted
+    err, _, _ = suite.mockRepo.Promote(domain.WithWorkspace(context.Background(), "ws3"), newUser.UserId, "ADMIN")
+    suite.EqualError(err, "user not found")
 }
 
 
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type userTokenRepository struct {
+	database   *mongo.Database
+	collection string
+
+	mu           sync.Mutex
+	indexesReady bool
+}
+
+// NewUserTokenRepository returns a UserTokenRepository kept in Mongo. Tokens
+// are removed by a TTL index once they expire.
+func NewUserTokenRepository(db *mongo.Database, collection string) domain.UserTokenRepository {
+	return &userTokenRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (u *userTokenRepository) ensureIndexes(c context.Context) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.indexesReady {
+		return nil
+	}
+
+	_, err := u.database.Collection(u.collection).Indexes().CreateMany(c, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "userid", Value: 1}, {Key: "purpose", Value: 1}}},
+		{Keys: bson.D{{Key: "expiresat", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	if err != nil {
+		return err
+	}
+	u.indexesReady = true
+	return nil
+}
+
+// Create implements domain.UserTokenRepository.
+func (u *userTokenRepository) Create(c context.Context, token domain.UserToken) error {
+	if err := u.ensureIndexes(c); err != nil {
+		return err
+	}
+	_, err := u.database.Collection(u.collection).InsertOne(c, token)
+	return err
+}
+
+// Consume implements domain.UserTokenRepository. The token is checked and
+// marked used in one update, so two requests cannot both redeem it.
+func (u *userTokenRepository) Consume(c context.Context, hash string, purpose string, now time.Time) (*domain.UserToken, error) {
+	filter := bson.D{
+		{Key: "hash", Value: hash},
+		{Key: "purpose", Value: purpose},
+		{Key: "usedat", Value: nil},
+		{Key: "expiresat", Value: bson.D{{Key: "$gt", Value: now}}},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "usedat", Value: now}}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var token domain.UserToken
+	err := u.database.Collection(u.collection).FindOneAndUpdate(c, filter, update, opts).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, domain.ErrInvalidUserToken
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// DeleteForUser implements domain.UserTokenRepository.
+func (u *userTokenRepository) DeleteForUser(c context.Context, user_id string, purpose string) error {
+	filter := bson.D{{Key: "userid", Value: user_id}, {Key: "purpose", Value: purpose}}
+	_, err := u.database.Collection(u.collection).DeleteMany(c, filter)
+	return err
+}
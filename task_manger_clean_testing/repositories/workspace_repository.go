@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// workspaceFilter returns the filter every tenant-scoped query starts from.
+func workspaceFilter(c context.Context) (bson.D, error) {
+	workspace_id, err := domain.WorkspaceFrom(c)
+	if err != nil {
+		return nil, err
+	}
+	return bson.D{{Key: "workspaceid", Value: workspace_id}}, nil
+}
+
+type workspaceRepository struct {
+	database   *mongo.Database
+	collection string
+
+	mu           sync.Mutex
+	indexesReady bool
+}
+
+func NewWorkspaceRepository(db *mongo.Database, collection string) domain.WorkspaceRepository {
+	return &workspaceRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (w *workspaceRepository) ensureIndexes(c context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.indexesReady {
+		return nil
+	}
+
+	_, err := w.database.Collection(w.collection).Indexes().CreateOne(c, mongo.IndexModel{
+		Keys: bson.D{{Key: "id", Value: 1}}, Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+	w.indexesReady = true
+	return nil
+}
+
+// Create implements domain.WorkspaceRepository.
+func (w *workspaceRepository) Create(c context.Context, workspace domain.Workspace) error {
+	if err := w.ensureIndexes(c); err != nil {
+		return err
+	}
+	_, err := w.database.Collection(w.collection).InsertOne(c, workspace)
+	return err
+}
+
+// GetByIDs implements domain.WorkspaceRepository.
+func (w *workspaceRepository) GetByIDs(c context.Context, ids []string) ([]domain.Workspace, error) {
+	filter := bson.D{{Key: "id", Value: bson.D{{Key: "$in", Value: ids}}}}
+	cursor, err := w.database.Collection(w.collection).Find(c, filter)
+	if err != nil {
+		return nil, err
+	}
+	workspaces := []domain.Workspace{}
+	if err := cursor.All(c, &workspaces); err != nil {
+		return nil, err
+	}
+	return workspaces, nil
+}
+
+// MigrateToWorkspaces moves data from before workspaces into the default
+// workspace: tasks and search documents without a workspace join it, and
+// users without memberships join it with the role they signed up with. It
+// is idempotent and cheap once everything has moved.
+func MigrateToWorkspaces(c context.Context, db *mongo.Database, users string, tasks string, taskSearch string, workspaces string) error {
+	_, err := db.Collection(workspaces).UpdateOne(c,
+		bson.D{{Key: "id", Value: domain.DefaultWorkspaceID}},
+		bson.D{{Key: "$setOnInsert", Value: domain.Workspace{
+			ID:        domain.DefaultWorkspaceID,
+			Name:      "Default",
+			CreatedAt: time.Now(),
+		}}},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		return err
+	}
+
+	// Search documents were unique by task ID, which is only unique per
+	// workspace now. Codes 26 and 27 mean there is nothing to drop.
+	_, err = db.Collection(taskSearch).Indexes().DropOne(c, "id_1")
+	var commandErr mongo.CommandError
+	if err != nil && !(errors.As(err, &commandErr) && (commandErr.Code == 26 || commandErr.Code == 27)) {
+		return err
+	}
+
+	unscoped := bson.D{{Key: "workspaceid", Value: bson.D{{Key: "$exists", Value: false}}}}
+	toDefault := bson.D{{Key: "$set", Value: bson.D{{Key: "workspaceid", Value: domain.DefaultWorkspaceID}}}}
+	for _, collection := range []string{tasks, taskSearch} {
+		if _, err := db.Collection(collection).UpdateMany(c, unscoped, toDefault); err != nil {
+			return err
+		}
+	}
+
+	// The pipeline update copies each user's own usertype into the membership
+	withoutMemberships := bson.D{{Key: "workspaces", Value: bson.D{{Key: "$exists", Value: false}}}}
+	joinDefault := mongo.Pipeline{bson.D{{Key: "$set", Value: bson.D{
+		{Key: "workspaces", Value: bson.A{bson.D{
+			{Key: "workspaceid", Value: domain.DefaultWorkspaceID},
+			{Key: "role", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$usertype", "USER"}}}},
+		}}},
+	}}}}
+	_, err = db.Collection(users).UpdateMany(c, withoutMemberships, joinDefault)
+	return err
+}
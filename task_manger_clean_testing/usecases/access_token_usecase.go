@@ -0,0 +1,166 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"log/slog"
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ScopesByRole lists the scopes each role can grant its access tokens. A
+// token never has more than its owner's current role allows, so demoting a
+// user also narrows their tokens.
+var ScopesByRole = map[string][]string{
+	"USER":  {domain.ScopeTaskRead, domain.ScopeUserRead},
+	"ADMIN": domain.Scopes,
+}
+
+// accessTokenTouchInterval is how stale LastUsedAt may get before a use of
+// the token writes it again, so a busy bot does not write on every request.
+const accessTokenTouchInterval = time.Minute
+
+type AccessTokenUseCase struct {
+	tokenRepository domain.AccessTokenRepository
+	userRepository  domain.UserRepository
+	audit           domain.AuditUsecase
+	logger          *slog.Logger
+	contextTimeout  time.Duration
+	now             func() time.Time
+}
+
+func NewAccessTokenUseCase(tokenRepository domain.AccessTokenRepository, userRepository domain.UserRepository, audit domain.AuditUsecase, logger *slog.Logger, timeout time.Duration) domain.AccessTokenUsecase {
+	return &AccessTokenUseCase{
+		tokenRepository: tokenRepository,
+		userRepository:  userRepository,
+		audit:           audit,
+		logger:          logger,
+		contextTimeout:  timeout,
+		now:             time.Now,
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Create implements domain.AccessTokenUsecase.
+func (a *AccessTokenUseCase) Create(c context.Context, user_id string, role string, request domain.NewAccessToken) (*domain.CreatedAccessToken, error) {
+	ctx, cancel := withTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	var scopes []string
+	for _, scope := range request.Scopes {
+		if !containsString(domain.Scopes, scope) {
+			return nil, domain.ErrUnknownScope
+		}
+		if !containsString(ScopesByRole[role], scope) {
+			return nil, domain.ErrScopeNotAllowed
+		}
+		if !containsString(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	workspace_id, err := domain.WorkspaceFrom(c)
+	if err != nil {
+		return nil, err
+	}
+	now := a.now().UTC()
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(now) {
+		return nil, domain.ErrExpiryInPast
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	secret := domain.AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	token := domain.AccessToken{
+		ID:          primitive.NewObjectID().Hex(),
+		UserID:      user_id,
+		Name:        strings.TrimSpace(request.Name),
+		Hash:        hashUserToken(secret),
+		Scopes:      scopes,
+		WorkspaceID: workspace_id,
+		CreatedAt:   now,
+		ExpiresAt:   request.ExpiresAt,
+	}
+	if err := a.tokenRepository.Create(ctx, token); err != nil {
+		return nil, err
+	}
+	recordWrite(ctx, a.audit, a.logger, domain.AuditAccessTokenCreate, token.ID, nil, token)
+	return &domain.CreatedAccessToken{AccessToken: token, Token: secret}, nil
+}
+
+// List implements domain.AccessTokenUsecase.
+func (a *AccessTokenUseCase) List(c context.Context, user_id string) ([]domain.AccessToken, error) {
+	ctx, cancel := withTimeout(c, a.contextTimeout)
+	defer cancel()
+	return a.tokenRepository.ListForUser(ctx, user_id)
+}
+
+// Revoke implements domain.AccessTokenUsecase.
+func (a *AccessTokenUseCase) Revoke(c context.Context, user_id string, id string) error {
+	ctx, cancel := withTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if err := a.tokenRepository.Delete(ctx, user_id, id); err != nil {
+		return err
+	}
+	recordWrite(ctx, a.audit, a.logger, domain.AuditAccessTokenRevoke, id, nil, nil)
+	return nil
+}
+
+// Authenticate implements domain.AccessTokenUsecase.
+func (a *AccessTokenUseCase) Authenticate(c context.Context, secret string) (domain.User, []string, error) {
+	ctx, cancel := withTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	token, err := a.tokenRepository.GetByHash(ctx, hashUserToken(secret))
+	if err != nil {
+		return domain.User{}, nil, err
+	}
+	now := a.now()
+	// The TTL index only sweeps every minute or so
+	if token.ExpiresAt != nil && !token.ExpiresAt.After(now) {
+		return domain.User{}, nil, domain.ErrInvalidAccessToken
+	}
+	user, err := a.userRepository.GetUser(ctx, token.UserID)
+	if err != nil {
+		return domain.User{}, nil, domain.ErrInvalidAccessToken
+	}
+
+	// Tokens from before workspaces act in the default one
+	workspace_id := token.WorkspaceID
+	if workspace_id == "" {
+		workspace_id = domain.DefaultWorkspaceID
+	}
+	role, ok := user.Role(workspace_id)
+	if !ok {
+		return domain.User{}, nil, domain.ErrInvalidAccessToken
+	}
+	user.Workspaces = []domain.WorkspaceMembership{{WorkspaceID: workspace_id, Role: role}}
+	scopes := []string{}
+	for _, scope := range token.Scopes {
+		if containsString(ScopesByRole[role], scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= accessTokenTouchInterval {
+		if err := a.tokenRepository.SetLastUsed(ctx, token.ID, now); err != nil {
+			return domain.User{}, nil, err
+		}
+	}
+	return user, scopes, nil
+}
@@ -0,0 +1,146 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AccessTokenUseCaseTestSuite struct {
+	suite.Suite
+	mockTokenRepo *mocks.AccessTokenRepository
+	mockUserRepo  *mocks.UserRepository
+	mockAudit     *mocks.AuditUsecase
+	now           time.Time
+	ctx           context.Context
+	tokens        *AccessTokenUseCase
+}
+
+func (suite *AccessTokenUseCaseTestSuite) SetupTest() {
+	suite.mockTokenRepo = new(mocks.AccessTokenRepository)
+	suite.mockUserRepo = new(mocks.UserRepository)
+	suite.mockAudit = new(mocks.AuditUsecase)
+	suite.now = time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+	suite.ctx = domain.WithWorkspace(context.Background(), "ws1")
+	suite.tokens = NewAccessTokenUseCase(suite.mockTokenRepo, suite.mockUserRepo, suite.mockAudit, discardLogger, time.Second*2).(*AccessTokenUseCase)
+	suite.tokens.now = func() time.Time { return suite.now }
+}
+
+func (suite *AccessTokenUseCaseTestSuite) TestCreateStoresOnlyTheHash() {
+	var stored domain.AccessToken
+	suite.mockTokenRepo.On("Create", mock.Anything, mock.AnythingOfType("domain.AccessToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(domain.AccessToken) }).Return(nil).Once()
+	suite.mockAudit.On("Record", mock.Anything, domain.AuditAccessTokenCreate, mock.Anything, nil, mock.Anything).Return(nil).Once()
+
+	request := domain.NewAccessToken{Name: " ci ", Scopes: []string{domain.ScopeTaskRead, domain.ScopeTaskRead}}
+	created, err := suite.tokens.Create(suite.ctx, "1", "USER", request)
+	suite.Require().NoError(err)
+
+	assert.True(suite.T(), strings.HasPrefix(created.Token, domain.AccessTokenPrefix))
+	assert.Equal(suite.T(), hashUserToken(created.Token), stored.Hash)
+	assert.Equal(suite.T(), "1", stored.UserID)
+	assert.Equal(suite.T(), "ci", stored.Name)
+	assert.Equal(suite.T(), []string{domain.ScopeTaskRead}, stored.Scopes)
+	assert.Equal(suite.T(), "ws1", stored.WorkspaceID)
+	assert.Equal(suite.T(), stored.ID, created.ID)
+	suite.mockAudit.AssertExpectations(suite.T())
+}
+
+func (suite *AccessTokenUseCaseTestSuite) TestCreateRejectsScopesOutsideTheRole() {
+	request := domain.NewAccessToken{Name: "ci", Scopes: []string{domain.ScopeUserAdmin}}
+	_, err := suite.tokens.Create(suite.ctx, "1", "USER", request)
+	assert.ErrorIs(suite.T(), err, domain.ErrScopeNotAllowed)
+
+	request.Scopes = []string{"task:everything"}
+	_, err = suite.tokens.Create(suite.ctx, "1", "ADMIN", request)
+	assert.ErrorIs(suite.T(), err, domain.ErrUnknownScope)
+
+	past := suite.now.Add(-time.Hour)
+	request = domain.NewAccessToken{Name: "ci", Scopes: []string{domain.ScopeTaskRead}, ExpiresAt: &past}
+	_, err = suite.tokens.Create(suite.ctx, "1", "ADMIN", request)
+	assert.ErrorIs(suite.T(), err, domain.ErrExpiryInPast)
+
+	suite.mockTokenRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *AccessTokenUseCaseTestSuite) TestAuthenticateIntersectsScopesWithRole() {
+	token := &domain.AccessToken{
+		ID:          "t1",
+		UserID:      "1",
+		Scopes:      []string{domain.ScopeTaskRead, domain.ScopeTaskWrite, domain.ScopeUserAdmin},
+		WorkspaceID: "ws1",
+	}
+	suite.mockTokenRepo.On("GetByHash", mock.Anything, hashUserToken("tm_pat_x")).Return(token, nil)
+	// The owner was demoted in the token's workspace after creating it
+	owner := domain.User{UserId: "1", UserType: stringPtr("ADMIN"), Workspaces: []domain.WorkspaceMembership{
+		{WorkspaceID: domain.DefaultWorkspaceID, Role: "ADMIN"},
+		{WorkspaceID: "ws1", Role: "USER"},
+	}}
+	suite.mockUserRepo.On("GetUser", mock.Anything, "1").Return(owner, nil)
+	suite.mockTokenRepo.On("SetLastUsed", mock.Anything, "t1", suite.now).Return(nil).Once()
+
+	user, scopes, err := suite.tokens.Authenticate(context.Background(), "tm_pat_x")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "1", user.UserId)
+	assert.Equal(suite.T(), []string{domain.ScopeTaskRead}, scopes)
+	assert.Equal(suite.T(), []domain.WorkspaceMembership{{WorkspaceID: "ws1", Role: "USER"}}, user.Workspaces)
+	suite.mockTokenRepo.AssertExpectations(suite.T())
+}
+
+func (suite *AccessTokenUseCaseTestSuite) TestAuthenticateRejectsTokenOfLeftWorkspace() {
+	token := &domain.AccessToken{ID: "t1", UserID: "1", Scopes: []string{domain.ScopeTaskRead}, WorkspaceID: "ws1"}
+	suite.mockTokenRepo.On("GetByHash", mock.Anything, hashUserToken("tm_pat_x")).Return(token, nil)
+	suite.mockUserRepo.On("GetUser", mock.Anything, "1").Return(domain.User{UserId: "1", Workspaces: []domain.WorkspaceMembership{
+		{WorkspaceID: domain.DefaultWorkspaceID, Role: "ADMIN"},
+	}}, nil)
+
+	_, _, err := suite.tokens.Authenticate(context.Background(), "tm_pat_x")
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidAccessToken)
+	suite.mockTokenRepo.AssertNotCalled(suite.T(), "SetLastUsed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AccessTokenUseCaseTestSuite) TestAuthenticateSkipsRecentLastUsedWrite() {
+	recent := suite.now.Add(-10 * time.Second)
+	// Tokens from before workspaces have none and act in the default one
+	token := &domain.AccessToken{ID: "t1", UserID: "1", Scopes: []string{domain.ScopeTaskRead}, LastUsedAt: &recent}
+	suite.mockTokenRepo.On("GetByHash", mock.Anything, hashUserToken("tm_pat_x")).Return(token, nil)
+	suite.mockUserRepo.On("GetUser", mock.Anything, "1").Return(domain.User{UserId: "1", Workspaces: []domain.WorkspaceMembership{
+		{WorkspaceID: domain.DefaultWorkspaceID, Role: "USER"},
+	}}, nil)
+
+	user, _, err := suite.tokens.Authenticate(context.Background(), "tm_pat_x")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), domain.DefaultWorkspaceID, user.Workspaces[0].WorkspaceID)
+	suite.mockTokenRepo.AssertNotCalled(suite.T(), "SetLastUsed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AccessTokenUseCaseTestSuite) TestAuthenticateRejectsExpiredToken() {
+	expired := suite.now.Add(-time.Second)
+	token := &domain.AccessToken{ID: "t1", UserID: "1", Scopes: []string{domain.ScopeTaskRead}, ExpiresAt: &expired}
+	suite.mockTokenRepo.On("GetByHash", mock.Anything, hashUserToken("tm_pat_x")).Return(token, nil)
+
+	_, _, err := suite.tokens.Authenticate(context.Background(), "tm_pat_x")
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidAccessToken)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "GetUser", mock.Anything, mock.Anything)
+}
+
+func (suite *AccessTokenUseCaseTestSuite) TestRevoke() {
+	suite.mockTokenRepo.On("Delete", mock.Anything, "1", "t1").Return(nil).Once()
+	suite.mockAudit.On("Record", mock.Anything, domain.AuditAccessTokenRevoke, "t1", nil, nil).Return(nil).Once()
+	assert.NoError(suite.T(), suite.tokens.Revoke(context.Background(), "1", "t1"))
+
+	suite.mockTokenRepo.On("Delete", mock.Anything, "2", "t1").Return(domain.ErrAccessTokenNotFound).Once()
+	assert.ErrorIs(suite.T(), suite.tokens.Revoke(context.Background(), "2", "t1"), domain.ErrAccessTokenNotFound)
+	suite.mockAudit.AssertExpectations(suite.T())
+}
+
+func TestAccessTokenUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AccessTokenUseCaseTestSuite))
+}
@@ -0,0 +1,176 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"time"
+)
+
+const (
+	verificationTokenTTL  = 24 * time.Hour
+	passwordResetTokenTTL = time.Hour
+)
+
+type AccountUseCase struct {
+	userRepository  domain.UserRepository
+	tokenRepository domain.UserTokenRepository
+	mailer          domain.Mailer
+	audit           domain.AuditUsecase
+	logger          *slog.Logger
+	baseURL         string
+	contextTimeout  time.Duration
+	now             func() time.Time
+}
+
+// NewAccountUseCase returns the email verification and password reset flows.
+// Links in the mails point at baseURL.
+func NewAccountUseCase(userRepository domain.UserRepository, tokenRepository domain.UserTokenRepository, mailer domain.Mailer, audit domain.AuditUsecase, logger *slog.Logger, baseURL string, timeout time.Duration) domain.AccountUsecase {
+	return &AccountUseCase{
+		userRepository:  userRepository,
+		tokenRepository: tokenRepository,
+		mailer:          mailer,
+		audit:           audit,
+		logger:          logger,
+		baseURL:         strings.TrimRight(baseURL, "/"),
+		contextTimeout:  timeout,
+		now:             time.Now,
+	}
+}
+
+// hashUserToken is the form a token is stored and looked up in. The tokens
+// are random, so a plain SHA-256 is enough; there is nothing to brute force.
+func hashUserToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// issueUserToken stores a new token for user_id and returns it in clear text.
+func issueUserToken(c context.Context, tokens domain.UserTokenRepository, user_id string, purpose string, now time.Time, ttl time.Duration) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	err := tokens.Create(c, domain.UserToken{
+		Hash:      hashUserToken(token),
+		UserID:    user_id,
+		Purpose:   purpose,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (a *AccountUseCase) link(path string, token string) string {
+	return a.baseURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// SendVerification implements domain.AccountUsecase.
+func (a *AccountUseCase) SendVerification(c context.Context, user domain.User) error {
+	ctx, cancel := withTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	if user.Email == nil {
+		return fmt.Errorf("user has no email")
+	}
+	token, err := issueUserToken(ctx, a.tokenRepository, user.UserId, domain.UserTokenVerifyEmail, a.now(), verificationTokenTTL)
+	if err != nil {
+		return err
+	}
+	return a.mailer.Send(ctx, domain.Mail{
+		To:      *user.Email,
+		Subject: "Verify your email address",
+		Body: "Open this link to verify your email address:\n\n" + a.link("/verify", token) +
+			"\n\nThe link expires in 24 hours.",
+	})
+}
+
+// ResendVerification implements domain.AccountUsecase. Earlier links stop
+// working once a new one is sent.
+func (a *AccountUseCase) ResendVerification(c context.Context, email string) error {
+	ctx, cancel := withTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	user, err := a.userRepository.GetUserByEmail(ctx, email)
+	if err != nil || user.EmailVerified {
+		// Say nothing about whether the account exists or is verified
+		return nil
+	}
+	if err := a.tokenRepository.DeleteForUser(ctx, user.UserId, domain.UserTokenVerifyEmail); err != nil {
+		return err
+	}
+	return a.SendVerification(ctx, user)
+}
+
+// VerifyEmail implements domain.AccountUsecase.
+func (a *AccountUseCase) VerifyEmail(c context.Context, token string) error {
+	ctx, cancel := withTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	userToken, err := a.tokenRepository.Consume(ctx, hashUserToken(token), domain.UserTokenVerifyEmail, a.now())
+	if err != nil {
+		return err
+	}
+	if err := a.userRepository.SetEmailVerified(ctx, userToken.UserID); err != nil {
+		return err
+	}
+	recordWrite(ctx, a.audit, a.logger, domain.AuditUserVerifyEmail, userToken.UserID, nil, nil)
+	return nil
+}
+
+// ForgotPassword implements domain.AccountUsecase. Only the newest reset link
+// works.
+func (a *AccountUseCase) ForgotPassword(c context.Context, email string) error {
+	ctx, cancel := withTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	user, err := a.userRepository.GetUserByEmail(ctx, email)
+	if err != nil || user.Email == nil {
+		return nil
+	}
+	if err := a.tokenRepository.DeleteForUser(ctx, user.UserId, domain.UserTokenPasswordReset); err != nil {
+		return err
+	}
+	token, err := issueUserToken(ctx, a.tokenRepository, user.UserId, domain.UserTokenPasswordReset, a.now(), passwordResetTokenTTL)
+	if err != nil {
+		return err
+	}
+	return a.mailer.Send(ctx, domain.Mail{
+		To:      *user.Email,
+		Subject: "Reset your password",
+		Body: "Open this link to choose a new password:\n\n" + a.link("/password/reset", token) +
+			"\n\nThe link expires in one hour. If you did not ask for it, ignore this mail.",
+	})
+}
+
+// ResetPassword implements domain.AccountUsecase. Following a mailed link
+// also proves the user owns the address, so the email is marked verified.
+func (a *AccountUseCase) ResetPassword(c context.Context, token string, hashedPassword string) error {
+	ctx, cancel := withTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	userToken, err := a.tokenRepository.Consume(ctx, hashUserToken(token), domain.UserTokenPasswordReset, a.now())
+	if err != nil {
+		return err
+	}
+	if err := a.userRepository.UpdatePassword(ctx, userToken.UserID, hashedPassword); err != nil {
+		return err
+	}
+	if err := a.userRepository.SetEmailVerified(ctx, userToken.UserID); err != nil {
+		return err
+	}
+	recordWrite(ctx, a.audit, a.logger, domain.AuditUserPasswordReset, userToken.UserID, nil, nil)
+	return nil
+}
@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type AccountUseCaseTestSuite struct {
+	suite.Suite
+	mockUserRepo  *mocks.UserRepository
+	mockTokenRepo *mocks.UserTokenRepository
+	mockMailer    *mocks.Mailer
+	mockAudit     *mocks.AuditUsecase
+	now           time.Time
+	accounts      *AccountUseCase
+}
+
+func (suite *AccountUseCaseTestSuite) SetupTest() {
+	suite.mockUserRepo = new(mocks.UserRepository)
+	suite.mockTokenRepo = new(mocks.UserTokenRepository)
+	suite.mockMailer = new(mocks.Mailer)
+	suite.mockAudit = new(mocks.AuditUsecase)
+	suite.now = time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+	suite.accounts = NewAccountUseCase(suite.mockUserRepo, suite.mockTokenRepo, suite.mockMailer, suite.mockAudit, discardLogger, "https://tasks.example.com/", time.Second*2).(*AccountUseCase)
+	suite.accounts.now = func() time.Time { return suite.now }
+}
+
+// tokenFromMail extracts the token from the link in a sent mail.
+func (suite *AccountUseCaseTestSuite) tokenFromMail(mail domain.Mail, path string) string {
+	prefix := "https://tasks.example.com" + path + "?token="
+	start := strings.Index(mail.Body, prefix)
+	suite.Require().NotEqual(-1, start)
+	rest := mail.Body[start+len(prefix):]
+	token, err := url.QueryUnescape(rest[:strings.IndexByte(rest, '\n')])
+	suite.Require().NoError(err)
+	return token
+}
+
+func (suite *AccountUseCaseTestSuite) TestSendVerificationStoresOnlyTheHash() {
+	email := "a@example.com"
+	var stored domain.UserToken
+	var sent domain.Mail
+	suite.mockTokenRepo.On("Create", mock.Anything, mock.AnythingOfType("domain.UserToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(domain.UserToken) }).Return(nil)
+	suite.mockMailer.On("Send", mock.Anything, mock.AnythingOfType("domain.Mail")).
+		Run(func(args mock.Arguments) { sent = args.Get(1).(domain.Mail) }).Return(nil)
+
+	err := suite.accounts.SendVerification(context.Background(), domain.User{UserId: "1", Email: &email})
+	assert.NoError(suite.T(), err)
+
+	token := suite.tokenFromMail(sent, "/verify")
+	assert.Equal(suite.T(), email, sent.To)
+	assert.Equal(suite.T(), hashUserToken(token), stored.Hash)
+	assert.NotContains(suite.T(), stored.Hash, token)
+	assert.Equal(suite.T(), "1", stored.UserID)
+	assert.Equal(suite.T(), domain.UserTokenVerifyEmail, stored.Purpose)
+	assert.Equal(suite.T(), suite.now.Add(verificationTokenTTL), stored.ExpiresAt)
+}
+
+func (suite *AccountUseCaseTestSuite) TestVerifyEmail() {
+	suite.mockTokenRepo.On("Consume", mock.Anything, hashUserToken("abc"), domain.UserTokenVerifyEmail, suite.now).
+		Return(&domain.UserToken{UserID: "1"}, nil).Once()
+	suite.mockUserRepo.On("SetEmailVerified", mock.Anything, "1").Return(nil).Once()
+	suite.mockAudit.On("Record", mock.Anything, domain.AuditUserVerifyEmail, "1", nil, nil).Return(nil).Once()
+
+	assert.NoError(suite.T(), suite.accounts.VerifyEmail(context.Background(), "abc"))
+	suite.mockUserRepo.AssertExpectations(suite.T())
+	suite.mockAudit.AssertExpectations(suite.T())
+}
+
+func (suite *AccountUseCaseTestSuite) TestVerifyEmailRejectsInvalidToken() {
+	suite.mockTokenRepo.On("Consume", mock.Anything, hashUserToken("used"), domain.UserTokenVerifyEmail, suite.now).
+		Return(nil, domain.ErrInvalidUserToken).Once()
+
+	err := suite.accounts.VerifyEmail(context.Background(), "used")
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidUserToken)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "SetEmailVerified", mock.Anything, mock.Anything)
+}
+
+func (suite *AccountUseCaseTestSuite) TestForgotPasswordIgnoresUnknownEmail() {
+	suite.mockUserRepo.On("GetUserByEmail", mock.Anything, "nobody@example.com").Return(domain.User{}, mongo.ErrNoDocuments).Once()
+
+	assert.NoError(suite.T(), suite.accounts.ForgotPassword(context.Background(), "nobody@example.com"))
+	suite.mockMailer.AssertNotCalled(suite.T(), "Send", mock.Anything, mock.Anything)
+}
+
+func (suite *AccountUseCaseTestSuite) TestForgotAndResetPassword() {
+	email := "a@example.com"
+	var stored domain.UserToken
+	var sent domain.Mail
+	suite.mockUserRepo.On("GetUserByEmail", mock.Anything, email).Return(domain.User{UserId: "1", Email: &email}, nil).Once()
+	suite.mockTokenRepo.On("DeleteForUser", mock.Anything, "1", domain.UserTokenPasswordReset).Return(nil).Once()
+	suite.mockTokenRepo.On("Create", mock.Anything, mock.AnythingOfType("domain.UserToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(domain.UserToken) }).Return(nil).Once()
+	suite.mockMailer.On("Send", mock.Anything, mock.AnythingOfType("domain.Mail")).
+		Run(func(args mock.Arguments) { sent = args.Get(1).(domain.Mail) }).Return(nil).Once()
+
+	assert.NoError(suite.T(), suite.accounts.ForgotPassword(context.Background(), email))
+	assert.Equal(suite.T(), suite.now.Add(passwordResetTokenTTL), stored.ExpiresAt)
+
+	token := suite.tokenFromMail(sent, "/password/reset")
+	suite.mockTokenRepo.On("Consume", mock.Anything, stored.Hash, domain.UserTokenPasswordReset, suite.now).
+		Return(&stored, nil).Once()
+	suite.mockUserRepo.On("UpdatePassword", mock.Anything, "1", "new-hash").Return(nil).Once()
+	suite.mockUserRepo.On("SetEmailVerified", mock.Anything, "1").Return(nil).Once()
+	suite.mockAudit.On("Record", mock.Anything, domain.AuditUserPasswordReset, "1", nil, nil).Return(nil).Once()
+
+	assert.NoError(suite.T(), suite.accounts.ResetPassword(context.Background(), token, "new-hash"))
+	suite.mockUserRepo.AssertExpectations(suite.T())
+	suite.mockTokenRepo.AssertExpectations(suite.T())
+	suite.mockAudit.AssertExpectations(suite.T())
+}
+
+func TestAccountUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AccountUseCaseTestSuite))
+}
@@ -0,0 +1,136 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"task_manger_clean_architecture/domain"
+	"time"
+)
+
+// auditAppendAttempts bounds how often Record retries when other replicas
+// keep taking the next position in the chain.
+const auditAppendAttempts = 5
+
+type AuditUseCase struct {
+	auditRepository domain.AuditRepository
+	contextTimeout  time.Duration
+
+	// mu serialises Record so entries are chained one after the other; last
+	// caches the head of the chain and is dropped whenever an append fails,
+	// as it does when another process appended in the meantime.
+	mu   sync.Mutex
+	last *domain.AuditEntry
+}
+
+func NewAuditUseCase(auditRepository domain.AuditRepository, timeout time.Duration) domain.AuditUsecase {
+	return &AuditUseCase{
+		auditRepository: auditRepository,
+		contextTimeout:  timeout,
+	}
+}
+
+func auditSnapshot(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Record implements domain.AuditUsecase. The actor is taken from the
+// domain.AuditActor in c. When another writer took the next position first,
+// the head of the chain is read again and the entry appended after it.
+func (a *AuditUseCase) Record(c context.Context, action string, target string, before interface{}, after interface{}) error {
+	beforeSnapshot, err := auditSnapshot(before)
+	if err != nil {
+		return err
+	}
+	afterSnapshot, err := auditSnapshot(after)
+	if err != nil {
+		return err
+	}
+	actor := domain.AuditActorFrom(c)
+
+	ctx, cancel := withTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entry := domain.AuditEntry{
+		ActorID:   actor.UID,
+		Action:    action,
+		Target:    target,
+		Before:    beforeSnapshot,
+		After:     afterSnapshot,
+		ClientIP:  actor.ClientIP,
+		UserAgent: actor.UserAgent,
+		// Mongo keeps millisecond precision, so hash what will be read back.
+		Timestamp: time.Now().UTC().Truncate(time.Millisecond),
+	}
+	for attempt := 1; ; attempt++ {
+		if a.last == nil {
+			if a.last, err = a.auditRepository.Last(ctx); err != nil {
+				return err
+			}
+		}
+		entry.Seq, entry.PrevHash = 1, ""
+		if a.last != nil {
+			entry.Seq = a.last.Seq + 1
+			entry.PrevHash = a.last.Hash
+		}
+		entry.Hash = entry.ComputeHash()
+
+		err = a.auditRepository.Append(ctx, entry)
+		if err == nil {
+			a.last = &entry
+			return nil
+		}
+		a.last = nil
+		if !errors.Is(err, domain.ErrAuditSeqTaken) || attempt == auditAppendAttempts {
+			return err
+		}
+	}
+}
+
+// Query implements domain.AuditUsecase.
+func (a *AuditUseCase) Query(c context.Context, filter domain.AuditFilter) ([]*domain.AuditEntry, int64, error) {
+	ctx, cancel := withTimeout(c, a.contextTimeout)
+	defer cancel()
+	return a.auditRepository.Find(ctx, filter)
+}
+
+// Verify implements domain.AuditUsecase. It recomputes every hash and checks
+// each entry points at its predecessor.
+func (a *AuditUseCase) Verify(c context.Context) (*domain.AuditVerification, error) {
+	ctx, cancel := withTimeout(c, a.contextTimeout)
+	defer cancel()
+
+	result := &domain.AuditVerification{Valid: true}
+	var prev *domain.AuditEntry
+	err := a.auditRepository.Scan(ctx, func(entry *domain.AuditEntry) error {
+		result.Entries++
+		if !result.Valid {
+			return nil
+		}
+		expectedSeq, expectedPrevHash := int64(1), ""
+		if prev != nil {
+			expectedSeq, expectedPrevHash = prev.Seq+1, prev.Hash
+		}
+		if entry.Seq != expectedSeq || entry.PrevHash != expectedPrevHash || entry.Hash != entry.ComputeHash() {
+			result.Valid = false
+			result.BrokenAt = entry.Seq
+		}
+		prev = entry
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
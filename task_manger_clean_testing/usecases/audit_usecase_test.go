@@ -0,0 +1,255 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditUseCaseTestSuite struct {
+	suite.Suite
+	mockRepo     *mocks.AuditRepository
+	auditUseCase domain.AuditUsecase
+}
+
+func (suite *AuditUseCaseTestSuite) SetupTest() {
+	suite.mockRepo = new(mocks.AuditRepository)
+	suite.auditUseCase = NewAuditUseCase(suite.mockRepo, time.Second*2)
+}
+
+func (suite *AuditUseCaseTestSuite) TestRecordChainsEntries() {
+	ctx := domain.WithAuditActor(context.Background(), domain.AuditActor{UID: "admin", ClientIP: "10.0.0.1", UserAgent: "curl"})
+	var appended []domain.AuditEntry
+
+	suite.mockRepo.On("Last", mock.Anything).Return(nil, nil).Once()
+	suite.mockRepo.On("Append", mock.Anything, mock.AnythingOfType("domain.AuditEntry")).
+		Run(func(args mock.Arguments) { appended = append(appended, args.Get(1).(domain.AuditEntry)) }).
+		Return(nil)
+
+	err := suite.auditUseCase.Record(ctx, domain.AuditTaskCreate, "1", nil, domain.Task{ID: "1", Title: "Task"})
+	assert.NoError(suite.T(), err)
+	err = suite.auditUseCase.Record(ctx, domain.AuditTaskDelete, "1", domain.Task{ID: "1", Title: "Task"}, nil)
+	assert.NoError(suite.T(), err)
+
+	assert.Len(suite.T(), appended, 2)
+	first, second := appended[0], appended[1]
+	assert.Equal(suite.T(), int64(1), first.Seq)
+	assert.Equal(suite.T(), "", first.PrevHash)
+	assert.Equal(suite.T(), "admin", first.ActorID)
+	assert.Equal(suite.T(), "10.0.0.1", first.ClientIP)
+	assert.Equal(suite.T(), "curl", first.UserAgent)
+	assert.JSONEq(suite.T(), `{"id":"1","title":"Task","description":"","due_date":"0001-01-01T00:00:00Z","status":"","created_by":"","created_at":"0001-01-01T00:00:00Z","workspace_id":""}`, string(first.After))
+	assert.Equal(suite.T(), first.ComputeHash(), first.Hash)
+
+	// The head of the chain is cached, so Last is only read once
+	assert.Equal(suite.T(), int64(2), second.Seq)
+	assert.Equal(suite.T(), first.Hash, second.PrevHash)
+	assert.Nil(suite.T(), second.After)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+// sharedAuditRepository is the one store several replicas append to. Like
+// the unique index in Mongo, it refuses a second entry at the same seq.
+type sharedAuditRepository struct {
+	domain.AuditRepository
+	mu      sync.Mutex
+	entries []*domain.AuditEntry
+}
+
+func (r *sharedAuditRepository) Append(c context.Context, entry domain.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.entries {
+		if existing.Seq == entry.Seq {
+			return domain.ErrAuditSeqTaken
+		}
+	}
+	r.entries = append(r.entries, &entry)
+	return nil
+}
+
+func (r *sharedAuditRepository) Last(c context.Context) (*domain.AuditEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) == 0 {
+		return nil, nil
+	}
+	last := *r.entries[len(r.entries)-1]
+	return &last, nil
+}
+
+func (r *sharedAuditRepository) Scan(c context.Context, fn func(entry *domain.AuditEntry) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, entry := range r.entries {
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRecordAcrossReplicas(t *testing.T) {
+	store := &sharedAuditRepository{}
+	first := NewAuditUseCase(store, time.Second*2)
+	second := NewAuditUseCase(store, time.Second*2)
+	ctx := context.Background()
+
+	assert.NoError(t, first.Record(ctx, domain.AuditTaskCreate, "1", nil, nil))
+	assert.NoError(t, second.Record(ctx, domain.AuditTaskUpdate, "1", nil, nil))
+	// first still takes entry 1 for the head, so its append collides with
+	// the one of second and has to move behind it
+	assert.NoError(t, first.Record(ctx, domain.AuditTaskDelete, "1", nil, nil))
+
+	assert.Len(t, store.entries, 3)
+	assert.Equal(t, domain.AuditTaskDelete, store.entries[2].Action)
+	result, err := first.Verify(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &domain.AuditVerification{Valid: true, Entries: 3}, result)
+}
+
+func (suite *AuditUseCaseTestSuite) TestRecordGivesUpWhenSeqKeepsBeingTaken() {
+	suite.mockRepo.On("Last", mock.Anything).Return(&domain.AuditEntry{Seq: 7, Hash: "h"}, nil).Times(auditAppendAttempts)
+	suite.mockRepo.On("Append", mock.Anything, mock.AnythingOfType("domain.AuditEntry")).Return(domain.ErrAuditSeqTaken).Times(auditAppendAttempts)
+
+	err := suite.auditUseCase.Record(context.Background(), domain.AuditTaskCreate, "1", nil, nil)
+	assert.ErrorIs(suite.T(), err, domain.ErrAuditSeqTaken)
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func (suite *AuditUseCaseTestSuite) chain(n int) []*domain.AuditEntry {
+	var entries []*domain.AuditEntry
+	prevHash := ""
+	for i := 1; i <= n; i++ {
+		entry := &domain.AuditEntry{Seq: int64(i), Action: domain.AuditTaskUpdate, Target: "1", PrevHash: prevHash, Timestamp: time.Now().UTC()}
+		entry.Hash = entry.ComputeHash()
+		prevHash = entry.Hash
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func (suite *AuditUseCaseTestSuite) scan(entries []*domain.AuditEntry) {
+	suite.mockRepo.On("Scan", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		fn := args.Get(1).(func(*domain.AuditEntry) error)
+		for _, entry := range entries {
+			fn(entry)
+		}
+	}).Return(nil)
+}
+
+func (suite *AuditUseCaseTestSuite) TestVerifyIntactChain() {
+	suite.scan(suite.chain(3))
+
+	result, err := suite.auditUseCase.Verify(context.Background())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &domain.AuditVerification{Valid: true, Entries: 3}, result)
+}
+
+func (suite *AuditUseCaseTestSuite) TestVerifyDetectsTampering() {
+	entries := suite.chain(3)
+	entries[1].Target = "2"
+	suite.scan(entries)
+
+	result, err := suite.auditUseCase.Verify(context.Background())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &domain.AuditVerification{Valid: false, Entries: 3, BrokenAt: 2}, result)
+}
+
+func (suite *AuditUseCaseTestSuite) TestVerifyDetectsRemovedEntry() {
+	entries := suite.chain(3)
+	suite.scan([]*domain.AuditEntry{entries[0], entries[2]})
+
+	result, err := suite.auditUseCase.Verify(context.Background())
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), result.Valid)
+	assert.Equal(suite.T(), int64(3), result.BrokenAt)
+}
+
+func (suite *AuditUseCaseTestSuite) TestAuditedTaskUpdate() {
+	mockTaskUseCase := new(mocks.TaskUsecase)
+	mockAudit := new(mocks.AuditUsecase)
+	taskUseCase := NewAuditedTaskUseCase(mockTaskUseCase, mockAudit, discardLogger)
+
+	before := &domain.Task{ID: "1", Status: "Pending"}
+	after := &domain.Task{ID: "1", Status: "Completed"}
+	update := domain.Task{ID: "1", Status: "Completed"}
+
+	mockTaskUseCase.On("GetTasksById", mock.Anything, "1").Return(before, nil).Once()
+	mockTaskUseCase.On("UpdateTask", mock.Anything, "1", update).Return(nil)
+	mockTaskUseCase.On("GetTasksById", mock.Anything, "1").Return(after, nil).Once()
+	mockAudit.On("Record", mock.Anything, domain.AuditTaskUpdate, "1", before, after).Return(nil)
+
+	err := taskUseCase.UpdateTask(context.Background(), "1", update)
+	assert.NoError(suite.T(), err)
+
+	mockTaskUseCase.AssertExpectations(suite.T())
+	mockAudit.AssertExpectations(suite.T())
+}
+
+func (suite *AuditUseCaseTestSuite) TestAuditFailureAfterWriteIsLogged() {
+	mockTaskUseCase := new(mocks.TaskUsecase)
+	mockAudit := new(mocks.AuditUsecase)
+	var logs bytes.Buffer
+	taskUseCase := NewAuditedTaskUseCase(mockTaskUseCase, mockAudit, slog.New(slog.NewTextHandler(&logs, nil)))
+
+	task := domain.Task{ID: "1", Status: "Pending"}
+	mockTaskUseCase.On("AddTask", mock.Anything, task).Return(nil)
+	mockTaskUseCase.On("UpdateTask", mock.Anything, "1", task).Return(nil)
+	mockTaskUseCase.On("DeleteById", mock.Anything, "1").Return(int64(1), nil)
+	mockTaskUseCase.On("GetTasksById", mock.Anything, "1").Return(&task, nil)
+	mockAudit.On("Record", mock.Anything, mock.Anything, "1", mock.Anything, mock.Anything).Return(errors.New("audit log unavailable"))
+
+	// The writes happened, so they are not reported as failed
+	assert.NoError(suite.T(), taskUseCase.AddTask(context.Background(), task))
+	assert.NoError(suite.T(), taskUseCase.UpdateTask(context.Background(), "1", task))
+	deletedCount, err := taskUseCase.DeleteById(context.Background(), "1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), deletedCount)
+
+	for _, action := range []string{domain.AuditTaskCreate, domain.AuditTaskUpdate, domain.AuditTaskDelete} {
+		assert.Contains(suite.T(), logs.String(), "action="+action)
+	}
+	assert.Equal(suite.T(), 3, strings.Count(logs.String(), "audit log unavailable"))
+	mockTaskUseCase.AssertExpectations(suite.T())
+}
+
+func (suite *AuditUseCaseTestSuite) TestAuditedSignupOmitsSecrets() {
+	mockUserUseCase := new(mocks.UserUseCase)
+	mockAudit := new(mocks.AuditUsecase)
+	userUseCase := NewAuditedUserUseCase(mockUserUseCase, mockAudit, discardLogger)
+
+	user := domain.User{Email: stringPtr("john.doe@example.com"), Password: stringPtr("hash"), Token: stringPtr("token")}
+
+	mockUserUseCase.On("Signup", mock.Anything, mock.AnythingOfType("domain.User")).Return("inserted", nil)
+	mockAudit.On("Record", mock.Anything, domain.AuditUserSignup, mock.AnythingOfType("string"), nil,
+		mock.MatchedBy(func(after map[string]interface{}) bool {
+			_, hasPassword := after["password"]
+			_, hasToken := after["token"]
+			return after["userid"] != "" && !hasPassword && !hasToken
+		})).Return(nil)
+
+	_, err := userUseCase.Signup(context.Background(), user)
+	assert.NoError(suite.T(), err)
+
+	mockAudit.AssertExpectations(suite.T())
+}
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestAuditUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditUseCaseTestSuite))
+}
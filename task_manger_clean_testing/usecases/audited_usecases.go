@@ -0,0 +1,190 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"task_manger_clean_architecture/domain"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// recordWrite records a write that has already succeeded. The write cannot be
+// taken back, so a failure to record it is logged rather than returned: an
+// error would tell the caller the write failed, and a retry would repeat it.
+func recordWrite(c context.Context, audit domain.AuditUsecase, logger *slog.Logger, action string, target string, before interface{}, after interface{}) {
+	if err := audit.Record(c, action, target, before, after); err != nil {
+		logger.ErrorContext(c, "recording audit entry", "action", action, "target", target, "error", err)
+	}
+}
+
+// auditedTaskUseCase records every task write in the audit log. Reads are
+// passed straight through to the embedded use case.
+type auditedTaskUseCase struct {
+	domain.TaskUsecase
+	audit  domain.AuditUsecase
+	logger *slog.Logger
+}
+
+func NewAuditedTaskUseCase(taskUseCase domain.TaskUsecase, audit domain.AuditUsecase, logger *slog.Logger) domain.TaskUsecase {
+	return &auditedTaskUseCase{
+		TaskUsecase: taskUseCase,
+		audit:       audit,
+		logger:      logger,
+	}
+}
+
+// stored returns the task as stored after a write, or the task written when
+// it cannot be read back.
+func (a *auditedTaskUseCase) stored(c context.Context, id string, written domain.Task) *domain.Task {
+	if task, err := a.TaskUsecase.GetTasksById(c, id); err == nil && task != nil {
+		return task
+	}
+	written.ID = id
+	return &written
+}
+
+// AddTask implements domain.TaskUsecase.
+func (a *auditedTaskUseCase) AddTask(c context.Context, newTask domain.Task) error {
+	if err := a.TaskUsecase.AddTask(c, newTask); err != nil {
+		return err
+	}
+	recordWrite(c, a.audit, a.logger, domain.AuditTaskCreate, newTask.ID, nil, a.stored(c, newTask.ID, newTask))
+	return nil
+}
+
+// UpdateTask implements domain.TaskUsecase.
+func (a *auditedTaskUseCase) UpdateTask(c context.Context, id string, updatedTask domain.Task) error {
+	before, err := a.TaskUsecase.GetTasksById(c, id)
+	if err != nil {
+		return err
+	}
+	if err := a.TaskUsecase.UpdateTask(c, id, updatedTask); err != nil {
+		return err
+	}
+	recordWrite(c, a.audit, a.logger, domain.AuditTaskUpdate, id, before, a.stored(c, id, updatedTask))
+	return nil
+}
+
+// DeleteById implements domain.TaskUsecase.
+func (a *auditedTaskUseCase) DeleteById(c context.Context, id string) (int64, error) {
+	before, err := a.TaskUsecase.GetTasksById(c, id)
+	if err != nil {
+		return 0, err
+	}
+	deletedCount, err := a.TaskUsecase.DeleteById(c, id)
+	if err != nil || deletedCount == 0 {
+		return deletedCount, err
+	}
+	recordWrite(c, a.audit, a.logger, domain.AuditTaskDelete, id, before, nil)
+	return deletedCount, nil
+}
+
+// ReindexTasks implements domain.TaskUsecase.
+func (a *auditedTaskUseCase) ReindexTasks(c context.Context) (int, error) {
+	indexed, err := a.TaskUsecase.ReindexTasks(c)
+	if err != nil {
+		return indexed, err
+	}
+	recordWrite(c, a.audit, a.logger, domain.AuditTaskReindex, "", nil, map[string]int{"indexed": indexed})
+	return indexed, nil
+}
+
+// auditedUserUseCase records signups, promotions, profile and password
+// changes and token updates in the audit log. Reads are passed straight through to the embedded use case.
+type auditedUserUseCase struct {
+	domain.UserUseCase
+	audit  domain.AuditUsecase
+	logger *slog.Logger
+}
+
+func NewAuditedUserUseCase(userUseCase domain.UserUseCase, audit domain.AuditUsecase, logger *slog.Logger) domain.UserUseCase {
+	return &auditedUserUseCase{
+		UserUseCase: userUseCase,
+		audit:       audit,
+		logger:      logger,
+	}
+}
+
+// userAuditView is the part of a user that is safe to copy into the audit
+// log: no password hash and no tokens.
+func userAuditView(user domain.User) map[string]interface{} {
+	return map[string]interface{}{
+		"userid":        user.UserId,
+		"email":         user.Email,
+		"firstname":     user.FirstName,
+		"lastname":      user.LastName,
+		"phone":         user.Phone,
+		"usertype":      user.UserType,
+		"emailverified": user.EmailVerified,
+	}
+}
+
+// Signup implements domain.UserUseCase.
+func (a *auditedUserUseCase) Signup(c context.Context, user domain.User) (interface{}, error) {
+	// Fix the ID up front so the entry can name the new account.
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+		user.UserId = user.ID.Hex()
+	}
+	result, err := a.UserUseCase.Signup(c, user)
+	if err != nil {
+		return result, err
+	}
+	recordWrite(c, a.audit, a.logger, domain.AuditUserSignup, user.UserId, nil, userAuditView(user))
+	return result, nil
+}
+
+// Promote implements domain.UserUseCase.
+func (a *auditedUserUseCase) Promote(c context.Context, user_id string, userType string) (error, int64, int64) {
+	before, err := a.UserUseCase.GetUser(c, user_id)
+	if err != nil {
+		return err, 0, 0
+	}
+	err, matchedCount, modifiedCount := a.UserUseCase.Promote(c, user_id, userType)
+	if err != nil || modifiedCount == 0 {
+		return err, matchedCount, modifiedCount
+	}
+	workspace_id, _ := domain.WorkspaceFrom(c)
+	role, _ := before.Role(workspace_id)
+	recordWrite(c, a.audit, a.logger, domain.AuditUserPromote, user_id,
+		map[string]interface{}{"usertype": role, "workspace_id": workspace_id},
+		map[string]interface{}{"usertype": userType, "workspace_id": workspace_id})
+	return nil, matchedCount, modifiedCount
+}
+
+// UpdateAllTokens implements domain.UserUseCase. Tokens are replaced when
+// their user signs in, before the request is attributed to anyone, so the
+// user is recorded as the actor.
+func (a *auditedUserUseCase) UpdateAllTokens(c context.Context, token string, refreshToken string, user_id string) error {
+	if err := a.UserUseCase.UpdateAllTokens(c, token, refreshToken, user_id); err != nil {
+		return err
+	}
+	actor := domain.AuditActorFrom(c)
+	actor.UID = user_id
+	c = domain.WithAuditActor(c, actor)
+	recordWrite(c, a.audit, a.logger, domain.AuditUserTokensUpdate, user_id, nil, nil)
+	return nil
+}
+
+// UpdateProfile implements domain.UserUseCase.
+func (a *auditedUserUseCase) UpdateProfile(c context.Context, user_id string, role string, update domain.ProfileUpdate) (domain.User, error) {
+	before, err := a.UserUseCase.GetUser(c, user_id)
+	if err != nil {
+		return domain.User{}, err
+	}
+	after, err := a.UserUseCase.UpdateProfile(c, user_id, role, update)
+	if err != nil {
+		return after, err
+	}
+	recordWrite(c, a.audit, a.logger, domain.AuditUserProfileUpdate, user_id, userAuditView(before), userAuditView(after))
+	return after, nil
+}
+
+// ChangePassword implements domain.UserUseCase. Neither hash is recorded.
+func (a *auditedUserUseCase) ChangePassword(c context.Context, user_id string, password string) error {
+	if err := a.UserUseCase.ChangePassword(c, user_id, password); err != nil {
+		return err
+	}
+	recordWrite(c, a.audit, a.logger, domain.AuditUserPasswordChange, user_id, nil, nil)
+	return nil
+}
@@ -0,0 +1,218 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const inviteTTL = 7 * 24 * time.Hour
+
+type InviteUseCase struct {
+	inviteRepository    domain.InviteRepository
+	userRepository      domain.UserRepository
+	workspaceRepository domain.WorkspaceRepository
+	mailer              domain.Mailer
+	audit               domain.AuditUsecase
+	logger              *slog.Logger
+	baseURL             string
+	contextTimeout      time.Duration
+	now                 func() time.Time
+}
+
+// NewInviteUseCase returns the invitations to workspaces. Links in the mails
+// point at baseURL.
+func NewInviteUseCase(inviteRepository domain.InviteRepository, userRepository domain.UserRepository, workspaceRepository domain.WorkspaceRepository, mailer domain.Mailer, audit domain.AuditUsecase, logger *slog.Logger, baseURL string, timeout time.Duration) domain.InviteUsecase {
+	return &InviteUseCase{
+		inviteRepository:    inviteRepository,
+		userRepository:      userRepository,
+		workspaceRepository: workspaceRepository,
+		mailer:              mailer,
+		audit:               audit,
+		logger:              logger,
+		baseURL:             strings.TrimRight(baseURL, "/"),
+		contextTimeout:      timeout,
+		now:                 time.Now,
+	}
+}
+
+// Invite implements domain.InviteUsecase. Earlier invites for the address
+// stop working.
+func (i *InviteUseCase) Invite(c context.Context, invitedBy string, request domain.NewInvite) (*domain.Invite, error) {
+	ctx, cancel := withTimeout(c, i.contextTimeout)
+	defer cancel()
+
+	workspace_id, err := domain.WorkspaceFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+	email := strings.TrimSpace(request.Email)
+	if user, err := i.userRepository.GetUserByEmail(ctx, email); err == nil {
+		if _, ok := user.Role(workspace_id); ok {
+			return nil, domain.ErrAlreadyMember
+		}
+	} else if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	if err := i.inviteRepository.DeletePending(ctx, email); err != nil {
+		return nil, err
+	}
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	now := i.now().UTC()
+	invite := domain.Invite{
+		ID:          primitive.NewObjectID().Hex(),
+		WorkspaceID: workspace_id,
+		Email:       email,
+		Role:        request.Role,
+		Hash:        hashUserToken(token),
+		InvitedBy:   invitedBy,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(inviteTTL),
+	}
+	if err := i.inviteRepository.Create(ctx, invite); err != nil {
+		return nil, err
+	}
+	recordWrite(ctx, i.audit, i.logger, domain.AuditInviteCreate, invite.ID, nil, invite)
+	if err := i.send(ctx, invite, token); err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+func (i *InviteUseCase) send(c context.Context, invite domain.Invite, token string) error {
+	name := invite.WorkspaceID
+	if workspaces, err := i.workspaceRepository.GetByIDs(c, []string{invite.WorkspaceID}); err == nil && len(workspaces) == 1 {
+		name = workspaces[0].Name
+	}
+	return i.mailer.Send(c, domain.Mail{
+		To:      invite.Email,
+		Subject: "You are invited to " + name,
+		Body: "You are invited to join " + name + " on Task Manager. Open this link to accept:\n\n" +
+			i.baseURL + "/invites/" + url.PathEscape(token) +
+			"\n\nThe invite expires in 7 days.",
+	})
+}
+
+// List implements domain.InviteUsecase.
+func (i *InviteUseCase) List(c context.Context) ([]domain.Invite, error) {
+	ctx, cancel := withTimeout(c, i.contextTimeout)
+	defer cancel()
+	return i.inviteRepository.ListPending(ctx, i.now())
+}
+
+// Revoke implements domain.InviteUsecase.
+func (i *InviteUseCase) Revoke(c context.Context, id string) error {
+	ctx, cancel := withTimeout(c, i.contextTimeout)
+	defer cancel()
+
+	if err := i.inviteRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	recordWrite(ctx, i.audit, i.logger, domain.AuditInviteRevoke, id, nil, nil)
+	return nil
+}
+
+// Resend implements domain.InviteUsecase. The invite gets a full TTL again.
+func (i *InviteUseCase) Resend(c context.Context, id string) (*domain.Invite, error) {
+	ctx, cancel := withTimeout(c, i.contextTimeout)
+	defer cancel()
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	invite, err := i.inviteRepository.Renew(ctx, id, hashUserToken(token), i.now().UTC().Add(inviteTTL))
+	if err != nil {
+		return nil, err
+	}
+	recordWrite(ctx, i.audit, i.logger, domain.AuditInviteResend, id, nil, nil)
+	if err := i.send(ctx, *invite, token); err != nil {
+		return nil, err
+	}
+	return invite, nil
+}
+
+// Lookup implements domain.InviteUsecase.
+func (i *InviteUseCase) Lookup(c context.Context, token string) (*domain.Invite, error) {
+	ctx, cancel := withTimeout(c, i.contextTimeout)
+	defer cancel()
+	return i.inviteRepository.GetByHash(ctx, hashUserToken(token), i.now())
+}
+
+// asActor attributes what follows to user_id: the request that accepts an
+// invite is not signed in, but the new member is known once it is accepted.
+func asActor(c context.Context, user_id string) context.Context {
+	actor := domain.AuditActorFrom(c)
+	actor.UID = user_id
+	return domain.WithAuditActor(c, actor)
+}
+
+// Accept implements domain.InviteUsecase. Following the mailed link proves
+// the user owns the invited address, so a new account starts verified.
+func (i *InviteUseCase) Accept(c context.Context, token string, newUser *domain.User) (domain.User, error) {
+	ctx, cancel := withTimeout(c, i.contextTimeout)
+	defer cancel()
+
+	hash := hashUserToken(token)
+	invite, err := i.inviteRepository.GetByHash(ctx, hash, i.now())
+	if err != nil {
+		return domain.User{}, err
+	}
+	existing, err := i.userRepository.GetUserByEmail(ctx, invite.Email)
+	switch {
+	case err == mongo.ErrNoDocuments:
+		if newUser == nil {
+			return domain.User{}, domain.ErrInviteNeedsAccount
+		}
+	case err != nil:
+		return domain.User{}, err
+	case !existing.EmailVerified:
+		return domain.User{}, domain.ErrInviteUnverifiedAccount
+	}
+
+	// Check everything before spending the invite, but only act once it is ours
+	invite, err = i.inviteRepository.Consume(ctx, hash, i.now())
+	if err != nil {
+		return domain.User{}, err
+	}
+	membership := domain.WorkspaceMembership{WorkspaceID: invite.WorkspaceID, Role: invite.Role}
+	if existing.UserId != "" {
+		actorCtx := asActor(ctx, existing.UserId)
+		if err := i.userRepository.AddMembership(ctx, existing.UserId, membership); err != nil {
+			return domain.User{}, err
+		}
+		recordWrite(actorCtx, i.audit, i.logger, domain.AuditInviteAccept, invite.ID, nil, membership)
+		return i.userRepository.GetUser(ctx, existing.UserId)
+	}
+
+	user := *newUser
+	email := invite.Email
+	userType := "USER"
+	now := i.now().UTC()
+	id := primitive.NewObjectID()
+	user.ID = id
+	user.UserId = id.Hex()
+	user.Email = &email
+	user.UserType = &userType
+	user.EmailVerified = true
+	user.Workspaces = []domain.WorkspaceMembership{membership}
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	actorCtx := asActor(ctx, user.UserId)
+	if _, err := i.userRepository.Signup(ctx, user); err != nil {
+		return domain.User{}, err
+	}
+	recordWrite(actorCtx, i.audit, i.logger, domain.AuditUserSignup, user.UserId, nil, userAuditView(user))
+	recordWrite(actorCtx, i.audit, i.logger, domain.AuditInviteAccept, invite.ID, nil, membership)
+	return user, nil
+}
@@ -0,0 +1,180 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type InviteUseCaseTestSuite struct {
+	suite.Suite
+	mockInviteRepo    *mocks.InviteRepository
+	mockUserRepo      *mocks.UserRepository
+	mockWorkspaceRepo *mocks.WorkspaceRepository
+	mockMailer        *mocks.Mailer
+	mockAudit         *mocks.AuditUsecase
+	now               time.Time
+	ctx               context.Context
+	invites           *InviteUseCase
+}
+
+func (suite *InviteUseCaseTestSuite) SetupTest() {
+	suite.mockInviteRepo = new(mocks.InviteRepository)
+	suite.mockUserRepo = new(mocks.UserRepository)
+	suite.mockWorkspaceRepo = new(mocks.WorkspaceRepository)
+	suite.mockMailer = new(mocks.Mailer)
+	suite.mockAudit = new(mocks.AuditUsecase)
+	suite.now = time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+	suite.ctx = domain.WithWorkspace(context.Background(), "ws2")
+	suite.invites = NewInviteUseCase(suite.mockInviteRepo, suite.mockUserRepo, suite.mockWorkspaceRepo, suite.mockMailer, suite.mockAudit, discardLogger, "https://tasks.example.com/", time.Second*2).(*InviteUseCase)
+	suite.invites.now = func() time.Time { return suite.now }
+}
+
+// expectMail captures the token mailed for the invite.
+func (suite *InviteUseCaseTestSuite) expectMail(token *string) {
+	suite.mockWorkspaceRepo.On("GetByIDs", mock.Anything, []string{"ws2"}).Return([]domain.Workspace{{ID: "ws2", Name: "Acme"}}, nil)
+	suite.mockMailer.On("Send", mock.Anything, mock.AnythingOfType("domain.Mail")).
+		Run(func(args mock.Arguments) {
+			mail := args.Get(1).(domain.Mail)
+			assert.Equal(suite.T(), "new@example.com", mail.To)
+			assert.Contains(suite.T(), mail.Subject, "Acme")
+			link := mail.Body[strings.Index(mail.Body, "https://tasks.example.com/invites/"):]
+			*token = strings.Fields(strings.TrimPrefix(link, "https://tasks.example.com/invites/"))[0]
+		}).Return(nil).Once()
+}
+
+func (suite *InviteUseCaseTestSuite) TestInviteMailsToken() {
+	var stored domain.Invite
+	var token string
+	suite.mockUserRepo.On("GetUserByEmail", mock.Anything, "new@example.com").Return(domain.User{}, mongo.ErrNoDocuments)
+	suite.mockInviteRepo.On("DeletePending", mock.Anything, "new@example.com").Return(nil).Once()
+	suite.mockInviteRepo.On("Create", mock.Anything, mock.AnythingOfType("domain.Invite")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(domain.Invite) }).Return(nil).Once()
+	suite.mockAudit.On("Record", mock.Anything, domain.AuditInviteCreate, mock.Anything, nil, mock.Anything).Return(nil).Once()
+	suite.expectMail(&token)
+
+	invite, err := suite.invites.Invite(suite.ctx, "1", domain.NewInvite{Email: " new@example.com ", Role: "USER"})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), stored, *invite)
+	assert.Equal(suite.T(), "ws2", invite.WorkspaceID)
+	assert.Equal(suite.T(), "USER", invite.Role)
+	assert.Equal(suite.T(), "1", invite.InvitedBy)
+	assert.Equal(suite.T(), suite.now.Add(inviteTTL), invite.ExpiresAt)
+	assert.Equal(suite.T(), hashUserToken(token), invite.Hash)
+	suite.mockInviteRepo.AssertExpectations(suite.T())
+	suite.mockMailer.AssertExpectations(suite.T())
+}
+
+func (suite *InviteUseCaseTestSuite) TestInviteRejectsMember() {
+	email := "new@example.com"
+	suite.mockUserRepo.On("GetUserByEmail", mock.Anything, email).Return(domain.User{UserId: "2", Email: &email, Workspaces: []domain.WorkspaceMembership{
+		{WorkspaceID: "ws2", Role: "USER"},
+	}}, nil)
+
+	_, err := suite.invites.Invite(suite.ctx, "1", domain.NewInvite{Email: email, Role: "ADMIN"})
+	assert.ErrorIs(suite.T(), err, domain.ErrAlreadyMember)
+	suite.mockInviteRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *InviteUseCaseTestSuite) TestResendRenewsToken() {
+	var token string
+	renewed := &domain.Invite{ID: "i1", WorkspaceID: "ws2", Email: "new@example.com", Role: "USER"}
+	suite.mockInviteRepo.On("Renew", mock.Anything, "i1", mock.AnythingOfType("string"), suite.now.Add(inviteTTL)).
+		Run(func(args mock.Arguments) { renewed.Hash = args.String(2) }).Return(renewed, nil).Once()
+	suite.mockAudit.On("Record", mock.Anything, domain.AuditInviteResend, "i1", nil, nil).Return(nil).Once()
+	suite.expectMail(&token)
+
+	invite, err := suite.invites.Resend(suite.ctx, "i1")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), hashUserToken(token), invite.Hash)
+}
+
+func (suite *InviteUseCaseTestSuite) TestRevoke() {
+	suite.mockInviteRepo.On("Delete", mock.Anything, "i1").Return(nil).Once()
+	suite.mockAudit.On("Record", mock.Anything, domain.AuditInviteRevoke, "i1", nil, nil).Return(nil).Once()
+	suite.Require().NoError(suite.invites.Revoke(suite.ctx, "i1"))
+
+	suite.mockInviteRepo.On("Delete", mock.Anything, "gone").Return(domain.ErrInviteNotFound).Once()
+	assert.ErrorIs(suite.T(), suite.invites.Revoke(suite.ctx, "gone"), domain.ErrInviteNotFound)
+	suite.mockAudit.AssertExpectations(suite.T())
+}
+
+func (suite *InviteUseCaseTestSuite) expectInvite(token string) *domain.Invite {
+	invite := &domain.Invite{ID: "i1", WorkspaceID: "ws2", Email: "new@example.com", Role: "ADMIN", Hash: hashUserToken(token)}
+	suite.mockInviteRepo.On("GetByHash", mock.Anything, hashUserToken(token), suite.now).Return(invite, nil)
+	return invite
+}
+
+func (suite *InviteUseCaseTestSuite) TestAcceptSignsUpNewUser() {
+	invite := suite.expectInvite("token")
+	first, last, password, email := "New", "User", "hashed", "other@example.com"
+	var created domain.User
+	suite.mockUserRepo.On("GetUserByEmail", mock.Anything, "new@example.com").Return(domain.User{}, mongo.ErrNoDocuments)
+	suite.mockInviteRepo.On("Consume", mock.Anything, hashUserToken("token"), suite.now).Return(invite, nil).Once()
+	suite.mockUserRepo.On("Signup", mock.Anything, mock.AnythingOfType("domain.User")).
+		Run(func(args mock.Arguments) { created = args.Get(1).(domain.User) }).Return(nil, nil).Once()
+	suite.mockAudit.On("Record", mock.MatchedBy(func(c context.Context) bool {
+		return domain.AuditActorFrom(c).UID == created.UserId
+	}), domain.AuditUserSignup, mock.Anything, nil, mock.Anything).Return(nil).Once()
+	suite.mockAudit.On("Record", mock.Anything, domain.AuditInviteAccept, "i1", nil, domain.WorkspaceMembership{WorkspaceID: "ws2", Role: "ADMIN"}).Return(nil).Once()
+
+	user, err := suite.invites.Accept(context.Background(), "token", &domain.User{FirstName: &first, LastName: &last, Password: &password, Email: &email})
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), created, user)
+	assert.Equal(suite.T(), "new@example.com", *user.Email)
+	assert.Equal(suite.T(), "USER", *user.UserType)
+	assert.True(suite.T(), user.EmailVerified)
+	assert.Equal(suite.T(), []domain.WorkspaceMembership{{WorkspaceID: "ws2", Role: "ADMIN"}}, user.Workspaces)
+	suite.mockAudit.AssertExpectations(suite.T())
+}
+
+func (suite *InviteUseCaseTestSuite) TestAcceptAttachesExistingUser() {
+	invite := suite.expectInvite("token")
+	email := "new@example.com"
+	existing := domain.User{UserId: "2", Email: &email, EmailVerified: true}
+	suite.mockUserRepo.On("GetUserByEmail", mock.Anything, email).Return(existing, nil)
+	suite.mockInviteRepo.On("Consume", mock.Anything, hashUserToken("token"), suite.now).Return(invite, nil).Once()
+	suite.mockUserRepo.On("AddMembership", mock.Anything, "2", domain.WorkspaceMembership{WorkspaceID: "ws2", Role: "ADMIN"}).Return(nil).Once()
+	suite.mockAudit.On("Record", mock.Anything, domain.AuditInviteAccept, "i1", nil, mock.Anything).Return(nil).Once()
+	suite.mockUserRepo.On("GetUser", mock.Anything, "2").Return(existing, nil)
+
+	user, err := suite.invites.Accept(context.Background(), "token", nil)
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "2", user.UserId)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Signup", mock.Anything, mock.Anything)
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+func (suite *InviteUseCaseTestSuite) TestAcceptKeepsInviteOnRefusal() {
+	suite.expectInvite("token")
+	email := "new@example.com"
+	suite.mockUserRepo.On("GetUserByEmail", mock.Anything, email).Return(domain.User{UserId: "2", Email: &email}, nil).Once()
+
+	_, err := suite.invites.Accept(context.Background(), "token", nil)
+	assert.ErrorIs(suite.T(), err, domain.ErrInviteUnverifiedAccount)
+
+	suite.mockUserRepo.On("GetUserByEmail", mock.Anything, email).Return(domain.User{}, mongo.ErrNoDocuments).Once()
+	_, err = suite.invites.Accept(context.Background(), "token", nil)
+	assert.ErrorIs(suite.T(), err, domain.ErrInviteNeedsAccount)
+
+	suite.mockInviteRepo.AssertNotCalled(suite.T(), "Consume", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *InviteUseCaseTestSuite) TestAcceptRejectsSpentInvite() {
+	suite.mockInviteRepo.On("GetByHash", mock.Anything, hashUserToken("token"), suite.now).Return(nil, domain.ErrInvalidInvite)
+
+	_, err := suite.invites.Accept(context.Background(), "token", nil)
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidInvite)
+}
+
+func TestInviteUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(InviteUseCaseTestSuite))
+}
@@ -0,0 +1,238 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"time"
+)
+
+// LoginThrottlePolicy describes how failed logins for one kind of key slow
+// down and eventually lock further attempts.
+type LoginThrottlePolicy struct {
+	// FreeAttempts failures are allowed before any delay applies.
+	FreeAttempts int
+	// BaseDelay is the wait after the first failure past FreeAttempts; it
+	// doubles with every further failure up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// LockoutThreshold failures within FailureWindow lock the key for
+	// LockoutDuration.
+	LockoutThreshold int
+	LockoutDuration  time.Duration
+	FailureWindow    time.Duration
+}
+
+// delay returns how long to wait after the given number of failures.
+func (p LoginThrottlePolicy) delay(failures int) time.Duration {
+	extra := failures - p.FreeAttempts
+	if extra <= 0 {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := 1; i < extra && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+var (
+	// DefaultAccountThrottlePolicy protects a single account from password
+	// guessing.
+	DefaultAccountThrottlePolicy = LoginThrottlePolicy{
+		FreeAttempts:     3,
+		BaseDelay:        time.Second,
+		MaxDelay:         30 * time.Second,
+		LockoutThreshold: 10,
+		LockoutDuration:  15 * time.Minute,
+		FailureWindow:    15 * time.Minute,
+	}
+	// DefaultIPThrottlePolicy is looser, since several users can share an
+	// address, but stops one client from spraying many accounts.
+	DefaultIPThrottlePolicy = LoginThrottlePolicy{
+		FreeAttempts:     10,
+		BaseDelay:        time.Second,
+		MaxDelay:         time.Minute,
+		LockoutThreshold: 50,
+		LockoutDuration:  15 * time.Minute,
+		FailureWindow:    15 * time.Minute,
+	}
+)
+
+type LoginThrottleUseCase struct {
+	store          domain.LoginAttemptStore
+	audit          domain.AuditUsecase
+	logger         *slog.Logger
+	accountPolicy  LoginThrottlePolicy
+	ipPolicy       LoginThrottlePolicy
+	contextTimeout time.Duration
+	now            func() time.Time
+}
+
+func NewLoginThrottleUseCase(store domain.LoginAttemptStore, audit domain.AuditUsecase, logger *slog.Logger, timeout time.Duration) domain.LoginThrottleUsecase {
+	return &LoginThrottleUseCase{
+		store:          store,
+		audit:          audit,
+		logger:         logger,
+		accountPolicy:  DefaultAccountThrottlePolicy,
+		ipPolicy:       DefaultIPThrottlePolicy,
+		contextTimeout: timeout,
+		now:            time.Now,
+	}
+}
+
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// throttleKey is one of the keys a login attempt counts against.
+type throttleKey struct {
+	key    string
+	policy LoginThrottlePolicy
+}
+
+func (l *LoginThrottleUseCase) keys(email string, ip string) []throttleKey {
+	return []throttleKey{
+		{accountKey(email), l.accountPolicy},
+		{ipKey(ip), l.ipPolicy},
+	}
+}
+
+// retryAfter returns how long an attempt must still wait under policy, given
+// the key as it was before the attempt, and whether the wait is a lockout.
+// Attempts still being checked count as failures, so a burst cannot get more
+// than the threshold past the lockout.
+func (l *LoginThrottleUseCase) retryAfter(attempt *domain.LoginAttempt, policy LoginThrottlePolicy, now time.Time) (time.Duration, bool) {
+	if attempt == nil {
+		return 0, false
+	}
+	if now.Before(attempt.LockedUntil) {
+		return attempt.LockedUntil.Sub(now), true
+	}
+	if now.Sub(attempt.LastFailure) > policy.FailureWindow {
+		return 0, false
+	}
+	if attempt.Failures >= policy.LockoutThreshold {
+		return policy.LockoutDuration, true
+	}
+	if wait := attempt.LastFailure.Add(policy.delay(attempt.Failures)).Sub(now); wait > 0 {
+		return wait, false
+	}
+	return 0, false
+}
+
+// Reserve implements domain.LoginThrottleUsecase. The attempt is counted
+// before it is judged, so concurrent attempts each see the ones before them.
+// A refused attempt still restarts the wait. It is cheap enough to run before the password hash is compared.
+func (l *LoginThrottleUseCase) Reserve(c context.Context, email string, ip string) error {
+	ctx, cancel := withTimeout(c, l.contextTimeout)
+	defer cancel()
+
+	now := l.now()
+	var reserved []string
+	var throttled *domain.LoginThrottledError
+	for _, k := range l.keys(email, ip) {
+		before, err := l.store.Reserve(ctx, k.key, now, k.policy.FailureWindow)
+		if err != nil {
+			l.release(ctx, reserved)
+			return err
+		}
+		reserved = append(reserved, k.key)
+		wait, locked := l.retryAfter(before, k.policy, now)
+		if wait > 0 && (throttled == nil || wait > throttled.RetryAfter) {
+			throttled = &domain.LoginThrottledError{RetryAfter: wait, Locked: locked}
+		}
+	}
+	if throttled != nil {
+		// A refused attempt is not a failure
+		if err := l.release(ctx, reserved); err != nil {
+			return err
+		}
+		return throttled
+	}
+	return nil
+}
+
+// release takes back the attempts reserved for keys.
+func (l *LoginThrottleUseCase) release(c context.Context, keys []string) error {
+	for _, key := range keys {
+		if err := l.store.Release(c, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Failure implements domain.LoginThrottleUsecase. Reserve already counted the
+// attempt; this locks whichever of the account and the address reached its
+// threshold.
+func (l *LoginThrottleUseCase) Failure(c context.Context, email string, ip string) error {
+	ctx, cancel := withTimeout(c, l.contextTimeout)
+	defer cancel()
+
+	now := l.now()
+	for _, k := range l.keys(email, ip) {
+		if err := l.lockout(ctx, k, email, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *LoginThrottleUseCase) lockout(c context.Context, k throttleKey, email string, now time.Time) error {
+	attempt, err := l.store.Get(c, k.key)
+	if err != nil {
+		return err
+	}
+	if attempt == nil || attempt.Failures < k.policy.LockoutThreshold || now.Before(attempt.LockedUntil) {
+		return nil
+	}
+
+	until := now.Add(k.policy.LockoutDuration)
+	if err := l.store.Lock(c, k.key, until); err != nil {
+		return err
+	}
+	recordWrite(c, l.audit, l.logger, domain.AuditUserLockout, k.key, nil, map[string]interface{}{
+		"email":        email,
+		"failures":     attempt.Failures,
+		"locked_until": until,
+	})
+	return nil
+}
+
+// Success implements domain.LoginThrottleUsecase. The account is cleared, but
+// the address only gets its attempt back: one good password does not vouch
+// for everything else it has been trying.
+func (l *LoginThrottleUseCase) Success(c context.Context, email string, ip string) error {
+	ctx, cancel := withTimeout(c, l.contextTimeout)
+	defer cancel()
+	if err := l.store.Reset(ctx, accountKey(email)); err != nil {
+		return err
+	}
+	return l.store.Release(ctx, ipKey(ip))
+}
+
+// Unlock implements domain.LoginThrottleUsecase.
+func (l *LoginThrottleUseCase) Unlock(c context.Context, email string) error {
+	ctx, cancel := withTimeout(c, l.contextTimeout)
+	defer cancel()
+
+	key := accountKey(email)
+	before, err := l.store.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if err := l.store.Reset(ctx, key); err != nil {
+		return err
+	}
+	recordWrite(ctx, l.audit, l.logger, domain.AuditUserUnlock, key, before, nil)
+	return nil
+}
@@ -0,0 +1,137 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"task_manger_clean_architecture/repositories"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoginThrottleUseCaseTestSuite struct {
+	suite.Suite
+	mockAudit *mocks.AuditUsecase
+	now       time.Time
+	throttle  *LoginThrottleUseCase
+}
+
+func (suite *LoginThrottleUseCaseTestSuite) SetupTest() {
+	suite.mockAudit = new(mocks.AuditUsecase)
+	suite.now = time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+	suite.throttle = NewLoginThrottleUseCase(repositories.NewInMemoryLoginAttemptStore(), suite.mockAudit, discardLogger, time.Second*2).(*LoginThrottleUseCase)
+	suite.throttle.now = func() time.Time { return suite.now }
+}
+
+// fail makes times failed logins, each waiting as long as it is told to.
+func (suite *LoginThrottleUseCaseTestSuite) fail(times int, email string, ip string) {
+	for i := 0; i < times; i++ {
+		for throttled := suite.throttled(email, ip); throttled != nil; throttled = suite.throttled(email, ip) {
+			suite.now = suite.now.Add(throttled.RetryAfter)
+		}
+		assert.NoError(suite.T(), suite.throttle.Failure(context.Background(), email, ip))
+	}
+}
+
+func (suite *LoginThrottleUseCaseTestSuite) throttled(email string, ip string) *domain.LoginThrottledError {
+	err := suite.throttle.Reserve(context.Background(), email, ip)
+	if err == nil {
+		return nil
+	}
+	var throttled *domain.LoginThrottledError
+	assert.True(suite.T(), errors.As(err, &throttled))
+	return throttled
+}
+
+func (suite *LoginThrottleUseCaseTestSuite) TestFreeAttemptsAreNotDelayed() {
+	suite.fail(DefaultAccountThrottlePolicy.FreeAttempts, "a@example.com", "10.0.0.1")
+	assert.Nil(suite.T(), suite.throttled("a@example.com", "10.0.0.1"))
+}
+
+func (suite *LoginThrottleUseCaseTestSuite) TestDelayDoublesAndExpires() {
+	suite.fail(DefaultAccountThrottlePolicy.FreeAttempts+1, "a@example.com", "10.0.0.1")
+	throttled := suite.throttled("A@example.com", "10.0.0.2")
+	assert.NotNil(suite.T(), throttled)
+	assert.Equal(suite.T(), time.Second, throttled.RetryAfter)
+	assert.False(suite.T(), throttled.Locked)
+
+	suite.fail(2, "a@example.com", "10.0.0.1")
+	assert.Equal(suite.T(), 4*time.Second, suite.throttled("a@example.com", "10.0.0.2").RetryAfter)
+
+	suite.now = suite.now.Add(4 * time.Second)
+	assert.Nil(suite.T(), suite.throttled("a@example.com", "10.0.0.2"))
+}
+
+func (suite *LoginThrottleUseCaseTestSuite) TestLockoutIsAuditedAndUnlocked() {
+	suite.mockAudit.On("Record", mock.Anything, domain.AuditUserLockout, "account:a@example.com", nil, mock.Anything).Return(nil).Once()
+	suite.mockAudit.On("Record", mock.Anything, domain.AuditUserUnlock, "account:a@example.com", mock.Anything, nil).Return(nil).Once()
+
+	suite.fail(DefaultAccountThrottlePolicy.LockoutThreshold, "a@example.com", "10.0.0.1")
+	throttled := suite.throttled("a@example.com", "10.0.0.2")
+	assert.NotNil(suite.T(), throttled)
+	assert.True(suite.T(), throttled.Locked)
+	assert.Equal(suite.T(), DefaultAccountThrottlePolicy.LockoutDuration, throttled.RetryAfter)
+
+	assert.NoError(suite.T(), suite.throttle.Unlock(context.Background(), "a@example.com"))
+	assert.Nil(suite.T(), suite.throttled("a@example.com", "10.0.0.2"))
+	suite.mockAudit.AssertExpectations(suite.T())
+}
+
+func (suite *LoginThrottleUseCaseTestSuite) TestSuccessResetsOnlyTheAccount() {
+	suite.fail(DefaultAccountThrottlePolicy.FreeAttempts+1, "a@example.com", "10.0.0.1")
+	suite.fail(DefaultIPThrottlePolicy.FreeAttempts-DefaultAccountThrottlePolicy.FreeAttempts, "c@example.com", "10.0.0.1")
+	suite.now = suite.now.Add(DefaultAccountThrottlePolicy.MaxDelay)
+	assert.Nil(suite.T(), suite.throttled("a@example.com", "10.0.0.1"))
+	assert.NoError(suite.T(), suite.throttle.Success(context.Background(), "a@example.com", "10.0.0.1"))
+
+	assert.Nil(suite.T(), suite.throttled("a@example.com", "10.0.0.2"))
+	assert.NotNil(suite.T(), suite.throttled("b@example.com", "10.0.0.1"))
+}
+
+// burst makes attempts concurrent logins and returns how many got through.
+func (suite *LoginThrottleUseCaseTestSuite) burst(attempts int, email string) int {
+	var passed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ip := fmt.Sprintf("10.0.1.%d", i)
+			if suite.throttle.Reserve(context.Background(), email, ip) == nil {
+				passed.Add(1)
+				assert.NoError(suite.T(), suite.throttle.Failure(context.Background(), email, ip))
+			}
+		}(i)
+	}
+	wg.Wait()
+	return int(passed.Load())
+}
+
+func (suite *LoginThrottleUseCaseTestSuite) TestBurstGetsOnlyTheFreeAttempts() {
+	assert.Equal(suite.T(), DefaultAccountThrottlePolicy.FreeAttempts+1, suite.burst(50, "a@example.com"))
+}
+
+func (suite *LoginThrottleUseCaseTestSuite) TestBurstCannotOvershootTheLockout() {
+	suite.mockAudit.On("Record", mock.Anything, domain.AuditUserLockout, "account:a@example.com", nil, mock.Anything).Return(nil).Once()
+
+	suite.fail(DefaultAccountThrottlePolicy.LockoutThreshold-1, "a@example.com", "10.0.0.1")
+	suite.now = suite.now.Add(DefaultAccountThrottlePolicy.MaxDelay)
+	assert.Equal(suite.T(), 1, suite.burst(50, "a@example.com"))
+
+	throttled := suite.throttled("a@example.com", "10.0.0.2")
+	assert.NotNil(suite.T(), throttled)
+	assert.True(suite.T(), throttled.Locked)
+	suite.mockAudit.AssertExpectations(suite.T())
+}
+
+func TestLoginThrottleUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(LoginThrottleUseCaseTestSuite))
+}
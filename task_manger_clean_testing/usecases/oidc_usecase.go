@@ -0,0 +1,167 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"log/slog"
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// oidcLoginTTL is how long a user has to get through the provider's login
+// page.
+const oidcLoginTTL = 10 * time.Minute
+
+type OIDCUseCase struct {
+	provider        domain.IdentityProvider
+	loginRepository domain.OIDCLoginRepository
+	userRepository  domain.UserRepository
+	audit           domain.AuditUsecase
+	logger          *slog.Logger
+	signupOpen      bool
+	contextTimeout  time.Duration
+	now             func() time.Time
+}
+
+// NewOIDCUseCase returns the login through provider. Unless signupOpen, only
+// existing accounts can log in, so invite-only deployments stay closed.
+func NewOIDCUseCase(provider domain.IdentityProvider, loginRepository domain.OIDCLoginRepository, userRepository domain.UserRepository, audit domain.AuditUsecase, logger *slog.Logger, signupOpen bool, timeout time.Duration) domain.OIDCUsecase {
+	return &OIDCUseCase{
+		provider:        provider,
+		loginRepository: loginRepository,
+		userRepository:  userRepository,
+		audit:           audit,
+		logger:          logger,
+		signupOpen:      signupOpen,
+		contextTimeout:  timeout,
+		now:             time.Now,
+	}
+}
+
+func randomToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// codeChallenge is the RFC 7636 S256 challenge of verifier.
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// Begin implements domain.OIDCUsecase.
+func (o *OIDCUseCase) Begin(c context.Context) (string, string, error) {
+	ctx, cancel := withTimeout(c, o.contextTimeout)
+	defer cancel()
+
+	var values [3]string
+	for i := range values {
+		value, err := randomToken()
+		if err != nil {
+			return "", "", err
+		}
+		values[i] = value
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	redirect, err := o.provider.AuthCodeURL(ctx, state, nonce, codeChallenge(verifier))
+	if err != nil {
+		return "", "", err
+	}
+	now := o.now()
+	err = o.loginRepository.Create(ctx, domain.OIDCLogin{
+		StateHash:    hashUserToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(oidcLoginTTL),
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return state, redirect, nil
+}
+
+// Complete implements domain.OIDCUsecase.
+func (o *OIDCUseCase) Complete(c context.Context, state string, code string) (domain.User, error) {
+	ctx, cancel := withTimeout(c, o.contextTimeout)
+	defer cancel()
+
+	login, err := o.loginRepository.Consume(ctx, hashUserToken(state), o.now())
+	if err != nil {
+		return domain.User{}, err
+	}
+	identity, err := o.provider.Exchange(ctx, code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if !identity.EmailVerified || identity.Email == "" {
+		return domain.User{}, domain.ErrOIDCEmailNotVerified
+	}
+
+	user, err := o.userRepository.GetUserByEmail(ctx, identity.Email)
+	if err == mongo.ErrNoDocuments {
+		if !o.signupOpen {
+			return domain.User{}, domain.ErrSignupClosed
+		}
+		return o.createUser(ctx, identity)
+	}
+	if err != nil {
+		return domain.User{}, err
+	}
+	if user.EmailVerified {
+		return user, nil
+	}
+
+	// Anyone could have signed up with this address before its owner came
+	// through the provider. Take the account back from them: drop the
+	// password and second factor they may have set and revoke their tokens.
+	if err := o.userRepository.UpdatePassword(ctx, user.UserId, ""); err != nil {
+		return domain.User{}, err
+	}
+	if err := o.userRepository.SetTwoFactor(ctx, user.UserId, domain.TwoFactorSettings{}); err != nil {
+		return domain.User{}, err
+	}
+	if err := o.userRepository.SetEmailVerified(ctx, user.UserId); err != nil {
+		return domain.User{}, err
+	}
+	recordWrite(ctx, o.audit, o.logger, domain.AuditUserVerifyEmail, user.UserId, nil, nil)
+	return o.userRepository.GetUser(ctx, user.UserId)
+}
+
+// createUser signs up the owner of identity. The account has no password,
+// so it can only log in through the provider until the user resets one. Like
+// the accounts of /signup, it starts in no workspace.
+func (o *OIDCUseCase) createUser(c context.Context, identity domain.OIDCIdentity) (domain.User, error) {
+	email := strings.TrimSpace(identity.Email)
+	firstName := identity.GivenName
+	lastName := identity.FamilyName
+	userType := "USER"
+	now := o.now().UTC()
+	id := primitive.NewObjectID()
+	user := domain.User{
+		ID:            id,
+		UserId:        id.Hex(),
+		Email:         &email,
+		FirstName:     &firstName,
+		LastName:      &lastName,
+		UserType:      &userType,
+		EmailVerified: true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if _, err := o.userRepository.Signup(c, user); err != nil {
+		return domain.User{}, err
+	}
+	recordWrite(c, o.audit, o.logger, domain.AuditUserSignup, user.UserId, nil, userAuditView(user))
+	return user, nil
+}
@@ -0,0 +1,148 @@
+package usecases
+
+import (
+	"context"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type OIDCUseCaseTestSuite struct {
+	suite.Suite
+	mockProvider  *mocks.IdentityProvider
+	mockLoginRepo *mocks.OIDCLoginRepository
+	mockUserRepo  *mocks.UserRepository
+	mockAudit     *mocks.AuditUsecase
+	now           time.Time
+	oidc          *OIDCUseCase
+}
+
+func (suite *OIDCUseCaseTestSuite) SetupTest() {
+	suite.mockProvider = new(mocks.IdentityProvider)
+	suite.mockLoginRepo = new(mocks.OIDCLoginRepository)
+	suite.mockUserRepo = new(mocks.UserRepository)
+	suite.mockAudit = new(mocks.AuditUsecase)
+	suite.now = time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+	suite.oidc = NewOIDCUseCase(suite.mockProvider, suite.mockLoginRepo, suite.mockUserRepo, suite.mockAudit, discardLogger, true, time.Second*2).(*OIDCUseCase)
+	suite.oidc.now = func() time.Time { return suite.now }
+}
+
+// expectLogin sets up a stored login for state whose code yields identity.
+func (suite *OIDCUseCaseTestSuite) expectLogin(state string, identity domain.OIDCIdentity) {
+	login := &domain.OIDCLogin{Nonce: "nonce", CodeVerifier: "verifier"}
+	suite.mockLoginRepo.On("Consume", mock.Anything, hashUserToken(state), suite.now).Return(login, nil).Once()
+	suite.mockProvider.On("Exchange", mock.Anything, "code", "verifier", "nonce").Return(identity, nil).Once()
+}
+
+func (suite *OIDCUseCaseTestSuite) TestBeginStoresVerifierForTheChallenge() {
+	var stored domain.OIDCLogin
+	var challenge, nonce string
+	suite.mockProvider.On("AuthCodeURL", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			nonce = args.String(2)
+			challenge = args.String(3)
+		}).Return("https://idp.example.com/authorize?x", nil)
+	suite.mockLoginRepo.On("Create", mock.Anything, mock.AnythingOfType("domain.OIDCLogin")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(domain.OIDCLogin) }).Return(nil)
+
+	state, redirect, err := suite.oidc.Begin(context.Background())
+	suite.Require().NoError(err)
+
+	assert.Equal(suite.T(), "https://idp.example.com/authorize?x", redirect)
+	assert.Equal(suite.T(), hashUserToken(state), stored.StateHash)
+	assert.Equal(suite.T(), nonce, stored.Nonce)
+	assert.Equal(suite.T(), codeChallenge(stored.CodeVerifier), challenge)
+	assert.NotEqual(suite.T(), state, stored.CodeVerifier)
+	assert.Equal(suite.T(), suite.now.Add(oidcLoginTTL), stored.ExpiresAt)
+}
+
+func (suite *OIDCUseCaseTestSuite) TestCodeChallengeMatchesRFC7636() {
+	// Appendix B of RFC 7636
+	assert.Equal(suite.T(), "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", codeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
+
+func (suite *OIDCUseCaseTestSuite) TestCompleteReturnsExistingVerifiedUser() {
+	email := "sso@example.com"
+	existing := domain.User{UserId: "1", Email: &email, EmailVerified: true}
+	suite.expectLogin("state", domain.OIDCIdentity{Subject: "s", Email: email, EmailVerified: true})
+	suite.mockUserRepo.On("GetUserByEmail", mock.Anything, email).Return(existing, nil)
+
+	user, err := suite.oidc.Complete(context.Background(), "state", "code")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "1", user.UserId)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Signup", mock.Anything, mock.Anything)
+}
+
+func (suite *OIDCUseCaseTestSuite) TestCompleteCreatesUserOnFirstLogin() {
+	var created domain.User
+	suite.expectLogin("state", domain.OIDCIdentity{Subject: "s", Email: "new@example.com", EmailVerified: true, GivenName: "New", FamilyName: "User"})
+	suite.mockUserRepo.On("GetUserByEmail", mock.Anything, "new@example.com").Return(domain.User{}, mongo.ErrNoDocuments)
+	suite.mockUserRepo.On("Signup", mock.Anything, mock.AnythingOfType("domain.User")).
+		Run(func(args mock.Arguments) { created = args.Get(1).(domain.User) }).Return(nil, nil).Once()
+	suite.mockAudit.On("Record", mock.Anything, domain.AuditUserSignup, mock.Anything, nil, mock.Anything).Return(nil).Once()
+
+	user, err := suite.oidc.Complete(context.Background(), "state", "code")
+	suite.Require().NoError(err)
+
+	assert.Equal(suite.T(), created.UserId, user.UserId)
+	assert.Equal(suite.T(), created.ID.Hex(), created.UserId)
+	assert.Equal(suite.T(), "new@example.com", *created.Email)
+	assert.Equal(suite.T(), "USER", *created.UserType)
+	assert.Empty(suite.T(), created.Workspaces)
+	assert.True(suite.T(), created.EmailVerified)
+	assert.Nil(suite.T(), created.Password)
+	suite.mockAudit.AssertExpectations(suite.T())
+}
+
+func (suite *OIDCUseCaseTestSuite) TestCompleteCreatesNoUserWhenSignupClosed() {
+	suite.oidc.signupOpen = false
+	suite.expectLogin("state", domain.OIDCIdentity{Subject: "s", Email: "new@example.com", EmailVerified: true})
+	suite.mockUserRepo.On("GetUserByEmail", mock.Anything, "new@example.com").Return(domain.User{}, mongo.ErrNoDocuments)
+
+	_, err := suite.oidc.Complete(context.Background(), "state", "code")
+	assert.ErrorIs(suite.T(), err, domain.ErrSignupClosed)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "Signup", mock.Anything, mock.Anything)
+}
+
+func (suite *OIDCUseCaseTestSuite) TestCompleteTakesBackUnverifiedAccount() {
+	email := "sso@example.com"
+	squatted := domain.User{UserId: "1", Email: &email}
+	suite.expectLogin("state", domain.OIDCIdentity{Subject: "s", Email: email, EmailVerified: true})
+	suite.mockUserRepo.On("GetUserByEmail", mock.Anything, email).Return(squatted, nil)
+	suite.mockUserRepo.On("UpdatePassword", mock.Anything, "1", "").Return(nil).Once()
+	suite.mockUserRepo.On("SetTwoFactor", mock.Anything, "1", domain.TwoFactorSettings{}).Return(nil).Once()
+	suite.mockUserRepo.On("SetEmailVerified", mock.Anything, "1").Return(nil).Once()
+	suite.mockAudit.On("Record", mock.Anything, domain.AuditUserVerifyEmail, "1", nil, nil).Return(nil).Once()
+	suite.mockUserRepo.On("GetUser", mock.Anything, "1").Return(domain.User{UserId: "1", Email: &email, EmailVerified: true}, nil)
+
+	user, err := suite.oidc.Complete(context.Background(), "state", "code")
+	suite.Require().NoError(err)
+	assert.True(suite.T(), user.EmailVerified)
+	suite.mockUserRepo.AssertExpectations(suite.T())
+}
+
+func (suite *OIDCUseCaseTestSuite) TestCompleteRequiresVerifiedEmail() {
+	suite.expectLogin("state", domain.OIDCIdentity{Subject: "s", Email: "sso@example.com"})
+
+	_, err := suite.oidc.Complete(context.Background(), "state", "code")
+	assert.ErrorIs(suite.T(), err, domain.ErrOIDCEmailNotVerified)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "GetUserByEmail", mock.Anything, mock.Anything)
+}
+
+func (suite *OIDCUseCaseTestSuite) TestCompleteRejectsUnknownState() {
+	suite.mockLoginRepo.On("Consume", mock.Anything, hashUserToken("forged"), suite.now).Return(nil, domain.ErrInvalidOIDCState)
+
+	_, err := suite.oidc.Complete(context.Background(), "forged", "code")
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidOIDCState)
+	suite.mockProvider.AssertNotCalled(suite.T(), "Exchange", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestOIDCUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(OIDCUseCaseTestSuite))
+}
@@ -0,0 +1,67 @@
+package usecases
+
+import (
+	"context"
+	"task_manger_clean_architecture/domain"
+)
+
+// publishingTaskUseCase publishes every task write that succeeded to the
+// watchers of the workspace. Reads are passed straight through to the
+// embedded use case.
+type publishingTaskUseCase struct {
+	domain.TaskUsecase
+	events domain.TaskEventBus
+}
+
+func NewPublishingTaskUseCase(taskUseCase domain.TaskUsecase, events domain.TaskEventBus) domain.TaskUsecase {
+	return &publishingTaskUseCase{
+		TaskUsecase: taskUseCase,
+		events:      events,
+	}
+}
+
+// AddTask implements domain.TaskUsecase.
+func (p *publishingTaskUseCase) AddTask(c context.Context, newTask domain.Task) error {
+	if err := p.TaskUsecase.AddTask(c, newTask); err != nil {
+		return err
+	}
+	p.publish(c, domain.TaskCreated, newTask.ID, newTask)
+	return nil
+}
+
+// UpdateTask implements domain.TaskUsecase.
+func (p *publishingTaskUseCase) UpdateTask(c context.Context, id string, updatedTask domain.Task) error {
+	if err := p.TaskUsecase.UpdateTask(c, id, updatedTask); err != nil {
+		return err
+	}
+	p.publish(c, domain.TaskUpdated, id, updatedTask)
+	return nil
+}
+
+// DeleteById implements domain.TaskUsecase.
+func (p *publishingTaskUseCase) DeleteById(c context.Context, id string) (int64, error) {
+	deletedCount, err := p.TaskUsecase.DeleteById(c, id)
+	if err != nil || deletedCount == 0 {
+		return deletedCount, err
+	}
+	workspace_id, _ := domain.WorkspaceFrom(c)
+	p.events.Publish(c, domain.TaskEvent{
+		Type: domain.TaskDeleted,
+		Task: domain.Task{ID: id, WorkspaceID: workspace_id},
+	})
+	return deletedCount, nil
+}
+
+// publish sends the stored task, which has the fields the use case set. The
+// write has succeeded, so when the task cannot be read back the one written
+// is sent instead.
+func (p *publishingTaskUseCase) publish(c context.Context, event_type string, id string, written domain.Task) {
+	task := written
+	if stored, err := p.TaskUsecase.GetTasksById(c, id); err == nil && stored != nil {
+		task = *stored
+	} else {
+		task.ID = id
+		task.WorkspaceID, _ = domain.WorkspaceFrom(c)
+	}
+	p.events.Publish(c, domain.TaskEvent{Type: event_type, Task: task})
+}
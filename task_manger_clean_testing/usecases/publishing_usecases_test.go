@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PublishingTaskUseCaseTestSuite struct {
+	suite.Suite
+	next   *mocks.TaskUsecase
+	events *mocks.TaskEventBus
+	tasks  domain.TaskUsecase
+	ctx    context.Context
+}
+
+func (suite *PublishingTaskUseCaseTestSuite) SetupTest() {
+	suite.next = new(mocks.TaskUsecase)
+	suite.events = new(mocks.TaskEventBus)
+	suite.tasks = NewPublishingTaskUseCase(suite.next, suite.events)
+	suite.ctx = domain.WithWorkspace(context.Background(), "ws1")
+}
+
+func (suite *PublishingTaskUseCaseTestSuite) TestPublishesTheStoredTask() {
+	stored := &domain.Task{ID: "1", Title: "Task", Status: "done", WorkspaceID: "ws1"}
+	suite.next.On("UpdateTask", suite.ctx, "1", domain.Task{Title: "Task", Status: "done"}).Return(nil)
+	suite.next.On("GetTasksById", suite.ctx, "1").Return(stored, nil)
+	suite.events.On("Publish", suite.ctx, domain.TaskEvent{Type: domain.TaskUpdated, Task: *stored}).Return()
+
+	suite.NoError(suite.tasks.UpdateTask(suite.ctx, "1", domain.Task{Title: "Task", Status: "done"}))
+	suite.events.AssertExpectations(suite.T())
+}
+
+func (suite *PublishingTaskUseCaseTestSuite) TestPublishesTheWrittenTaskWhenItCannotBeRead() {
+	suite.next.On("AddTask", suite.ctx, domain.Task{ID: "1", Title: "Task"}).Return(nil)
+	suite.next.On("GetTasksById", suite.ctx, "1").Return(nil, errors.New("timeout"))
+	suite.events.On("Publish", suite.ctx, domain.TaskEvent{
+		Type: domain.TaskCreated,
+		Task: domain.Task{ID: "1", Title: "Task", WorkspaceID: "ws1"},
+	}).Return()
+
+	suite.NoError(suite.tasks.AddTask(suite.ctx, domain.Task{ID: "1", Title: "Task"}))
+	suite.events.AssertExpectations(suite.T())
+}
+
+func (suite *PublishingTaskUseCaseTestSuite) TestPublishesDeletions() {
+	suite.next.On("DeleteById", suite.ctx, "1").Return(int64(1), nil).Once()
+	suite.events.On("Publish", suite.ctx, domain.TaskEvent{
+		Type: domain.TaskDeleted,
+		Task: domain.Task{ID: "1", WorkspaceID: "ws1"},
+	}).Return()
+
+	deleted, err := suite.tasks.DeleteById(suite.ctx, "1")
+	suite.NoError(err)
+	suite.Equal(int64(1), deleted)
+	suite.events.AssertExpectations(suite.T())
+}
+
+func (suite *PublishingTaskUseCaseTestSuite) TestFailedWritesAreNotPublished() {
+	suite.next.On("AddTask", suite.ctx, mock.Anything).Return(errors.New("duplicate key"))
+	suite.next.On("DeleteById", suite.ctx, "2").Return(int64(0), nil)
+
+	suite.Error(suite.tasks.AddTask(suite.ctx, domain.Task{ID: "1"}))
+	deleted, err := suite.tasks.DeleteById(suite.ctx, "2")
+	suite.NoError(err)
+	suite.Zero(deleted)
+	suite.events.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything)
+}
+
+func TestPublishingTaskUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(PublishingTaskUseCaseTestSuite))
+}
@@ -0,0 +1,33 @@
+package usecases
+
+import (
+	"context"
+	"task_manger_clean_architecture/domain"
+	"time"
+)
+
+// defaultReportWindows are the time windows every task report covers.
+var defaultReportWindows = []domain.ReportWindow{
+	{Name: "7d", Duration: 7 * 24 * time.Hour},
+	{Name: "30d", Duration: 30 * 24 * time.Hour},
+	{Name: "90d", Duration: 90 * 24 * time.Hour},
+}
+
+type TaskReportUseCase struct {
+	reportRepository domain.TaskReportRepository
+	contextTimeout   time.Duration
+}
+
+func NewTaskReportUseCase(reportRepository domain.TaskReportRepository, timeout time.Duration) domain.TaskReportUsecase {
+	return &TaskReportUseCase{
+		reportRepository: reportRepository,
+		contextTimeout:   timeout,
+	}
+}
+
+// TaskReport implements domain.TaskReportUsecase.
+func (r *TaskReportUseCase) TaskReport(c context.Context) (*domain.TaskReport, error) {
+	ctx, cancel := withTimeout(c, r.contextTimeout)
+	defer cancel()
+	return r.reportRepository.TaskReport(ctx, time.Now(), defaultReportWindows)
+}
@@ -0,0 +1,40 @@
+package usecases
+
+import (
+	"context"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TaskReportUseCaseTestSuite struct {
+	suite.Suite
+	mockRepo          *mocks.TaskReportRepository
+	taskReportUseCase domain.TaskReportUsecase
+}
+
+func (suite *TaskReportUseCaseTestSuite) SetupTest() {
+	suite.mockRepo = new(mocks.TaskReportRepository)
+	suite.taskReportUseCase = NewTaskReportUseCase(suite.mockRepo, time.Second*2)
+}
+
+func (suite *TaskReportUseCaseTestSuite) TestTaskReport() {
+	report := &domain.TaskReport{Total: 3, ByStatus: map[string]int64{"pending": 3}}
+
+	suite.mockRepo.On("TaskReport", mock.Anything, mock.AnythingOfType("time.Time"), defaultReportWindows).Return(report, nil)
+
+	result, err := suite.taskReportUseCase.TaskReport(context.Background())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), report, result)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+}
+
+func TestTaskReportUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(TaskReportUseCaseTestSuite))
+}
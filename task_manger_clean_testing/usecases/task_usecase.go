@@ -4,17 +4,21 @@ import (
 	"context"
 	"task_manger_clean_architecture/domain"
 	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type TaskUseCase struct {
 	taskRepository domain.TaskRepository
+	taskSearcher   domain.TaskSearcher
 	contextTimeout time.Duration
 }
 
 
-func NewTaskUseCase(taskRepository domain.TaskRepository, timeout time.Duration) domain.TaskUsecase {
+func NewTaskUseCase(taskRepository domain.TaskRepository, taskSearcher domain.TaskSearcher, timeout time.Duration) domain.TaskUsecase {
 	return &TaskUseCase{
 		taskRepository: taskRepository,
+		taskSearcher:   taskSearcher,
 		contextTimeout: timeout,
 	}
 }
@@ -22,23 +26,35 @@ func NewTaskUseCase(taskRepository domain.TaskRepository, timeout time.Duration)
 
 // AddTask implements domain.TaskUsecase.
 func (t *TaskUseCase) AddTask(c context.Context, newTask domain.Task) error {
-	ctx, cancel := context.WithTimeout(c, t.contextTimeout)
+	ctx, cancel := withTimeout(c, t.contextTimeout)
 	defer cancel()
-	return t.taskRepository.AddTask(ctx, newTask)
+	newTask.CreatedAt = time.Now()
+	newTask.CompletedAt = nil
+	if domain.IsTaskCompleted(newTask.Status) {
+		newTask.CompletedAt = &newTask.CreatedAt
+	}
+	if err := t.taskRepository.AddTask(ctx, newTask); err != nil {
+		return err
+	}
+	return t.taskSearcher.IndexTask(ctx, newTask)
 }
 
 
 // DeleteById implements domain.TaskUsecase.
 func (t *TaskUseCase) DeleteById(c context.Context, user_id string) (int64, error) {
-	ctx, cancel := context.WithTimeout(c, t.contextTimeout)
+	ctx, cancel := withTimeout(c, t.contextTimeout)
 	defer cancel()
-	return t.taskRepository.DeleteById(ctx, user_id)
+	deletedCount, err := t.taskRepository.DeleteById(ctx, user_id)
+	if err != nil || deletedCount == 0 {
+		return deletedCount, err
+	}
+	return deletedCount, t.taskSearcher.RemoveTask(ctx, user_id)
 }
 
 
 // GetTasks implements domain.TaskUsecase.
 func (t *TaskUseCase) GetTasks(c context.Context) ([]*domain.Task, error) {
-	ctx, cancel := context.WithTimeout(c, t.contextTimeout)
+	ctx, cancel := withTimeout(c, t.contextTimeout)
 	defer cancel()
 	return t.taskRepository.GetTasks(ctx)
 }
@@ -46,7 +62,7 @@ func (t *TaskUseCase) GetTasks(c context.Context) ([]*domain.Task, error) {
 
 // GetTasksById implements domain.TaskUsecase.
 func (t *TaskUseCase) GetTasksById(c context.Context, user_id string) (*domain.Task, error) {
-	ctx, cancel := context.WithTimeout(c, t.contextTimeout)
+	ctx, cancel := withTimeout(c, t.contextTimeout)
 	defer cancel()
 	return t.taskRepository.GetTasksById(ctx, user_id )
 }
@@ -54,8 +70,82 @@ func (t *TaskUseCase) GetTasksById(c context.Context, user_id string) (*domain.T
 
 // UpdateTask implements domain.TaskUsecase.
 func (t *TaskUseCase) UpdateTask(c context.Context, user_id string, updatedTask domain.Task) error {
-	ctx, cancel := context.WithTimeout(c, t.contextTimeout)
+	ctx, cancel := withTimeout(c, t.contextTimeout)
+	defer cancel()
+	existing, err := t.taskRepository.GetTasksById(ctx, user_id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return mongo.ErrNoDocuments
+	}
+
+	// The creator and creation time never change; the completion time is set
+	// when a task first moves to a completed status and cleared if it reopens.
+	updatedTask.CompletedAt = nil
+	updatedTask.CreatedBy = existing.CreatedBy
+	updatedTask.CreatedAt = existing.CreatedAt
+	if domain.IsTaskCompleted(updatedTask.Status) {
+		if existing.CompletedAt != nil && domain.IsTaskCompleted(existing.Status) {
+			updatedTask.CompletedAt = existing.CompletedAt
+		} else {
+			now := time.Now()
+			updatedTask.CompletedAt = &now
+		}
+	}
+	if err := t.taskRepository.UpdateTask(ctx,user_id,updatedTask); err != nil {
+		return err
+	}
+	updatedTask.ID = user_id
+	return t.taskSearcher.IndexTask(ctx, updatedTask)
+}
+
+
+// SearchTasks implements domain.TaskUsecase.
+func (t *TaskUseCase) SearchTasks(c context.Context, query string, limit int) ([]*domain.TaskSearchResult, error) {
+	ctx, cancel := withTimeout(c, t.contextTimeout)
+	defer cancel()
+	return t.taskSearcher.SearchTasks(ctx, query, limit)
+}
+
+
+// ReindexTasks implements domain.TaskUsecase. It feeds every stored task to
+// the searcher, e.g. to populate a new index from an existing collection, and
+// removes the indexed tasks that are no longer stored.
+func (t *TaskUseCase) ReindexTasks(c context.Context) (int, error) {
+	ctx, cancel := withTimeout(c, t.contextTimeout)
 	defer cancel()
-	return t.taskRepository.UpdateTask(ctx,user_id,updatedTask)
+	tasks, err := t.taskRepository.GetTasks(ctx)
+	if err != nil {
+		return 0, err
+	}
+	stored := make(map[string]bool, len(tasks))
+	for i, task := range tasks {
+		if err := t.taskSearcher.IndexTask(ctx, *task); err != nil {
+			return i, err
+		}
+		stored[task.ID] = true
+	}
+
+	indexed, err := t.taskSearcher.IndexedTaskIDs(ctx)
+	if err != nil {
+		return len(tasks), err
+	}
+	for _, id := range indexed {
+		if stored[id] {
+			continue
+		}
+		// Look again, the task may have been added since the listing
+		task, err := t.taskRepository.GetTasksById(ctx, id)
+		if err != nil {
+			return len(tasks), err
+		}
+		if task == nil {
+			if err := t.taskSearcher.RemoveTask(ctx, id); err != nil {
+				return len(tasks), err
+			}
+		}
+	}
+	return len(tasks), nil
 }
 
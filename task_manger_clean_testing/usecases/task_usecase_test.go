@@ -10,17 +10,20 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type TaskUseCaseTestSuite struct {
 	suite.Suite
-	mockRepo    *mocks.TaskRepository
-	taskUseCase domain.TaskUsecase
+	mockRepo     *mocks.TaskRepository
+	mockSearcher *mocks.TaskSearcher
+	taskUseCase  domain.TaskUsecase
 }
 
-func (suite *TaskUseCaseTestSuite) SetupSuite() {
+func (suite *TaskUseCaseTestSuite) SetupTest() {
 	suite.mockRepo = new(mocks.TaskRepository)
-	suite.taskUseCase = NewTaskUseCase(suite.mockRepo, time.Second*2)
+	suite.mockSearcher = new(mocks.TaskSearcher)
+	suite.taskUseCase = NewTaskUseCase(suite.mockRepo, suite.mockSearcher, time.Second*2)
 }
 
 func (suite *TaskUseCaseTestSuite) TestAddTask() {
@@ -32,24 +35,32 @@ func (suite *TaskUseCaseTestSuite) TestAddTask() {
 		Status:      "Pending",
 	}
 
-	suite.mockRepo.On("AddTask", mock.Anything, task).Return(nil)
+	// The use case stamps the creation time before storing the task
+	stamped := mock.MatchedBy(func(t domain.Task) bool {
+		return t.ID == task.ID && !t.CreatedAt.IsZero() && t.CompletedAt == nil
+	})
+	suite.mockRepo.On("AddTask", mock.Anything, stamped).Return(nil)
+	suite.mockSearcher.On("IndexTask", mock.Anything, stamped).Return(nil)
 
 	err := suite.taskUseCase.AddTask(context.Background(), task)
 	assert.NoError(suite.T(), err)
 
 	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockSearcher.AssertExpectations(suite.T())
 }
 
 func (suite *TaskUseCaseTestSuite) TestDeleteById() {
 	id := "1"
 
 	suite.mockRepo.On("DeleteById", mock.Anything, id).Return(int64(1), nil)
+	suite.mockSearcher.On("RemoveTask", mock.Anything, id).Return(nil)
 
 	count, err := suite.taskUseCase.DeleteById(context.Background(), id)
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), int64(1), count)
 
 	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockSearcher.AssertExpectations(suite.T())
 }
 
 func (suite *TaskUseCaseTestSuite) TestGetTasks() {
@@ -108,12 +119,97 @@ func (suite *TaskUseCaseTestSuite) TestUpdateTask() {
 		Status:      "In Progress",
 	}
 
-	suite.mockRepo.On("UpdateTask", mock.Anything, id, updatedTask).Return(nil)
+	existing := &domain.Task{
+		ID:        id,
+		Title:     "Original Task",
+		Status:    "Pending",
+		CreatedBy: "demoid",
+		CreatedAt: time.Now().Add(-time.Hour),
+	}
+
+	// The creator and creation time are carried over from the stored task
+	stored := updatedTask
+	stored.CreatedBy = existing.CreatedBy
+	stored.CreatedAt = existing.CreatedAt
+
+	suite.mockRepo.On("GetTasksById", mock.Anything, id).Return(existing, nil)
+	suite.mockRepo.On("UpdateTask", mock.Anything, id, stored).Return(nil)
+	suite.mockSearcher.On("IndexTask", mock.Anything, stored).Return(nil)
+
+	err := suite.taskUseCase.UpdateTask(context.Background(), id, updatedTask)
+	assert.NoError(suite.T(), err)
+
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockSearcher.AssertExpectations(suite.T())
+}
+
+func (suite *TaskUseCaseTestSuite) TestUpdateTaskSetsCompletedAt() {
+	id := "1"
+	existing := &domain.Task{ID: id, Title: "Task", Status: "Pending", CreatedAt: time.Now().Add(-time.Hour)}
+	updatedTask := domain.Task{ID: id, Title: "Task", Status: "Completed"}
+
+	completed := mock.MatchedBy(func(t domain.Task) bool {
+		return t.CompletedAt != nil && t.CompletedAt.After(existing.CreatedAt)
+	})
+	suite.mockRepo.On("GetTasksById", mock.Anything, id).Return(existing, nil)
+	suite.mockRepo.On("UpdateTask", mock.Anything, id, completed).Return(nil)
+	suite.mockSearcher.On("IndexTask", mock.Anything, completed).Return(nil)
 
 	err := suite.taskUseCase.UpdateTask(context.Background(), id, updatedTask)
 	assert.NoError(suite.T(), err)
 
 	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockSearcher.AssertExpectations(suite.T())
+}
+
+func (suite *TaskUseCaseTestSuite) TestUpdateMissingTask() {
+	suite.mockRepo.On("GetTasksById", mock.Anything, "404").Return(nil, nil)
+
+	err := suite.taskUseCase.UpdateTask(context.Background(), "404", domain.Task{Title: "Task"})
+	assert.ErrorIs(suite.T(), err, mongo.ErrNoDocuments)
+
+	// Nothing is written, and above all no search document for a task that
+	// does not exist
+	suite.mockRepo.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything)
+	suite.mockSearcher.AssertNotCalled(suite.T(), "IndexTask", mock.Anything, mock.Anything)
+}
+
+func (suite *TaskUseCaseTestSuite) TestReindexRemovesTasksThatAreGone() {
+	tasks := []*domain.Task{{ID: "1", Title: "Task 1"}, {ID: "2", Title: "Task 2"}}
+	suite.mockRepo.On("GetTasks", mock.Anything).Return(tasks, nil)
+	suite.mockSearcher.On("IndexTask", mock.Anything, *tasks[0]).Return(nil)
+	suite.mockSearcher.On("IndexTask", mock.Anything, *tasks[1]).Return(nil)
+	suite.mockSearcher.On("IndexedTaskIDs", mock.Anything).Return([]string{"1", "2", "gone", "new"}, nil)
+	suite.mockRepo.On("GetTasksById", mock.Anything, "gone").Return(nil, nil)
+	suite.mockRepo.On("GetTasksById", mock.Anything, "new").Return(&domain.Task{ID: "new"}, nil)
+	suite.mockSearcher.On("RemoveTask", mock.Anything, "gone").Return(nil).Once()
+
+	indexed, err := suite.taskUseCase.ReindexTasks(context.Background())
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, indexed)
+
+	// A task added since the listing stays indexed
+	suite.mockSearcher.AssertNotCalled(suite.T(), "RemoveTask", mock.Anything, "new")
+	suite.mockRepo.AssertExpectations(suite.T())
+	suite.mockSearcher.AssertExpectations(suite.T())
+}
+
+func (suite *TaskUseCaseTestSuite) TestSearchTasks() {
+	results := []*domain.TaskSearchResult{
+		{
+			Task:       &domain.Task{ID: "1", Title: "Write report"},
+			Score:      3,
+			Highlights: map[string]string{"title": "Write <mark>report</mark>"},
+		},
+	}
+
+	suite.mockSearcher.On("SearchTasks", mock.Anything, "report", 20).Return(results, nil)
+
+	result, err := suite.taskUseCase.SearchTasks(context.Background(), "report", 20)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), results, result)
+
+	suite.mockSearcher.AssertExpectations(suite.T())
 }
 
 func TestTaskUseCaseTestSuite(t *testing.T) {
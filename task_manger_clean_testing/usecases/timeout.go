@@ -0,0 +1,17 @@
+package usecases
+
+import (
+	"context"
+	"time"
+)
+
+// withTimeout bounds c by timeout unless its caller has set a deadline
+// already, such as the budget of an HTTP request. A request then has one
+// deadline however many use cases it goes through, and callers without
+// one, such as jobs, are still bounded.
+func withTimeout(c context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := c.Deadline(); ok {
+		return context.WithCancel(c)
+	}
+	return context.WithTimeout(c, timeout)
+}
@@ -0,0 +1,29 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTimeoutKeepsCallerDeadline(t *testing.T) {
+	parent, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	want, _ := parent.Deadline()
+
+	ctx, cancelChild := withTimeout(parent, time.Second)
+	defer cancelChild()
+	got, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, want, got)
+}
+
+func TestWithTimeoutBoundsCallerWithoutDeadline(t *testing.T) {
+	ctx, cancel := withTimeout(context.Background(), time.Second)
+	defer cancel()
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 100*time.Millisecond)
+}
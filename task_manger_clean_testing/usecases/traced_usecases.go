@@ -0,0 +1,176 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"task_manger_clean_architecture/domain"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("task_manger_clean_architecture/usecases")
+
+// endSpan ends span, marking it failed when err is set. Finding nothing is
+// not a failure of the call.
+func endSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracedTaskUseCase wraps every call of a task use case in a span. The calls
+// below get the span's context, so the repository spans nest under it.
+type tracedTaskUseCase struct {
+	next domain.TaskUsecase
+}
+
+func NewTracedTaskUseCase(taskUseCase domain.TaskUsecase) domain.TaskUsecase {
+	return &tracedTaskUseCase{next: taskUseCase}
+}
+
+// GetTasks implements domain.TaskUsecase.
+func (t *tracedTaskUseCase) GetTasks(c context.Context) (tasks []*domain.Task, err error) {
+	c, span := tracer.Start(c, "TaskUseCase.GetTasks")
+	defer func() { endSpan(span, err) }()
+	return t.next.GetTasks(c)
+}
+
+// GetTasksById implements domain.TaskUsecase.
+func (t *tracedTaskUseCase) GetTasksById(c context.Context, id string) (task *domain.Task, err error) {
+	c, span := tracer.Start(c, "TaskUseCase.GetTasksById")
+	defer func() { endSpan(span, err) }()
+	return t.next.GetTasksById(c, id)
+}
+
+// DeleteById implements domain.TaskUsecase.
+func (t *tracedTaskUseCase) DeleteById(c context.Context, id string) (deleted int64, err error) {
+	c, span := tracer.Start(c, "TaskUseCase.DeleteById")
+	defer func() { endSpan(span, err) }()
+	return t.next.DeleteById(c, id)
+}
+
+// UpdateTask implements domain.TaskUsecase.
+func (t *tracedTaskUseCase) UpdateTask(c context.Context, id string, updatedTask domain.Task) (err error) {
+	c, span := tracer.Start(c, "TaskUseCase.UpdateTask")
+	defer func() { endSpan(span, err) }()
+	return t.next.UpdateTask(c, id, updatedTask)
+}
+
+// AddTask implements domain.TaskUsecase.
+func (t *tracedTaskUseCase) AddTask(c context.Context, newTask domain.Task) (err error) {
+	c, span := tracer.Start(c, "TaskUseCase.AddTask")
+	defer func() { endSpan(span, err) }()
+	return t.next.AddTask(c, newTask)
+}
+
+// SearchTasks implements domain.TaskUsecase.
+func (t *tracedTaskUseCase) SearchTasks(c context.Context, query string, limit int) (results []*domain.TaskSearchResult, err error) {
+	c, span := tracer.Start(c, "TaskUseCase.SearchTasks")
+	defer func() { endSpan(span, err) }()
+	return t.next.SearchTasks(c, query, limit)
+}
+
+// ReindexTasks implements domain.TaskUsecase.
+func (t *tracedTaskUseCase) ReindexTasks(c context.Context) (indexed int, err error) {
+	c, span := tracer.Start(c, "TaskUseCase.ReindexTasks")
+	defer func() { endSpan(span, err) }()
+	return t.next.ReindexTasks(c)
+}
+
+// tracedUserUseCase wraps every call of a user use case in a span.
+type tracedUserUseCase struct {
+	next domain.UserUseCase
+}
+
+func NewTracedUserUseCase(userUseCase domain.UserUseCase) domain.UserUseCase {
+	return &tracedUserUseCase{next: userUseCase}
+}
+
+// Signup implements domain.UserUseCase.
+func (u *tracedUserUseCase) Signup(c context.Context, user domain.User) (result interface{}, err error) {
+	c, span := tracer.Start(c, "UserUseCase.Signup")
+	defer func() { endSpan(span, err) }()
+	return u.next.Signup(c, user)
+}
+
+// Login implements domain.UserUseCase.
+func (u *tracedUserUseCase) Login(c context.Context, email string) (user *domain.User, err error) {
+	c, span := tracer.Start(c, "UserUseCase.Login")
+	defer func() { endSpan(span, err) }()
+	return u.next.Login(c, email)
+}
+
+// GetUsers implements domain.UserUseCase.
+func (u *tracedUserUseCase) GetUsers(c context.Context, startIndex int64, recordsPerPage int64) (users []*domain.User, err error) {
+	c, span := tracer.Start(c, "UserUseCase.GetUsers")
+	defer func() { endSpan(span, err) }()
+	return u.next.GetUsers(c, startIndex, recordsPerPage)
+}
+
+// GetUser implements domain.UserUseCase.
+func (u *tracedUserUseCase) GetUser(c context.Context, user_id string) (user domain.User, err error) {
+	c, span := tracer.Start(c, "UserUseCase.GetUser")
+	defer func() { endSpan(span, err) }()
+	return u.next.GetUser(c, user_id)
+}
+
+// GetMember implements domain.UserUseCase.
+func (u *tracedUserUseCase) GetMember(c context.Context, user_id string) (user domain.User, err error) {
+	c, span := tracer.Start(c, "UserUseCase.GetMember")
+	defer func() { endSpan(span, err) }()
+	return u.next.GetMember(c, user_id)
+}
+
+// GetMembers implements domain.UserUseCase.
+func (u *tracedUserUseCase) GetMembers(c context.Context, user_ids []string) (users []domain.User, err error) {
+	c, span := tracer.Start(c, "UserUseCase.GetMembers")
+	defer func() { endSpan(span, err) }()
+	return u.next.GetMembers(c, user_ids)
+}
+
+// Promote implements domain.UserUseCase.
+func (u *tracedUserUseCase) Promote(c context.Context, user_id string, userType string) (err error, matchedCount int64, modifiedCount int64) {
+	c, span := tracer.Start(c, "UserUseCase.Promote")
+	defer func() { endSpan(span, err) }()
+	return u.next.Promote(c, user_id, userType)
+}
+
+// UpdateAllTokens implements domain.UserUseCase.
+func (u *tracedUserUseCase) UpdateAllTokens(c context.Context, token string, refreshToken string, user_id string) (err error) {
+	c, span := tracer.Start(c, "UserUseCase.UpdateAllTokens")
+	defer func() { endSpan(span, err) }()
+	return u.next.UpdateAllTokens(c, token, refreshToken, user_id)
+}
+
+// GetUserByEmail implements domain.UserUseCase.
+func (u *tracedUserUseCase) GetUserByEmail(c context.Context, email string) (user domain.User, err error) {
+	c, span := tracer.Start(c, "UserUseCase.GetUserByEmail")
+	defer func() { endSpan(span, err) }()
+	return u.next.GetUserByEmail(c, email)
+}
+
+// UpdateProfile implements domain.UserUseCase.
+func (u *tracedUserUseCase) UpdateProfile(c context.Context, user_id string, role string, update domain.ProfileUpdate) (user domain.User, err error) {
+	c, span := tracer.Start(c, "UserUseCase.UpdateProfile")
+	defer func() { endSpan(span, err) }()
+	return u.next.UpdateProfile(c, user_id, role, update)
+}
+
+// ChangePassword implements domain.UserUseCase.
+func (u *tracedUserUseCase) ChangePassword(c context.Context, user_id string, password string) (err error) {
+	c, span := tracer.Start(c, "UserUseCase.ChangePassword")
+	defer func() { endSpan(span, err) }()
+	return u.next.ChangePassword(c, user_id, password)
+}
+
+// UpgradePasswordHash implements domain.UserUseCase.
+func (u *tracedUserUseCase) UpgradePasswordHash(c context.Context, user_id string, password string) (err error) {
+	c, span := tracer.Start(c, "UserUseCase.UpgradePasswordHash")
+	defer func() { endSpan(span, err) }()
+	return u.next.UpgradePasswordHash(c, user_id, password)
+}
@@ -0,0 +1,92 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TracedUseCaseTestSuite struct {
+	suite.Suite
+	recorder        *tracetest.SpanRecorder
+	earlier         int
+	mockTaskUseCase *mocks.TaskUsecase
+	mockUserUseCase *mocks.UserUseCase
+	tasks           domain.TaskUsecase
+	users           domain.UserUseCase
+}
+
+// SetupSuite installs the recording tracer provider once: the tracer of the
+// package keeps the first provider set.
+func (suite *TracedUseCaseTestSuite) SetupSuite() {
+	suite.recorder = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(suite.recorder)))
+}
+
+func (suite *TracedUseCaseTestSuite) SetupTest() {
+	suite.earlier = len(suite.recorder.Ended())
+	suite.mockTaskUseCase = new(mocks.TaskUsecase)
+	suite.mockUserUseCase = new(mocks.UserUseCase)
+	suite.tasks = NewTracedTaskUseCase(suite.mockTaskUseCase)
+	suite.users = NewTracedUserUseCase(suite.mockUserUseCase)
+}
+
+// ended returns the spans the test ended.
+func (suite *TracedUseCaseTestSuite) ended() []sdktrace.ReadOnlySpan {
+	return suite.recorder.Ended()[suite.earlier:]
+}
+
+func (suite *TracedUseCaseTestSuite) TestSpanContinuesRequestTrace() {
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "GET /task/:task_id")
+	var inner trace.SpanContext
+	suite.mockTaskUseCase.On("GetTasksById", mock.Anything, "1").
+		Run(func(args mock.Arguments) { inner = trace.SpanContextFromContext(args.Get(0).(context.Context)) }).
+		Return(&domain.Task{ID: "1"}, nil).Once()
+
+	_, err := suite.tasks.GetTasksById(ctx, "1")
+	parent.End()
+	suite.Require().NoError(err)
+
+	spans := suite.ended()
+	suite.Require().Len(spans, 2)
+	assert.Equal(suite.T(), "TaskUseCase.GetTasksById", spans[0].Name())
+	assert.Equal(suite.T(), parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	// The use case passes its own span on, so the repository spans nest under it
+	assert.Equal(suite.T(), spans[0].SpanContext().SpanID(), inner.SpanID())
+}
+
+func (suite *TracedUseCaseTestSuite) TestSpanRecordsError() {
+	suite.mockTaskUseCase.On("AddTask", mock.Anything, mock.Anything).Return(errors.New("timeout")).Once()
+
+	suite.Error(suite.tasks.AddTask(context.Background(), domain.Task{}))
+	spans := suite.ended()
+	suite.Require().Len(spans, 1)
+	assert.Equal(suite.T(), codes.Error, spans[0].Status().Code)
+}
+
+func (suite *TracedUseCaseTestSuite) TestNotFoundIsNoError() {
+	suite.mockUserUseCase.On("GetUserByEmail", mock.Anything, "a@example.com").Return(domain.User{}, mongo.ErrNoDocuments).Once()
+
+	_, err := suite.users.GetUserByEmail(context.Background(), "a@example.com")
+	suite.ErrorIs(err, mongo.ErrNoDocuments)
+	spans := suite.ended()
+	suite.Require().Len(spans, 1)
+	assert.Equal(suite.T(), "UserUseCase.GetUserByEmail", spans[0].Name())
+	assert.Equal(suite.T(), codes.Unset, spans[0].Status().Code)
+}
+
+func TestTracedUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(TracedUseCaseTestSuite))
+}
@@ -0,0 +1,211 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"log/slog"
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"time"
+)
+
+const (
+	loginChallengeTTL  = 5 * time.Minute
+	recoveryCodeCount  = 10
+	recoveryCodeLength = 10
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type TwoFactorUseCase struct {
+	userRepository  domain.UserRepository
+	tokenRepository domain.UserTokenRepository
+	totp            domain.TOTP
+	audit           domain.AuditUsecase
+	logger          *slog.Logger
+	contextTimeout  time.Duration
+	now             func() time.Time
+}
+
+func NewTwoFactorUseCase(userRepository domain.UserRepository, tokenRepository domain.UserTokenRepository, totp domain.TOTP, audit domain.AuditUsecase, logger *slog.Logger, timeout time.Duration) domain.TwoFactorUsecase {
+	return &TwoFactorUseCase{
+		userRepository:  userRepository,
+		tokenRepository: tokenRepository,
+		totp:            totp,
+		audit:           audit,
+		logger:          logger,
+		contextTimeout:  timeout,
+		now:             time.Now,
+	}
+}
+
+// normalizeRecoveryCode lets users type a recovery code in any case, with or
+// without the dash it is shown with.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	raw := make([]byte, recoveryCodeLength*5/8)
+	for i := 0; i < recoveryCodeCount; i++ {
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+		code := recoveryCodeEncoding.EncodeToString(raw)
+		codes = append(codes, code[:recoveryCodeLength/2]+"-"+code[recoveryCodeLength/2:])
+		hashes = append(hashes, hashUserToken(code))
+	}
+	return codes, hashes, nil
+}
+
+// Enroll implements domain.TwoFactorUsecase. An enabled factor has to be
+// disabled first, so a stolen session cannot swap it for its own.
+func (t *TwoFactorUseCase) Enroll(c context.Context, user_id string) (*domain.TwoFactorEnrollment, error) {
+	ctx, cancel := withTimeout(c, t.contextTimeout)
+	defer cancel()
+
+	user, err := t.userRepository.GetUser(ctx, user_id)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactor.Enabled {
+		return nil, domain.ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := t.totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	settings := domain.TwoFactorSettings{Secret: secret, RecoveryCodes: hashes}
+	if err := t.userRepository.SetTwoFactor(ctx, user_id, settings); err != nil {
+		return nil, err
+	}
+
+	account := user_id
+	if user.Email != nil {
+		account = *user.Email
+	}
+	return &domain.TwoFactorEnrollment{
+		Secret:        secret,
+		URI:           t.totp.URI(secret, account),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// Confirm implements domain.TwoFactorUsecase. Only an authenticator code is
+// accepted, which proves the secret was saved.
+func (t *TwoFactorUseCase) Confirm(c context.Context, user_id string, code string) error {
+	ctx, cancel := withTimeout(c, t.contextTimeout)
+	defer cancel()
+
+	user, err := t.userRepository.GetUser(ctx, user_id)
+	if err != nil {
+		return err
+	}
+	if user.TwoFactor.Enabled {
+		return domain.ErrTwoFactorAlreadyEnabled
+	}
+	if user.TwoFactor.Secret == "" {
+		return domain.ErrTwoFactorNotEnrolled
+	}
+	step, ok := t.totp.Validate(user.TwoFactor.Secret, code, t.now())
+	if !ok {
+		return domain.ErrInvalidTwoFactorCode
+	}
+
+	settings := user.TwoFactor
+	settings.Enabled = true
+	settings.LastStep = step
+	if err := t.userRepository.SetTwoFactor(ctx, user_id, settings); err != nil {
+		return err
+	}
+	recordWrite(ctx, t.audit, t.logger, domain.AuditUserTwoFactorEnable, user_id, nil, nil)
+	return nil
+}
+
+// Disable implements domain.TwoFactorUsecase.
+func (t *TwoFactorUseCase) Disable(c context.Context, user_id string, code string) error {
+	ctx, cancel := withTimeout(c, t.contextTimeout)
+	defer cancel()
+
+	user, err := t.userRepository.GetUser(ctx, user_id)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactor.Enabled {
+		return domain.ErrTwoFactorNotEnabled
+	}
+	// An administrator of any workspace needs the second factor there
+	if user.HasRole("ADMIN") {
+		return domain.ErrTwoFactorRequired
+	}
+	if err := t.verifyCode(ctx, user, code); err != nil {
+		return err
+	}
+
+	if err := t.userRepository.SetTwoFactor(ctx, user_id, domain.TwoFactorSettings{}); err != nil {
+		return err
+	}
+	recordWrite(ctx, t.audit, t.logger, domain.AuditUserTwoFactorDisable, user_id, nil, nil)
+	return nil
+}
+
+// verifyCode accepts a current authenticator code that was not used before,
+// or an unused recovery code, which is used up.
+func (t *TwoFactorUseCase) verifyCode(c context.Context, user domain.User, code string) error {
+	code = strings.TrimSpace(code)
+	if step, ok := t.totp.Validate(user.TwoFactor.Secret, code, t.now()); ok {
+		fresh, err := t.userRepository.UseTOTPStep(c, user.UserId, step)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return domain.ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	used, err := t.userRepository.UseRecoveryCode(c, user.UserId, hashUserToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return domain.ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// IssueChallenge implements domain.TwoFactorUsecase.
+func (t *TwoFactorUseCase) IssueChallenge(c context.Context, user_id string) (string, error) {
+	ctx, cancel := withTimeout(c, t.contextTimeout)
+	defer cancel()
+	return issueUserToken(ctx, t.tokenRepository, user_id, domain.UserTokenLoginChallenge, t.now(), loginChallengeTTL)
+}
+
+// CompleteChallenge implements domain.TwoFactorUsecase. The user is returned
+// along with ErrInvalidTwoFactorCode, so the failure can be counted against
+// the account.
+func (t *TwoFactorUseCase) CompleteChallenge(c context.Context, challenge string, code string) (string, error) {
+	ctx, cancel := withTimeout(c, t.contextTimeout)
+	defer cancel()
+
+	token, err := t.tokenRepository.Consume(ctx, hashUserToken(challenge), domain.UserTokenLoginChallenge, t.now())
+	if err != nil {
+		return "", err
+	}
+	user, err := t.userRepository.GetUser(ctx, token.UserID)
+	if err != nil {
+		return "", err
+	}
+	if !user.TwoFactor.Enabled {
+		return "", domain.ErrTwoFactorNotEnabled
+	}
+	return user.UserId, t.verifyCode(ctx, user, code)
+}
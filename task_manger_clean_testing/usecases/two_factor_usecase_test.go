@@ -0,0 +1,158 @@
+package usecases
+
+import (
+	"context"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type TwoFactorUseCaseTestSuite struct {
+	suite.Suite
+	mockUserRepo  *mocks.UserRepository
+	mockTokenRepo *mocks.UserTokenRepository
+	mockTOTP      *mocks.TOTP
+	mockAudit     *mocks.AuditUsecase
+	now           time.Time
+	twoFactor     *TwoFactorUseCase
+}
+
+func (suite *TwoFactorUseCaseTestSuite) SetupTest() {
+	suite.mockUserRepo = new(mocks.UserRepository)
+	suite.mockTokenRepo = new(mocks.UserTokenRepository)
+	suite.mockTOTP = new(mocks.TOTP)
+	suite.mockAudit = new(mocks.AuditUsecase)
+	suite.now = time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+	suite.twoFactor = NewTwoFactorUseCase(suite.mockUserRepo, suite.mockTokenRepo, suite.mockTOTP, suite.mockAudit, discardLogger, time.Second*2).(*TwoFactorUseCase)
+	suite.twoFactor.now = func() time.Time { return suite.now }
+}
+
+func (suite *TwoFactorUseCaseTestSuite) enabledUser(userType string) domain.User {
+	return domain.User{
+		UserId:   "1",
+		UserType: stringPtr(userType),
+		Workspaces: []domain.WorkspaceMembership{{WorkspaceID: domain.DefaultWorkspaceID, Role: userType}},
+		TwoFactor: domain.TwoFactorSettings{
+			Enabled:       true,
+			Secret:        "SECRET",
+			RecoveryCodes: []string{hashUserToken("ABCDEFGHIJ")},
+		},
+	}
+}
+
+func (suite *TwoFactorUseCaseTestSuite) TestEnrollStoresHashedRecoveryCodes() {
+	email := "a@example.com"
+	var stored domain.TwoFactorSettings
+	suite.mockUserRepo.On("GetUser", mock.Anything, "1").Return(domain.User{UserId: "1", Email: &email}, nil)
+	suite.mockTOTP.On("GenerateSecret").Return("SECRET", nil)
+	suite.mockTOTP.On("URI", "SECRET", email).Return("otpauth://totp/x")
+	suite.mockUserRepo.On("SetTwoFactor", mock.Anything, "1", mock.AnythingOfType("domain.TwoFactorSettings")).
+		Run(func(args mock.Arguments) { stored = args.Get(2).(domain.TwoFactorSettings) }).Return(nil)
+
+	enrollment, err := suite.twoFactor.Enroll(context.Background(), "1")
+	suite.Require().NoError(err)
+
+	assert.Equal(suite.T(), "otpauth://totp/x", enrollment.URI)
+	assert.False(suite.T(), stored.Enabled)
+	assert.Equal(suite.T(), "SECRET", stored.Secret)
+	suite.Require().Len(enrollment.RecoveryCodes, recoveryCodeCount)
+	suite.Require().Len(stored.RecoveryCodes, recoveryCodeCount)
+	for i, code := range enrollment.RecoveryCodes {
+		assert.Equal(suite.T(), hashUserToken(normalizeRecoveryCode(code)), stored.RecoveryCodes[i])
+	}
+}
+
+func (suite *TwoFactorUseCaseTestSuite) TestEnrollRefusesWhenEnabled() {
+	suite.mockUserRepo.On("GetUser", mock.Anything, "1").Return(suite.enabledUser("USER"), nil)
+
+	_, err := suite.twoFactor.Enroll(context.Background(), "1")
+	assert.ErrorIs(suite.T(), err, domain.ErrTwoFactorAlreadyEnabled)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "SetTwoFactor", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TwoFactorUseCaseTestSuite) TestConfirm() {
+	pending := domain.User{UserId: "1", TwoFactor: domain.TwoFactorSettings{Secret: "SECRET"}}
+	suite.mockUserRepo.On("GetUser", mock.Anything, "1").Return(pending, nil)
+	suite.mockTOTP.On("Validate", "SECRET", "123456", suite.now).Return(int64(42), true)
+	suite.mockUserRepo.On("SetTwoFactor", mock.Anything, "1", domain.TwoFactorSettings{Enabled: true, Secret: "SECRET", LastStep: 42}).Return(nil).Once()
+	suite.mockAudit.On("Record", mock.Anything, domain.AuditUserTwoFactorEnable, "1", nil, nil).Return(nil).Once()
+
+	assert.NoError(suite.T(), suite.twoFactor.Confirm(context.Background(), "1", "123456"))
+	suite.mockUserRepo.AssertExpectations(suite.T())
+	suite.mockAudit.AssertExpectations(suite.T())
+}
+
+func (suite *TwoFactorUseCaseTestSuite) TestConfirmRejectsWrongCode() {
+	pending := domain.User{UserId: "1", TwoFactor: domain.TwoFactorSettings{Secret: "SECRET"}}
+	suite.mockUserRepo.On("GetUser", mock.Anything, "1").Return(pending, nil)
+	suite.mockTOTP.On("Validate", "SECRET", "000000", suite.now).Return(int64(0), false)
+
+	err := suite.twoFactor.Confirm(context.Background(), "1", "000000")
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidTwoFactorCode)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "SetTwoFactor", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TwoFactorUseCaseTestSuite) TestDisableWithRecoveryCode() {
+	suite.mockUserRepo.On("GetUser", mock.Anything, "1").Return(suite.enabledUser("USER"), nil)
+	suite.mockTOTP.On("Validate", "SECRET", "abcde-fghij", suite.now).Return(int64(0), false)
+	suite.mockUserRepo.On("UseRecoveryCode", mock.Anything, "1", hashUserToken("ABCDEFGHIJ")).Return(true, nil).Once()
+	suite.mockUserRepo.On("SetTwoFactor", mock.Anything, "1", domain.TwoFactorSettings{}).Return(nil).Once()
+	suite.mockAudit.On("Record", mock.Anything, domain.AuditUserTwoFactorDisable, "1", nil, nil).Return(nil).Once()
+
+	assert.NoError(suite.T(), suite.twoFactor.Disable(context.Background(), "1", "abcde-fghij"))
+	suite.mockUserRepo.AssertExpectations(suite.T())
+	suite.mockAudit.AssertExpectations(suite.T())
+}
+
+func (suite *TwoFactorUseCaseTestSuite) TestAdminCannotDisable() {
+	suite.mockUserRepo.On("GetUser", mock.Anything, "1").Return(suite.enabledUser("ADMIN"), nil)
+
+	err := suite.twoFactor.Disable(context.Background(), "1", "123456")
+	assert.ErrorIs(suite.T(), err, domain.ErrTwoFactorRequired)
+	suite.mockUserRepo.AssertNotCalled(suite.T(), "SetTwoFactor", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TwoFactorUseCaseTestSuite) TestCompleteChallenge() {
+	challenge, err := suite.issueChallenge()
+	suite.Require().NoError(err)
+
+	suite.mockTokenRepo.On("Consume", mock.Anything, hashUserToken(challenge), domain.UserTokenLoginChallenge, suite.now).
+		Return(&domain.UserToken{UserID: "1"}, nil).Once()
+	suite.mockUserRepo.On("GetUser", mock.Anything, "1").Return(suite.enabledUser("USER"), nil)
+	suite.mockTOTP.On("Validate", "SECRET", "123456", suite.now).Return(int64(42), true)
+	suite.mockUserRepo.On("UseTOTPStep", mock.Anything, "1", int64(42)).Return(true, nil).Once()
+
+	user_id, err := suite.twoFactor.CompleteChallenge(context.Background(), challenge, "123456")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "1", user_id)
+}
+
+func (suite *TwoFactorUseCaseTestSuite) TestCompleteChallengeRejectsReplayedCode() {
+	suite.mockTokenRepo.On("Consume", mock.Anything, hashUserToken("challenge"), domain.UserTokenLoginChallenge, suite.now).
+		Return(&domain.UserToken{UserID: "1"}, nil).Once()
+	suite.mockUserRepo.On("GetUser", mock.Anything, "1").Return(suite.enabledUser("USER"), nil)
+	suite.mockTOTP.On("Validate", "SECRET", "123456", suite.now).Return(int64(42), true)
+	suite.mockUserRepo.On("UseTOTPStep", mock.Anything, "1", int64(42)).Return(false, nil).Once()
+
+	user_id, err := suite.twoFactor.CompleteChallenge(context.Background(), "challenge", "123456")
+	assert.ErrorIs(suite.T(), err, domain.ErrInvalidTwoFactorCode)
+	// The user comes back with the error so the failure can be throttled
+	assert.Equal(suite.T(), "1", user_id)
+}
+
+func (suite *TwoFactorUseCaseTestSuite) issueChallenge() (string, error) {
+	suite.mockTokenRepo.On("Create", mock.Anything, mock.MatchedBy(func(token domain.UserToken) bool {
+		return token.UserID == "1" && token.Purpose == domain.UserTokenLoginChallenge &&
+			token.ExpiresAt.Equal(suite.now.Add(loginChallengeTTL))
+	})).Return(nil).Once()
+	return suite.twoFactor.IssueChallenge(context.Background(), "1")
+}
+
+func TestTwoFactorUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(TwoFactorUseCaseTestSuite))
+}
@@ -3,12 +3,21 @@ package usecases
 import (
 	"context"
 	"fmt"
+	"strings"
 	"task_manger_clean_architecture/domain"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ProfileFieldsByRole lists the profile fields each role may change on its
+// own account. Admins cannot move their own address, so a hijacked admin
+// session cannot redirect the account's password reset mail.
+var ProfileFieldsByRole = map[string][]string{
+	"USER":  {"firstname", "lastname", "phone", "email"},
+	"ADMIN": {"firstname", "lastname", "phone"},
+}
+
 type UserUseCase struct {
 	UserRepository domain.UserRepository
 	contextTimeout time.Duration
@@ -25,19 +34,32 @@ func NewUserUseCase(userRepository domain.UserRepository, timeout time.Duration)
 
 // GetUser implements domain.UserUseCase.
 func (u *UserUseCase) GetUser(c context.Context, user_id string) (domain.User, error) {
-	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	ctx, cancel := withTimeout(c, u.contextTimeout)
 	defer cancel()
 	return u.UserRepository.GetUser(ctx, user_id)
 }
+// GetMember implements domain.UserUseCase.
+func (u *UserUseCase) GetMember(c context.Context, user_id string) (domain.User, error) {
+	ctx, cancel := withTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.UserRepository.GetMember(ctx, user_id)
+}
+
+// GetMembers implements domain.UserUseCase.
+func (u *UserUseCase) GetMembers(c context.Context, user_ids []string) ([]domain.User, error) {
+	ctx, cancel := withTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.UserRepository.GetMembers(ctx, user_ids)
+}
 func (u *UserUseCase) GetUserByEmail(c context.Context,email string) (domain.User, error) {
-	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	ctx, cancel := withTimeout(c, u.contextTimeout)
 	defer cancel()
 	return u.UserRepository.GetUserByEmail(ctx, email)
 }
 
 // GetUsers implements domain.UserUseCase.
 func (u *UserUseCase) GetUsers(c context.Context, startIndex int64, recordsPerPage int64) ([]*domain.User, error) {
-    ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+    ctx, cancel := withTimeout(c, u.contextTimeout)
     defer cancel()
     
     users, err := u.UserRepository.GetUsers(ctx, startIndex, recordsPerPage)
@@ -51,31 +73,102 @@ func (u *UserUseCase) GetUsers(c context.Context, startIndex int64, recordsPerPa
 
 // Login implements domain.UserUseCase.
 func (u *UserUseCase) Login(c context.Context, email string) (*domain.User, error) {
-	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	ctx, cancel := withTimeout(c, u.contextTimeout)
 	defer cancel()
 	return u.UserRepository.Login(ctx, email)
 }
 
 // Promote implements domain.UserUseCase.
 func (u *UserUseCase) Promote(c context.Context, user_id string, userType string) (error,int64, int64) {
-	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	ctx, cancel := withTimeout(c, u.contextTimeout)
 	defer cancel()
 	return u.UserRepository.Promote(ctx, user_id, userType)
 }
 
-// Signup implements domain.UserUseCase.
+// Signup implements domain.UserUseCase. Accounts sign up as users and in no
+// workspace: they create one or accept an invite to one. Whatever user type
+// the client asked for is ignored.
 func (u *UserUseCase) Signup(c context.Context, user domain.User) (interface{}, error) {
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
-	user.ID = primitive.NewObjectID()
+	if user.ID.IsZero() {
+		user.ID = primitive.NewObjectID()
+	}
 	user.UserId = user.ID.Hex()
+	userType := "USER"
+	user.UserType = &userType
+	user.Workspaces = nil
 
-	ctx, cancel := context.WithTimeout(c, u.contextTimeout)
+	ctx, cancel := withTimeout(c, u.contextTimeout)
 	defer cancel()
 	return u.UserRepository.Signup(ctx, user)
 }
 // UpdateToken implements domain.UserUseCase.
-func (u *UserUseCase) UpdateAllTokens(token string, refreshToken string, user_id string) error {
-	return u.UserRepository.UpdateAllTokens(token, refreshToken, user_id)
+func (u *UserUseCase) UpdateAllTokens(c context.Context, token string, refreshToken string, user_id string) error {
+	ctx, cancel := withTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.UserRepository.UpdateAllTokens(ctx, token, refreshToken, user_id)
 }
 
+// UpdateProfile implements domain.UserUseCase.
+func (u *UserUseCase) UpdateProfile(c context.Context, user_id string, role string, update domain.ProfileUpdate) (domain.User, error) {
+	ctx, cancel := withTimeout(c, u.contextTimeout)
+	defer cancel()
+
+	// An account in no workspace has no role there and edits its profile
+	// like a user
+	if role == "" {
+		role = "USER"
+	}
+	for _, field := range update.Fields() {
+		if !profileFieldAllowed(role, field) {
+			return domain.User{}, fmt.Errorf("%w: %s", domain.ErrProfileFieldNotEditable, field)
+		}
+	}
+
+	current, err := u.UserRepository.GetUser(ctx, user_id)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if update.Email != nil {
+		email := strings.TrimSpace(*update.Email)
+		if current.Email != nil && strings.EqualFold(email, *current.Email) {
+			// Not a change, so keep the address verified
+			update.Email = nil
+		} else {
+			if existing, err := u.UserRepository.GetUserByEmail(ctx, email); err == nil && existing.UserId != "" {
+				return domain.User{}, domain.ErrEmailTaken
+			}
+			update.Email = &email
+		}
+	}
+
+	if err := u.UserRepository.UpdateProfile(ctx, user_id, update); err != nil {
+		return domain.User{}, err
+	}
+	return u.UserRepository.GetUser(ctx, user_id)
+}
+
+func profileFieldAllowed(role string, field string) bool {
+	for _, allowed := range ProfileFieldsByRole[strings.ToUpper(role)] {
+		if allowed == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangePassword implements domain.UserUseCase. The password must already be
+// hashed.
+func (u *UserUseCase) ChangePassword(c context.Context, user_id string, password string) error {
+	ctx, cancel := withTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.UserRepository.UpdatePassword(ctx, user_id, password)
+}
+
+// UpgradePasswordHash implements domain.UserUseCase.
+func (u *UserUseCase) UpgradePasswordHash(c context.Context, user_id string, password string) error {
+	ctx, cancel := withTimeout(c, u.contextTimeout)
+	defer cancel()
+	return u.UserRepository.SetPasswordHash(ctx, user_id, password)
+}
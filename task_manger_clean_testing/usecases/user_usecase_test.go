@@ -2,6 +2,7 @@ package usecases
 
 import (
 	"context"
+	"errors"
 	"task_manger_clean_architecture/domain"
 	"task_manger_clean_architecture/domain/mocks"
 	"testing"
@@ -130,6 +131,8 @@ func (c *TestUserUseCase) TestSignup() {
 		FirstName: stringPtr("John"),
 		LastName:  stringPtr("Doe"),
 		Email:     stringPtr("john.doe@example.com"),
+		// Asked for by the client, which has no say in it
+		UserType: stringPtr("ADMIN"),
 	}
 
 	// Set up the mock expectation to match the modified user.
@@ -137,7 +140,9 @@ func (c *TestUserUseCase) TestSignup() {
 		// Validate the fields individually
 		return u.FirstName != nil && *u.FirstName == "John" &&
 			u.LastName != nil && *u.LastName == "Doe" &&
-			u.Email != nil && *u.Email == "john.doe@example.com"
+			u.Email != nil && *u.Email == "john.doe@example.com" &&
+			// New accounts are users in no workspace
+			u.UserType != nil && *u.UserType == "USER" && len(u.Workspaces) == 0
 	})).Return("user_id", nil)
 
 	// Call the Signup method.
@@ -158,9 +163,70 @@ func (c *TestUserUseCase) TestUpdateAllTokens() {
 	refreshToken := "new_refresh_token"
 	userID := "1"
 
-	c.mockRepo.On("UpdateAllTokens", token, refreshToken, userID).Return(nil)
+	c.mockRepo.On("UpdateAllTokens", mock.Anything, token, refreshToken, userID).Return(nil)
 
-	err := c.UserUseCase.UpdateAllTokens(token, refreshToken, userID)
+	err := c.UserUseCase.UpdateAllTokens(context.Background(), token, refreshToken, userID)
+	assert.NoError(c.T(), err)
+
+	c.mockRepo.AssertExpectations(c.T())
+}
+
+// TestUpdateProfileRejectsFieldsOutsideRole tests that admins cannot change their own email.
+func (c *TestUserUseCase) TestUpdateProfileRejectsFieldsOutsideRole() {
+	update := domain.ProfileUpdate{Email: stringPtr("new@example.com")}
+
+	_, err := c.UserUseCase.UpdateProfile(context.Background(), "1", "ADMIN", update)
+	assert.ErrorIs(c.T(), err, domain.ErrProfileFieldNotEditable)
+
+	c.mockRepo.AssertNotCalled(c.T(), "UpdateProfile", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestUpdateProfileWithoutWorkspace tests that an account in no workspace edits its profile like a user.
+func (c *TestUserUseCase) TestUpdateProfileWithoutWorkspace() {
+	update := domain.ProfileUpdate{FirstName: stringPtr("Bisrat")}
+	updated := domain.User{UserId: "1", FirstName: stringPtr("Bisrat")}
+
+	c.mockRepo.On("GetUser", mock.Anything, "1").Return(domain.User{UserId: "1"}, nil).Once()
+	c.mockRepo.On("UpdateProfile", mock.Anything, "1", update).Return(nil).Once()
+	c.mockRepo.On("GetUser", mock.Anything, "1").Return(updated, nil).Once()
+
+	result, err := c.UserUseCase.UpdateProfile(context.Background(), "1", "", update)
+	assert.NoError(c.T(), err)
+	assert.Equal(c.T(), updated, result)
+}
+
+// TestUpdateProfileEmail tests that a new email is checked for uniqueness before it is stored.
+func (c *TestUserUseCase) TestUpdateProfileEmail() {
+	current := domain.User{UserId: "1", Email: stringPtr("old@example.com")}
+	updated := domain.User{UserId: "1", Email: stringPtr("new@example.com"), TokenVersion: 1}
+
+	c.mockRepo.On("GetUser", mock.Anything, "1").Return(current, nil).Once()
+	c.mockRepo.On("GetUserByEmail", mock.Anything, "taken@example.com").Return(domain.User{UserId: "2"}, nil).Once()
+
+	_, err := c.UserUseCase.UpdateProfile(context.Background(), "1", "USER", domain.ProfileUpdate{Email: stringPtr("taken@example.com")})
+	assert.ErrorIs(c.T(), err, domain.ErrEmailTaken)
+
+	c.mockRepo.On("GetUser", mock.Anything, "1").Return(current, nil).Once()
+	c.mockRepo.On("GetUserByEmail", mock.Anything, "new@example.com").Return(domain.User{}, errors.New("not found")).Once()
+	c.mockRepo.On("UpdateProfile", mock.Anything, "1", domain.ProfileUpdate{Email: stringPtr("new@example.com")}).Return(nil).Once()
+	c.mockRepo.On("GetUser", mock.Anything, "1").Return(updated, nil).Once()
+
+	result, err := c.UserUseCase.UpdateProfile(context.Background(), "1", "USER", domain.ProfileUpdate{Email: stringPtr(" new@example.com ")})
+	assert.NoError(c.T(), err)
+	assert.Equal(c.T(), updated, result)
+
+	c.mockRepo.AssertExpectations(c.T())
+}
+
+// TestUpdateProfileSameEmail tests that resending the current email does not count as a change.
+func (c *TestUserUseCase) TestUpdateProfileSameEmail() {
+	current := domain.User{UserId: "1", Email: stringPtr("me@example.com"), EmailVerified: true}
+	update := domain.ProfileUpdate{FirstName: stringPtr("Jane"), Email: stringPtr("ME@example.com")}
+
+	c.mockRepo.On("GetUser", mock.Anything, "1").Return(current, nil)
+	c.mockRepo.On("UpdateProfile", mock.Anything, "1", domain.ProfileUpdate{FirstName: stringPtr("Jane")}).Return(nil).Once()
+
+	_, err := c.UserUseCase.UpdateProfile(context.Background(), "1", "USER", update)
 	assert.NoError(c.T(), err)
 
 	c.mockRepo.AssertExpectations(c.T())
@@ -0,0 +1,101 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"task_manger_clean_architecture/domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type WorkspaceUseCase struct {
+	workspaceRepository domain.WorkspaceRepository
+	userRepository      domain.UserRepository
+	audit               domain.AuditUsecase
+	logger              *slog.Logger
+	contextTimeout      time.Duration
+	now                 func() time.Time
+}
+
+func NewWorkspaceUseCase(workspaceRepository domain.WorkspaceRepository, userRepository domain.UserRepository, audit domain.AuditUsecase, logger *slog.Logger, timeout time.Duration) domain.WorkspaceUsecase {
+	return &WorkspaceUseCase{
+		workspaceRepository: workspaceRepository,
+		userRepository:      userRepository,
+		audit:               audit,
+		logger:              logger,
+		contextTimeout:      timeout,
+		now:                 time.Now,
+	}
+}
+
+// Create implements domain.WorkspaceUsecase.
+func (w *WorkspaceUseCase) Create(c context.Context, user_id string, name string) (*domain.Workspace, error) {
+	ctx, cancel := withTimeout(c, w.contextTimeout)
+	defer cancel()
+
+	workspace := domain.Workspace{
+		ID:        primitive.NewObjectID().Hex(),
+		Name:      strings.TrimSpace(name),
+		CreatedBy: user_id,
+		CreatedAt: w.now().UTC(),
+	}
+	if err := w.workspaceRepository.Create(ctx, workspace); err != nil {
+		return nil, err
+	}
+	membership := domain.WorkspaceMembership{WorkspaceID: workspace.ID, Role: "ADMIN"}
+	if err := w.userRepository.AddMembership(ctx, user_id, membership); err != nil {
+		return nil, err
+	}
+	recordWrite(ctx, w.audit, w.logger, domain.AuditWorkspaceCreate, workspace.ID, nil, workspace)
+	return &workspace, nil
+}
+
+// List implements domain.WorkspaceUsecase.
+func (w *WorkspaceUseCase) List(c context.Context, user_id string) ([]domain.MemberWorkspace, error) {
+	ctx, cancel := withTimeout(c, w.contextTimeout)
+	defer cancel()
+
+	user, err := w.userRepository.GetUser(ctx, user_id)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(user.Workspaces))
+	for _, membership := range user.Workspaces {
+		ids = append(ids, membership.WorkspaceID)
+	}
+	workspaces, err := w.workspaceRepository.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the user's order, so the workspace a login starts in comes first
+	byID := make(map[string]domain.Workspace, len(workspaces))
+	for _, workspace := range workspaces {
+		byID[workspace.ID] = workspace
+	}
+	listed := make([]domain.MemberWorkspace, 0, len(user.Workspaces))
+	for _, membership := range user.Workspaces {
+		if workspace, ok := byID[membership.WorkspaceID]; ok {
+			listed = append(listed, domain.MemberWorkspace{Workspace: workspace, Role: membership.Role})
+		}
+	}
+	return listed, nil
+}
+
+// Membership implements domain.WorkspaceUsecase.
+func (w *WorkspaceUseCase) Membership(c context.Context, user_id string, workspace_id string) (domain.WorkspaceMembership, error) {
+	ctx, cancel := withTimeout(c, w.contextTimeout)
+	defer cancel()
+
+	user, err := w.userRepository.GetUser(ctx, user_id)
+	if err != nil {
+		return domain.WorkspaceMembership{}, err
+	}
+	role, ok := user.Role(workspace_id)
+	if !ok {
+		return domain.WorkspaceMembership{}, domain.ErrNotWorkspaceMember
+	}
+	return domain.WorkspaceMembership{WorkspaceID: workspace_id, Role: role}, nil
+}
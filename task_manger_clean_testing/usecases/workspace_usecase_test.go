@@ -0,0 +1,99 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"task_manger_clean_architecture/domain"
+	"task_manger_clean_architecture/domain/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type WorkspaceUseCaseTestSuite struct {
+	suite.Suite
+	mockWorkspaceRepo *mocks.WorkspaceRepository
+	mockUserRepo      *mocks.UserRepository
+	mockAudit         *mocks.AuditUsecase
+	now               time.Time
+	workspaces        *WorkspaceUseCase
+}
+
+func (suite *WorkspaceUseCaseTestSuite) SetupTest() {
+	suite.mockWorkspaceRepo = new(mocks.WorkspaceRepository)
+	suite.mockUserRepo = new(mocks.UserRepository)
+	suite.mockAudit = new(mocks.AuditUsecase)
+	suite.now = time.Date(2024, 8, 1, 12, 0, 0, 0, time.UTC)
+	suite.workspaces = NewWorkspaceUseCase(suite.mockWorkspaceRepo, suite.mockUserRepo, suite.mockAudit, discardLogger, time.Second*2).(*WorkspaceUseCase)
+	suite.workspaces.now = func() time.Time { return suite.now }
+}
+
+func (suite *WorkspaceUseCaseTestSuite) TestCreateMakesCreatorAdmin() {
+	var stored domain.Workspace
+	suite.mockWorkspaceRepo.On("Create", mock.Anything, mock.AnythingOfType("domain.Workspace")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(domain.Workspace) }).Return(nil).Once()
+	suite.mockUserRepo.On("AddMembership", mock.Anything, "1", mock.MatchedBy(func(m domain.WorkspaceMembership) bool {
+		return m.WorkspaceID == stored.ID && m.Role == "ADMIN"
+	})).Return(nil).Once()
+	suite.mockAudit.On("Record", mock.Anything, domain.AuditWorkspaceCreate, mock.Anything, nil, mock.Anything).Return(nil).Once()
+
+	workspace, err := suite.workspaces.Create(context.Background(), "1", " Acme ")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "Acme", workspace.Name)
+	assert.Equal(suite.T(), "1", workspace.CreatedBy)
+	assert.Equal(suite.T(), suite.now, workspace.CreatedAt)
+	assert.Equal(suite.T(), stored, *workspace)
+	suite.mockUserRepo.AssertExpectations(suite.T())
+	suite.mockAudit.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceUseCaseTestSuite) TestCreateSurvivesAuditFailure() {
+	suite.mockWorkspaceRepo.On("Create", mock.Anything, mock.AnythingOfType("domain.Workspace")).Return(nil).Once()
+	suite.mockUserRepo.On("AddMembership", mock.Anything, "1", mock.Anything).Return(nil).Once()
+	suite.mockAudit.On("Record", mock.Anything, domain.AuditWorkspaceCreate, mock.Anything, nil, mock.Anything).Return(errors.New("audit down")).Once()
+
+	// The workspace exists already, so the caller must learn about it
+	workspace, err := suite.workspaces.Create(context.Background(), "1", "Acme")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), "Acme", workspace.Name)
+	suite.mockAudit.AssertExpectations(suite.T())
+}
+
+func (suite *WorkspaceUseCaseTestSuite) TestListKeepsMembershipOrder() {
+	suite.mockUserRepo.On("GetUser", mock.Anything, "1").Return(domain.User{UserId: "1", Workspaces: []domain.WorkspaceMembership{
+		{WorkspaceID: "ws2", Role: "ADMIN"},
+		{WorkspaceID: "gone", Role: "USER"},
+		{WorkspaceID: domain.DefaultWorkspaceID, Role: "USER"},
+	}}, nil)
+	suite.mockWorkspaceRepo.On("GetByIDs", mock.Anything, []string{"ws2", "gone", domain.DefaultWorkspaceID}).Return([]domain.Workspace{
+		{ID: domain.DefaultWorkspaceID, Name: "Default"},
+		{ID: "ws2", Name: "Acme"},
+	}, nil)
+
+	workspaces, err := suite.workspaces.List(context.Background(), "1")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), []domain.MemberWorkspace{
+		{Workspace: domain.Workspace{ID: "ws2", Name: "Acme"}, Role: "ADMIN"},
+		{Workspace: domain.Workspace{ID: domain.DefaultWorkspaceID, Name: "Default"}, Role: "USER"},
+	}, workspaces)
+}
+
+func (suite *WorkspaceUseCaseTestSuite) TestMembership() {
+	suite.mockUserRepo.On("GetUser", mock.Anything, "1").Return(domain.User{UserId: "1", Workspaces: []domain.WorkspaceMembership{
+		{WorkspaceID: "ws2", Role: "ADMIN"},
+	}}, nil)
+
+	membership, err := suite.workspaces.Membership(context.Background(), "1", "ws2")
+	suite.Require().NoError(err)
+	assert.Equal(suite.T(), domain.WorkspaceMembership{WorkspaceID: "ws2", Role: "ADMIN"}, membership)
+
+	_, err = suite.workspaces.Membership(context.Background(), "1", "ws3")
+	assert.ErrorIs(suite.T(), err, domain.ErrNotWorkspaceMember)
+}
+
+func TestWorkspaceUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(WorkspaceUseCaseTestSuite))
+}